@@ -0,0 +1,136 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+// maxPooledBufferSize is the max capacity of a buffer that is returned to the pool.
+// Larger buffers are left to the garbage collector, so a single huge document
+// doesn't pin its memory for the lifetime of the pool.
+const maxPooledBufferSize = 1 << 20
+
+// BufferPool is a pool of buffers records are serialized into, which reduces allocations
+// and GC pressure under sustained load. A nil pool is valid and disables pooling,
+// so every value is serialized into a newly allocated buffer.
+type BufferPool struct {
+	pool sync.Pool
+}
+
+// NewBufferPool creates a new instance of the [BufferPool].
+func NewBufferPool() *BufferPool {
+	return &BufferPool{
+		pool: sync.Pool{
+			New: func() any {
+				return new(bytes.Buffer)
+			},
+		},
+	}
+}
+
+// EncodeJSON returns the JSON encoding of the value, the same as [json.Marshal] does.
+func (p *BufferPool) EncodeJSON(value any) ([]byte, error) {
+	if p == nil {
+		return json.Marshal(value) //nolint:wrapcheck // the pool mimics json.Marshal
+	}
+
+	var data []byte
+	err := p.WithJSON(value, func(encoded []byte) error {
+		data = bytes.Clone(encoded)
+
+		return nil
+	})
+
+	return data, err
+}
+
+// WithJSON encodes the value as JSON into a buffer and passes its bytes to the fn.
+// The bytes are only valid until the fn returns, as the buffer is reused afterwards.
+func (p *BufferPool) WithJSON(value any, fn func([]byte) error) error {
+	if p == nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal json: %w", err)
+		}
+
+		return fn(data)
+	}
+
+	buf := p.get()
+	defer p.put(buf)
+
+	if err := json.NewEncoder(buf).Encode(value); err != nil {
+		return fmt.Errorf("encode json: %w", err)
+	}
+
+	// the encoder terminates a value with a newline, which json.Marshal doesn't
+	return fn(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// EncodeExtJSON returns the Extended JSON encoding of the value, the same as [bson.MarshalExtJSON] does.
+// HTML characters are never escaped.
+func (p *BufferPool) EncodeExtJSON(value any, canonical bool) ([]byte, error) {
+	if p == nil {
+		return bson.MarshalExtJSON(value, canonical, false) //nolint:wrapcheck // the pool mimics bson.MarshalExtJSON
+	}
+
+	buf := p.get()
+	defer p.put(buf)
+
+	valueWriter, err := bsonrw.NewExtJSONValueWriter(buf, canonical, false)
+	if err != nil {
+		return nil, fmt.Errorf("create extended json value writer: %w", err)
+	}
+
+	encoder, err := bson.NewEncoder(valueWriter)
+	if err != nil {
+		return nil, fmt.Errorf("create encoder: %w", err)
+	}
+
+	if err := encoder.Encode(value); err != nil {
+		return nil, fmt.Errorf("encode extended json: %w", err)
+	}
+
+	// the value writer terminates a document with a newline, which bson.MarshalExtJSON doesn't
+	return bytes.Clone(bytes.TrimSuffix(buf.Bytes(), []byte("\n"))), nil
+}
+
+// get returns an empty buffer from the pool.
+func (p *BufferPool) get() *bytes.Buffer {
+	buf, ok := p.pool.Get().(*bytes.Buffer)
+	if !ok {
+		return new(bytes.Buffer)
+	}
+
+	buf.Reset()
+
+	return buf
+}
+
+// put returns the buffer to the pool, unless it has grown too large.
+func (p *BufferPool) put(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferSize {
+		return
+	}
+
+	p.pool.Put(buf)
+}
@@ -0,0 +1,96 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBufferPool_EncodeJSON(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]any{"b": "<tag>", "a": []any{1, 2.5, nil}, "c": map[string]any{"d": true}}
+
+	want, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("marshal json: %v", err)
+	}
+
+	pools := map[string]*BufferPool{
+		"pooled":   NewBufferPool(),
+		"unpooled": nil,
+	}
+
+	for name, pool := range pools {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// marshal a few times to make sure reused buffers don't leak previous values
+			for range 3 {
+				got, err := pool.EncodeJSON(value)
+				if err != nil {
+					t.Fatalf("BufferPool.EncodeJSON() error = %v", err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Errorf("BufferPool.EncodeJSON() = %s, want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBufferPool_EncodeExtJSON(t *testing.T) {
+	t.Parallel()
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "html", Value: "<tag>"},
+		{Key: "count", Value: int64(42)},
+	})
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+
+	pools := map[string]*BufferPool{
+		"pooled":   NewBufferPool(),
+		"unpooled": nil,
+	}
+
+	for name, pool := range pools {
+		for _, canonical := range []bool{true, false} {
+			want, err := bson.MarshalExtJSON(bson.Raw(document), canonical, false)
+			if err != nil {
+				t.Fatalf("marshal extended json: %v", err)
+			}
+
+			for range 3 {
+				got, err := pool.EncodeExtJSON(bson.Raw(document), canonical)
+				if err != nil {
+					t.Fatalf("%s: BufferPool.EncodeExtJSON() error = %v", name, err)
+				}
+
+				if !bytes.Equal(got, want) {
+					t.Errorf("%s: BufferPool.EncodeExtJSON() = %s, want %s", name, got, want)
+				}
+			}
+		}
+	}
+}
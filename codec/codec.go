@@ -19,6 +19,7 @@ import (
 
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -51,3 +52,73 @@ func (sc StringObjectIDCodec) EncodeValue(
 
 	return vw.WriteObjectID(objectID)
 }
+
+// UUIDEncoding defines whether and how UUID strings are encoded into binary UUIDs.
+type UUIDEncoding string
+
+// The list of available UUID encodings is listed below.
+const (
+	// UUIDEncodingNone keeps UUID strings as they are.
+	UUIDEncodingNone UUIDEncoding = "none"
+	// UUIDEncodingStandard encodes UUID strings into binaries of the subtype 4.
+	UUIDEncodingStandard UUIDEncoding = "standard"
+	// UUIDEncodingLegacy encodes UUID strings into binaries of the legacy subtype 3.
+	UUIDEncodingLegacy UUIDEncoding = "legacy"
+)
+
+// Subtype returns the binary subtype UUIDs are encoded with, and false if UUID strings are kept as they are.
+func (e UUIDEncoding) Subtype() (byte, bool) {
+	switch e {
+	case UUIDEncodingStandard:
+		return bsontype.BinaryUUID, true
+
+	case UUIDEncodingLegacy:
+		return bsontype.BinaryUUIDOld, true
+
+	case UUIDEncodingNone:
+		return 0, false
+
+	default:
+		return 0, false
+	}
+}
+
+// StringUUIDCodec is the Codec used for string values.
+// It tries to convert strings into binary UUIDs of its subtype.
+type StringUUIDCodec struct {
+	// Subtype is the binary subtype UUIDs are written with,
+	// e.g. [bsontype.BinaryUUID] or [bsontype.BinaryUUIDOld].
+	Subtype byte
+	// Fallback encodes strings that are not UUIDs. If it's nil, they're written as they are.
+	Fallback bsoncodec.ValueEncoder
+}
+
+// EncodeValue implements the [bsoncodec.ValueEncoder] interface.
+// The method tries to convert a string value into a binary UUID.
+//
+//   - If a string is a canonical UUID string it returns it as a binary of the codec's subtype.
+//   - If a string is not a UUID string it's encoded with the fallback encoder, or written as it is.
+func (sc StringUUIDCodec) EncodeValue(
+	ec bsoncodec.EncodeContext,
+	vw bsonrw.ValueWriter,
+	val reflect.Value,
+) error {
+	if val.Kind() != reflect.String {
+		return bsoncodec.ValueEncoderError{
+			Name:     "StringEncodeValue",
+			Kinds:    []reflect.Kind{reflect.String},
+			Received: val,
+		}
+	}
+
+	data, err := ParseUUID(val.String())
+	if err == nil {
+		return vw.WriteBinaryWithSubtype(data, sc.Subtype)
+	}
+
+	if sc.Fallback != nil {
+		return sc.Fallback.EncodeValue(ec, vw, val) //nolint:wrapcheck // the fallback is a part of the codec
+	}
+
+	return vw.WriteString(val.String())
+}
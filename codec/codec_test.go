@@ -21,6 +21,7 @@ import (
 
 	"github.com/brianvoe/gofakeit"
 	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
 	"go.mongodb.org/mongo-driver/bson/bsonrw/bsonrwtest"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -28,7 +29,8 @@ import (
 
 // valueReaderWriter implements the [bsonrw.ValueWriter]
 // and overrides its WriteString and WriteObjectID methods
-// to use them within the StringObjectIDCodec.EncodeValue method.
+// to use them within the StringObjectIDCodec.EncodeValue method,
+// as well as its WriteBinaryWithSubtype method used within the StringUUIDCodec.EncodeValue method.
 type valueReaderWriter struct {
 	bsonrwtest.ValueReaderWriter
 
@@ -51,6 +53,12 @@ func (vrw *valueReaderWriter) WriteObjectID(oid primitive.ObjectID) error {
 	return nil
 }
 
+func (vrw *valueReaderWriter) WriteBinaryWithSubtype(data []byte, subtype byte) error {
+	vrw.value = primitive.Binary{Subtype: subtype, Data: data}
+
+	return nil
+}
+
 func TestStringObjectIDCodec_EncodeValue_ValidObjectID(t *testing.T) {
 	t.Parallel()
 
@@ -108,3 +116,28 @@ func TestStringObjectIDCodec_EncodeValue_InvalidParameter(t *testing.T) {
 	is.True(errors.As(err, &valueEncoderError))
 	is.Equal(valueEncoderError.Name, "StringEncodeValue")
 }
+
+func TestStringUUIDCodec_EncodeValue(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	codec := StringUUIDCodec{Subtype: bson.TypeBinaryUUIDOld, Fallback: StringObjectIDCodec{}}
+
+	// a UUID string is written as a binary of the codec's subtype
+	vw := newValueReaderWriter()
+	err := codec.EncodeValue(bsoncodec.EncodeContext{}, vw, reflect.ValueOf(testUUIDString))
+	is.NoErr(err)
+	is.Equal(vw.value, primitive.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: testUUID})
+
+	// other strings are encoded with the fallback encoder
+	objectID := primitive.NewObjectID()
+	err = codec.EncodeValue(bsoncodec.EncodeContext{}, vw, reflect.ValueOf(objectID.Hex()))
+	is.NoErr(err)
+	is.Equal(vw.value, objectID)
+
+	// without the fallback encoder other strings are written as they are
+	codec.Fallback = nil
+	err = codec.EncodeValue(bsoncodec.EncodeContext{}, vw, reflect.ValueOf(objectID.Hex()))
+	is.NoErr(err)
+	is.Equal(vw.value, objectID.Hex())
+}
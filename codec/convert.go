@@ -0,0 +1,519 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DateTimeFormat defines how BSON date and time values are represented after conversion.
+type DateTimeFormat string
+
+// The list of available date and time formats is listed below.
+const (
+	// DateTimeFormatRFC3339 represents date and time values as RFC 3339 strings in UTC.
+	DateTimeFormatRFC3339 DateTimeFormat = "rfc3339"
+	// DateTimeFormatMillis represents date and time values as milliseconds since the Unix epoch.
+	DateTimeFormatMillis DateTimeFormat = "millis"
+	// DateTimeFormatNative represents date and time values as [time.Time] values in UTC,
+	// which are marshaled into JSON as RFC 3339 strings.
+	DateTimeFormatNative DateTimeFormat = "native"
+)
+
+// TimestampFormat defines how BSON timestamp values are represented after conversion.
+type TimestampFormat string
+
+// The list of available timestamp formats is listed below.
+const (
+	// TimestampFormatTimestamp represents timestamp values as maps with the "T" seconds and "I" increments,
+	// which are marshaled into JSON the same way as [primitive.Timestamp] values.
+	TimestampFormatTimestamp TimestampFormat = "timestamp"
+	// TimestampFormatDate represents timestamp values the same way as date and time values,
+	// according to the [DateTimeFormat]. Their increments are lost.
+	TimestampFormatDate TimestampFormat = "date"
+)
+
+// DecimalFormat defines how BSON 128-bit decimal values are represented after conversion.
+type DecimalFormat string
+
+// The list of available decimal formats is listed below.
+const (
+	// DecimalFormatString represents decimal values as strings, without losing precision.
+	DecimalFormatString DecimalFormat = "string"
+	// DecimalFormatFloat represents decimal values as float64 numbers.
+	DecimalFormatFloat DecimalFormat = "float"
+)
+
+// UUIDFormat defines how BSON binary UUID values are represented after conversion.
+type UUIDFormat string
+
+// The list of available UUID formats is listed below.
+const (
+	// UUIDFormatBytes represents UUID values as bytes, the same as other binary data.
+	UUIDFormatBytes UUIDFormat = "bytes"
+	// UUIDFormatString represents UUID values, i.e. binaries of the subtypes 3 and 4,
+	// as canonical UUID strings.
+	UUIDFormatString UUIDFormat = "string"
+)
+
+// BinaryFormat defines how BSON binary values are represented after conversion.
+type BinaryFormat string
+
+// The list of available binary formats is listed below.
+const (
+	// BinaryFormatBytes represents binary values as bytes, losing their subtypes.
+	BinaryFormatBytes BinaryFormat = "bytes"
+	// BinaryFormatBase64 represents binary values as standard base64-encoded strings.
+	// Their subtypes can be collected with [Converter.BinarySubtypes].
+	BinaryFormatBase64 BinaryFormat = "base64"
+)
+
+// Converter converts native BSON values, like [primitive.DateTime], [primitive.Decimal128]
+// or [primitive.Binary], into Go-native values that are safe to marshal into JSON
+// and to put into records' structured data.
+type Converter struct {
+	DateTimeFormat  DateTimeFormat
+	TimestampFormat TimestampFormat
+	DecimalFormat   DecimalFormat
+	UUIDFormat      UUIDFormat
+	BinaryFormat    BinaryFormat
+	// Strict makes [Converter.ConvertRaw] return a [LossyConversionError]
+	// instead of converting a value that can't be represented faithfully.
+	Strict bool
+	// IDType is the type _id values of records' keys are converted into, see [IDType.Convert].
+	IDType IDType
+}
+
+// lossyTypes are BSON types whose converted values can't be told apart from other types' ones,
+// or lose information, like min and max keys converted into nil.
+var lossyTypes = map[bsontype.Type]bool{
+	bsontype.Regex:         true,
+	bsontype.JavaScript:    true,
+	bsontype.Symbol:        true,
+	bsontype.Timestamp:     true,
+	bsontype.Undefined:     true,
+	bsontype.MinKey:        true,
+	bsontype.MaxKey:        true,
+	bsontype.DBPointer:     true,
+	bsontype.CodeWithScope: true,
+}
+
+// LossyConversionError occurs in the strict mode when a BSON value can't be represented faithfully.
+type LossyConversionError struct {
+	// Path is a dot-separated path of the field the value belongs to, array elements are referred to by index.
+	Path string
+	// Type is the BSON type of the value.
+	Type bsontype.Type
+}
+
+// Error returns the error message.
+func (e *LossyConversionError) Error() string {
+	return fmt.Sprintf("%s value of the %q field can't be converted without losing information", e.Type, e.Path)
+}
+
+// ConvertDocument converts all values of the provided document, including nested ones.
+func (c Converter) ConvertDocument(document map[string]any) map[string]any {
+	if document == nil {
+		return nil
+	}
+
+	converted := make(map[string]any, len(document))
+	for key, value := range document {
+		converted[key] = c.Convert(value)
+	}
+
+	return converted
+}
+
+// Convert converts the provided value. Values that aren't native BSON types are returned as they are.
+func (c Converter) Convert(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return c.ConvertDocument(value)
+
+	case primitive.M:
+		return c.ConvertDocument(value)
+
+	case primitive.D:
+		return c.ConvertDocument(value.Map())
+
+	case []any:
+		return c.convertArray(value)
+
+	case primitive.A:
+		return c.convertArray(value)
+
+	case primitive.DateTime:
+		return c.convertDateTime(value.Time())
+
+	case primitive.Timestamp:
+		return c.convertTimestamp(value)
+
+	case primitive.Decimal128:
+		return c.convertDecimal(value)
+
+	case primitive.Binary:
+		return c.convertBinary(value.Subtype, value.Data)
+
+	case primitive.ObjectID:
+		return value.Hex()
+
+	case primitive.Regex:
+		return value.String()
+
+	case primitive.JavaScript:
+		return string(value)
+
+	case primitive.Symbol:
+		return string(value)
+
+	case primitive.DBPointer:
+		return map[string]any{"DB": value.DB, "Pointer": value.Pointer.Hex()}
+
+	case primitive.CodeWithScope:
+		return map[string]any{"Code": string(value.Code), "Scope": c.Convert(value.Scope)}
+
+	case primitive.Null, primitive.Undefined, primitive.MinKey, primitive.MaxKey:
+		return nil
+
+	default:
+		return value
+	}
+}
+
+// ConvertRaw converts the provided raw document in a single pass over its elements,
+// producing the same values as decoding it into a map and calling [Converter.ConvertDocument],
+// but without allocating intermediate BSON values.
+func (c Converter) ConvertRaw(document bson.Raw) (map[string]any, error) {
+	if document == nil {
+		return nil, nil //nolint:nilnil // a nil document is converted into a nil map
+	}
+
+	elements, err := document.Elements()
+	if err != nil {
+		return nil, fmt.Errorf("read document elements: %w", err)
+	}
+
+	converted := make(map[string]any, len(elements))
+	for _, element := range elements {
+		value, err := c.convertRawValue(element.Value())
+		if err != nil {
+			if lossyErr := prefixLossyPath(err, element.Key()); lossyErr != nil {
+				return nil, lossyErr
+			}
+
+			return nil, fmt.Errorf("convert %q field: %w", element.Key(), err)
+		}
+
+		converted[element.Key()] = value
+	}
+
+	return converted, nil
+}
+
+// convertRawValue converts the provided raw value.
+func (c Converter) convertRawValue(value bson.RawValue) (any, error) {
+	if c.Strict && c.isLossy(value) {
+		return nil, &LossyConversionError{Type: value.Type}
+	}
+
+	switch value.Type {
+	case bsontype.EmbeddedDocument:
+		return c.ConvertRaw(value.Document())
+
+	case bsontype.Array:
+		return c.convertRawArray(value.Array())
+
+	case bsontype.Double:
+		return value.Double(), nil
+
+	case bsontype.String:
+		return value.StringValue(), nil
+
+	case bsontype.Binary:
+		return c.convertBinary(value.Binary()), nil
+
+	case bsontype.ObjectID:
+		return value.ObjectID().Hex(), nil
+
+	case bsontype.Boolean:
+		return value.Boolean(), nil
+
+	case bsontype.DateTime:
+		return c.convertDateTime(value.Time()), nil
+
+	case bsontype.Regex:
+		pattern, options := value.Regex()
+
+		return primitive.Regex{Pattern: pattern, Options: options}.String(), nil
+
+	case bsontype.JavaScript:
+		return value.JavaScript(), nil
+
+	case bsontype.Symbol:
+		return value.Symbol(), nil
+
+	case bsontype.Int32:
+		return value.Int32(), nil
+
+	case bsontype.Timestamp:
+		t, i := value.Timestamp()
+
+		return c.convertTimestamp(primitive.Timestamp{T: t, I: i}), nil
+
+	case bsontype.Int64:
+		return value.Int64(), nil
+
+	case bsontype.Decimal128:
+		return c.convertDecimal(value.Decimal128()), nil
+
+	case bsontype.Null, bsontype.Undefined, bsontype.MinKey, bsontype.MaxKey:
+		return nil, nil
+
+	case bsontype.DBPointer, bsontype.CodeWithScope:
+		// these deprecated types are rare enough to be decoded the regular way
+		var decoded any
+		if err := value.Unmarshal(&decoded); err != nil {
+			return nil, fmt.Errorf("unmarshal %s value: %w", value.Type, err)
+		}
+
+		return c.Convert(decoded), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported bson type %s", value.Type)
+	}
+}
+
+// convertRawArray converts all elements of the provided raw array.
+func (c Converter) convertRawArray(array bson.Raw) ([]any, error) {
+	values, err := array.Values()
+	if err != nil {
+		return nil, fmt.Errorf("read array values: %w", err)
+	}
+
+	converted := make([]any, len(values))
+	for i, value := range values {
+		converted[i], err = c.convertRawValue(value)
+		if err != nil {
+			if lossyErr := prefixLossyPath(err, strconv.Itoa(i)); lossyErr != nil {
+				return nil, lossyErr
+			}
+
+			return nil, fmt.Errorf("convert array element %d: %w", i, err)
+		}
+	}
+
+	return converted, nil
+}
+
+// convertArray converts all elements of the provided array.
+func (c Converter) convertArray(array []any) []any {
+	converted := make([]any, len(array))
+	for i, value := range array {
+		converted[i] = c.Convert(value)
+	}
+
+	return converted
+}
+
+// convertDateTime converts a date and time according to the converter's [DateTimeFormat].
+func (c Converter) convertDateTime(t time.Time) any {
+	switch c.DateTimeFormat {
+	case DateTimeFormatMillis:
+		return t.UnixMilli()
+
+	case DateTimeFormatNative:
+		return t.UTC()
+
+	case DateTimeFormatRFC3339:
+		return t.UTC().Format(time.RFC3339Nano)
+
+	default:
+		return t.UTC().Format(time.RFC3339Nano)
+	}
+}
+
+// convertTimestamp converts a timestamp according to the converter's [TimestampFormat].
+func (c Converter) convertTimestamp(timestamp primitive.Timestamp) any {
+	switch c.TimestampFormat {
+	case TimestampFormatDate:
+		return c.convertDateTime(time.Unix(int64(timestamp.T), 0))
+
+	case TimestampFormatTimestamp:
+		return map[string]any{"T": timestamp.T, "I": timestamp.I}
+
+	default:
+		return map[string]any{"T": timestamp.T, "I": timestamp.I}
+	}
+}
+
+// convertBinary converts binary data according to the converter's [UUIDFormat] and [BinaryFormat].
+// UUIDs are converted into UUID strings first, other binaries are converted into bytes or base64 strings.
+func (c Converter) convertBinary(subtype byte, data []byte) any {
+	if c.UUIDFormat == UUIDFormatString && isUUID(subtype, data) {
+		return FormatUUID(data)
+	}
+
+	if c.BinaryFormat == BinaryFormatBase64 {
+		return base64.StdEncoding.EncodeToString(data)
+	}
+
+	return data
+}
+
+// isUUID checks whether binary data of the subtype is a UUID.
+func isUUID(subtype byte, data []byte) bool {
+	return (subtype == bson.TypeBinaryUUID || subtype == bson.TypeBinaryUUIDOld) && len(data) == uuidSize
+}
+
+// convertDecimal converts a decimal according to the converter's [DecimalFormat].
+// If a decimal can't be represented as a float64 number, it's converted into a string.
+func (c Converter) convertDecimal(decimal primitive.Decimal128) any {
+	switch c.DecimalFormat {
+	case DecimalFormatFloat:
+		if f, err := strconv.ParseFloat(decimal.String(), 64); err == nil {
+			return f
+		}
+
+		return decimal.String()
+
+	case DecimalFormatString:
+		return decimal.String()
+
+	default:
+		return decimal.String()
+	}
+}
+
+// isLossy checks whether the converted value of the provided raw value loses information.
+func (c Converter) isLossy(value bson.RawValue) bool {
+	if lossyTypes[value.Type] {
+		return true
+	}
+
+	// JSON has no representation of NaN and infinities
+	if f, ok := value.DoubleOK(); ok {
+		return math.IsNaN(f) || math.IsInf(f, 0)
+	}
+
+	// only generic binary data is represented as bytes faithfully, other subtypes like UUIDs lose their subtype,
+	// unless UUIDs are represented as UUID strings or subtypes are collected along with base64 strings
+	if subtype, data, ok := value.BinaryOK(); ok {
+		if c.BinaryFormat == BinaryFormatBase64 || (c.UUIDFormat == UUIDFormatString && isUUID(subtype, data)) {
+			return false
+		}
+
+		return subtype != bson.TypeBinaryGeneric && subtype != bson.TypeBinaryBinaryOld
+	}
+
+	if decimal, ok := value.Decimal128OK(); ok {
+		return c.DecimalFormat == DecimalFormatFloat && !decimalFitsFloat(decimal)
+	}
+
+	return false
+}
+
+// decimalFitsFloat checks whether the decimal can be represented as a float64 number exactly.
+func decimalFitsFloat(decimal primitive.Decimal128) bool {
+	exact, ok := new(big.Rat).SetString(decimal.String())
+	if !ok {
+		return false
+	}
+
+	f, err := strconv.ParseFloat(decimal.String(), 64)
+	if err != nil {
+		return false
+	}
+
+	return new(big.Rat).SetFloat64(f).Cmp(exact) == 0
+}
+
+// prefixLossyPath prepends the field name or the array index to the path of a [LossyConversionError],
+// so the error refers to the whole path of the field. It returns nil if the error is of another type.
+func prefixLossyPath(err error, name string) error {
+	var lossyErr *LossyConversionError
+	if !errors.As(err, &lossyErr) {
+		return nil
+	}
+
+	if lossyErr.Path == "" {
+		lossyErr.Path = name
+	} else {
+		lossyErr.Path = name + "." + lossyErr.Path
+	}
+
+	return lossyErr
+}
+
+// BinarySubtypes returns the subtypes of the binary fields of the raw document converted into base64 strings,
+// by dot-separated paths of the fields, array elements are referred to by index.
+// It returns nil if the converter doesn't convert binaries into base64 strings or the document has none.
+func (c Converter) BinarySubtypes(document bson.Raw) (map[string]byte, error) {
+	if c.BinaryFormat != BinaryFormatBase64 || document == nil {
+		return nil, nil //nolint:nilnil // no binaries are converted into base64 strings
+	}
+
+	subtypes := make(map[string]byte)
+	if err := c.collectBinarySubtypes(document, "", subtypes); err != nil {
+		return nil, err
+	}
+
+	if len(subtypes) == 0 {
+		return nil, nil //nolint:nilnil // the document has no binaries
+	}
+
+	return subtypes, nil
+}
+
+// collectBinarySubtypes puts the subtypes of the binary fields of the raw document or array into the subtypes,
+// prefixing their paths with the prefix.
+func (c Converter) collectBinarySubtypes(document bson.Raw, prefix string, subtypes map[string]byte) error {
+	elements, err := document.Elements()
+	if err != nil {
+		return fmt.Errorf("read document elements: %w", err)
+	}
+
+	for _, element := range elements {
+		path := prefix + element.Key()
+		value := element.Value()
+
+		if value.Type == bsontype.EmbeddedDocument || value.Type == bsontype.Array {
+			if err := c.collectBinarySubtypes(value.Value, path+".", subtypes); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		subtype, data, ok := value.BinaryOK()
+		if !ok || (c.UUIDFormat == UUIDFormatString && isUUID(subtype, data)) {
+			continue
+		}
+
+		subtypes[path] = subtype
+	}
+
+	return nil
+}
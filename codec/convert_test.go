@@ -0,0 +1,477 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	opencdcv1 "github.com/conduitio/conduit-commons/proto/opencdc/v1"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestConverter_Convert(t *testing.T) {
+	t.Parallel()
+
+	dateTime := primitive.NewDateTimeFromTime(time.Date(2023, 1, 2, 3, 4, 5, 6000000, time.UTC))
+	decimal, err := primitive.ParseDecimal128("12.50")
+	if err != nil {
+		t.Fatalf("parse decimal: %v", err)
+	}
+
+	objectID, err := primitive.ObjectIDFromHex("63b2c2a1f1a2b3c4d5e6f7a8")
+	if err != nil {
+		t.Fatalf("parse object id: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		converter Converter
+		value     any
+		want      any
+	}{
+		{
+			name:      "date_time_rfc3339",
+			converter: Converter{DateTimeFormat: DateTimeFormatRFC3339},
+			value:     dateTime,
+			want:      "2023-01-02T03:04:05.006Z",
+		},
+		{
+			name:      "date_time_millis",
+			converter: Converter{DateTimeFormat: DateTimeFormatMillis},
+			value:     dateTime,
+			want:      int64(1672628645006),
+		},
+		{
+			name:      "date_time_native",
+			converter: Converter{DateTimeFormat: DateTimeFormatNative},
+			value:     dateTime,
+			want:      time.Date(2023, 1, 2, 3, 4, 5, 6000000, time.UTC),
+		},
+		{
+			name:      "timestamp",
+			converter: Converter{TimestampFormat: TimestampFormatTimestamp},
+			value:     primitive.Timestamp{T: 1672628645, I: 3},
+			want:      map[string]any{"T": uint32(1672628645), "I": uint32(3)},
+		},
+		{
+			name:      "timestamp_date_time_millis",
+			converter: Converter{TimestampFormat: TimestampFormatDate, DateTimeFormat: DateTimeFormatMillis},
+			value:     primitive.Timestamp{T: 1672628645, I: 3},
+			want:      int64(1672628645000),
+		},
+		{
+			name:      "timestamp_date_time_rfc3339",
+			converter: Converter{TimestampFormat: TimestampFormatDate, DateTimeFormat: DateTimeFormatRFC3339},
+			value:     primitive.Timestamp{T: 1672628645, I: 3},
+			want:      "2023-01-02T03:04:05Z",
+		},
+		{
+			name:      "decimal_string",
+			converter: Converter{DecimalFormat: DecimalFormatString},
+			value:     decimal,
+			want:      "12.50",
+		},
+		{
+			name:      "decimal_float",
+			converter: Converter{DecimalFormat: DecimalFormatFloat},
+			value:     decimal,
+			want:      12.5,
+		},
+		{
+			name:  "binary",
+			value: primitive.Binary{Subtype: 0x00, Data: []byte("data")},
+			want:  []byte("data"),
+		},
+		{
+			name:      "uuid_string",
+			converter: Converter{UUIDFormat: UUIDFormatString},
+			value:     primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: testUUID},
+			want:      testUUIDString,
+		},
+		{
+			name:      "legacy_uuid_string",
+			converter: Converter{UUIDFormat: UUIDFormatString},
+			value:     primitive.Binary{Subtype: bson.TypeBinaryUUIDOld, Data: testUUID},
+			want:      testUUIDString,
+		},
+		{
+			name:      "uuid_bytes",
+			converter: Converter{UUIDFormat: UUIDFormatBytes},
+			value:     primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: testUUID},
+			want:      testUUID,
+		},
+		{
+			name:      "binary_base64",
+			converter: Converter{BinaryFormat: BinaryFormatBase64},
+			value:     primitive.Binary{Subtype: 0x80, Data: []byte("data")},
+			want:      "ZGF0YQ==",
+		},
+		{
+			name:  "object_id",
+			value: objectID,
+			want:  "63b2c2a1f1a2b3c4d5e6f7a8",
+		},
+		{
+			name:  "null",
+			value: primitive.Null{},
+			want:  nil,
+		},
+		{
+			name:  "db_pointer",
+			value: primitive.DBPointer{DB: "test.users", Pointer: objectID},
+			want:  map[string]any{"DB": "test.users", "Pointer": "63b2c2a1f1a2b3c4d5e6f7a8"},
+		},
+		{
+			name:  "code_with_scope",
+			value: primitive.CodeWithScope{Code: "x + 1", Scope: primitive.D{{Key: "x", Value: dateTime}}},
+			want:  map[string]any{"Code": "x + 1", "Scope": map[string]any{"x": "2023-01-02T03:04:05.006Z"}},
+		},
+		{
+			name:  "non_bson_value",
+			value: int32(42),
+			want:  int32(42),
+		},
+		{
+			name:      "nested_document_and_array",
+			converter: Converter{DateTimeFormat: DateTimeFormatMillis, DecimalFormat: DecimalFormatString},
+			value: map[string]any{
+				"createdAt": dateTime,
+				"nested": primitive.D{
+					{Key: "price", Value: decimal},
+				},
+				"tags": primitive.A{"a", dateTime},
+			},
+			want: map[string]any{
+				"createdAt": int64(1672628645006),
+				"nested": map[string]any{
+					"price": "12.50",
+				},
+				"tags": []any{"a", int64(1672628645006)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.converter.Convert(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Converter.Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertRaw(t *testing.T) {
+	t.Parallel()
+
+	decimal, err := primitive.ParseDecimal128("12.50")
+	if err != nil {
+		t.Fatalf("parse decimal: %v", err)
+	}
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "name", Value: "test"},
+		{Key: "count", Value: int32(42)},
+		{Key: "total", Value: int64(1 << 40)},
+		{Key: "ratio", Value: 0.5},
+		{Key: "active", Value: true},
+		{Key: "price", Value: decimal},
+		{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))},
+		{Key: "data", Value: primitive.Binary{Data: []byte("data")}},
+		{Key: "uuid", Value: primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: testUUID}},
+		{Key: "pattern", Value: primitive.Regex{Pattern: "^a", Options: "i"}},
+		{Key: "ts", Value: primitive.Timestamp{T: 1, I: 2}},
+		{Key: "pointer", Value: primitive.DBPointer{DB: "test.users", Pointer: primitive.NewObjectID()}},
+		{Key: "code", Value: primitive.CodeWithScope{Code: "x + 1", Scope: bson.D{{Key: "x", Value: 1}}}},
+		{Key: "empty", Value: primitive.Null{}},
+		{Key: "nested", Value: bson.D{{Key: "tags", Value: bson.A{"a", primitive.MaxKey{}, bson.D{{Key: "b", Value: 1}}}}}},
+	})
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+
+	converters := map[string]Converter{
+		"defaults":     {},
+		"millis_float": {DateTimeFormat: DateTimeFormatMillis, DecimalFormat: DecimalFormatFloat},
+		"uuid_string":  {UUIDFormat: UUIDFormatString},
+		"base64":       {BinaryFormat: BinaryFormatBase64},
+		"native_timestamp_date_time": {
+			DateTimeFormat: DateTimeFormatNative, TimestampFormat: TimestampFormatDate,
+		},
+	}
+
+	for name, converter := range converters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var decoded map[string]any
+			if err := bson.Unmarshal(document, &decoded); err != nil {
+				t.Fatalf("unmarshal document: %v", err)
+			}
+
+			got, err := converter.ConvertRaw(document)
+			if err != nil {
+				t.Fatalf("Converter.ConvertRaw() error = %v", err)
+			}
+
+			// the single-pass conversion must match the decode and convert round-trip
+			want := converter.ConvertDocument(decoded)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Converter.ConvertRaw() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertRaw_nil(t *testing.T) {
+	t.Parallel()
+
+	got, err := Converter{}.ConvertRaw(nil)
+	if err != nil {
+		t.Fatalf("Converter.ConvertRaw() error = %v", err)
+	}
+
+	if got != nil {
+		t.Errorf("Converter.ConvertRaw() = %v, want nil", got)
+	}
+}
+
+func TestConverter_ConvertRaw_proto(t *testing.T) {
+	t.Parallel()
+
+	decimal, err := primitive.ParseDecimal128("12.50")
+	if err != nil {
+		t.Fatalf("parse decimal: %v", err)
+	}
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: primitive.Timestamp{T: 1, I: 2}},
+		{Key: "price", Value: decimal},
+		{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))},
+		{Key: "uuid", Value: primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: testUUID}},
+		{Key: "ts", Value: primitive.Timestamp{T: 1, I: 2}},
+		{Key: "pointer", Value: primitive.DBPointer{DB: "test.users", Pointer: primitive.NewObjectID()}},
+		{Key: "code", Value: primitive.CodeWithScope{Code: "x + 1", Scope: bson.D{{Key: "x", Value: 1}}}},
+		{Key: "nested", Value: bson.D{{Key: "tags", Value: bson.A{"a", primitive.MaxKey{}, primitive.Undefined{}}}}},
+	})
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+
+	converters := map[string]Converter{
+		"defaults":       {},
+		"millis_float":   {DateTimeFormat: DateTimeFormatMillis, DecimalFormat: DecimalFormatFloat},
+		"uuid_string":    {UUIDFormat: UUIDFormatString},
+		"base64":         {BinaryFormat: BinaryFormatBase64},
+		"timestamp_date": {TimestampFormat: TimestampFormatDate},
+	}
+
+	for name, converter := range converters {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			converted, err := converter.ConvertRaw(document)
+			if err != nil {
+				t.Fatalf("Converter.ConvertRaw() error = %v", err)
+			}
+
+			// the converted values must be serializable by the records' proto encoding
+			record := opencdc.Record{
+				Key:     opencdc.StructuredData{"_id": converted["_id"]},
+				Payload: opencdc.Change{After: opencdc.StructuredData(converted)},
+			}
+
+			if err := record.ToProto(&opencdcv1.Record{}); err != nil {
+				t.Errorf("Record.ToProto() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestConverter_ConvertRaw_strict(t *testing.T) {
+	t.Parallel()
+
+	exact, err := primitive.ParseDecimal128("12.5")
+	if err != nil {
+		t.Fatalf("parse decimal: %v", err)
+	}
+
+	inexact, err := primitive.ParseDecimal128("0.1")
+	if err != nil {
+		t.Fatalf("parse decimal: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		converter Converter
+		document  bson.D
+		wantPath  string
+		wantType  bsontype.Type
+	}{
+		{
+			name:      "faithful",
+			converter: Converter{Strict: true, DecimalFormat: DecimalFormatFloat},
+			document: bson.D{
+				{Key: "_id", Value: primitive.NewObjectID()},
+				{Key: "price", Value: exact},
+				{Key: "data", Value: primitive.Binary{Data: []byte("data")}},
+				{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Now())},
+				{Key: "tags", Value: bson.A{"a", 1.5}},
+			},
+		},
+		{
+			name:      "regex",
+			converter: Converter{Strict: true},
+			document:  bson.D{{Key: "pattern", Value: primitive.Regex{Pattern: "^a"}}},
+			wantPath:  "pattern",
+			wantType:  bsontype.Regex,
+		},
+		{
+			name:      "nested_array_max_key",
+			converter: Converter{Strict: true},
+			document: bson.D{
+				{Key: "nested", Value: bson.D{{Key: "tags", Value: bson.A{"a", primitive.MaxKey{}}}}},
+			},
+			wantPath: "nested.tags.1",
+			wantType: bsontype.MaxKey,
+		},
+		{
+			name:      "nan",
+			converter: Converter{Strict: true},
+			document:  bson.D{{Key: "ratio", Value: math.NaN()}},
+			wantPath:  "ratio",
+			wantType:  bsontype.Double,
+		},
+		{
+			name:      "uuid",
+			converter: Converter{Strict: true},
+			document:  bson.D{{Key: "id", Value: primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: make([]byte, 16)}}},
+			wantPath:  "id",
+			wantType:  bsontype.Binary,
+		},
+		{
+			name:      "uuid_string",
+			converter: Converter{Strict: true, UUIDFormat: UUIDFormatString},
+			document:  bson.D{{Key: "id", Value: primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: testUUID}}},
+		},
+		{
+			name:      "inexact_float_decimal",
+			converter: Converter{Strict: true, DecimalFormat: DecimalFormatFloat},
+			document:  bson.D{{Key: "price", Value: inexact}},
+			wantPath:  "price",
+			wantType:  bsontype.Decimal128,
+		},
+		{
+			name:      "string_decimal",
+			converter: Converter{Strict: true, DecimalFormat: DecimalFormatString},
+			document:  bson.D{{Key: "price", Value: inexact}},
+		},
+		{
+			name:      "not_strict",
+			converter: Converter{},
+			document:  bson.D{{Key: "pattern", Value: primitive.Regex{Pattern: "^a"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			document, err := bson.Marshal(tt.document)
+			if err != nil {
+				t.Fatalf("bson.Marshal() error = %v", err)
+			}
+
+			_, err = tt.converter.ConvertRaw(document)
+			if tt.wantPath == "" {
+				if err != nil {
+					t.Fatalf("Converter.ConvertRaw() error = %v", err)
+				}
+
+				return
+			}
+
+			var lossyErr *LossyConversionError
+			if !errors.As(err, &lossyErr) {
+				t.Fatalf("Converter.ConvertRaw() error = %v, want LossyConversionError", err)
+			}
+
+			if lossyErr.Path != tt.wantPath || lossyErr.Type != tt.wantType {
+				t.Errorf("Converter.ConvertRaw() error = %v, want path %q and type %s", err, tt.wantPath, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestConverter_BinarySubtypes(t *testing.T) {
+	t.Parallel()
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "name", Value: "test"},
+		{Key: "photo", Value: primitive.Binary{Data: []byte("data")}},
+		{Key: "id", Value: primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: testUUID}},
+		{Key: "files", Value: bson.A{bson.D{{Key: "data", Value: primitive.Binary{Subtype: 0x80, Data: []byte("a")}}}}},
+	})
+	if err != nil {
+		t.Fatalf("marshal document: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		converter Converter
+		want      map[string]byte
+	}{
+		{
+			name:      "base64",
+			converter: Converter{BinaryFormat: BinaryFormatBase64},
+			want:      map[string]byte{"photo": 0x00, "id": bson.TypeBinaryUUID, "files.0.data": 0x80},
+		},
+		{
+			name:      "base64_uuid_string",
+			converter: Converter{BinaryFormat: BinaryFormatBase64, UUIDFormat: UUIDFormatString},
+			want:      map[string]byte{"photo": 0x00, "files.0.data": 0x80},
+		},
+		{
+			name:      "bytes",
+			converter: Converter{BinaryFormat: BinaryFormatBytes},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tt.converter.BinarySubtypes(document)
+			if err != nil {
+				t.Fatalf("Converter.BinarySubtypes() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Converter.BinarySubtypes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
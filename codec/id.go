@@ -0,0 +1,166 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IDType defines how values of the _id field are treated by the connectors.
+type IDType string
+
+// The list of available _id types is listed below.
+const (
+	// IDTypeAuto leaves _id values as they are, except for ObjectIDs,
+	// which are represented as hex strings and converted back by the string to ObjectID codec.
+	IDTypeAuto IDType = "auto"
+	// IDTypeObjectID treats _id values as ObjectIDs represented as hex strings.
+	IDTypeObjectID IDType = "objectid"
+	// IDTypeUUID treats _id values as UUIDs, i.e. binaries of the subtype 4, represented as canonical UUID strings.
+	IDTypeUUID IDType = "uuid"
+	// IDTypeInt treats _id values as 64-bit integers.
+	IDTypeInt IDType = "int"
+	// IDTypeString treats _id values as strings, even if they're valid hex representations of ObjectIDs.
+	IDTypeString IDType = "string"
+)
+
+const (
+	// uuidSize is the number of bytes of a UUID.
+	uuidSize = 16
+	// uuidStringSize is the length of the canonical textual representation of a UUID.
+	uuidStringSize = 36
+)
+
+// uuidDashes are the positions of dashes in the canonical textual representation of a UUID.
+var uuidDashes = []int{8, 13, 18, 23}
+
+// Convert converts a converted _id value into its representation in records' keys.
+// Values that don't match the type are returned as they are, so documents of mixed-type collections
+// still get keys.
+func (t IDType) Convert(value any) any {
+	switch {
+	case t == IDTypeUUID:
+		if data, ok := value.([]byte); ok && len(data) == uuidSize {
+			return FormatUUID(data)
+		}
+
+	case t == IDTypeInt:
+		if id, ok := toInt64(value); ok {
+			return id
+		}
+	}
+
+	return value
+}
+
+// Encode converts an _id value of a record key or payload into the BSON value documents are filtered
+// and written with. Values that don't match the type are returned as they are.
+func (t IDType) Encode(value any) any {
+	switch {
+	case t == IDTypeObjectID:
+		if str, ok := value.(string); ok {
+			if objectID, err := primitive.ObjectIDFromHex(str); err == nil {
+				return objectID
+			}
+		}
+
+	case t == IDTypeUUID:
+		if str, ok := value.(string); ok {
+			if data, err := ParseUUID(str); err == nil {
+				return primitive.Binary{Subtype: bsontype.BinaryUUID, Data: data}
+			}
+		}
+
+	case t == IDTypeInt:
+		if str, ok := value.(string); ok {
+			if id, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return id
+			}
+		}
+
+		if id, ok := toInt64(value); ok {
+			return id
+		}
+
+	case t == IDTypeString:
+		// numeric keys, e.g. raw keys like 42, are unmarshaled from JSON into floats
+		if number, ok := value.(float64); ok {
+			return strconv.FormatFloat(number, 'f', -1, 64)
+		}
+	}
+
+	return value
+}
+
+// FormatUUID formats the bytes of a UUID in its canonical textual representation.
+// Bytes of an unexpected length are formatted as hex.
+func FormatUUID(data []byte) string {
+	if len(data) != uuidSize {
+		return hex.EncodeToString(data)
+	}
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", data[0:4], data[4:6], data[6:8], data[8:10], data[10:16])
+}
+
+// ParseUUID parses the bytes of a UUID from its canonical textual representation.
+func ParseUUID(str string) ([]byte, error) {
+	if len(str) != uuidStringSize {
+		return nil, fmt.Errorf("invalid uuid %q", str)
+	}
+
+	for _, position := range uuidDashes {
+		if str[position] != '-' {
+			return nil, fmt.Errorf("invalid uuid %q", str)
+		}
+	}
+
+	data, err := hex.DecodeString(strings.ReplaceAll(str, "-", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decode uuid %q: %w", str, err)
+	}
+
+	return data, nil
+}
+
+// toInt64 converts an integer value, or a float without a fractional part, into int64.
+func toInt64(value any) (int64, bool) {
+	switch value := value.(type) {
+	case int32:
+		return int64(value), true
+
+	case int64:
+		return value, true
+
+	case int:
+		return int64(value), true
+
+	case float64:
+		if value != math.Trunc(value) || value < math.MinInt64 || value >= math.MaxInt64 {
+			return 0, false
+		}
+
+		return int64(value), true
+
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testUUID = []byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+
+const testUUIDString = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestIDType_Convert(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		idType IDType
+		value  any
+		want   any
+	}{
+		{name: "auto", idType: IDTypeAuto, value: testUUID, want: testUUID},
+		{name: "uuid", idType: IDTypeUUID, value: testUUID, want: testUUIDString},
+		{name: "uuid_mismatch", idType: IDTypeUUID, value: "bob", want: "bob"},
+		{name: "int_int32", idType: IDTypeInt, value: int32(42), want: int64(42)},
+		{name: "int_float", idType: IDTypeInt, value: 42.0, want: int64(42)},
+		{name: "int_fraction", idType: IDTypeInt, value: 4.2, want: 4.2},
+		{name: "string", idType: IDTypeString, value: "63bd5ee3ad5b1d4c6ad2b7e0", want: "63bd5ee3ad5b1d4c6ad2b7e0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(tt.idType.Convert(tt.value), tt.want)
+		})
+	}
+}
+
+func TestIDType_Encode(t *testing.T) {
+	t.Parallel()
+
+	objectID := primitive.NewObjectID()
+
+	tests := []struct {
+		name   string
+		idType IDType
+		value  any
+		want   any
+	}{
+		{name: "auto", idType: IDTypeAuto, value: objectID.Hex(), want: objectID.Hex()},
+		{name: "objectid", idType: IDTypeObjectID, value: objectID.Hex(), want: objectID},
+		{name: "objectid_mismatch", idType: IDTypeObjectID, value: "bob", want: "bob"},
+		{
+			name:   "uuid",
+			idType: IDTypeUUID,
+			value:  testUUIDString,
+			want:   primitive.Binary{Subtype: bsontype.BinaryUUID, Data: testUUID},
+		},
+		{name: "uuid_mismatch", idType: IDTypeUUID, value: "bob", want: "bob"},
+		{name: "int_float", idType: IDTypeInt, value: 42.0, want: int64(42)},
+		{name: "int_string", idType: IDTypeInt, value: "42", want: int64(42)},
+		{name: "string_hex", idType: IDTypeString, value: objectID.Hex(), want: objectID.Hex()},
+		{name: "string_number", idType: IDTypeString, value: 42.0, want: "42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(tt.idType.Encode(tt.value), tt.want)
+		})
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	data, err := ParseUUID(testUUIDString)
+	is.NoErr(err)
+	is.Equal(data, testUUID)
+	is.Equal(FormatUUID(data), testUUIDString)
+
+	_, err = ParseUUID("123e4567e89b12d3a456426614174000")
+	is.True(err != nil)
+
+	_, err = ParseUUID("123e4567-e89b-12d3-a456-42661417400z")
+	is.True(err != nil)
+}
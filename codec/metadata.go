@@ -0,0 +1,51 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+// The list of record metadata fields shared by the source and destination connectors and their metrics
+// is listed below.
+const (
+	// MetadataFieldCaptureMode is a name of a record metadata field that stores the mode
+	// the record was captured in, one of the capture modes.
+	MetadataFieldCaptureMode = "mongo.captureMode"
+	// MetadataFieldRetryAttempts is a name of a record metadata field that stores the number of retry attempts
+	// made before the record was read or written, so downstream systems can correlate anomalies with retry storms.
+	MetadataFieldRetryAttempts = "mongo.retry.attempts"
+	// MetadataFieldClusterTime is a name of a record metadata field that holds the cluster time of the change
+	// as "<seconds>.<increment>", which orders changes globally, even across shards.
+	MetadataFieldClusterTime = "mongo.clusterTime"
+	// MetadataFieldRecordType is a name of a record metadata field
+	// that distinguishes records that don't carry collection documents.
+	MetadataFieldRecordType = "mongo.recordType"
+)
+
+// The list of capture modes stamped on records is listed below.
+const (
+	CaptureModeSnapshot = "snapshot"
+	CaptureModeCDC      = "cdc"
+	CaptureModePolling  = "polling"
+)
+
+// The list of values of the record type metadata field shared by the source and destination connectors
+// is listed below.
+const (
+	// RecordTypeCollectionMetadata is a record type of a record that describes a collection structure.
+	RecordTypeCollectionMetadata = "collectionMetadata"
+	// RecordTypeHeartbeat is a record type of a record that only advances the CDC position.
+	RecordTypeHeartbeat = "heartbeat"
+	// RecordTypeCollectionEvent is a record type of a record that describes a collection lifecycle event,
+	// e.g. a drop or a rename of the collection.
+	RecordTypeCollectionEvent = "collectionEvent"
+)
@@ -0,0 +1,98 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+)
+
+// errTrailingJSON occurs when JSON data has something after its top-level value.
+var errTrailingJSON = errors.New("invalid character after top-level value")
+
+// IntegerDecoding defines which Go type integral JSON numbers are decoded into.
+type IntegerDecoding string
+
+// The list of available integer decodings is listed below.
+const (
+	// IntegerDecodingInt64 decodes integral JSON numbers into 64-bit integers,
+	// so integers larger than 2^53 keep their precision. Other numbers are decoded into float64.
+	IntegerDecodingInt64 IntegerDecoding = "int64"
+	// IntegerDecodingDouble decodes all JSON numbers into float64, the same as [json.Unmarshal] does.
+	IntegerDecodingDouble IntegerDecoding = "double"
+)
+
+// UnmarshalJSON unmarshals JSON data into the value the same as [json.Unmarshal] does,
+// but keeps numbers decoded into interface values as [json.Number], see [IntegerDecoding.Decode].
+func UnmarshalJSON(data []byte, value any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(value); err != nil {
+		return err //nolint:wrapcheck // the function mimics json.Unmarshal
+	}
+
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return errTrailingJSON
+	}
+
+	return nil
+}
+
+// Decode replaces the [json.Number] values left by [UnmarshalJSON] with numbers of the decoding's types.
+// Maps and slices are decoded in place.
+func (d IntegerDecoding) Decode(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		return d.decodeNumber(value)
+
+	case map[string]any:
+		for k, v := range value {
+			value[k] = d.Decode(v)
+		}
+
+		return value
+
+	case []any:
+		for i, v := range value {
+			value[i] = d.Decode(v)
+		}
+
+		return value
+
+	default:
+		return value
+	}
+}
+
+// decodeNumber converts a JSON number into an int64 if it's integral and the decoding is int64,
+// or into a float64 otherwise. Numbers out of the float64 range are kept as their strings.
+func (d IntegerDecoding) decodeNumber(number json.Number) any {
+	if d != IntegerDecodingDouble {
+		if integer, err := strconv.ParseInt(number.String(), 10, 64); err == nil {
+			return integer
+		}
+	}
+
+	float, err := strconv.ParseFloat(number.String(), 64)
+	if err != nil {
+		return number.String()
+	}
+
+	return float
+}
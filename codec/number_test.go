@@ -0,0 +1,95 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package codec
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIntegerDecoding_Decode(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"id":9223372036854775807,"big":18446744073709551616,"price":1.5,"tags":[{"n":7}]}`)
+
+	tests := []struct {
+		name     string
+		integers IntegerDecoding
+		want     map[string]any
+	}{
+		{
+			name:     "int64",
+			integers: IntegerDecodingInt64,
+			want: map[string]any{
+				"id": int64(math.MaxInt64), "big": float64(1 << 64), "price": 1.5,
+				"tags": []any{map[string]any{"n": int64(7)}},
+			},
+		},
+		{
+			name:     "double",
+			integers: IntegerDecodingDouble,
+			want: map[string]any{
+				"id": float64(math.MaxInt64), "big": float64(1 << 64), "price": 1.5,
+				"tags": []any{map[string]any{"n": float64(7)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			var got map[string]any
+			is.NoErr(UnmarshalJSON(data, &got))
+			is.Equal(tt.integers.Decode(got), tt.want)
+		})
+	}
+}
+
+func TestUnmarshalJSON_trailingData(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	var got any
+	is.True(UnmarshalJSON([]byte(`{"a":1} {"b":2}`), &got) != nil)
+	is.True(UnmarshalJSON([]byte(`{"a":1`), &got) != nil)
+}
+
+func TestConverter_ConvertRaw_int64RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// snapshots encode int64 values into JSON payloads, the destination decodes them back without losing precision
+	document, err := bson.Marshal(bson.D{{Key: "_id", Value: int64(9007199254740993)}})
+	is.NoErr(err)
+
+	converted, err := Converter{}.ConvertRaw(document)
+	is.NoErr(err)
+
+	payload, err := (*BufferPool)(nil).EncodeJSON(converted)
+	is.NoErr(err)
+	is.Equal(string(payload), `{"_id":9007199254740993}`)
+
+	var got map[string]any
+	is.NoErr(UnmarshalJSON(payload, &got))
+	is.Equal(IntegerDecodingInt64.Decode(got), map[string]any{"_id": int64(9007199254740993)})
+}
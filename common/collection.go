@@ -17,51 +17,84 @@ package common
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+const (
+	// namespaceExistsCode is a code of the error MongoDB returns when a collection already exists.
+	namespaceExistsCode = 48
+	// unauthorizedCode is a code of the error MongoDB returns when the user lacks the privileges of a command.
+	unauthorizedCode = 13
+)
+
+// NotExistError occurs when a database or a collection doesn't exist.
+type NotExistError struct {
+	// Kind is either "database" or "collection".
+	Kind string
+	Name string
+}
+
+// Error returns a formatted error message for the [NotExistError].
+func (e *NotExistError) Error() string {
+	return fmt.Sprintf("%s %q doesn't exist", e.Kind, e.Name)
+}
+
+// NotVisibleError occurs when a database can't be listed by the user the connector is authenticated as,
+// so it's unknown whether it exists.
+type NotVisibleError struct {
+	// Kind is a kind of the namespace, e.g. "database".
+	Kind string
+	Name string
+	// Err is the error the server returned.
+	Err error
+}
+
+// Error returns a formatted error message for the [NotVisibleError].
+func (e *NotVisibleError) Error() string {
+	return fmt.Sprintf("%s %q is not visible for this user, make sure the user has read privileges on it: %v",
+		e.Kind, e.Name, e.Err)
+}
+
+// Unwrap returns the error the server returned.
+func (e *NotVisibleError) Unwrap() error {
+	return e.Err
+}
+
+// GetMongoCollectionWithRetry is the same as [GetMongoCollection], but it retries if the database
+// or the collection doesn't exist. See [Namespaces.CollectionWithRetry] for details.
+func GetMongoCollectionWithRetry(
+	ctx context.Context, client *mongo.Client, db, collection string, maxRetries int, backoff time.Duration,
+) (*mongo.Collection, error) {
+	return NewNamespaces(client).CollectionWithRetry(ctx, db, collection, maxRetries, backoff)
+}
+
 // GetMongoCollection checks if the provided database and collection
 // exist in a Mongo instance the client is connected to, and returns the [mongo.Collection] if they exist.
 // By default, the Go Mongo driver creates a database and collection if they don't exist,
 // so this function may come in handy when it comes to validations.
+// Use [Namespaces] to check several collections without listing the namespaces every time.
 func GetMongoCollection(ctx context.Context, client *mongo.Client, db, collection string) (*mongo.Collection, error) {
-	databaseNames, err := client.ListDatabaseNames(ctx, bson.M{})
-	if err != nil {
-		return nil, fmt.Errorf("list database names: %w", err)
-	}
-
-	databaseExist := false
-	for _, databaseName := range databaseNames {
-		if databaseName == db {
-			databaseExist = true
-
-			break
-		}
-	}
-
-	if !databaseExist {
-		return nil, fmt.Errorf("database %q doesn't exist", db)
-	}
-
-	collectionNames, err := client.Database(db).ListCollectionNames(ctx, bson.M{})
-	if err != nil {
-		return nil, fmt.Errorf("list collection names: %w", err)
-	}
+	return NewNamespaces(client).Collection(ctx, db, collection)
+}
 
-	collectionExist := false
-	for _, collectionName := range collectionNames {
-		if collectionName == collection {
-			collectionExist = true
+// CreateMongoCollection creates the provided collection, along with its database if it doesn't exist,
+// and returns the [mongo.Collection]. The options are the fields of the create command, like capped or validator.
+// It doesn't fail if the collection is created concurrently by someone else.
+func CreateMongoCollection(
+	ctx context.Context, client *mongo.Client, db, collection string, options bson.D,
+) (*mongo.Collection, error) {
+	command := append(bson.D{{Key: "create", Value: collection}}, options...)
 
-			break
-		}
-	}
+	err := client.Database(db).RunCommand(ctx, command).Err()
 
-	if !collectionExist {
-		return nil, fmt.Errorf("collection %q doesn't exist", collection)
+	var commandErr mongo.CommandError
+	if err != nil && (!errors.As(err, &commandErr) || commandErr.Code != namespaceExistsCode) {
+		return nil, fmt.Errorf("run create command: %w", err)
 	}
 
 	return client.Database(db).Collection(collection), nil
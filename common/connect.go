@@ -0,0 +1,185 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/auth"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// serverlessServiceIDField is a name of a hello command response field
+// that is present only if the server is behind a load balancer, which is how Atlas Serverless is deployed.
+const serverlessServiceIDField = "serviceId"
+
+// authenticationFailedCode is a code of the error MongoDB returns when it rejects the credentials.
+const authenticationFailedCode = 18
+
+// The list of reasons of connect errors is listed below.
+const (
+	// ConnectReasonAuth means the server rejected the credentials of the connection string or the auth options.
+	ConnectReasonAuth = "auth failed"
+	// ConnectReasonUnreachable means no server of the connection string could be reached in time.
+	ConnectReasonUnreachable = "server unreachable"
+)
+
+// ConnectError occurs when the connector can't work with the MongoDB instance it connects to.
+// Its reason is a short user-readable description of the problem, and its hint tells users what to check.
+type ConnectError struct {
+	Reason string
+	Hint   string
+	// Err is the error the driver returned.
+	Err error
+}
+
+// Error returns a formatted error message for the [ConnectError].
+func (e *ConnectError) Error() string {
+	return fmt.Sprintf("%s, %s: %v", e.Reason, e.Hint, e.Err)
+}
+
+// Unwrap returns the error the driver returned.
+func (e *ConnectError) Unwrap() error {
+	return e.Err
+}
+
+// ferretDBBuildInfoFields are names of buildInfo command response fields that are present only
+// if the server is FerretDB. FerretDB v1 reports its version in the former, and v2 in the latter.
+var ferretDBBuildInfoFields = []string{"ferretdbVersion", "ferretdb"}
+
+// Connect connects to a MongoDB instance described by the provided config and pings it.
+// If the Atlas Serverless mode is auto and the instance turns out to be Atlas Serverless,
+// the client is reconnected in the serverless compatibility mode.
+func Connect(ctx context.Context, cfg config.Config, registry *bsoncodec.Registry) (*mongo.Client, error) {
+	client, err := connect(ctx, cfg, registry)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Serverless != config.ServerlessAuto {
+		return client, nil
+	}
+
+	serverless, err := IsServerless(ctx, client)
+	if err != nil {
+		_ = client.Disconnect(ctx)
+
+		return nil, fmt.Errorf("detect atlas serverless: %w", err)
+	}
+
+	if !serverless {
+		return client, nil
+	}
+
+	sdk.Logger(ctx).Info().Msg("atlas serverless instance detected, enabling the compatibility mode")
+
+	if err = client.Disconnect(ctx); err != nil {
+		return nil, fmt.Errorf("disconnect from mongo: %w", err)
+	}
+
+	cfg.Serverless = config.ServerlessEnabled
+
+	return connect(ctx, cfg, registry)
+}
+
+// IsServerless checks whether the client is connected to an Atlas Serverless instance.
+// Such instances are only reachable through a load balancer, so the hello command response contains a service id.
+func IsServerless(ctx context.Context, client *mongo.Client) (bool, error) {
+	result, err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Raw()
+	if err != nil {
+		return false, fmt.Errorf("run hello command: %w", err)
+	}
+
+	_, err = result.LookupErr(serverlessServiceIDField)
+
+	return err == nil, nil
+}
+
+// IsFerretDB checks whether the client is connected to FerretDB, a wire-compatible proxy
+// that doesn't support Change Streams.
+func IsFerretDB(ctx context.Context, client *mongo.Client) (bool, error) {
+	result, err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Raw()
+	if err != nil {
+		return false, fmt.Errorf("run buildInfo command: %w", err)
+	}
+
+	for _, field := range ferretDBBuildInfoFields {
+		if _, err := result.LookupErr(field); err == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// connect creates a new client and makes sure the server is reachable.
+func connect(ctx context.Context, cfg config.Config, registry *bsoncodec.Registry) (*mongo.Client, error) {
+	client, err := mongo.Connect(ctx, cfg.GetClientOptions().SetRegistry(registry))
+	if err != nil {
+		// the DNS seedlist is resolved while connecting, errors of its lookups are cryptic
+		var dnsErr *net.DNSError
+		if cfg.URI.Scheme == config.SchemeMongoDBSRV && errors.As(err, &dnsErr) {
+			return nil, fmt.Errorf("resolve the DNS seedlist of %q, make sure the host name is correct "+
+				"and the DNS server resolves its SRV and TXT records: %w", cfg.URI.Hostname(), err)
+		}
+
+		return nil, fmt.Errorf("connect to mongo: %w", err)
+	}
+
+	if err = client.Ping(ctx, nil); err != nil {
+		_ = client.Disconnect(ctx)
+
+		return nil, pingError(err)
+	}
+
+	return client, nil
+}
+
+// pingError describes the error of the ping command of a new client the way users can act on,
+// telling failed authentications and unreachable servers apart from other errors.
+func pingError(err error) error {
+	var (
+		authErr            *auth.Error
+		serverErr          mongo.ServerError
+		serverSelectionErr topology.ServerSelectionError
+	)
+
+	switch {
+	case errors.As(err, &authErr) || (errors.As(err, &serverErr) && serverErr.HasErrorCode(authenticationFailedCode)):
+		return &ConnectError{
+			Reason: ConnectReasonAuth,
+			Hint:   "make sure the username, the password, the auth mechanism and the auth database are correct",
+			Err:    err,
+		}
+
+	case errors.As(err, &serverSelectionErr):
+		return &ConnectError{
+			Reason: ConnectReasonUnreachable,
+			Hint:   "make sure the hosts are correct and accept connections from the connector",
+			Err:    err,
+		}
+
+	default:
+		return fmt.Errorf("ping mongo server: %w", err)
+	}
+}
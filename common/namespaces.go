@@ -0,0 +1,204 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Namespaces is a registry of databases and collections that exist in a Mongo instance.
+// It caches the results of existence checks, so that checking many collections costs
+// a single listDatabases call and a single listCollections call per database,
+// until the registry is refreshed. It's safe for concurrent use.
+type Namespaces struct {
+	client *mongo.Client
+
+	mu sync.Mutex
+	// databases is nil until the database names are listed.
+	databases map[string]struct{}
+	// collections holds the collection names of every listed database.
+	collections map[string]map[string]struct{}
+}
+
+// NewNamespaces creates a new instance of the [Namespaces] registry.
+func NewNamespaces(client *mongo.Client) *Namespaces {
+	return &Namespaces{
+		client:      client,
+		collections: make(map[string]map[string]struct{}),
+	}
+}
+
+// Refresh drops everything the registry has cached,
+// so the next checks list the databases and collections again.
+func (n *Namespaces) Refresh() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.databases = nil
+	n.collections = make(map[string]map[string]struct{})
+}
+
+// Collection checks if the provided database and collection exist,
+// and returns the [mongo.Collection] if they do. Otherwise, it returns a [NotExistError].
+func (n *Namespaces) Collection(ctx context.Context, db, collection string) (*mongo.Collection, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	collections, err := n.collectionsOf(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := collections[collection]; !ok {
+		return nil, &NotExistError{Kind: "collection", Name: collection}
+	}
+
+	return n.client.Database(db).Collection(collection), nil
+}
+
+// CollectionNames returns the sorted names of all collections of the provided database,
+// or a [NotExistError] if the database doesn't exist.
+func (n *Namespaces) CollectionNames(ctx context.Context, db string) ([]string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	collections, err := n.collectionsOf(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(collections))
+	for name := range collections {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names, nil
+}
+
+// CollectionWithRetry is the same as [Namespaces.Collection], but if the database or the collection
+// doesn't exist, it refreshes the registry and retries up to maxRetries times,
+// doubling the backoff between attempts.
+// This way, connectors created alongside the databases they work with, e.g. by provisioning automation,
+// don't fail if they open moments before the databases are created.
+func (n *Namespaces) CollectionWithRetry(
+	ctx context.Context, db, collection string, maxRetries int, backoff time.Duration,
+) (*mongo.Collection, error) {
+	for attempt := 0; ; attempt++ {
+		mongoCollection, err := n.Collection(ctx, db, collection)
+
+		var notExistErr *NotExistError
+		if err == nil || attempt >= maxRetries || !errors.As(err, &notExistErr) {
+			return mongoCollection, err
+		}
+
+		delay := backoff << attempt
+		sdk.Logger(ctx).Warn().Err(err).
+			Int("attempt", attempt+1).
+			Dur("delay", delay).
+			Msg("retrying to get mongo collection")
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for get mongo collection retry: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+
+		n.Refresh()
+	}
+}
+
+// collectionsOf returns the cached collection names of the provided database, listing them if needed.
+// The caller must hold the mutex.
+func (n *Namespaces) collectionsOf(ctx context.Context, db string) (map[string]struct{}, error) {
+	if collections, ok := n.collections[db]; ok {
+		return collections, nil
+	}
+
+	if n.databases == nil {
+		// users without the listDatabases privilege get the databases they have privileges on
+		databaseNames, err := n.client.ListDatabaseNames(ctx, bson.M{},
+			options.ListDatabases().SetAuthorizedDatabases(true))
+
+		switch {
+		case isUnauthorizedErr(err):
+			// servers that don't support listing authorized databases are asked for collections right away
+			n.databases = make(map[string]struct{})
+
+		case err != nil:
+			return nil, fmt.Errorf("list database names: %w", err)
+
+		default:
+			n.databases = toSet(databaseNames)
+		}
+	}
+
+	collections, err := n.listCollections(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	// a database that's not listed exists only if the user can see some of its collections
+	if _, ok := n.databases[db]; !ok && len(collections) == 0 {
+		return nil, &NotExistError{Kind: "database", Name: db}
+	}
+
+	n.collections[db] = collections
+
+	return collections, nil
+}
+
+// listCollections lists the names of the database's collections the user has privileges on.
+func (n *Namespaces) listCollections(ctx context.Context, db string) (map[string]struct{}, error) {
+	collectionNames, err := n.client.Database(db).ListCollectionNames(ctx, bson.M{},
+		options.ListCollections().SetAuthorizedCollections(true))
+	if err != nil {
+		if isUnauthorizedErr(err) {
+			return nil, &NotVisibleError{Kind: "database", Name: db, Err: err}
+		}
+
+		return nil, fmt.Errorf("list collection names: %w", err)
+	}
+
+	return toSet(collectionNames), nil
+}
+
+// isUnauthorizedErr checks whether the server rejected a command because the user lacks its privileges.
+func isUnauthorizedErr(err error) bool {
+	var serverErr mongo.ServerError
+
+	return errors.As(err, &serverErr) && serverErr.HasErrorCode(unauthorizedCode)
+}
+
+// toSet converts the provided names into a set.
+func toSet(names []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+
+	return set
+}
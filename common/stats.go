@@ -0,0 +1,121 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CollectionStats contains statistics of a collection, helping to make sure
+// the connector works with the intended collection and to predict how long a snapshot takes.
+type CollectionStats struct {
+	// EstimatedCount is the estimated number of documents in the collection.
+	EstimatedCount int64
+	// AvgDocumentSize is the average size of documents in bytes.
+	AvgDocumentSize int64
+	// TotalSize is the total uncompressed size of documents in bytes.
+	TotalSize int64
+	// Indexes are the names of indexes of the collection.
+	Indexes []string
+}
+
+// storageStats defines the storage statistics returned by the $collStats aggregation stage.
+// The numbers are decoded as floats, as their BSON types vary between server versions.
+type storageStats struct {
+	Count float64 `bson:"count"`
+	Size  float64 `bson:"size"`
+}
+
+// GetCollectionStats returns statistics of the provided collection. The storage statistics
+// are summed across shards. Servers not supporting the $collStats stage, like Atlas Serverless,
+// leave the sizes at zero instead of failing.
+func GetCollectionStats(ctx context.Context, collection *mongo.Collection) (CollectionStats, error) {
+	estimatedCount, err := collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return CollectionStats{}, fmt.Errorf("estimate document count: %w", err)
+	}
+
+	indexes, err := collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return CollectionStats{}, fmt.Errorf("list index specifications: %w", err)
+	}
+
+	stats := CollectionStats{
+		EstimatedCount: estimatedCount,
+		Indexes:        make([]string, 0, len(indexes)),
+	}
+
+	for _, index := range indexes {
+		stats.Indexes = append(stats.Indexes, index.Name)
+	}
+
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$collStats", Value: bson.M{"storageStats": bson.M{}}}},
+	})
+	if err != nil {
+		sdk.Logger(ctx).Debug().Err(err).Msg("collection storage statistics are not available")
+
+		return stats, nil
+	}
+	defer cursor.Close(ctx)
+
+	var count int64
+	for cursor.Next(ctx) {
+		var shard struct {
+			StorageStats storageStats `bson:"storageStats"`
+		}
+		if err := cursor.Decode(&shard); err != nil {
+			return CollectionStats{}, fmt.Errorf("decode storage statistics: %w", err)
+		}
+
+		stats.TotalSize += int64(shard.StorageStats.Size)
+		count += int64(shard.StorageStats.Count)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return CollectionStats{}, fmt.Errorf("read storage statistics: %w", err)
+	}
+
+	if count > 0 {
+		stats.AvgDocumentSize = stats.TotalSize / count
+	}
+
+	return stats, nil
+}
+
+// LogCollectionStats logs statistics of the provided collection. The statistics are informational,
+// so an error of getting them is logged instead of being returned.
+func LogCollectionStats(ctx context.Context, collection *mongo.Collection) {
+	stats, err := GetCollectionStats(ctx, collection)
+	if err != nil {
+		sdk.Logger(ctx).Warn().Err(err).Str("collection", collection.Name()).Msg("failed to get collection statistics")
+
+		return
+	}
+
+	sdk.Logger(ctx).Info().
+		Str("db", collection.Database().Name()).
+		Str("collection", collection.Name()).
+		Int64("estimatedCount", stats.EstimatedCount).
+		Int64("avgDocumentSize", stats.AvgDocumentSize).
+		Int64("totalSize", stats.TotalSize).
+		Strs("indexes", stats.Indexes).
+		Msg("collection statistics")
+}
@@ -17,13 +17,17 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/validator"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 )
 
 // defaultConnectionURI is a default MongoDB connection URI string.
@@ -48,11 +52,61 @@ const (
 	KeyAuthTLSCAFile = "auth.tls.caFile"
 	// KeyAuthTLSCertificateKeyFile is a config name for a TLS certificate key file.
 	KeyAuthTLSCertificateKeyFile = "auth.tls.certificateKeyFile"
+	// KeyAuthTLSInsecureSkipVerify is a config name for a TLS insecure skip verify flag.
+	KeyAuthTLSInsecureSkipVerify = "auth.tls.insecureSkipVerify"
+	// KeyAuthTLSServerName is a config name for a TLS server name override.
+	KeyAuthTLSServerName = "auth.tls.serverName"
+	// KeyAuthTLSMinVersion is a config name for a TLS min version.
+	KeyAuthTLSMinVersion = "auth.tls.minVersion"
 	// KeyAuthAWSSessionToken is a config name for an AWS session token.
 	KeyAuthAWSSessionToken = "auth.awsSessionToken" //nolint:gosec // it's not hardcoded credential
+	// KeyAtlasServerless is a config name for an Atlas Serverless compatibility mode.
+	KeyAtlasServerless = "atlas.serverless"
+	// KeyBufferPoolEnabled is a config name for a bufferPool.enabled field.
+	KeyBufferPoolEnabled = "bufferPool.enabled"
+	// KeyTelemetryEnabled is a config name for a telemetry.enabled field.
+	KeyTelemetryEnabled = "telemetry.enabled"
+	// KeyCommandMonitorEnabled is a config name for a commandMonitor.enabled field.
+	KeyCommandMonitorEnabled = "commandMonitor.enabled"
+	// KeyCommandMonitorSlowThreshold is a config name for a commandMonitor.slowThreshold field.
+	KeyCommandMonitorSlowThreshold = "commandMonitor.slowThreshold"
+	// KeyObjectIDCodecEnabled is a config name for an objectIDCodec.enabled field.
+	KeyObjectIDCodecEnabled = "objectIDCodec.enabled"
+	// KeyIDType is a config name for an id.type field.
+	KeyIDType = "id.type"
+	// KeyOpenMaxRetries is a config name for an open.maxRetries field.
+	KeyOpenMaxRetries = "open.maxRetries"
+	// KeyOpenRetryBackoff is a config name for an open.retryBackoff field.
+	KeyOpenRetryBackoff = "open.retryBackoff"
+	// KeySRVMaxHosts is a config name for a srv.maxHosts field.
+	KeySRVMaxHosts = "srv.maxHosts"
+	// KeySRVServiceName is a config name for a srv.serviceName field.
+	KeySRVServiceName = "srv.serviceName"
 
 	// defaultServerSelectionTimeout is a default value for the ServerSelectionTimeout option.
 	defaultServerSelectionTimeout = time.Second * 5
+	// serverlessServerSelectionTimeout is a value for the ServerSelectionTimeout option
+	// used for Atlas Serverless instances, which may take longer to respond while scaling.
+	serverlessServerSelectionTimeout = time.Second * 30
+	// defaultAtlasServerless is a default value for the atlas.serverless field.
+	defaultAtlasServerless = ServerlessAuto
+	// defaultBufferPoolEnabled is a default value for the bufferPool.enabled field.
+	defaultBufferPoolEnabled = true
+	// defaultTelemetryEnabled is a default value for the telemetry.enabled field.
+	defaultTelemetryEnabled = true
+	// defaultCommandMonitorSlowThreshold is a default value for the commandMonitor.slowThreshold field,
+	// which matches the default slow operation threshold of the server profiler.
+	defaultCommandMonitorSlowThreshold = 100 * time.Millisecond
+	// defaultObjectIDCodecEnabled is a default value for the objectIDCodec.enabled field.
+	defaultObjectIDCodecEnabled = true
+	// defaultIDType is a default value for the id.type field.
+	defaultIDType = codec.IDTypeAuto
+	// defaultOpenMaxRetries is a default value for the open.maxRetries field.
+	defaultOpenMaxRetries = 3
+	// defaultOpenRetryBackoff is a default value for the open.retryBackoff field.
+	defaultOpenRetryBackoff = time.Second
+	// logLevelOff is a driver log level that suppresses logging of a component.
+	logLevelOff options.LogLevel = 0
 
 	// awsSessionTokenPropertyName is a name of a AWS session token property
 	// for the auth mechanism properties.
@@ -61,8 +115,25 @@ const (
 	tlsCAFileQueryName = "tlsCAFile"
 	// tlsCertificateKeyFileQueryName is a URL query name for a TLS certificate key file.
 	tlsCertificateKeyFileQueryName = "tlsCertificateKeyFile"
+	// srvMaxHostsQueryName is a URL query name for the max number of hosts selected from the DNS seedlist.
+	srvMaxHostsQueryName = "srvMaxHosts"
+	// srvServiceNameQueryName is a URL query name for a service name of SRV records.
+	srvServiceNameQueryName = "srvServiceName"
+
+	// SchemeMongoDB is a scheme of standard connection strings.
+	SchemeMongoDB = "mongodb"
+	// SchemeMongoDBSRV is a scheme of connection strings with a DNS seedlist, e.g. the ones of Atlas clusters.
+	SchemeMongoDBSRV = "mongodb+srv"
 )
 
+// tlsVersions maps the available values of the auth.tls.minVersion field to TLS versions.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
 // AuthMechanism defines a MongoDB authentication mechanism.
 type AuthMechanism string
 
@@ -85,6 +156,19 @@ func (am AuthMechanism) IsValid() bool {
 	return false
 }
 
+// ServerlessMode defines whether the connector works in the Atlas Serverless compatibility mode.
+type ServerlessMode string
+
+// The list of available serverless modes is listed below.
+const (
+	// ServerlessAuto enables the compatibility mode if the connector detects an Atlas Serverless instance.
+	ServerlessAuto ServerlessMode = "auto"
+	// ServerlessEnabled forces the compatibility mode.
+	ServerlessEnabled ServerlessMode = "enabled"
+	// ServerlessDisabled disables the compatibility mode.
+	ServerlessDisabled ServerlessMode = "disabled"
+)
+
 // Config contains configurable values shared between
 // source and destination MongoDB connector.
 type Config struct {
@@ -96,6 +180,43 @@ type Config struct {
 	// Collection is the name of a collection the connector must
 	// write to (destination) or read from (source).
 	Collection string `key:"collection" validate:"required"`
+	// Serverless defines whether the connector works in the Atlas Serverless compatibility mode.
+	Serverless ServerlessMode `key:"atlas.serverless" validate:"oneof=auto enabled disabled"`
+	// BufferPoolEnabled determines whether records are serialized into pooled buffers.
+	// Disabling pooling may be useful for debugging memory issues.
+	BufferPoolEnabled bool `key:"bufferPool.enabled"`
+	// TelemetryEnabled determines whether the client logs and monitors its operations,
+	// e.g. driver logs enabled via the MONGODB_LOG_* environment variables and collection statistics.
+	// Disabling it results in the leanest possible client.
+	TelemetryEnabled bool `key:"telemetry.enabled"`
+	// CommandMonitorEnabled determines whether the client logs failed commands
+	// and the ones taking at least the CommandMonitorSlowThreshold, along with their durations.
+	CommandMonitorEnabled bool `key:"commandMonitor.enabled"`
+	// CommandMonitorSlowThreshold is the min duration of commands the command monitor logs as slow ones.
+	CommandMonitorSlowThreshold time.Duration `key:"commandMonitor.slowThreshold" validate:"gte=0"`
+	// ObjectIDCodecEnabled determines whether string values that are valid hex representations
+	// of ObjectIDs are encoded into ObjectIDs. Disabling it keeps such strings as plain strings.
+	ObjectIDCodecEnabled bool `key:"objectIDCodec.enabled"`
+	// IDType defines how values of the _id field are represented in records' keys,
+	// and converted back when documents are filtered and written.
+	IDType codec.IDType `key:"id.type" validate:"oneof=auto objectid uuid int string"`
+	// OpenMaxRetries is the max number of times the connector checks again whether its database
+	// and collection exist on open, if they don't. Zero means the connector fails right away.
+	OpenMaxRetries int `key:"open.maxRetries" validate:"gte=0"`
+	// OpenRetryBackoff is a delay before the first open retry, every next retry waits twice as long.
+	OpenRetryBackoff time.Duration `key:"open.retryBackoff" validate:"gte=0"`
+	// SRVMaxHosts is the max number of hosts randomly selected from the DNS seedlist of a mongodb+srv URI.
+	// Zero means no limit.
+	SRVMaxHosts int `key:"srv.maxHosts" validate:"gte=0"`
+	// SRVServiceName is a service name of the SRV records of a mongodb+srv URI.
+	// If it's empty, the default mongodb service name is used.
+	SRVServiceName string `key:"srv.serviceName"`
+	// AutoEncryption contains options of the client-side field level encryption.
+	// It's not parsed from the raw config, connectors set it from their own options.
+	AutoEncryption *options.AutoEncryptionOptions
+	// ReadPreference is the read preference of the client.
+	// It's not parsed from the raw config, connectors set it from their own options.
+	ReadPreference *readpref.ReadPref
 
 	Auth AuthConfig
 }
@@ -117,6 +238,13 @@ type AuthConfig struct {
 	// TLSCertificateKeyFile is the path to the client certificate
 	// file or the client private key file.
 	TLSCertificateKeyFile string `key:"auth.tls.certificateKeyFile" validate:"omitempty,file"`
+	// TLSInsecureSkipVerify disables the verification of the server's certificate chain and host name.
+	// It should only be used for development, e.g. with self-signed certificates.
+	TLSInsecureSkipVerify bool `key:"auth.tls.insecureSkipVerify"`
+	// TLSServerName overrides the host name used to verify the server's certificate.
+	TLSServerName string `key:"auth.tls.serverName"`
+	// TLSMinVersion is the minimum TLS version the connector accepts.
+	TLSMinVersion string `key:"auth.tls.minVersion" validate:"omitempty,oneof=1.0 1.1 1.2 1.3"`
 	// AWSSessionToken is an AWS session token.
 	AWSSessionToken string `key:"auth.awsSessionToken"`
 }
@@ -124,9 +252,18 @@ type AuthConfig struct {
 // Parse maps the incoming map to the [Config] and validates it.
 func Parse(raw map[string]string) (Config, error) {
 	config := Config{
-		URI:        defaultConnectionURI,
-		DB:         raw[KeyDB],
-		Collection: raw[KeyCollection],
+		URI:                         defaultConnectionURI,
+		DB:                          raw[KeyDB],
+		Collection:                  raw[KeyCollection],
+		Serverless:                  defaultAtlasServerless,
+		BufferPoolEnabled:           defaultBufferPoolEnabled,
+		TelemetryEnabled:            defaultTelemetryEnabled,
+		CommandMonitorSlowThreshold: defaultCommandMonitorSlowThreshold,
+		ObjectIDCodecEnabled:        defaultObjectIDCodecEnabled,
+		IDType:                      defaultIDType,
+		OpenMaxRetries:              defaultOpenMaxRetries,
+		OpenRetryBackoff:            defaultOpenRetryBackoff,
+		SRVServiceName:              raw[KeySRVServiceName],
 		Auth: AuthConfig{
 			Username:              raw[KeyAuthUsername],
 			Password:              raw[KeyAuthPassword],
@@ -134,6 +271,8 @@ func Parse(raw map[string]string) (Config, error) {
 			Mechanism:             AuthMechanism(strings.ToUpper(raw[KeyAuthMechanism])),
 			TLSCAFile:             raw[KeyAuthTLSCAFile],
 			TLSCertificateKeyFile: raw[KeyAuthTLSCertificateKeyFile],
+			TLSServerName:         raw[KeyAuthTLSServerName],
+			TLSMinVersion:         raw[KeyAuthTLSMinVersion],
 			AWSSessionToken:       raw[KeyAuthAWSSessionToken],
 		},
 	}
@@ -142,17 +281,127 @@ func Parse(raw map[string]string) (Config, error) {
 	if uriStr := raw[KeyURI]; uriStr != "" {
 		uri, err := url.Parse(uriStr)
 		if err != nil {
-			return Config{}, fmt.Errorf("parse %q: %w", KeyURI, err)
+			return Config{}, validator.NewFormatError(KeyURI, err)
 		}
 
 		config.URI = uri
 	}
 
+	// parse srv.maxHosts if it's not empty
+	if srvMaxHosts := raw[KeySRVMaxHosts]; srvMaxHosts != "" {
+		maxHosts, err := strconv.Atoi(srvMaxHosts)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeySRVMaxHosts, err)
+		}
+
+		config.SRVMaxHosts = maxHosts
+	}
+
+	if err := config.validateScheme(); err != nil {
+		return Config{}, err
+	}
+
+	// set the atlas.serverless if it's not empty
+	if serverless := raw[KeyAtlasServerless]; serverless != "" {
+		config.Serverless = ServerlessMode(strings.ToLower(serverless))
+	}
+
+	// parse bufferPool.enabled if it's not empty
+	if bufferPoolEnabled := raw[KeyBufferPoolEnabled]; bufferPoolEnabled != "" {
+		enabled, err := strconv.ParseBool(bufferPoolEnabled)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyBufferPoolEnabled, err)
+		}
+
+		config.BufferPoolEnabled = enabled
+	}
+
+	// parse telemetry.enabled if it's not empty
+	if telemetryEnabled := raw[KeyTelemetryEnabled]; telemetryEnabled != "" {
+		enabled, err := strconv.ParseBool(telemetryEnabled)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyTelemetryEnabled, err)
+		}
+
+		config.TelemetryEnabled = enabled
+	}
+
+	// parse commandMonitor.enabled if it's not empty
+	if commandMonitorEnabled := raw[KeyCommandMonitorEnabled]; commandMonitorEnabled != "" {
+		enabled, err := strconv.ParseBool(commandMonitorEnabled)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyCommandMonitorEnabled, err)
+		}
+
+		config.CommandMonitorEnabled = enabled
+	}
+
+	// parse commandMonitor.slowThreshold if it's not empty
+	if slowThreshold := raw[KeyCommandMonitorSlowThreshold]; slowThreshold != "" {
+		threshold, err := time.ParseDuration(slowThreshold)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyCommandMonitorSlowThreshold, err)
+		}
+
+		config.CommandMonitorSlowThreshold = threshold
+	}
+
+	// the command monitor is a part of the telemetry, which is disabled for the leanest possible client
+	if config.CommandMonitorEnabled && !config.TelemetryEnabled {
+		return Config{}, validator.NewFieldError(KeyCommandMonitorEnabled, validator.ConstraintCompatible,
+			fmt.Errorf("%q can't be enabled if %q is disabled", KeyCommandMonitorEnabled, KeyTelemetryEnabled))
+	}
+
+	// parse objectIDCodec.enabled if it's not empty
+	if objectIDCodecEnabled := raw[KeyObjectIDCodecEnabled]; objectIDCodecEnabled != "" {
+		enabled, err := strconv.ParseBool(objectIDCodecEnabled)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyObjectIDCodecEnabled, err)
+		}
+
+		config.ObjectIDCodecEnabled = enabled
+	}
+
+	// set the id.type if it's not empty
+	if idType := raw[KeyIDType]; idType != "" {
+		config.IDType = codec.IDType(strings.ToLower(idType))
+	}
+
+	// parse open.maxRetries if it's not empty
+	if openMaxRetries := raw[KeyOpenMaxRetries]; openMaxRetries != "" {
+		maxRetries, err := strconv.Atoi(openMaxRetries)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyOpenMaxRetries, err)
+		}
+
+		config.OpenMaxRetries = maxRetries
+	}
+
+	// parse open.retryBackoff if it's not empty
+	if openRetryBackoff := raw[KeyOpenRetryBackoff]; openRetryBackoff != "" {
+		backoff, err := time.ParseDuration(openRetryBackoff)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyOpenRetryBackoff, err)
+		}
+
+		config.OpenRetryBackoff = backoff
+	}
+
+	// parse auth.tls.insecureSkipVerify if it's not empty
+	if insecureSkipVerify := raw[KeyAuthTLSInsecureSkipVerify]; insecureSkipVerify != "" {
+		skip, err := strconv.ParseBool(insecureSkipVerify)
+		if err != nil {
+			return Config{}, validator.NewFormatError(KeyAuthTLSInsecureSkipVerify, err)
+		}
+
+		config.Auth.TLSInsecureSkipVerify = skip
+	}
+
 	// validate auth mechanism if it's not empty
 	if config.Auth.Mechanism != "" && !config.Auth.Mechanism.IsValid() {
-		return Config{}, &InvalidAuthMechanismError{
+		return Config{}, validator.NewFieldError(KeyAuthMechanism, validator.ConstraintOneOf, &InvalidAuthMechanismError{
 			AuthMechanism: config.Auth.Mechanism,
-		}
+		})
 	}
 
 	if err := validator.ValidateStruct(&config); err != nil {
@@ -162,13 +411,88 @@ func Parse(raw map[string]string) (Config, error) {
 	return config, nil
 }
 
+// validateScheme makes sure the URI is a standard or a DNS seedlist connection string,
+// and the SRV options are only set for the latter.
+func (d *Config) validateScheme() error {
+	switch d.URI.Scheme {
+	case SchemeMongoDB:
+		if d.SRVMaxHosts != 0 || d.SRVServiceName != "" {
+			return validator.NewFieldError(KeySRVMaxHosts, validator.ConstraintCompatible,
+				fmt.Errorf("%q and %q require a %s:// URI", KeySRVMaxHosts, KeySRVServiceName, SchemeMongoDBSRV))
+		}
+
+		return nil
+
+	case SchemeMongoDBSRV:
+		// the hosts and ports are resolved from the SRV records
+		if d.URI.Port() != "" || strings.Contains(d.URI.Host, ",") {
+			return validator.NewFieldError(KeyURI, validator.ConstraintURI,
+				fmt.Errorf("%s:// URI must include exactly one host name without a port", SchemeMongoDBSRV))
+		}
+
+		return nil
+
+	default:
+		return validator.NewFieldError(KeyURI, validator.ConstraintURI, &InvalidURISchemeError{Scheme: d.URI.Scheme})
+	}
+}
+
+// ObjectIDCodec reports whether strings that are valid hex representations of ObjectIDs
+// are encoded into ObjectIDs. It's never the case for the string _id type, as hex string _ids would be converted.
+func (d *Config) ObjectIDCodec() bool {
+	return d.ObjectIDCodecEnabled && d.IDType != codec.IDTypeString
+}
+
+// GetBufferPool returns a pool of buffers records are serialized into,
+// or nil if pooling is disabled.
+func (d *Config) GetBufferPool() *codec.BufferPool {
+	if !d.BufferPoolEnabled {
+		return nil
+	}
+
+	return codec.NewBufferPool()
+}
+
 // GetClientOptions returns generated options for mongo connection depending on mechanism.
 func (d *Config) GetClientOptions() *options.ClientOptions {
 	uri, properties := d.getURIAndPropertiesByMechanism()
 	opts := options.Client().ApplyURI(uri).SetServerSelectionTimeout(defaultServerSelectionTimeout)
 
+	// Atlas Serverless instances are only available through a load balancer
+	// and may take longer to respond while scaling
+	if d.Serverless == ServerlessEnabled {
+		opts = opts.SetLoadBalanced(true).SetServerSelectionTimeout(serverlessServerSelectionTimeout)
+	}
+
+	d.applyTLSOptions(opts)
+
+	// explicit component levels take precedence over the ones of the environment variables
+	if !d.TelemetryEnabled {
+		opts = opts.SetLoggerOptions(options.Logger().
+			SetComponentLevel(options.LogComponentCommand, logLevelOff).
+			SetComponentLevel(options.LogComponentTopology, logLevelOff).
+			SetComponentLevel(options.LogComponentServerSelection, logLevelOff).
+			SetComponentLevel(options.LogComponentConnection, logLevelOff))
+	}
+
+	if d.CommandMonitorEnabled {
+		opts = opts.SetMonitor(commandMonitor(d.CommandMonitorSlowThreshold))
+	}
+
+	if d.AutoEncryption != nil {
+		opts = opts.SetAutoEncryptionOptions(d.AutoEncryption)
+	}
+
+	if d.ReadPreference != nil {
+		opts = opts.SetReadPreference(d.ReadPreference)
+	}
+
+	// TLS options aren't related to credentials
+	auth := d.Auth
+	auth.TLSInsecureSkipVerify, auth.TLSServerName, auth.TLSMinVersion = false, "", ""
+
 	// If we don't have any custom auth options, we should skip adding credential options
-	if d.Auth == (AuthConfig{}) {
+	if auth == (AuthConfig{}) {
 		return opts
 	}
 
@@ -183,13 +507,40 @@ func (d *Config) GetClientOptions() *options.ClientOptions {
 	return opts.SetAuth(cred)
 }
 
+// applyTLSOptions applies the TLS options on top of the TLS config generated from the URI,
+// enabling TLS if any of them is set.
+func (d *Config) applyTLSOptions(opts *options.ClientOptions) {
+	if !d.Auth.TLSInsecureSkipVerify && d.Auth.TLSServerName == "" && d.Auth.TLSMinVersion == "" {
+		return
+	}
+
+	tlsConfig := &tls.Config{} //nolint:gosec // the min version is set below if it's configured
+	if opts.TLSConfig != nil {
+		tlsConfig = opts.TLSConfig.Clone()
+	}
+
+	if d.Auth.TLSInsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true //nolint:gosec // it's explicitly enabled by the user
+	}
+
+	if d.Auth.TLSServerName != "" {
+		tlsConfig.ServerName = d.Auth.TLSServerName
+	}
+
+	if d.Auth.TLSMinVersion != "" {
+		tlsConfig.MinVersion = tlsVersions[d.Auth.TLSMinVersion]
+	}
+
+	opts.SetTLSConfig(tlsConfig)
+}
+
 // getURIAndPropertiesByMechanism generates uri and options depending on auth mechanism.
 func (d *Config) getURIAndPropertiesByMechanism() (string, map[string]string) {
+	uri := d.uriWithSRVOptions()
+
 	//nolint:exhaustive // because most of the mechanisms using same options
 	switch d.Auth.Mechanism {
 	case MongoDBX509:
-		uri := *d.URI
-
 		values := uri.Query()
 
 		if d.Auth.TLSCAFile != "" {
@@ -212,9 +563,32 @@ func (d *Config) getURIAndPropertiesByMechanism() (string, map[string]string) {
 			}
 		}
 
-		return d.URI.String(), properties
+		return uri.String(), properties
 
 	default:
-		return d.URI.String(), nil
+		return uri.String(), nil
+	}
+}
+
+// uriWithSRVOptions returns a copy of the URI with the SRV options in its query,
+// as the DNS seedlist is resolved while the URI is parsed.
+func (d *Config) uriWithSRVOptions() url.URL {
+	uri := *d.URI
+	if d.SRVMaxHosts == 0 && d.SRVServiceName == "" {
+		return uri
 	}
+
+	values := uri.Query()
+
+	if d.SRVMaxHosts != 0 {
+		values.Set(srvMaxHostsQueryName, strconv.Itoa(d.SRVMaxHosts))
+	}
+
+	if d.SRVServiceName != "" {
+		values.Set(srvServiceNameQueryName, d.SRVServiceName)
+	}
+
+	uri.RawQuery = values.Encode()
+
+	return uri
 }
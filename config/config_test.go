@@ -15,9 +15,14 @@
 package config
 
 import (
+	"crypto/tls"
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func TestAuthMechanism_IsValid(t *testing.T) {
@@ -102,8 +107,16 @@ func TestParse(t *testing.T) {
 					Scheme: "mongodb",
 					Host:   "localhost:27017",
 				},
-				DB:         "test",
-				Collection: "users",
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
 			},
 			wantErr: false,
 		},
@@ -123,8 +136,16 @@ func TestParse(t *testing.T) {
 					Path:     "/",
 					RawQuery: "directConnection=true",
 				},
-				DB:         "test",
-				Collection: "users",
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
 			},
 			wantErr: false,
 		},
@@ -143,8 +164,16 @@ func TestParse(t *testing.T) {
 					Scheme: "mongodb",
 					Host:   "localhost:27017",
 				},
-				DB:         "test",
-				Collection: "users",
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				Auth: AuthConfig{
 					Mechanism: SCRAMSHA256,
 				},
@@ -166,8 +195,16 @@ func TestParse(t *testing.T) {
 					Scheme: "mongodb",
 					Host:   "localhost:27017",
 				},
-				DB:         "test",
-				Collection: "users",
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				Auth: AuthConfig{
 					Mechanism: SCRAMSHA256,
 				},
@@ -191,8 +228,16 @@ func TestParse(t *testing.T) {
 					Scheme: "mongodb",
 					Host:   "localhost:27017",
 				},
-				DB:         "test",
-				Collection: "users",
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				Auth: AuthConfig{
 					Mechanism:             SCRAMSHA256,
 					TLSCAFile:             "config.go",
@@ -201,6 +246,268 @@ func TestParse(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success_with_tls_options",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                       "mongodb://localhost:27017",
+					KeyDB:                        "test",
+					KeyCollection:                "users",
+					KeyAuthTLSInsecureSkipVerify: "true",
+					KeyAuthTLSServerName:         "mongo.internal",
+					KeyAuthTLSMinVersion:         "1.2",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				Auth: AuthConfig{
+					TLSInsecureSkipVerify: true,
+					TLSServerName:         "mongo.internal",
+					TLSMinVersion:         "1.2",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_with_srv_options",
+			args: args{
+				raw: map[string]string{
+					KeyURI:            "mongodb+srv://cluster0.example.net",
+					KeyDB:             "test",
+					KeyCollection:     "users",
+					KeySRVMaxHosts:    "3",
+					KeySRVServiceName: "customdb",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb+srv",
+					Host:   "cluster0.example.net",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				SRVMaxHosts:                 3,
+				SRVServiceName:              "customdb",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_with_atlas_serverless",
+			args: args{
+				raw: map[string]string{
+					KeyURI:             "mongodb://localhost:27017",
+					KeyDB:              "test",
+					KeyCollection:      "users",
+					KeyAtlasServerless: "Enabled",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessEnabled,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_buffer_pool_disabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:               "mongodb://localhost:27017",
+					KeyDB:                "test",
+					KeyCollection:        "users",
+					KeyBufferPoolEnabled: "false",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_command_monitor",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                         "mongodb://localhost:27017",
+					KeyDB:                          "test",
+					KeyCollection:                  "users",
+					KeyCommandMonitorEnabled:       "true",
+					KeyCommandMonitorSlowThreshold: "1s",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				CommandMonitorEnabled:       true,
+				CommandMonitorSlowThreshold: time.Second,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_telemetry_disabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:              "mongodb://localhost:27017",
+					KeyDB:               "test",
+					KeyCollection:       "users",
+					KeyTelemetryEnabled: "false",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_object_id_codec_disabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                  "mongodb://localhost:27017",
+					KeyDB:                   "test",
+					KeyCollection:           "users",
+					KeyObjectIDCodecEnabled: "false",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_id_type",
+			args: args{
+				raw: map[string]string{
+					KeyURI:        "mongodb://localhost:27017",
+					KeyDB:         "test",
+					KeyCollection: "users",
+					KeyIDType:     "UUID",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      codec.IDTypeUUID,
+				OpenMaxRetries:              3,
+				OpenRetryBackoff:            time.Second,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_open_retries",
+			args: args{
+				raw: map[string]string{
+					KeyURI:              "mongodb://localhost:27017",
+					KeyDB:               "test",
+					KeyCollection:       "users",
+					KeyOpenMaxRetries:   "0",
+					KeyOpenRetryBackoff: "250ms",
+				},
+			},
+			want: Config{
+				URI: &url.URL{
+					Scheme: "mongodb",
+					Host:   "localhost:27017",
+				},
+				DB:                          "test",
+				Collection:                  "users",
+				Serverless:                  ServerlessAuto,
+				BufferPoolEnabled:           true,
+				TelemetryEnabled:            true,
+				ObjectIDCodecEnabled:        true,
+				IDType:                      defaultIDType,
+				OpenMaxRetries:              0,
+				OpenRetryBackoff:            250 * time.Millisecond,
+				CommandMonitorSlowThreshold: 100 * time.Millisecond,
+			},
+			wantErr: false,
+		},
 		{
 			name: "fail_missing_required_field",
 			args: args{
@@ -224,6 +531,56 @@ func TestParse(t *testing.T) {
 			want:    Config{},
 			wantErr: true,
 		},
+		{
+			name: "fail_invalid_uri_scheme",
+			args: args{
+				raw: map[string]string{
+					KeyURI:        "postgres://localhost:5432",
+					KeyDB:         "test",
+					KeyCollection: "users",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_srv_uri_with_port",
+			args: args{
+				raw: map[string]string{
+					KeyURI:        "mongodb+srv://cluster0.example.net:27017",
+					KeyDB:         "test",
+					KeyCollection: "users",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_srv_options_without_srv_uri",
+			args: args{
+				raw: map[string]string{
+					KeyURI:            "mongodb://localhost:27017",
+					KeyDB:             "test",
+					KeyCollection:     "users",
+					KeySRVServiceName: "customdb",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_srv_max_hosts",
+			args: args{
+				raw: map[string]string{
+					KeyURI:         "mongodb+srv://cluster0.example.net",
+					KeyDB:          "test",
+					KeyCollection:  "users",
+					KeySRVMaxHosts: "-1",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
 		{
 			name: "fail_invalid_auth_mechanism",
 			args: args{
@@ -237,6 +594,176 @@ func TestParse(t *testing.T) {
 			want:    Config{},
 			wantErr: true,
 		},
+		{
+			name: "fail_invalid_atlas_serverless",
+			args: args{
+				raw: map[string]string{
+					KeyURI:             "mongodb://localhost:27017",
+					KeyDB:              "test",
+					KeyCollection:      "users",
+					KeyAtlasServerless: "always",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_buffer_pool_enabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:               "mongodb://localhost:27017",
+					KeyDB:                "test",
+					KeyCollection:        "users",
+					KeyBufferPoolEnabled: "sometimes",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_tls_insecure_skip_verify",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                       "mongodb://localhost:27017",
+					KeyDB:                        "test",
+					KeyCollection:                "users",
+					KeyAuthTLSInsecureSkipVerify: "maybe",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_tls_min_version",
+			args: args{
+				raw: map[string]string{
+					KeyURI:               "mongodb://localhost:27017",
+					KeyDB:                "test",
+					KeyCollection:        "users",
+					KeyAuthTLSMinVersion: "1.4",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_telemetry_enabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:              "mongodb://localhost:27017",
+					KeyDB:               "test",
+					KeyCollection:       "users",
+					KeyTelemetryEnabled: "sometimes",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_command_monitor_enabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                   "mongodb://localhost:27017",
+					KeyDB:                    "test",
+					KeyCollection:            "users",
+					KeyCommandMonitorEnabled: "sometimes",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_command_monitor_slow_threshold",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                         "mongodb://localhost:27017",
+					KeyDB:                          "test",
+					KeyCollection:                  "users",
+					KeyCommandMonitorSlowThreshold: "-1s",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_command_monitor_telemetry_disabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                   "mongodb://localhost:27017",
+					KeyDB:                    "test",
+					KeyCollection:            "users",
+					KeyCommandMonitorEnabled: "true",
+					KeyTelemetryEnabled:      "false",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_object_id_codec_enabled",
+			args: args{
+				raw: map[string]string{
+					KeyURI:                  "mongodb://localhost:27017",
+					KeyDB:                   "test",
+					KeyCollection:           "users",
+					KeyObjectIDCodecEnabled: "sometimes",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_id_type",
+			args: args{
+				raw: map[string]string{
+					KeyURI:        "mongodb://localhost:27017",
+					KeyDB:         "test",
+					KeyCollection: "users",
+					KeyIDType:     "number",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_open_max_retries",
+			args: args{
+				raw: map[string]string{
+					KeyURI:            "mongodb://localhost:27017",
+					KeyDB:             "test",
+					KeyCollection:     "users",
+					KeyOpenMaxRetries: "three",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_open_max_retries",
+			args: args{
+				raw: map[string]string{
+					KeyURI:            "mongodb://localhost:27017",
+					KeyDB:             "test",
+					KeyCollection:     "users",
+					KeyOpenMaxRetries: "-1",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_open_retry_backoff",
+			args: args{
+				raw: map[string]string{
+					KeyURI:              "mongodb://localhost:27017",
+					KeyDB:               "test",
+					KeyCollection:       "users",
+					KeyOpenRetryBackoff: "soon",
+				},
+			},
+			want:    Config{},
+			wantErr: true,
+		},
 		{
 			name: "fail_tls_config_files_do_not_exist",
 			args: args{
@@ -268,3 +795,145 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_GetClientOptions_tls(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no_tls_options", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{URI: &url.URL{Scheme: "mongodb", Host: "localhost:27017"}}
+
+		if opts := cfg.GetClientOptions(); opts.TLSConfig != nil {
+			t.Errorf("GetClientOptions().TLSConfig = %v, want nil", opts.TLSConfig)
+		}
+
+		if opts := cfg.GetClientOptions(); opts.Auth != nil {
+			t.Errorf("GetClientOptions().Auth = %v, want nil", opts.Auth)
+		}
+	})
+
+	t.Run("tls_options", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := Config{
+			URI: &url.URL{Scheme: "mongodb", Host: "localhost:27017"},
+			Auth: AuthConfig{
+				TLSInsecureSkipVerify: true,
+				TLSServerName:         "mongo.internal",
+				TLSMinVersion:         "1.3",
+			},
+		}
+
+		opts := cfg.GetClientOptions()
+		if opts.TLSConfig == nil {
+			t.Fatal("GetClientOptions().TLSConfig = nil, want non-nil")
+		}
+
+		if !opts.TLSConfig.InsecureSkipVerify {
+			t.Error("GetClientOptions().TLSConfig.InsecureSkipVerify = false, want true")
+		}
+
+		if opts.TLSConfig.ServerName != "mongo.internal" {
+			t.Errorf("GetClientOptions().TLSConfig.ServerName = %q, want %q", opts.TLSConfig.ServerName, "mongo.internal")
+		}
+
+		if opts.TLSConfig.MinVersion != tls.VersionTLS13 {
+			t.Errorf("GetClientOptions().TLSConfig.MinVersion = %d, want %d", opts.TLSConfig.MinVersion, tls.VersionTLS13)
+		}
+
+		// TLS options alone must not produce credentials
+		if opts.Auth != nil {
+			t.Errorf("GetClientOptions().Auth = %v, want nil", opts.Auth)
+		}
+	})
+}
+
+func TestConfig_uriWithSRVOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "no_srv_options",
+			cfg:  Config{URI: &url.URL{Scheme: SchemeMongoDBSRV, Host: "cluster0.example.net", RawQuery: "w=majority"}},
+			want: "mongodb+srv://cluster0.example.net?w=majority",
+		},
+		{
+			name: "srv_options",
+			cfg: Config{
+				URI:            &url.URL{Scheme: SchemeMongoDBSRV, Host: "cluster0.example.net"},
+				SRVMaxHosts:    2,
+				SRVServiceName: "customdb",
+			},
+			want: "mongodb+srv://cluster0.example.net?srvMaxHosts=2&srvServiceName=customdb",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uri := tt.cfg.uriWithSRVOptions()
+			if got := uri.String(); got != tt.want {
+				t.Errorf("Config.uriWithSRVOptions() = %q, want %q", got, tt.want)
+			}
+
+			// the original URI must stay untouched
+			if tt.cfg.URI.Query().Has(srvMaxHostsQueryName) {
+				t.Errorf("Config.URI = %q, want no %s", tt.cfg.URI, srvMaxHostsQueryName)
+			}
+		})
+	}
+}
+
+func TestConfig_GetClientOptions_telemetry(t *testing.T) {
+	t.Parallel()
+
+	enabled := Config{URI: &url.URL{Scheme: "mongodb", Host: "localhost:27017"}, TelemetryEnabled: true}
+	if opts := enabled.GetClientOptions(); opts.LoggerOptions != nil {
+		t.Errorf("GetClientOptions().LoggerOptions = %v, want nil", opts.LoggerOptions)
+	}
+
+	disabled := Config{URI: &url.URL{Scheme: "mongodb", Host: "localhost:27017"}}
+
+	opts := disabled.GetClientOptions()
+	if opts.LoggerOptions == nil {
+		t.Fatal("GetClientOptions().LoggerOptions = nil, want non-nil")
+	}
+
+	for _, component := range []options.LogComponent{
+		options.LogComponentCommand,
+		options.LogComponentTopology,
+		options.LogComponentServerSelection,
+		options.LogComponentConnection,
+	} {
+		if level, ok := opts.LoggerOptions.ComponentLevels[component]; !ok || level != logLevelOff {
+			t.Errorf("GetClientOptions().LoggerOptions.ComponentLevels[%d] = %d, want %d", component, level, logLevelOff)
+		}
+	}
+}
+
+func TestConfig_GetClientOptions_commandMonitor(t *testing.T) {
+	t.Parallel()
+
+	disabled := Config{URI: &url.URL{Scheme: "mongodb", Host: "localhost:27017"}, TelemetryEnabled: true}
+	if opts := disabled.GetClientOptions(); opts.Monitor != nil {
+		t.Errorf("GetClientOptions().Monitor = %v, want nil", opts.Monitor)
+	}
+
+	enabled := Config{
+		URI:                         &url.URL{Scheme: "mongodb", Host: "localhost:27017"},
+		TelemetryEnabled:            true,
+		CommandMonitorEnabled:       true,
+		CommandMonitorSlowThreshold: time.Second,
+	}
+
+	opts := enabled.GetClientOptions()
+	if opts.Monitor == nil || opts.Monitor.Succeeded == nil || opts.Monitor.Failed == nil {
+		t.Fatalf("GetClientOptions().Monitor = %v, want a monitor of succeeded and failed commands", opts.Monitor)
+	}
+}
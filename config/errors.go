@@ -25,3 +25,13 @@ type InvalidAuthMechanismError struct {
 func (e *InvalidAuthMechanismError) Error() string {
 	return fmt.Sprintf("invalid auth mechanism %q", e.AuthMechanism)
 }
+
+// InvalidURISchemeError occurs when a URI is neither a standard nor a DNS seedlist connection string.
+type InvalidURISchemeError struct {
+	Scheme string
+}
+
+// Error returns a formatted error message for the [InvalidURISchemeError].
+func (e *InvalidURISchemeError) Error() string {
+	return fmt.Sprintf("invalid uri scheme %q, must be %q or %q", e.Scheme, SchemeMongoDB, SchemeMongoDBSRV)
+}
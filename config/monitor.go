@@ -0,0 +1,53 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// commandMonitor returns a monitor that logs failed commands and the ones taking at least the slow threshold.
+// Both are logged with the loggers of the contexts the commands are run with.
+func commandMonitor(slowThreshold time.Duration) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			if e.Duration < slowThreshold {
+				return
+			}
+
+			sdk.Logger(ctx).Info().
+				Str("command", e.CommandName).
+				Str("db", e.DatabaseName).
+				Int64("requestId", e.RequestID).
+				Str("connectionId", e.ConnectionID).
+				Dur("duration", e.Duration).
+				Msg("slow command")
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			sdk.Logger(ctx).Warn().
+				Str("command", e.CommandName).
+				Str("db", e.DatabaseName).
+				Int64("requestId", e.RequestID).
+				Str("connectionId", e.ConnectionID).
+				Dur("duration", e.Duration).
+				Str("failure", e.Failure).
+				Msg("command failed")
+		},
+	}
+}
@@ -24,6 +24,11 @@ import (
 
 var Connector = sdk.Connector{
 	NewSpecification: Specification,
-	NewSource:        source.NewSource,
+	NewSource:        newSource,
 	NewDestination:   destination.NewDestination,
 }
+
+// newSource creates a new source that stamps records with the connector's version.
+func newSource() sdk.Source {
+	return source.NewSourceWithVersion(version)
+}
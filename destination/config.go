@@ -0,0 +1,741 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/destination/writer"
+	"github.com/conduitio-labs/conduit-connector-mongo/transform"
+	"github.com/conduitio-labs/conduit-connector-mongo/validator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+const (
+	// defaultKeyFromPayload is the default value for the key.fromPayload field.
+	defaultKeyFromPayload = false
+	// defaultKeyFields is the default value for the key.fields field.
+	defaultKeyFields = "_id"
+	// defaultIndexesReplicate is the default value for the indexes.replicate field.
+	defaultIndexesReplicate = false
+	// defaultUpdateStrategy is the default value for the update.strategy field.
+	defaultUpdateStrategy = writer.UpdateStrategySet
+	// defaultTransactionEnabled is the default value for the transaction.enabled field.
+	defaultTransactionEnabled = false
+	// defaultBatchDeletesLast is the default value for the batch.deletesLast field.
+	defaultBatchDeletesLast = false
+	// defaultWriteMaxRetries is the default value for the write.maxRetries field.
+	defaultWriteMaxRetries = 0
+	// defaultWriteWorkers is the default value for the write.workers field.
+	defaultWriteWorkers = 1
+	// defaultWriteContinueOnError is the default value for the write.continueOnError field.
+	defaultWriteContinueOnError = false
+	// defaultWriteOnDuplicateKey is the default value for the write.onDuplicateKey field.
+	defaultWriteOnDuplicateKey = writer.DuplicateKeyFail
+	// defaultMetadataKeys is the default value for the metadata.keys field.
+	defaultMetadataKeys = "opencdc.collection,opencdc.createdAt"
+	// defaultMetadataPosition is the default value for the metadata.position field.
+	defaultMetadataPosition = true
+	// defaultCreateIfMissing is the default value for the createIfMissing field.
+	defaultCreateIfMissing = false
+	// defaultValidatorMode is the default value for the validator.mode field.
+	defaultValidatorMode = writer.ValidatorModeInstall
+	// defaultConvertUUID is the default value for the convert.uuid field.
+	defaultConvertUUID = codec.UUIDEncodingNone
+	// defaultConvertIntegers is the default value for the convert.integers field.
+	defaultConvertIntegers = codec.IntegerDecodingInt64
+)
+
+const (
+	// ConfigKeyKeyFromPayload is a config name for a key.fromPayload field.
+	ConfigKeyKeyFromPayload = "key.fromPayload"
+	// ConfigKeyKeyFields is a config name for a key.fields field.
+	ConfigKeyKeyFields = "key.fields"
+	// ConfigKeyKeyMapping is a config name for a key.mapping field.
+	ConfigKeyKeyMapping = "key.mapping"
+	// ConfigKeyNamespaceMapping is a config name for a namespace.mapping field.
+	ConfigKeyNamespaceMapping = "namespace.mapping"
+	// ConfigKeyIndexesReplicate is a config name for an indexes.replicate field.
+	ConfigKeyIndexesReplicate = "indexes.replicate"
+	// ConfigKeyUpdateStrategy is a config name for an update.strategy field.
+	ConfigKeyUpdateStrategy = "update.strategy"
+	// ConfigKeyConvertUUID is a config name for a convert.uuid field.
+	ConfigKeyConvertUUID = "convert.uuid"
+	// ConfigKeyConvertIntegers is a config name for a convert.integers field.
+	ConfigKeyConvertIntegers = "convert.integers"
+	// ConfigKeyWriteConcernW is a config name for a writeConcern.w field.
+	ConfigKeyWriteConcernW = "writeConcern.w"
+	// ConfigKeyWriteConcernJ is a config name for a writeConcern.j field.
+	ConfigKeyWriteConcernJ = "writeConcern.j"
+	// ConfigKeyWriteConcernWTimeout is a config name for a writeConcern.wtimeout field.
+	ConfigKeyWriteConcernWTimeout = "writeConcern.wtimeout"
+	// ConfigKeyUpdateArrays is a config name for an update.arrays field.
+	ConfigKeyUpdateArrays = "update.arrays"
+	// ConfigKeyTransactionEnabled is a config name for a transaction.enabled field.
+	ConfigKeyTransactionEnabled = "transaction.enabled"
+	// ConfigKeyBatchDeletesLast is a config name for a batch.deletesLast field.
+	ConfigKeyBatchDeletesLast = "batch.deletesLast"
+	// ConfigKeyWriteMaxRetries is a config name for a write.maxRetries field.
+	ConfigKeyWriteMaxRetries = "write.maxRetries"
+	// ConfigKeyWriteWorkers is a config name for a write.workers field.
+	ConfigKeyWriteWorkers = "write.workers"
+	// ConfigKeyWriteContinueOnError is a config name for a write.continueOnError field.
+	ConfigKeyWriteContinueOnError = "write.continueOnError"
+	// ConfigKeyWriteOnDuplicateKey is a config name for a write.onDuplicateKey field.
+	ConfigKeyWriteOnDuplicateKey = "write.onDuplicateKey"
+	// ConfigKeyMetadataField is a config name for a metadata.field field.
+	ConfigKeyMetadataField = "metadata.field"
+	// ConfigKeyMetadataKeys is a config name for a metadata.keys field.
+	ConfigKeyMetadataKeys = "metadata.keys"
+	// ConfigKeyMetadataPosition is a config name for a metadata.position field.
+	ConfigKeyMetadataPosition = "metadata.position"
+	// ConfigKeyTTLField is a config name for a ttl.field field.
+	ConfigKeyTTLField = "ttl.field"
+	// ConfigKeyTTLExpireAfterSeconds is a config name for a ttl.expireAfterSeconds field.
+	ConfigKeyTTLExpireAfterSeconds = "ttl.expireAfterSeconds"
+	// ConfigKeyCreateIfMissing is a config name for a createIfMissing field.
+	ConfigKeyCreateIfMissing = "createIfMissing"
+	// ConfigKeyCreateOptions is a config name for a createOptions field.
+	ConfigKeyCreateOptions = "createOptions"
+	// ConfigKeyCappedSize is a config name for a capped.size field.
+	ConfigKeyCappedSize = "capped.size"
+	// ConfigKeyCappedMax is a config name for a capped.max field.
+	ConfigKeyCappedMax = "capped.max"
+	// ConfigKeyValidatorSchema is a config name for a validator.schema field.
+	ConfigKeyValidatorSchema = "validator.schema"
+	// ConfigKeyValidatorMode is a config name for a validator.mode field.
+	ConfigKeyValidatorMode = "validator.mode"
+	// ConfigKeyWriteCondition is a config name for a write.condition field.
+	ConfigKeyWriteCondition = "write.condition"
+)
+
+// errNegativeWriteConcernW occurs when the writeConcern.w field is a negative number.
+var errNegativeWriteConcernW = errors.New("must not be a negative number")
+
+// errInvalidMetadataField occurs when the metadata.field field is not a valid top-level field name.
+var errInvalidMetadataField = errors.New("must be a top-level field other than _id, not starting with $")
+
+// errCreateOptionsName occurs when the createOptions field contains the name of the collection to create.
+var errCreateOptionsName = errors.New("must not contain the create field, the collection field is used instead")
+
+// errInvalidTTLField occurs when the ttl.field field is the _id field or starts with $.
+var errInvalidTTLField = errors.New("must be a field other than _id, not starting with $")
+
+// Config contains destination-specific configurable values.
+type Config struct {
+	config.Config
+
+	// KeyFromPayload determines whether or not the connector
+	// builds a key from a record payload if the record has no key.
+	KeyFromPayload bool `key:"key.fromPayload"`
+	// KeyFields is the list of payload fields the connector builds a key from.
+	KeyFields []string `key:"key.fields" validate:"required_if=KeyFromPayload true"`
+	// KeyMapping maps record key fields to document fields the connector filters documents by.
+	KeyMapping map[string]string `key:"key.mapping"`
+	// NamespaceMapping is the ordered list of rules routing records to collections by their source namespaces.
+	NamespaceMapping []writer.NamespaceRule `key:"namespace.mapping"`
+	// IndexesReplicate determines whether or not the connector creates indexes
+	// described by collection metadata records on the target collection.
+	IndexesReplicate bool `key:"indexes.replicate"`
+	// UpdateStrategy determines how the connector applies updates to documents.
+	UpdateStrategy writer.UpdateStrategy `key:"update.strategy" validate:"oneof=set flatten delta"`
+	// UpdateArrays maps payload fields to the strategies their arrays are applied to documents with on updates.
+	// Arrays of other fields are replaced.
+	UpdateArrays map[string]writer.ArrayStrategy `key:"update.arrays"`
+	// ConvertUUID determines whether and with which binary subtype UUID strings are written as binary UUIDs.
+	ConvertUUID codec.UUIDEncoding `key:"convert.uuid" validate:"oneof=none standard legacy"`
+	// ConvertIntegers determines whether integral JSON numbers are written as 64-bit integers or as doubles.
+	ConvertIntegers codec.IntegerDecoding `key:"convert.integers" validate:"oneof=int64 double"`
+	// WriteConcernW is the number of nodes, "majority" or a custom tag
+	// that must acknowledge write operations.
+	WriteConcernW string `key:"writeConcern.w"`
+	// WriteConcernJ determines whether or not write operations must be written to the on-disk journal
+	// before they're acknowledged. If it's nil, the server default is used.
+	WriteConcernJ *bool `key:"writeConcern.j"`
+	// WriteConcernWTimeout is a time limit for the write concern.
+	WriteConcernWTimeout time.Duration `key:"writeConcern.wtimeout" validate:"gte=0"`
+	// TransactionEnabled determines whether or not the connector writes each batch of records
+	// within a single transaction, so either all of them are written or none.
+	TransactionEnabled bool `key:"transaction.enabled"`
+	// BatchDeletesLast determines whether or not the connector writes deletes of a batch after
+	// records of other keys, but never after later records of the same key.
+	BatchDeletesLast bool `key:"batch.deletesLast"`
+	// WriteMaxRetries is the number of times the connector retries writing a record
+	// that failed with a transient error.
+	WriteMaxRetries int `key:"write.maxRetries" validate:"gte=0"`
+	// WriteWorkers is the number of workers the connector writes records of a batch with concurrently.
+	// Records are distributed among the workers by their keys, so records of the same key are written in order.
+	WriteWorkers int `key:"write.workers" validate:"gte=1,lte=64"`
+	// WriteContinueOnError determines whether or not the connector keeps writing records of a batch
+	// after a record fails, so the error reports every failed record of the batch.
+	WriteContinueOnError bool `key:"write.continueOnError"`
+	// WriteOnDuplicateKey determines how the connector handles inserts failing with duplicate key errors.
+	WriteOnDuplicateKey writer.DuplicateKeyPolicy `key:"write.onDuplicateKey" validate:"oneof=fail skip update replace"`
+	// MetadataField is a name of the sub-document the connector puts the selected record metadata into.
+	// If it's empty, no metadata is written.
+	MetadataField string `key:"metadata.field"`
+	// MetadataKeys is the list of metadata keys the connector puts into the metadata field.
+	MetadataKeys []string `key:"metadata.keys"`
+	// MetadataPosition determines whether or not the connector puts record positions into the metadata field.
+	MetadataPosition bool `key:"metadata.position"`
+	// TTLField is a name of the date field the connector creates a TTL index on.
+	// If it's empty, no TTL index is created.
+	TTLField string `key:"ttl.field"`
+	// TTLExpireAfterSeconds is the number of seconds after the TTL field's date documents expire in.
+	TTLExpireAfterSeconds int `key:"ttl.expireAfterSeconds" validate:"gte=0,lte=2147483647"`
+	// CreateIfMissing determines whether or not the connector creates the database and the collection
+	// if they don't exist, instead of failing.
+	CreateIfMissing bool `key:"createIfMissing"`
+	// CreateOptions is the fields of the create command the collection is created with, if it's missing.
+	CreateOptions bson.D `key:"createOptions"`
+	// CappedSize is the maximum size in bytes of the capped collection the connector creates, if it's missing.
+	// If it's zero, the collection isn't created as capped.
+	CappedSize int64 `key:"capped.size" validate:"gte=0"`
+	// CappedMax is the maximum number of documents of the capped collection the connector creates.
+	// If it's zero, the number of documents is limited by the size only.
+	CappedMax int64 `key:"capped.max" validate:"gte=0"`
+	// ValidatorSchema is the $jsonSchema the documents of the collection must satisfy.
+	// If it's nil, the collection's validator is left as it is.
+	ValidatorSchema bson.D `key:"validator.schema"`
+	// ValidatorMode determines whether the connector installs the validator on the collection or verifies it.
+	ValidatorMode writer.ValidatorMode `key:"validator.mode" validate:"oneof=install verify"`
+	// WriteCondition is an expression records are evaluated against, records it's false for are skipped.
+	// If it's empty, all records are written.
+	WriteCondition string `key:"write.condition"`
+}
+
+// ParseConfig maps the incoming map to the [Config] and validates it.
+func ParseConfig(raw map[string]string) (Config, error) {
+	commonConfig, err := config.Parse(raw)
+	if err != nil {
+		return Config{}, fmt.Errorf("parse common config: %w", err)
+	}
+
+	destinationConfig := Config{
+		Config:               commonConfig,
+		KeyFromPayload:       defaultKeyFromPayload,
+		KeyFields:            parseList(defaultKeyFields),
+		IndexesReplicate:     defaultIndexesReplicate,
+		UpdateStrategy:       defaultUpdateStrategy,
+		TransactionEnabled:   defaultTransactionEnabled,
+		BatchDeletesLast:     defaultBatchDeletesLast,
+		WriteMaxRetries:      defaultWriteMaxRetries,
+		WriteWorkers:         defaultWriteWorkers,
+		WriteContinueOnError: defaultWriteContinueOnError,
+		WriteOnDuplicateKey:  defaultWriteOnDuplicateKey,
+		MetadataKeys:         parseList(defaultMetadataKeys),
+		MetadataPosition:     defaultMetadataPosition,
+		CreateIfMissing:      defaultCreateIfMissing,
+		ValidatorMode:        defaultValidatorMode,
+		ConvertUUID:          defaultConvertUUID,
+		ConvertIntegers:      defaultConvertIntegers,
+		WriteCondition:       strings.TrimSpace(raw[ConfigKeyWriteCondition]),
+	}
+
+	// parse key.fromPayload if it's not empty
+	if keyFromPayloadStr := raw[ConfigKeyKeyFromPayload]; keyFromPayloadStr != "" {
+		keyFromPayload, err := strconv.ParseBool(keyFromPayloadStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyKeyFromPayload, err)
+		}
+
+		destinationConfig.KeyFromPayload = keyFromPayload
+	}
+
+	// set the key.fields if it's not empty
+	if keyFields, ok := raw[ConfigKeyKeyFields]; ok {
+		destinationConfig.KeyFields = parseList(keyFields)
+	}
+
+	// parse key.mapping if it's not empty
+	if keyMappingStr := raw[ConfigKeyKeyMapping]; keyMappingStr != "" {
+		keyMapping, err := parseMapping(keyMappingStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyKeyMapping, err)
+		}
+
+		destinationConfig.KeyMapping = keyMapping
+	}
+
+	// parse namespace.mapping if it's not empty
+	if namespaceMappingStr := raw[ConfigKeyNamespaceMapping]; namespaceMappingStr != "" {
+		namespaceMapping, err := parseNamespaceMapping(namespaceMappingStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyNamespaceMapping, err)
+		}
+
+		destinationConfig.NamespaceMapping = namespaceMapping
+	}
+
+	// parse indexes.replicate if it's not empty
+	if indexesReplicateStr := raw[ConfigKeyIndexesReplicate]; indexesReplicateStr != "" {
+		indexesReplicate, err := strconv.ParseBool(indexesReplicateStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyIndexesReplicate, err)
+		}
+
+		destinationConfig.IndexesReplicate = indexesReplicate
+	}
+
+	// set the update.strategy if it's not empty
+	if updateStrategy := raw[ConfigKeyUpdateStrategy]; updateStrategy != "" {
+		destinationConfig.UpdateStrategy = writer.UpdateStrategy(strings.ToLower(updateStrategy))
+	}
+
+	// parse update.arrays if it's not empty
+	if updateArraysStr := raw[ConfigKeyUpdateArrays]; updateArraysStr != "" {
+		mapping, err := parseMapping(updateArraysStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyUpdateArrays, err)
+		}
+
+		updateArrays, err := parseArrayStrategies(mapping)
+		if err != nil {
+			return Config{}, validator.NewFieldError(ConfigKeyUpdateArrays, validator.ConstraintOneOf, err)
+		}
+
+		destinationConfig.UpdateArrays = updateArrays
+	}
+
+	// set the convert.uuid if it's not empty
+	if convertUUID := raw[ConfigKeyConvertUUID]; convertUUID != "" {
+		destinationConfig.ConvertUUID = codec.UUIDEncoding(strings.ToLower(convertUUID))
+	}
+
+	// set the convert.integers if it's not empty
+	if convertIntegers := raw[ConfigKeyConvertIntegers]; convertIntegers != "" {
+		destinationConfig.ConvertIntegers = codec.IntegerDecoding(strings.ToLower(convertIntegers))
+	}
+
+	// parse transaction.enabled if it's not empty
+	if transactionEnabledStr := raw[ConfigKeyTransactionEnabled]; transactionEnabledStr != "" {
+		transactionEnabled, err := strconv.ParseBool(transactionEnabledStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyTransactionEnabled, err)
+		}
+
+		destinationConfig.TransactionEnabled = transactionEnabled
+	}
+
+	// parse batch.deletesLast if it's not empty
+	if batchDeletesLastStr := raw[ConfigKeyBatchDeletesLast]; batchDeletesLastStr != "" {
+		batchDeletesLast, err := strconv.ParseBool(batchDeletesLastStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyBatchDeletesLast, err)
+		}
+
+		destinationConfig.BatchDeletesLast = batchDeletesLast
+	}
+
+	// parse write.maxRetries if it's not empty
+	if writeMaxRetriesStr := raw[ConfigKeyWriteMaxRetries]; writeMaxRetriesStr != "" {
+		writeMaxRetries, err := strconv.Atoi(writeMaxRetriesStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyWriteMaxRetries, err)
+		}
+
+		destinationConfig.WriteMaxRetries = writeMaxRetries
+	}
+
+	// parse write.workers if it's not empty
+	if writeWorkersStr := raw[ConfigKeyWriteWorkers]; writeWorkersStr != "" {
+		writeWorkers, err := strconv.Atoi(writeWorkersStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyWriteWorkers, err)
+		}
+
+		destinationConfig.WriteWorkers = writeWorkers
+	}
+
+	// parse write.continueOnError if it's not empty
+	if writeContinueOnErrorStr := raw[ConfigKeyWriteContinueOnError]; writeContinueOnErrorStr != "" {
+		writeContinueOnError, err := strconv.ParseBool(writeContinueOnErrorStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyWriteContinueOnError, err)
+		}
+
+		destinationConfig.WriteContinueOnError = writeContinueOnError
+	}
+
+	// set the write.onDuplicateKey if it's not empty
+	if writeOnDuplicateKey := raw[ConfigKeyWriteOnDuplicateKey]; writeOnDuplicateKey != "" {
+		destinationConfig.WriteOnDuplicateKey = writer.DuplicateKeyPolicy(strings.ToLower(writeOnDuplicateKey))
+	}
+
+	if err := parseWriteConcern(raw, &destinationConfig); err != nil {
+		return Config{}, err
+	}
+
+	if err := parseMetadata(raw, &destinationConfig); err != nil {
+		return Config{}, err
+	}
+
+	if err := parseTTL(raw, &destinationConfig); err != nil {
+		return Config{}, err
+	}
+
+	if err := parseCreate(raw, &destinationConfig); err != nil {
+		return Config{}, err
+	}
+
+	// parse validator.schema if it's not empty
+	if validatorSchemaStr := strings.TrimSpace(raw[ConfigKeyValidatorSchema]); validatorSchemaStr != "" {
+		var validatorSchema bson.D
+		if err := bson.UnmarshalExtJSON([]byte(validatorSchemaStr), false, &validatorSchema); err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyValidatorSchema, err)
+		}
+
+		destinationConfig.ValidatorSchema = validatorSchema
+	}
+
+	// set the validator.mode if it's not empty
+	if validatorMode := raw[ConfigKeyValidatorMode]; validatorMode != "" {
+		destinationConfig.ValidatorMode = writer.ValidatorMode(strings.ToLower(validatorMode))
+	}
+
+	if err := validator.ValidateStruct(&destinationConfig); err != nil {
+		return Config{}, fmt.Errorf("validate destination config: %w", err)
+	}
+
+	if err := validateCapped(destinationConfig); err != nil {
+		return Config{}, err
+	}
+
+	// a transaction is bound to a single session, which can't be used by multiple workers
+	if destinationConfig.WriteWorkers > 1 && destinationConfig.TransactionEnabled {
+		return Config{}, validator.NewFieldError(ConfigKeyWriteWorkers, validator.ConstraintCompatible,
+			fmt.Errorf("must be 1 if %q is true", ConfigKeyTransactionEnabled))
+	}
+
+	// a transaction is aborted once a record fails, so no other records can be written
+	if destinationConfig.WriteContinueOnError && destinationConfig.TransactionEnabled {
+		return Config{}, validator.NewFieldError(ConfigKeyWriteContinueOnError, validator.ConstraintCompatible,
+			fmt.Errorf("must be false if %q is true", ConfigKeyTransactionEnabled))
+	}
+
+	// a transaction is aborted by the failed insert, so the record can't be skipped or written otherwise
+	if destinationConfig.WriteOnDuplicateKey != writer.DuplicateKeyFail && destinationConfig.TransactionEnabled {
+		return Config{}, validator.NewFieldError(ConfigKeyWriteOnDuplicateKey, validator.ConstraintCompatible,
+			fmt.Errorf("must be %q if %q is true", writer.DuplicateKeyFail, ConfigKeyTransactionEnabled))
+	}
+
+	// make sure the condition compiles before connecting
+	if _, err := destinationConfig.Condition(); err != nil {
+		return Config{}, err
+	}
+
+	return destinationConfig, nil
+}
+
+// Condition returns the condition records must satisfy to be written, or nil if it's not configured.
+func (c Config) Condition() (*transform.Condition, error) {
+	if c.WriteCondition == "" {
+		return nil, nil //nolint:nilnil // a nil condition means all records are written
+	}
+
+	condition, err := transform.NewCondition(c.WriteCondition)
+	if err != nil {
+		return nil, validator.NewFormatError(ConfigKeyWriteCondition, err)
+	}
+
+	return condition, nil
+}
+
+// GetCreateOptions returns the fields of the create command the collection is created with, if it's missing,
+// including the capped collection settings.
+func (c Config) GetCreateOptions() bson.D {
+	if c.CappedSize == 0 {
+		return c.CreateOptions
+	}
+
+	createOptions := append(bson.D{}, c.CreateOptions...)
+	createOptions = append(createOptions,
+		bson.E{Key: "capped", Value: true},
+		bson.E{Key: "size", Value: c.CappedSize},
+	)
+
+	if c.CappedMax > 0 {
+		createOptions = append(createOptions, bson.E{Key: "max", Value: c.CappedMax})
+	}
+
+	return createOptions
+}
+
+// GetWriteConcern returns the write concern the connector writes documents with,
+// or nil if none of its settings is set, so the server default is used.
+func (c Config) GetWriteConcern() *writeconcern.WriteConcern {
+	if c.WriteConcernW == "" && c.WriteConcernJ == nil && c.WriteConcernWTimeout == 0 {
+		return nil
+	}
+
+	writeConcern := &writeconcern.WriteConcern{
+		Journal:  c.WriteConcernJ,
+		WTimeout: c.WriteConcernWTimeout,
+	}
+
+	// the w is either a number of nodes or a string like "majority"
+	if c.WriteConcernW != "" {
+		writeConcern.W = c.WriteConcernW
+		if w, err := strconv.Atoi(c.WriteConcernW); err == nil {
+			writeConcern.W = w
+		}
+	}
+
+	return writeConcern
+}
+
+// parseWriteConcern parses the write concern settings into the destination config if they're not empty.
+func parseWriteConcern(raw map[string]string, destinationConfig *Config) error {
+	// set the writeConcern.w if it's not empty
+	if w := strings.TrimSpace(raw[ConfigKeyWriteConcernW]); w != "" {
+		if number, err := strconv.Atoi(w); err == nil && number < 0 {
+			return validator.NewFormatError(ConfigKeyWriteConcernW, errNegativeWriteConcernW)
+		}
+
+		destinationConfig.WriteConcernW = w
+	}
+
+	// parse writeConcern.j if it's not empty
+	if jStr := raw[ConfigKeyWriteConcernJ]; jStr != "" {
+		j, err := strconv.ParseBool(jStr)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyWriteConcernJ, err)
+		}
+
+		destinationConfig.WriteConcernJ = &j
+	}
+
+	// parse writeConcern.wtimeout if it's not empty
+	if wTimeoutStr := raw[ConfigKeyWriteConcernWTimeout]; wTimeoutStr != "" {
+		wTimeout, err := time.ParseDuration(wTimeoutStr)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyWriteConcernWTimeout, err)
+		}
+
+		destinationConfig.WriteConcernWTimeout = wTimeout
+	}
+
+	return nil
+}
+
+// parseMetadata parses the metadata settings into the destination config if they're not empty.
+func parseMetadata(raw map[string]string, destinationConfig *Config) error {
+	// set the metadata.field if it's not empty
+	if field := strings.TrimSpace(raw[ConfigKeyMetadataField]); field != "" {
+		if field == "_id" || strings.HasPrefix(field, "$") || strings.Contains(field, ".") {
+			return validator.NewFormatError(ConfigKeyMetadataField, errInvalidMetadataField)
+		}
+
+		destinationConfig.MetadataField = field
+	}
+
+	// set the metadata.keys if it's present
+	if keys, ok := raw[ConfigKeyMetadataKeys]; ok {
+		destinationConfig.MetadataKeys = parseList(keys)
+	}
+
+	// parse metadata.position if it's not empty
+	if positionStr := raw[ConfigKeyMetadataPosition]; positionStr != "" {
+		position, err := strconv.ParseBool(positionStr)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyMetadataPosition, err)
+		}
+
+		destinationConfig.MetadataPosition = position
+	}
+
+	return nil
+}
+
+// parseTTL parses the TTL index settings into the destination config if they're not empty.
+func parseTTL(raw map[string]string, destinationConfig *Config) error {
+	// set the ttl.field if it's not empty
+	if field := strings.TrimSpace(raw[ConfigKeyTTLField]); field != "" {
+		if field == "_id" || strings.HasPrefix(field, "$") {
+			return validator.NewFormatError(ConfigKeyTTLField, errInvalidTTLField)
+		}
+
+		destinationConfig.TTLField = field
+	}
+
+	// parse ttl.expireAfterSeconds if it's not empty
+	if expireAfterSecondsStr := raw[ConfigKeyTTLExpireAfterSeconds]; expireAfterSecondsStr != "" {
+		expireAfterSeconds, err := strconv.Atoi(expireAfterSecondsStr)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyTTLExpireAfterSeconds, err)
+		}
+
+		destinationConfig.TTLExpireAfterSeconds = expireAfterSeconds
+	}
+
+	return nil
+}
+
+// parseCreate parses the collection creation settings into the destination config if they're not empty.
+func parseCreate(raw map[string]string, destinationConfig *Config) error {
+	// parse createIfMissing if it's not empty
+	if createIfMissingStr := raw[ConfigKeyCreateIfMissing]; createIfMissingStr != "" {
+		createIfMissing, err := strconv.ParseBool(createIfMissingStr)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyCreateIfMissing, err)
+		}
+
+		destinationConfig.CreateIfMissing = createIfMissing
+	}
+
+	// parse createOptions if it's not empty
+	if createOptionsStr := strings.TrimSpace(raw[ConfigKeyCreateOptions]); createOptionsStr != "" {
+		var createOptions bson.D
+		if err := bson.UnmarshalExtJSON([]byte(createOptionsStr), false, &createOptions); err != nil {
+			return validator.NewFormatError(ConfigKeyCreateOptions, err)
+		}
+
+		// the collection name is the one the connector is configured with
+		for _, element := range createOptions {
+			if element.Key == "create" {
+				return validator.NewFormatError(ConfigKeyCreateOptions, errCreateOptionsName)
+			}
+		}
+
+		destinationConfig.CreateOptions = createOptions
+	}
+
+	// parse capped.size if it's not empty
+	if cappedSizeStr := raw[ConfigKeyCappedSize]; cappedSizeStr != "" {
+		cappedSize, err := strconv.ParseInt(cappedSizeStr, 10, 64)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyCappedSize, err)
+		}
+
+		destinationConfig.CappedSize = cappedSize
+	}
+
+	// parse capped.max if it's not empty
+	if cappedMaxStr := raw[ConfigKeyCappedMax]; cappedMaxStr != "" {
+		cappedMax, err := strconv.ParseInt(cappedMaxStr, 10, 64)
+		if err != nil {
+			return validator.NewFormatError(ConfigKeyCappedMax, err)
+		}
+
+		destinationConfig.CappedMax = cappedMax
+	}
+
+	return nil
+}
+
+// validateCapped makes sure the capped collection settings agree with the other collection creation settings.
+func validateCapped(destinationConfig Config) error {
+	// the number of documents alone doesn't make a collection capped, the server requires the size
+	if destinationConfig.CappedMax > 0 && destinationConfig.CappedSize == 0 {
+		return validator.NewFieldError(ConfigKeyCappedMax, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be set if %q is set", ConfigKeyCappedSize, ConfigKeyCappedMax))
+	}
+
+	if destinationConfig.CappedSize == 0 {
+		return nil
+	}
+
+	// the settings only apply to collections the connector creates
+	if !destinationConfig.CreateIfMissing {
+		return validator.NewFieldError(ConfigKeyCappedSize, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be true if %q is set", ConfigKeyCreateIfMissing, ConfigKeyCappedSize))
+	}
+
+	for _, element := range destinationConfig.CreateOptions {
+		if element.Key == "capped" || element.Key == "size" || element.Key == "max" {
+			return validator.NewFieldError(ConfigKeyCreateOptions, validator.ConstraintCompatible,
+				fmt.Errorf("must not contain the %s field if %q is set", element.Key, ConfigKeyCappedSize))
+		}
+	}
+
+	return nil
+}
+
+// parseList splits a comma-separated list and trims its elements, skipping empty ones.
+func parseList(value string) []string {
+	var list []string
+	for _, element := range strings.Split(value, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			list = append(list, element)
+		}
+	}
+
+	return list
+}
+
+// parseArrayStrategies converts a mapping of fields to strategy names into array strategies of fields.
+// Strategies are matched case-insensitively.
+func parseArrayStrategies(mapping map[string]string) (map[string]writer.ArrayStrategy, error) {
+	strategies := make(map[string]writer.ArrayStrategy, len(mapping))
+	for field, strategyStr := range mapping {
+		index := slices.IndexFunc(writer.ArrayStrategies, func(strategy writer.ArrayStrategy) bool {
+			return strings.EqualFold(string(strategy), strategyStr)
+		})
+		if index == -1 {
+			return nil, &InvalidArrayStrategyError{Field: field, Strategy: strategyStr}
+		}
+
+		strategies[field] = writer.ArrayStrategies[index]
+	}
+
+	return strategies, nil
+}
+
+// parseNamespaceMapping parses a comma-separated list of "pattern:target" pairs into namespace rules,
+// keeping their order, as the first rule a namespace matches wins.
+func parseNamespaceMapping(value string) ([]writer.NamespaceRule, error) {
+	elements := parseList(value)
+
+	rules := make([]writer.NamespaceRule, 0, len(elements))
+	for _, element := range elements {
+		pattern, target, ok := strings.Cut(element, ":")
+		if !ok {
+			return nil, &InvalidMappingError{Element: element}
+		}
+
+		rule, err := writer.NewNamespaceRule(strings.TrimSpace(pattern), strings.TrimSpace(target))
+		if err != nil {
+			return nil, fmt.Errorf("namespace rule %q: %w", element, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// parseMapping parses a comma-separated list of "from:to" pairs into a map.
+func parseMapping(value string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for _, element := range parseList(value) {
+		from, to, ok := strings.Cut(element, ":")
+		from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+		if !ok || from == "" || to == "" {
+			return nil, &InvalidMappingError{Element: element}
+		}
+
+		mapping[from] = to
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,1505 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/destination/writer"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	journal := true
+
+	ordersRule, err := writer.NewNamespaceRule("shop.orders", "orders_archive")
+	if err != nil {
+		t.Fatalf("writer.NewNamespaceRule() error = %v", err)
+	}
+
+	tenantsRule, err := writer.NewNamespaceRule("tenant_*.users", "*_users")
+	if err != nil {
+		t.Fatalf("writer.NewNamespaceRule() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		raw     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "success_required_only",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_key_from_payload_custom_fields",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyKeyFromPayload: "true",
+				ConfigKeyKeyFields:      "tenant_id, email",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      true,
+				KeyFields:           []string{"tenant_id", "email"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_indexes_replicate",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyIndexesReplicate: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				IndexesReplicate:    true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_update_strategy_flatten",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyUpdateStrategy: "Flatten",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      writer.UpdateStrategyFlatten,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_convert_uuid",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyConvertUUID: "Legacy",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         codec.UUIDEncodingLegacy,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_convert_integers",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyConvertIntegers: "Double",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     codec.IntegerDecodingDouble,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_update_arrays",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyUpdateArrays: "tags:AddToSet,events:push",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload: defaultKeyFromPayload,
+				KeyFields:      []string{"_id"},
+				UpdateStrategy: defaultUpdateStrategy,
+				UpdateArrays: map[string]writer.ArrayStrategy{
+					"tags":   writer.ArrayStrategyAddToSet,
+					"events": writer.ArrayStrategyPush,
+				},
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_update_strategy_delta",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyUpdateStrategy: "delta",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      writer.UpdateStrategyDelta,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_key_mapping",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyKeyMapping:  "tenantId:tenant.id, sku : code",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				KeyMapping:          map[string]string{"tenantId": "tenant.id", "sku": "code"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_namespace_mapping",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyNamespaceMapping: "shop.orders:orders_archive, tenant_*.users : *_users",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				NamespaceMapping:    []writer.NamespaceRule{ordersRule, tenantsRule},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_write_concern",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyWriteConcernW:        "majority",
+				ConfigKeyWriteConcernJ:        "true",
+				ConfigKeyWriteConcernWTimeout: "5s",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:       defaultKeyFromPayload,
+				KeyFields:            []string{"_id"},
+				UpdateStrategy:       defaultUpdateStrategy,
+				MetadataKeys:         []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:     true,
+				ValidatorMode:        defaultValidatorMode,
+				ConvertUUID:          defaultConvertUUID,
+				ConvertIntegers:      defaultConvertIntegers,
+				WriteWorkers:         defaultWriteWorkers,
+				WriteOnDuplicateKey:  defaultWriteOnDuplicateKey,
+				WriteConcernW:        "majority",
+				WriteConcernJ:        &journal,
+				WriteConcernWTimeout: 5 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_transaction_enabled",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyTransactionEnabled: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				TransactionEnabled:  true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_batch_deletes_last",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyBatchDeletesLast: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				BatchDeletesLast:    true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_write_max_retries",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyWriteMaxRetries: "3",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				WriteMaxRetries:     3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_write_on_duplicate_key_skip",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyWriteOnDuplicateKey: "Skip",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: writer.DuplicateKeySkip,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_write_workers",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyWriteWorkers: "8",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        8,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_write_continue_on_error",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyWriteContinueOnError: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:       defaultKeyFromPayload,
+				KeyFields:            []string{"_id"},
+				UpdateStrategy:       defaultUpdateStrategy,
+				MetadataKeys:         []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:     true,
+				ValidatorMode:        defaultValidatorMode,
+				ConvertUUID:          defaultConvertUUID,
+				ConvertIntegers:      defaultConvertIntegers,
+				WriteWorkers:         defaultWriteWorkers,
+				WriteOnDuplicateKey:  defaultWriteOnDuplicateKey,
+				WriteContinueOnError: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_metadata",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyMetadataField:    "_meta",
+				ConfigKeyMetadataKeys:     "opencdc.collection",
+				ConfigKeyMetadataPosition: "false",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataField:       "_meta",
+				MetadataKeys:        []string{"opencdc.collection"},
+				MetadataPosition:    false,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_ttl",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeyTTLField:              " createdAt ",
+				ConfigKeyTTLExpireAfterSeconds: "3600",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:        defaultKeyFromPayload,
+				KeyFields:             []string{"_id"},
+				UpdateStrategy:        defaultUpdateStrategy,
+				MetadataKeys:          []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:      true,
+				ValidatorMode:         defaultValidatorMode,
+				ConvertUUID:           defaultConvertUUID,
+				ConvertIntegers:       defaultConvertIntegers,
+				WriteWorkers:          defaultWriteWorkers,
+				WriteOnDuplicateKey:   defaultWriteOnDuplicateKey,
+				TTLField:              "createdAt",
+				TTLExpireAfterSeconds: 3600,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_create_if_missing",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "true",
+				ConfigKeyCreateOptions:   `{"capped": true, "size": 1048576}`,
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				CreateIfMissing:     true,
+				CreateOptions:       bson.D{{Key: "capped", Value: true}, {Key: "size", Value: int32(1048576)}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_capped",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "true",
+				ConfigKeyCappedSize:      "1048576",
+				ConfigKeyCappedMax:       "1000",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				CreateIfMissing:     true,
+				CappedSize:          1048576,
+				CappedMax:           1000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_validator",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyValidatorSchema: `{"required": ["email"]}`,
+				ConfigKeyValidatorMode:   "VERIFY",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorSchema:     bson.D{{Key: "required", Value: bson.A{"email"}}},
+				ValidatorMode:       writer.ValidatorModeVerify,
+				ConvertUUID:         codec.UUIDEncodingNone,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_write_condition",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyWriteCondition: ` operation != "delete" `,
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				KeyFromPayload:      defaultKeyFromPayload,
+				KeyFields:           []string{"_id"},
+				UpdateStrategy:      defaultUpdateStrategy,
+				MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+				MetadataPosition:    true,
+				ValidatorMode:       defaultValidatorMode,
+				ConvertUUID:         defaultConvertUUID,
+				ConvertIntegers:     defaultConvertIntegers,
+				WriteWorkers:        defaultWriteWorkers,
+				WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
+				WriteCondition:      `operation != "delete"`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "fail_invalid_common_config_missing_required",
+			raw: map[string]string{
+				config.KeyDB: "test",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_key_from_payload",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyKeyFromPayload: "yes",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_indexes_replicate",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyIndexesReplicate: "maybe",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_update_strategy",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyUpdateStrategy: "merge",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_key_mapping",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyKeyMapping:  "tenantId:tenant.id,sku",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_namespace_mapping",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyNamespaceMapping: "shop.orders",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_namespace_mapping_too_many_wildcards",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyNamespaceMapping: "shop.*:*_*",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_write_concern_w",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyWriteConcernW: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_write_concern_j",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyWriteConcernJ: "always",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_write_concern_wtimeout",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyWriteConcernWTimeout: "5",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_write_max_retries",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyWriteMaxRetries: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_metadata_field",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyMetadataField: "meta.lineage",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_ttl_field",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyTTLField:    "_id",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_ttl_expire_after_seconds",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeyTTLField:              "createdAt",
+				ConfigKeyTTLExpireAfterSeconds: "an hour",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_ttl_expire_after_seconds",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeyTTLField:              "createdAt",
+				ConfigKeyTTLExpireAfterSeconds: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_create_if_missing",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "please",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_create_options",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCreateOptions: "capped",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_create_options_with_name",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCreateOptions: `{"create": "events"}`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_capped_size",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "true",
+				ConfigKeyCappedSize:      "1MB",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_capped_max",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "true",
+				ConfigKeyCappedSize:      "1048576",
+				ConfigKeyCappedMax:       "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_capped_max_without_size",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "true",
+				ConfigKeyCappedMax:       "1000",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_capped_without_create_if_missing",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyCappedSize:  "1048576",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_capped_with_create_options_size",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCreateIfMissing: "true",
+				ConfigKeyCreateOptions:   `{"size": 4096}`,
+				ConfigKeyCappedSize:      "1048576",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_update_arrays_strategy",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyUpdateArrays: "tags:append",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_update_arrays_mapping",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyUpdateArrays: "tags",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_uuid",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyConvertUUID: "binary",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_integers",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyConvertIntegers: "float",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_zero_write_workers",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyWriteWorkers: "0",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_write_workers_with_transaction",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyWriteWorkers:       "4",
+				ConfigKeyTransactionEnabled: "true",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_write_continue_on_error_with_transaction",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyWriteContinueOnError: "true",
+				ConfigKeyTransactionEnabled:   "true",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_write_on_duplicate_key_with_transaction",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyWriteOnDuplicateKey: "update",
+				ConfigKeyTransactionEnabled:  "true",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_write_on_duplicate_key",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyWriteOnDuplicateKey: "ignore",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_write_continue_on_error",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyWriteContinueOnError: "maybe",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_validator_schema",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyValidatorSchema: "required",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_validator_mode",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyValidatorMode: "enforce",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_write_condition",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyWriteCondition: `operation ==`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_transaction_enabled",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyTransactionEnabled: "sure",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_batch_deletes_last",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyBatchDeletesLast: "sure",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_key_from_payload_empty_key_fields",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyKeyFromPayload: "true",
+				ConfigKeyKeyFields:      " , ",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseConfig(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_GetCreateOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config Config
+		want   bson.D
+	}{
+		{
+			name:   "not_set",
+			config: Config{},
+			want:   nil,
+		},
+		{
+			name:   "create_options_only",
+			config: Config{CreateOptions: bson.D{{Key: "timeseries", Value: bson.D{{Key: "timeField", Value: "ts"}}}}},
+			want:   bson.D{{Key: "timeseries", Value: bson.D{{Key: "timeField", Value: "ts"}}}},
+		},
+		{
+			name:   "capped_size",
+			config: Config{CappedSize: 4096},
+			want:   bson.D{{Key: "capped", Value: true}, {Key: "size", Value: int64(4096)}},
+		},
+		{
+			name: "capped_size_and_max_with_create_options",
+			config: Config{
+				CreateOptions: bson.D{{Key: "comment", Value: "logs"}},
+				CappedSize:    4096,
+				CappedMax:     100,
+			},
+			want: bson.D{
+				{Key: "comment", Value: "logs"},
+				{Key: "capped", Value: true},
+				{Key: "size", Value: int64(4096)},
+				{Key: "max", Value: int64(100)},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.config.GetCreateOptions(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Config.GetCreateOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_GetWriteConcern(t *testing.T) {
+	t.Parallel()
+
+	journal := true
+
+	tests := []struct {
+		name   string
+		config Config
+		want   *writeconcern.WriteConcern
+	}{
+		{
+			name:   "not_set",
+			config: Config{},
+			want:   nil,
+		},
+		{
+			name:   "number_of_nodes",
+			config: Config{WriteConcernW: "2", WriteConcernWTimeout: time.Second},
+			want:   &writeconcern.WriteConcern{W: 2, WTimeout: time.Second},
+		},
+		{
+			name:   "majority_with_journal",
+			config: Config{WriteConcernW: "majority", WriteConcernJ: &journal},
+			want:   &writeconcern.WriteConcern{W: "majority", Journal: &journal},
+		},
+		{
+			name:   "journal_only",
+			config: Config{WriteConcernJ: &journal},
+			want:   &writeconcern.WriteConcern{Journal: &journal},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tt.config.GetWriteConcern(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Config.GetWriteConcern() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
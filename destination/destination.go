@@ -18,13 +18,17 @@ package destination
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/common"
 	mconfig "github.com/conduitio-labs/conduit-connector-mongo/config"
 	"github.com/conduitio-labs/conduit-connector-mongo/destination/writer"
+	"github.com/conduitio-labs/conduit-connector-mongo/metrics"
+	"github.com/conduitio-labs/conduit-connector-mongo/transform"
 	"github.com/conduitio/conduit-commons/config"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
@@ -33,9 +37,13 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// metricsNameSuffix is appended to the connector ID to get the name the destination's metrics are published under.
+const metricsNameSuffix = ".destination"
+
 // Writer defines a writer interface needed for the [Destination].
 type Writer interface {
 	Write(ctx context.Context, record opencdc.Record) error
+	CreatePendingIndexes(ctx context.Context) error
 }
 
 // Destination Mongo Connector persists records to a MongoDB.
@@ -44,12 +52,31 @@ type Destination struct {
 
 	writer Writer
 	client *mongo.Client
-	config mconfig.Config
+	config Config
+	// condition is the condition records must satisfy to be written. If it's nil, all records are written.
+	condition *transform.Condition
+	// metrics are the metrics of the records the destination writes.
+	metrics *metrics.Destination
 }
 
 // NewDestination creates new instance of the Destination.
 func NewDestination() sdk.Destination {
-	return sdk.DestinationWithMiddleware(&Destination{}, sdk.DefaultDestinationMiddleware()...)
+	return sdk.DestinationWithMiddleware(
+		&Destination{metrics: &metrics.Destination{}},
+		sdk.DefaultDestinationMiddleware()...,
+	)
+}
+
+// NewDestinationWithConfig creates a new instance of the [Destination] that's already configured
+// with the parsed config. Unlike [NewDestination], it returns the destination without the SDK middleware,
+// so it can be embedded into Go programs.
+func NewDestinationWithConfig(cfg Config) *Destination {
+	return &Destination{config: cfg, metrics: &metrics.Destination{}}
+}
+
+// Metrics returns the metrics of the records the destination writes.
+func (d *Destination) Metrics() *metrics.Destination {
+	return d.metrics
 }
 
 // Parameters is a map of named Parameters that describe how to configure the Destination.
@@ -100,12 +127,236 @@ func (d *Destination) Parameters() config.Parameters {
 			Default:     "",
 			Description: "The path to the client certificate file or the client private key file.",
 		},
+		mconfig.KeyAuthTLSInsecureSkipVerify: {
+			Default: "false",
+			Description: "The field determines whether or not the connector skips the verification " +
+				"of the server's certificate chain and host name. It should only be used for development.",
+		},
+		mconfig.KeyAuthTLSServerName: {
+			Default:     "",
+			Description: "The host name used to verify the server's certificate instead of the one from the URI.",
+		},
+		mconfig.KeyAuthTLSMinVersion: {
+			Default:     "",
+			Description: "The minimum TLS version. The available values are 1.0, 1.1, 1.2 and 1.3.",
+		},
+		mconfig.KeyAtlasServerless: {
+			Default: "auto",
+			Description: "The Atlas Serverless compatibility mode. " +
+				"The available values are auto, enabled and disabled.",
+		},
+		mconfig.KeyBufferPoolEnabled: {
+			Default: "true",
+			Description: "The field determines whether or not records are serialized into pooled buffers, " +
+				"which reduces GC pressure under sustained load. Disabling it may be useful for debugging.",
+		},
+		mconfig.KeyTelemetryEnabled: {
+			Default: "true",
+			Description: "The field determines whether or not the client logs and monitors its operations, " +
+				"e.g. driver logs enabled via the MONGODB_LOG_* environment variables and collection statistics.",
+		},
+		mconfig.KeyCommandMonitorEnabled: {
+			Default: "false",
+			Description: "The field determines whether or not the client logs failed commands and the ones " +
+				"taking at least commandMonitor.slowThreshold, along with their durations. It requires telemetry.enabled.",
+		},
+		mconfig.KeyCommandMonitorSlowThreshold: {
+			Default:     "100ms",
+			Description: "The min duration of commands the command monitor logs as slow ones.",
+		},
+		mconfig.KeyObjectIDCodecEnabled: {
+			Default: "true",
+			Description: "The field determines whether or not string values that are valid hex representations " +
+				"of ObjectIDs are written as ObjectIDs. Disabling it keeps such strings as plain strings.",
+		},
+		mconfig.KeyIDType: {
+			Default: "auto",
+			Description: "The type of _id values, applied to record keys and document filters. " +
+				"The available values are auto, objectid, uuid, int and string.",
+		},
+		mconfig.KeyOpenMaxRetries: {
+			Default: "3",
+			Description: "The max number of times the connector checks again whether its database and collection " +
+				"exist on open, if they don't. Zero means the connector fails right away.",
+		},
+		mconfig.KeyOpenRetryBackoff: {
+			Default:     "1s",
+			Description: "The delay before the first open retry, every next retry waits twice as long.",
+		},
+		mconfig.KeySRVMaxHosts: {
+			Default: "0",
+			Description: "The max number of hosts randomly selected from the DNS seedlist of a mongodb+srv URI. " +
+				"Zero means no limit.",
+		},
+		mconfig.KeySRVServiceName: {
+			Default:     "",
+			Description: "The service name of the SRV records of a mongodb+srv URI. If it's empty, \"mongodb\" is used.",
+		},
+		ConfigKeyKeyFromPayload: {
+			Default: "false",
+			Description: "The field determines whether or not the connector builds a key " +
+				"from a record payload if the record has no key.",
+		},
+		ConfigKeyKeyFields: {
+			Default:     "_id",
+			Description: "The comma-separated list of payload fields the connector builds a key from.",
+		},
+		ConfigKeyKeyMapping: {
+			Default: "",
+			Description: "The comma-separated list of \"keyField:documentField\" pairs mapping record key fields " +
+				"to document fields the connector filters documents by.",
+		},
+		ConfigKeyNamespaceMapping: {
+			Default: "",
+			Description: "The comma-separated list of \"pattern:target\" pairs routing records whose collection " +
+				"metadata matches the pattern to the target collection. A * in the pattern matches any characters, " +
+				"and a * in the target is replaced with the characters matched by the * at the same position.",
+		},
+		ConfigKeyIndexesReplicate: {
+			Default: "false",
+			Description: "The field determines whether or not the connector creates indexes " +
+				"described by collection metadata records on the target collection after a snapshot.",
+		},
+		ConfigKeyUpdateStrategy: {
+			Default: "set",
+			Description: "The way the connector applies updates to documents. " +
+				"If set to \"flatten\" the connector sets only the changed leaves of embedded documents " +
+				"using dot-notation paths instead of overwriting whole embedded documents. " +
+				"If set to \"delta\" the connector sets and unsets only the fields changed by the update, " +
+				"as described by the mongo.updateDescription metadata of records.",
+		},
+		ConfigKeyUpdateArrays: {
+			Default: "",
+			Description: "The comma-separated list of \"field:strategy\" pairs defining how the connector applies " +
+				"arrays of the fields on updates. The strategy is one of \"replace\", \"push\", \"addToSet\" " +
+				"and \"positional\". Arrays of other fields are replaced.",
+		},
+		ConfigKeyConvertUUID: {
+			Default: "none",
+			Description: "The field determines whether or not the connector writes canonical UUID strings " +
+				"as binary UUIDs. If set to \"standard\" they're written with the binary subtype 4, " +
+				"if set to \"legacy\" with the subtype 3.",
+		},
+		ConfigKeyConvertIntegers: {
+			Default: "int64",
+			Description: "The field determines whether the connector writes integral JSON numbers of keys " +
+				"and payloads as 64-bit integers, so large IDs keep their precision, or as doubles if set to \"double\".",
+		},
+		ConfigKeyTransactionEnabled: {
+			Default: "false",
+			Description: "The field determines whether or not the connector writes each batch of records " +
+				"within a single transaction, so either all of them are written or none. " +
+				"It requires a replica set or a sharded cluster.",
+		},
+		ConfigKeyBatchDeletesLast: {
+			Default: "false",
+			Description: "The field determines whether or not the connector writes deletes of a batch " +
+				"after records of other keys, but never after later records of the same key, " +
+				"preventing transient unique index violations.",
+		},
+		ConfigKeyWriteMaxRetries: {
+			Default: "0",
+			Description: "The number of times the connector retries writing a record that failed with " +
+				"a transient error. Retried records get the mongo.retry.attempts metadata field.",
+		},
+		ConfigKeyWriteWorkers: {
+			Default: "1",
+			Description: "The number of workers the connector writes records of a batch with concurrently. " +
+				"Records are distributed among the workers by their keys, so records of the same key " +
+				"are written in order. It can't be used with transactions.",
+		},
+		ConfigKeyWriteContinueOnError: {
+			Default: "false",
+			Description: "The field determines whether or not the connector keeps writing records of a batch " +
+				"after a record fails, so the error reports the index, the code and the message of every " +
+				"failed record. It can't be used with transactions.",
+		},
+		ConfigKeyWriteOnDuplicateKey: {
+			Default: "fail",
+			Description: "The policy for inserts failing with duplicate key errors, e.g. when a snapshot is replayed. " +
+				"If set to \"fail\" the connector returns the error, \"skip\" keeps the existing document, " +
+				"\"update\" applies the record as an update and \"replace\" replaces the existing document. " +
+				"It can't be used with transactions.",
+		},
+		ConfigKeyMetadataField: {
+			Default: "",
+			Description: "The name of the sub-document the connector puts the selected record metadata into, " +
+				"e.g. _meta. If it's empty, no metadata is written.",
+		},
+		ConfigKeyMetadataKeys: {
+			Default:     "opencdc.collection,opencdc.createdAt",
+			Description: "The comma-separated list of metadata keys the connector puts into the metadata field.",
+		},
+		ConfigKeyMetadataPosition: {
+			Default:     "true",
+			Description: "The field determines whether or not the connector puts record positions into the metadata field.",
+		},
+		ConfigKeyCreateIfMissing: {
+			Default: "false",
+			Description: "The field determines whether or not the connector creates the database and the collection " +
+				"if they don't exist, instead of failing.",
+		},
+		ConfigKeyCreateOptions: {
+			Default: "",
+			Description: "The Extended JSON document of the create command options the collection is created with, " +
+				"e.g. {\"capped\": true, \"size\": 1048576}.",
+		},
+		ConfigKeyCappedSize: {
+			Default: "",
+			Description: "The maximum size in bytes of the capped collection the connector creates, if it's missing. " +
+				"If it's empty, the collection isn't created as capped.",
+		},
+		ConfigKeyCappedMax: {
+			Default: "",
+			Description: "The maximum number of documents of the capped collection the connector creates. " +
+				"If it's empty, the number of documents is limited by the capped.size only.",
+		},
+		ConfigKeyValidatorSchema: {
+			Default: "",
+			Description: "The Extended JSON $jsonSchema document the documents of the collection must satisfy. " +
+				"If it's empty, the collection's validator is left as it is.",
+		},
+		ConfigKeyValidatorMode: {
+			Default: "install",
+			Description: "The way the connector enforces the validator. " +
+				"If set to \"install\" the connector installs it on the collection, replacing the existing one, " +
+				"if set to \"verify\" the connector fails if the collection doesn't have it.",
+		},
+		ConfigKeyWriteCondition: {
+			Default: "",
+			Description: "The expression records are evaluated against before they're written, " +
+				"records it's false for are skipped, e.g. \"operation != 'delete'\". " +
+				"If it's empty, all records are written.",
+		},
+		ConfigKeyTTLField: {
+			Default: "",
+			Description: "The name of the date field the connector creates a TTL index on, " +
+				"so documents expire and get deleted automatically. If it's empty, no TTL index is created.",
+		},
+		ConfigKeyTTLExpireAfterSeconds: {
+			Default:     "0",
+			Description: "The number of seconds after the TTL field's date documents expire in.",
+		},
+		ConfigKeyWriteConcernW: {
+			Default: "",
+			Description: "The number of nodes, \"majority\" or a custom tag that must acknowledge write operations. " +
+				"If it's empty, the server default is used.",
+		},
+		ConfigKeyWriteConcernJ: {
+			Default: "",
+			Description: "The field determines whether or not write operations must be written " +
+				"to the on-disk journal before they're acknowledged. If it's empty, the server default is used.",
+		},
+		ConfigKeyWriteConcernWTimeout: {
+			Default:     "",
+			Description: "The time limit for the write concern, e.g. \"5s\".",
+		},
 	}
 }
 
 // Configure parses and initializes the config.
 func (d *Destination) Configure(_ context.Context, cfg config.Config) error {
-	configuration, err := mconfig.Parse(cfg)
+	configuration, err := ParseConfig(cfg)
 	if err != nil {
 		return fmt.Errorf("parse config: %w", err)
 	}
@@ -118,36 +369,148 @@ func (d *Destination) Configure(_ context.Context, cfg config.Config) error {
 // Open makes sure everything is prepared to receive records.
 func (d *Destination) Open(ctx context.Context) error {
 	var err error
-	d.client, err = mongo.Connect(ctx, d.config.GetClientOptions().SetRegistry(newBSONCodecRegistry()))
+	d.condition, err = d.config.Condition()
 	if err != nil {
-		return fmt.Errorf("connect to mongo: %w", err)
+		return err
 	}
 
-	if err = d.client.Ping(ctx, nil); err != nil {
-		return fmt.Errorf("ping to mongo: %w", err)
+	registry := newBSONCodecRegistry(d.config.ObjectIDCodec(), d.config.ConvertUUID)
+
+	d.client, err = common.Connect(ctx, d.config.Config, registry)
+	if err != nil {
+		return fmt.Errorf("connect to mongo: %w", err)
 	}
 
-	collection, err := common.GetMongoCollection(ctx, d.client, d.config.DB, d.config.Collection)
+	collection, err := d.getCollection(ctx)
 	if err != nil {
-		return fmt.Errorf("get mongo collection: %w", err)
+		return err
+	}
+
+	if d.config.TelemetryEnabled {
+		common.LogCollectionStats(ctx, collection)
+	}
+
+	if d.config.TTLField != "" {
+		//nolint:gosec // the value is validated to fit into int32
+		expireAfterSeconds := int32(d.config.TTLExpireAfterSeconds)
+
+		if err := writer.EnsureTTLIndex(ctx, collection, d.config.TTLField, expireAfterSeconds); err != nil {
+			return fmt.Errorf("ensure ttl index: %w", err)
+		}
+	}
+
+	if d.config.ValidatorSchema != nil {
+		err = writer.EnsureValidator(ctx, collection, d.config.ValidatorSchema, d.config.ValidatorMode)
+		if err != nil {
+			return fmt.Errorf("ensure validator: %w", err)
+		}
+	}
+
+	var keyFields []string
+	if d.config.KeyFromPayload {
+		keyFields = d.config.KeyFields
 	}
 
-	d.writer = writer.NewWriter(collection)
+	d.writer = writer.NewWriter(writer.Params{
+		Collection:          collection,
+		KeyFields:           keyFields,
+		KeyMapping:          d.config.KeyMapping,
+		ReplicateIndexes:    d.config.IndexesReplicate,
+		UpdateStrategy:      d.config.UpdateStrategy,
+		ArrayStrategies:     d.config.UpdateArrays,
+		WriteConcern:        d.config.GetWriteConcern(),
+		Buffers:             d.config.GetBufferPool(),
+		MaxRetries:          d.config.WriteMaxRetries,
+		MetadataField:       d.config.MetadataField,
+		MetadataKeys:        d.config.MetadataKeys,
+		MetadataPosition:    d.config.MetadataPosition,
+		KeepObjectIDStrings: !d.config.ObjectIDCodec(),
+		IDType:              d.config.IDType,
+		IntegerDecoding:     d.config.ConvertIntegers,
+		NamespaceRules:      d.config.NamespaceMapping,
+		DuplicateKeyPolicy:  d.config.WriteOnDuplicateKey,
+		Metrics:             d.metrics,
+	})
+
+	if id := sdk.ConnectorIDFromContext(ctx); id != "" {
+		metrics.Publish(id+metricsNameSuffix, d.metrics)
+	}
 
 	return nil
 }
 
-func newBSONCodecRegistry() *bsoncodec.Registry {
+// getCollection returns the collection the connector writes to.
+// If the database or the collection doesn't exist, it's created if the connector is configured to.
+func (d *Destination) getCollection(ctx context.Context) (*mongo.Collection, error) {
+	// there's no point in waiting for the collection to appear if it's created right away
+	maxRetries := d.config.OpenMaxRetries
+	if d.config.CreateIfMissing {
+		maxRetries = 0
+	}
+
+	collection, err := common.GetMongoCollectionWithRetry(ctx, d.client, d.config.DB, d.config.Collection,
+		maxRetries, d.config.OpenRetryBackoff)
+
+	var notExistErr *common.NotExistError
+	if d.config.CreateIfMissing && errors.As(err, &notExistErr) {
+		sdk.Logger(ctx).Info().Err(err).Msg("creating missing mongo collection")
+
+		collection, err = common.CreateMongoCollection(ctx, d.client, d.config.DB, d.config.Collection,
+			d.config.GetCreateOptions())
+		if err != nil {
+			return nil, fmt.Errorf("create mongo collection: %w", err)
+		}
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("get mongo collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// newBSONCodecRegistry returns a registry used to encode documents.
+// If objectIDCodec is true, strings that are valid hex representations of ObjectIDs are encoded into ObjectIDs.
+// UUID strings are encoded into binary UUIDs according to the UUID encoding.
+func newBSONCodecRegistry(objectIDCodec bool, uuidEncoding codec.UUIDEncoding) *bsoncodec.Registry {
 	registry := bson.NewRegistry()
-	registry.RegisterKindEncoder(reflect.String, codec.StringObjectIDCodec{})
+
+	var stringEncoder bsoncodec.ValueEncoder
+	if objectIDCodec {
+		stringEncoder = codec.StringObjectIDCodec{}
+	}
+
+	if subtype, ok := uuidEncoding.Subtype(); ok {
+		stringEncoder = codec.StringUUIDCodec{Subtype: subtype, Fallback: stringEncoder}
+	}
+
+	if stringEncoder != nil {
+		registry.RegisterKindEncoder(reflect.String, stringEncoder)
+	}
 
 	return registry
 }
 
 // Write writes a record into a Destination.
 func (d *Destination) Write(ctx context.Context, records []opencdc.Record) (int, error) {
+	if d.config.TransactionEnabled {
+		return d.writeTransaction(ctx, records)
+	}
+
+	if d.config.WriteWorkers > 1 {
+		return d.writeConcurrently(ctx, records)
+	}
+
+	if d.config.WriteContinueOnError {
+		return d.writeContinueOnError(ctx, records)
+	}
+
+	if d.config.BatchDeletesLast {
+		return d.writeDeletesLast(ctx, records)
+	}
+
 	for i, record := range records {
-		if err := d.writer.Write(ctx, record); err != nil {
+		if err := d.write(ctx, record); err != nil {
 			return i, fmt.Errorf("write record: %w", err)
 		}
 	}
@@ -155,8 +518,126 @@ func (d *Destination) Write(ctx context.Context, records []opencdc.Record) (int,
 	return len(records), nil
 }
 
+// write writes the record if it satisfies the condition, otherwise the record is skipped,
+// so it's still reported as written.
+func (d *Destination) write(ctx context.Context, record opencdc.Record) error {
+	match, err := d.condition.Match(record)
+	if err != nil {
+		return fmt.Errorf("match condition: %w", err)
+	}
+
+	if !match {
+		return nil
+	}
+
+	return d.writer.Write(ctx, record) //nolint:wrapcheck // the error is wrapped by the caller
+}
+
+// writeDeletesLast writes records with deletes moved after records of other keys.
+// If a write fails, only the leading records of the batch that are written are reported as such.
+func (d *Destination) writeDeletesLast(ctx context.Context, records []opencdc.Record) (int, error) {
+	order := deletesLastOrder(records)
+	for n, i := range order {
+		if err := d.write(ctx, records[i]); err != nil {
+			return writtenPrefix(order, n), fmt.Errorf("write record: %w", err)
+		}
+	}
+
+	return len(records), nil
+}
+
+// writeContinueOnError writes all records of a batch, even if some of them fail, and returns an error
+// listing every failed record. Only the leading records of the batch that are written are reported as such,
+// and the SDK nacks the whole rest of the batch with the error, including the written records.
+func (d *Destination) writeContinueOnError(ctx context.Context, records []opencdc.Record) (int, error) {
+	order := make([]int, len(records))
+	for i := range order {
+		order[i] = i
+	}
+
+	if d.config.BatchDeletesLast {
+		order = deletesLastOrder(records)
+	}
+
+	written := make([]bool, len(records))
+	errs := make([]error, len(records))
+
+	for _, i := range order {
+		if err := d.write(ctx, records[i]); err != nil {
+			errs[i] = err
+
+			continue
+		}
+
+		written[i] = true
+	}
+
+	if err := newBatchWriteError(errs); err != nil {
+		return leadingWritten(written), fmt.Errorf("write records: %w", err)
+	}
+
+	return len(records), nil
+}
+
+// writeTransaction writes records within a single transaction, so either all of them are written or none.
+func (d *Destination) writeTransaction(ctx context.Context, records []opencdc.Record) (int, error) {
+	session, err := d.client.StartSession()
+	if err != nil {
+		return 0, fmt.Errorf("start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	// the whole batch is either written or not, so the order doesn't affect the written count
+	ordered := records
+	if d.config.BatchDeletesLast {
+		ordered = make([]opencdc.Record, 0, len(records))
+		for _, i := range deletesLastOrder(records) {
+			ordered = append(ordered, records[i])
+		}
+	}
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (any, error) {
+		for _, record := range ordered {
+			if err := d.write(sessionCtx, record); err != nil {
+				return nil, fmt.Errorf("write record: %w", err)
+			}
+		}
+
+		return nil, nil //nolint:nilnil // the transaction has no result
+	})
+	if err != nil {
+		return 0, fmt.Errorf("with transaction: %w", err)
+	}
+
+	// indexes can't be created within the transaction, so pending ones
+	// are created once the snapshot is completed and the transaction is committed
+	snapshotCompleted := slices.ContainsFunc(records, func(record opencdc.Record) bool {
+		return record.Operation != opencdc.OperationSnapshot
+	})
+	if snapshotCompleted {
+		if err := d.writer.CreatePendingIndexes(ctx); err != nil {
+			// the records are committed already, so the failure is reported without failing them
+			sdk.Logger(ctx).Error().Err(err).Msg("failed to create replicated indexes")
+		}
+	}
+
+	return len(records), nil
+}
+
 // Teardown gracefully closes connections.
 func (d *Destination) Teardown(ctx context.Context) error {
+	if id := sdk.ConnectorIDFromContext(ctx); id != "" {
+		metrics.Unpublish(id + metricsNameSuffix)
+	}
+
+	// indexes can still be pending if the connector is stopped before the snapshot completes,
+	// and collection metadata records are not emitted again after a restart
+	if d.writer != nil {
+		if err := d.writer.CreatePendingIndexes(ctx); err != nil {
+			return fmt.Errorf("create pending indexes: %w", err)
+		}
+	}
+
 	if d.client != nil {
 		if err := d.client.Disconnect(ctx); err != nil {
 			return fmt.Errorf("client disconnect: %w", err)
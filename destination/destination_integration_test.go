@@ -235,6 +235,63 @@ func TestDestination_Write_updateFailureNoKeys(t *testing.T) {
 	is.NoErr(err)
 }
 
+func TestDestination_Write_transactionRollback(t *testing.T) {
+	is := is.New(t)
+
+	cfg := prepareConfig(t)
+	cfg[ConfigKeyTransactionEnabled] = "true"
+
+	destination := NewDestination()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := destination.Configure(ctx, cfg)
+	is.NoErr(err)
+
+	col, err := getTestCollection(ctx, cfg[config.KeyURI], cfg[config.KeyCollection])
+	is.NoErr(err)
+
+	t.Cleanup(func() {
+		err = col.Drop(context.Background())
+		is.NoErr(err)
+
+		err = destination.Teardown(ctx)
+		is.NoErr(err)
+	})
+
+	err = destination.Open(ctx)
+	is.NoErr(err)
+
+	testItem := createTestItem(t)
+
+	// the update has no key, so the whole batch, including the insert, must be rolled back
+	n, err := destination.Write(ctx, []opencdc.Record{
+		sdk.Util.Source.NewRecordCreate(nil, nil, nil, opencdc.StructuredData(testItem)),
+		sdk.Util.Source.NewRecordUpdate(
+			nil, nil,
+			opencdc.StructuredData{},
+			opencdc.StructuredData{},
+			opencdc.StructuredData{testNameFieldName: gofakeit.LastName()},
+		),
+	})
+	is.True(errors.Is(err, writer.ErrEmptyKey))
+	is.Equal(n, 0)
+
+	c, err := col.CountDocuments(ctx, bson.D{})
+	is.NoErr(err)
+	is.Equal(c, int64(0))
+
+	// a valid batch is committed
+	n, err = destination.Write(ctx, []opencdc.Record{
+		sdk.Util.Source.NewRecordCreate(nil, nil, nil, opencdc.StructuredData(testItem)),
+	})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	compareTestPayload(ctx, t, is, col, testItem)
+}
+
 func TestDestination_Write_deleteSuccess(t *testing.T) {
 	is := is.New(t)
 
@@ -310,6 +367,193 @@ func compareTestPayload(
 	is.Equal(opencdc.StructuredData(result), testRecordPayload)
 }
 
+func TestDestination_Open_ttlIndex(t *testing.T) {
+	is := is.New(t)
+
+	cfg := prepareConfig(t)
+	cfg[ConfigKeyTTLField] = "createdAt"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col, err := getTestCollection(ctx, cfg[config.KeyURI], cfg[config.KeyCollection])
+	is.NoErr(err)
+
+	t.Cleanup(func() {
+		err = col.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	// the second open changes the expiration of the existing index
+	for _, expireAfterSeconds := range []int32{3600, 60} {
+		cfg[ConfigKeyTTLExpireAfterSeconds] = fmt.Sprint(expireAfterSeconds)
+
+		destination := NewDestination()
+
+		err = destination.Configure(ctx, cfg)
+		is.NoErr(err)
+
+		err = destination.Open(ctx)
+		is.NoErr(err)
+
+		err = destination.Teardown(ctx)
+		is.NoErr(err)
+
+		specifications, err := col.Indexes().ListSpecifications(ctx)
+		is.NoErr(err)
+
+		var found bool
+		for _, specification := range specifications {
+			if specification.ExpireAfterSeconds != nil {
+				is.Equal(*specification.ExpireAfterSeconds, expireAfterSeconds)
+
+				found = true
+			}
+		}
+
+		is.True(found)
+	}
+}
+
+func TestDestination_Open_createIfMissing(t *testing.T) {
+	is := is.New(t)
+
+	cfg := prepareConfig(t)
+	cfg[ConfigKeyCreateIfMissing] = "true"
+	cfg[ConfigKeyCreateOptions] = `{"capped": true, "size": 1048576}`
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg[config.KeyURI]))
+	is.NoErr(err)
+
+	col := conn.Database(testDB).Collection(cfg[config.KeyCollection])
+
+	t.Cleanup(func() {
+		err = col.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	destination := NewDestination()
+
+	err = destination.Configure(ctx, cfg)
+	is.NoErr(err)
+
+	err = destination.Open(ctx)
+	is.NoErr(err)
+
+	err = destination.Teardown(ctx)
+	is.NoErr(err)
+
+	specifications, err := conn.Database(testDB).ListCollectionSpecifications(ctx, bson.M{
+		"name": cfg[config.KeyCollection],
+	})
+	is.NoErr(err)
+	is.Equal(len(specifications), 1)
+
+	capped, ok := specifications[0].Options.Lookup("capped").BooleanOK()
+	is.True(ok)
+	is.True(capped)
+}
+
+func TestDestination_Open_createCapped(t *testing.T) {
+	is := is.New(t)
+
+	cfg := prepareConfig(t)
+	cfg[ConfigKeyCreateIfMissing] = "true"
+	cfg[ConfigKeyCappedSize] = "1048576"
+	cfg[ConfigKeyCappedMax] = "1000"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conn, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg[config.KeyURI]))
+	is.NoErr(err)
+
+	col := conn.Database(testDB).Collection(cfg[config.KeyCollection])
+
+	t.Cleanup(func() {
+		err = col.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	destination := NewDestination()
+
+	err = destination.Configure(ctx, cfg)
+	is.NoErr(err)
+
+	err = destination.Open(ctx)
+	is.NoErr(err)
+
+	err = destination.Teardown(ctx)
+	is.NoErr(err)
+
+	specifications, err := conn.Database(testDB).ListCollectionSpecifications(ctx, bson.M{
+		"name": cfg[config.KeyCollection],
+	})
+	is.NoErr(err)
+	is.Equal(len(specifications), 1)
+
+	capped, ok := specifications[0].Options.Lookup("capped").BooleanOK()
+	is.True(ok)
+	is.True(capped)
+
+	size, ok := specifications[0].Options.Lookup("size").AsInt64OK()
+	is.True(ok)
+	is.Equal(size, int64(1048576))
+
+	maxDocuments, ok := specifications[0].Options.Lookup("max").AsInt64OK()
+	is.True(ok)
+	is.Equal(maxDocuments, int64(1000))
+}
+
+func TestDestination_Write_validator(t *testing.T) {
+	is := is.New(t)
+
+	cfg := prepareConfig(t)
+	cfg[ConfigKeyValidatorSchema] = `{"required": ["email"], "properties": {"email": {"bsonType": "string"}}}`
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	col, err := getTestCollection(ctx, cfg[config.KeyURI], cfg[config.KeyCollection])
+	is.NoErr(err)
+
+	t.Cleanup(func() {
+		err = col.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	// the verify mode succeeds once the validator is installed
+	for _, mode := range []writer.ValidatorMode{writer.ValidatorModeInstall, writer.ValidatorModeVerify} {
+		cfg[ConfigKeyValidatorMode] = string(mode)
+
+		destination := NewDestination()
+
+		err = destination.Configure(ctx, cfg)
+		is.NoErr(err)
+
+		err = destination.Open(ctx)
+		is.NoErr(err)
+
+		testItem := createTestItem(t)
+		testItem[testEmailFieldName] = 42
+
+		_, err = destination.Write(ctx, []opencdc.Record{sdk.Util.Source.NewRecordCreate(
+			nil,
+			nil,
+			nil,
+			opencdc.StructuredData(testItem))})
+
+		var validationErr *writer.ValidationError
+		is.True(errors.As(err, &validationErr))
+
+		err = destination.Teardown(ctx)
+		is.NoErr(err)
+	}
+}
+
 func getTestCollection(ctx context.Context, uri, collection string) (*mongo.Collection, error) {
 	conn, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
 	if err != nil {
@@ -17,13 +17,20 @@ package destination
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/config"
 	"github.com/conduitio-labs/conduit-connector-mongo/destination/mock"
+	"github.com/conduitio-labs/conduit-connector-mongo/transform"
 	"github.com/conduitio/conduit-commons/opencdc"
 	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.uber.org/mock/gomock"
 )
 
@@ -41,13 +48,33 @@ func TestDestination_Configure_success(t *testing.T) {
 
 	is.NoErr(err)
 
-	is.Equal(d.config, config.Config{
-		URI: &url.URL{
-			Scheme: "mongodb",
-			Host:   "localhost:27017",
+	is.Equal(d.config, Config{
+		Config: config.Config{
+			URI: &url.URL{
+				Scheme: "mongodb",
+				Host:   "localhost:27017",
+			},
+			DB:                          "test",
+			Collection:                  "users",
+			Serverless:                  config.ServerlessAuto,
+			BufferPoolEnabled:           true,
+			TelemetryEnabled:            true,
+			ObjectIDCodecEnabled:        true,
+			IDType:                      codec.IDTypeAuto,
+			OpenMaxRetries:              3,
+			OpenRetryBackoff:            time.Second,
+			CommandMonitorSlowThreshold: 100 * time.Millisecond,
 		},
-		DB:         "test",
-		Collection: "users",
+		KeyFromPayload:      defaultKeyFromPayload,
+		KeyFields:           []string{"_id"},
+		UpdateStrategy:      defaultUpdateStrategy,
+		MetadataKeys:        []string{"opencdc.collection", "opencdc.createdAt"},
+		MetadataPosition:    true,
+		ValidatorMode:       defaultValidatorMode,
+		ConvertUUID:         defaultConvertUUID,
+		ConvertIntegers:     defaultConvertIntegers,
+		WriteWorkers:        defaultWriteWorkers,
+		WriteOnDuplicateKey: defaultWriteOnDuplicateKey,
 	})
 }
 
@@ -64,7 +91,7 @@ func TestDestination_Configure_mechanismFailure(t *testing.T) {
 		config.KeyAuthMechanism: "not existing mechanism",
 	})
 
-	is.Equal(err.Error(), "parse config: invalid auth mechanism \"NOT EXISTING MECHANISM\"")
+	is.Equal(err.Error(), "parse config: parse common config: invalid auth mechanism \"NOT EXISTING MECHANISM\"")
 }
 
 func TestDestination_Configure_structValidateFailure(t *testing.T) {
@@ -123,6 +150,86 @@ func TestDestination_Write_failInsertRecord(t *testing.T) {
 	is.Equal(err.Error(), "write record: insert record: fail")
 }
 
+func TestDestination_Write_continueOnError(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	records := []opencdc.Record{
+		{Position: opencdc.Position("1")},
+		{Position: opencdc.Position("2")},
+		{Position: opencdc.Position("3")},
+		{Position: opencdc.Position("4")},
+	}
+
+	duplicateErr := mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "duplicate key"}},
+	}
+
+	it := mock.NewMockWriter(ctrl)
+	gomock.InOrder(
+		it.EXPECT().Write(ctx, records[0]).Return(nil),
+		it.EXPECT().Write(ctx, records[1]).Return(fmt.Errorf("insert record: %w", duplicateErr)),
+		it.EXPECT().Write(ctx, records[2]).Return(nil),
+		it.EXPECT().Write(ctx, records[3]).Return(errors.New("update record: fail")),
+	)
+
+	d := Destination{
+		writer: it,
+		config: Config{WriteContinueOnError: true},
+	}
+
+	count, err := d.Write(ctx, records)
+	is.Equal(count, 1)
+
+	var batchErr *BatchWriteError
+	is.True(errors.As(err, &batchErr))
+	is.Equal(len(batchErr.Records), 2)
+
+	is.Equal(batchErr.Records[0].Index, 1)
+	is.Equal(batchErr.Records[0].Code, 11000)
+	is.True(errors.As(batchErr.Records[0], &mongo.WriteException{}))
+
+	is.Equal(batchErr.Records[1].Index, 3)
+	is.Equal(batchErr.Records[1].Code, 0)
+	is.Equal(batchErr.Records[1].Message, "update record: fail")
+
+	is.Equal(err.Error(), "write records: 2 record(s) failed: "+
+		"record 1 (code 11000): insert record: write exception: write errors: [duplicate key]; "+
+		"record 3: update record: fail")
+}
+
+func TestDestination_Write_condition(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	condition, err := transform.NewCondition(`operation != "delete"`)
+	is.NoErr(err)
+
+	create := opencdc.Record{Operation: opencdc.OperationCreate}
+
+	it := mock.NewMockWriter(ctrl)
+	it.EXPECT().Write(ctx, create).Return(nil)
+
+	d := Destination{
+		writer:    it,
+		condition: condition,
+	}
+
+	// the skipped delete is still reported as written
+	count, err := d.Write(ctx, []opencdc.Record{create, {Operation: opencdc.OperationDelete}})
+	is.NoErr(err)
+
+	is.Equal(count, 2)
+}
+
 func TestDestination_Teardown_successWriterIsNil(t *testing.T) {
 	t.Parallel()
 
@@ -137,3 +244,24 @@ func TestDestination_Teardown_successWriterIsNil(t *testing.T) {
 	err := d.Teardown(ctx)
 	is.NoErr(err)
 }
+
+func TestNewBSONCodecRegistry_uuid(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	objectID := primitive.NewObjectID()
+	document := bson.M{"uuid": "123e4567-e89b-12d3-a456-426614174000", "objectId": objectID.Hex()}
+
+	data, err := bson.MarshalWithRegistry(newBSONCodecRegistry(true, codec.UUIDEncodingLegacy), document)
+	is.NoErr(err)
+
+	subtype, _ := bson.Raw(data).Lookup("uuid").Binary()
+	is.Equal(subtype, bson.TypeBinaryUUIDOld)
+	is.Equal(bson.Raw(data).Lookup("objectId").ObjectID(), objectID)
+
+	data, err = bson.MarshalWithRegistry(newBSONCodecRegistry(false, codec.UUIDEncodingNone), document)
+	is.NoErr(err)
+	is.Equal(bson.Raw(data).Lookup("uuid").StringValue(), "123e4567-e89b-12d3-a456-426614174000")
+	is.Equal(bson.Raw(data).Lookup("objectId").StringValue(), objectID.Hex())
+}
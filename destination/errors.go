@@ -0,0 +1,142 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/destination/writer"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InvalidMappingError occurs when an element of a mapping is not a "from:to" pair.
+type InvalidMappingError struct {
+	Element string
+}
+
+// Error returns a formatted error message for the [InvalidMappingError].
+func (e *InvalidMappingError) Error() string {
+	return fmt.Sprintf("invalid mapping element %q, expected a \"from:to\" pair", e.Element)
+}
+
+// InvalidArrayStrategyError occurs when a field of the update.arrays field is mapped to an unknown array strategy.
+type InvalidArrayStrategyError struct {
+	Field    string
+	Strategy string
+}
+
+// Error returns a formatted error message for the [InvalidArrayStrategyError].
+func (e *InvalidArrayStrategyError) Error() string {
+	return fmt.Sprintf("invalid array strategy %q of field %q, expected one of %v",
+		e.Strategy, e.Field, writer.ArrayStrategies)
+}
+
+// RecordWriteError describes a record of a batch that failed to be written.
+type RecordWriteError struct {
+	// Index is the index of the record in the batch.
+	Index int
+	// Code is the code of the MongoDB error the record failed with, or zero if it's not a server error.
+	Code int
+	// Message is the message of the error the record failed with.
+	Message string
+
+	err error
+}
+
+// Error returns a formatted error message for the [RecordWriteError].
+func (e *RecordWriteError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("record %d: %s", e.Index, e.Message)
+	}
+
+	return fmt.Sprintf("record %d (code %d): %s", e.Index, e.Code, e.Message)
+}
+
+// Unwrap returns the error the record failed with.
+func (e *RecordWriteError) Unwrap() error {
+	return e.err
+}
+
+// BatchWriteError occurs when records of a batch fail to be written, and reports every failed record.
+type BatchWriteError struct {
+	Records []*RecordWriteError
+}
+
+// Error returns a formatted error message for the [BatchWriteError].
+func (e *BatchWriteError) Error() string {
+	messages := make([]string, len(e.Records))
+	for i, record := range e.Records {
+		messages[i] = record.Error()
+	}
+
+	return fmt.Sprintf("%d record(s) failed: %s", len(e.Records), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the failed records.
+func (e *BatchWriteError) Unwrap() []error {
+	errs := make([]error, len(e.Records))
+	for i, record := range e.Records {
+		errs[i] = record
+	}
+
+	return errs
+}
+
+// newBatchWriteError returns a [BatchWriteError] of the errors of a batch's records, which are nil
+// for the written records, or nil if all records are written.
+func newBatchWriteError(errs []error) error {
+	var records []*RecordWriteError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		records = append(records, &RecordWriteError{
+			Index:   i,
+			Code:    errorCode(err),
+			Message: err.Error(),
+			err:     err,
+		})
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	return &BatchWriteError{Records: records}
+}
+
+// errorCode returns the code of the MongoDB server error, or zero if the error is not a server error.
+func errorCode(err error) int {
+	var writeException mongo.WriteException
+	if errors.As(err, &writeException) {
+		if len(writeException.WriteErrors) > 0 {
+			return writeException.WriteErrors[0].Code
+		}
+
+		if writeException.WriteConcernError != nil {
+			return writeException.WriteConcernError.Code
+		}
+	}
+
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) {
+		return int(commandErr.Code)
+	}
+
+	return 0
+}
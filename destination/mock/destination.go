@@ -41,6 +41,20 @@ func (m *MockWriter) EXPECT() *MockWriterMockRecorder {
 	return m.recorder
 }
 
+// CreatePendingIndexes mocks base method.
+func (m *MockWriter) CreatePendingIndexes(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingIndexes", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePendingIndexes indicates an expected call of CreatePendingIndexes.
+func (mr *MockWriterMockRecorder) CreatePendingIndexes(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingIndexes", reflect.TypeOf((*MockWriter)(nil).CreatePendingIndexes), ctx)
+}
+
 // Write mocks base method.
 func (m *MockWriter) Write(ctx context.Context, record opencdc.Record) error {
 	m.ctrl.T.Helper()
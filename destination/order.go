@@ -0,0 +1,95 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// deletesLastOrder returns the order records of a batch are written in, so deletes are written
+// after records of other keys. A delete is never moved after a later record of the same key,
+// so the final state of every document stays the same. Records without keys can't be matched
+// to deletes, so all deletes preceding them are written before them.
+func deletesLastOrder(records []opencdc.Record) []int {
+	order := make([]int, 0, len(records))
+
+	var deletes []int
+	deleteKeys := make(map[string]int)
+
+	for i, record := range records {
+		if record.Operation == opencdc.OperationDelete {
+			deletes = append(deletes, i)
+			deleteKeys[recordKey(record)]++
+
+			continue
+		}
+
+		key := recordKey(record)
+		if key != "" && deleteKeys[key] == 0 {
+			order = append(order, i)
+
+			continue
+		}
+
+		// the pending deletes of the key must be written first,
+		// or all of them if the record has no key to match them by
+		var pending []int
+		for _, j := range deletes {
+			if deleteKey := recordKey(records[j]); key == "" || deleteKey == key {
+				order = append(order, j)
+				deleteKeys[deleteKey]--
+
+				continue
+			}
+
+			pending = append(pending, j)
+		}
+
+		deletes = pending
+		order = append(order, i)
+	}
+
+	return append(order, deletes...)
+}
+
+// writtenPrefix returns the number of leading records of a batch that are written,
+// if the records are written in the order and the first n of them succeed.
+func writtenPrefix(order []int, n int) int {
+	written := make([]bool, len(order))
+	for _, i := range order[:n] {
+		written[i] = true
+	}
+
+	return leadingWritten(written)
+}
+
+// recordKey returns a string identifying the key of a record, or an empty string if the record has no key.
+func recordKey(record opencdc.Record) string {
+	if record.Key == nil {
+		return ""
+	}
+
+	return string(record.Key.Bytes())
+}
+
+// leadingWritten returns the number of leading records that are written.
+func leadingWritten(written []bool) int {
+	n := 0
+	for n < len(written) && written[n] {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,90 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestDeletesLastOrder(t *testing.T) {
+	t.Parallel()
+
+	create := func(key string) opencdc.Record {
+		return opencdc.Record{Operation: opencdc.OperationCreate, Key: opencdc.RawData(key)}
+	}
+	update := func(key string) opencdc.Record {
+		return opencdc.Record{Operation: opencdc.OperationUpdate, Key: opencdc.RawData(key)}
+	}
+	del := func(key string) opencdc.Record {
+		return opencdc.Record{Operation: opencdc.OperationDelete, Key: opencdc.RawData(key)}
+	}
+
+	tests := []struct {
+		name    string
+		records []opencdc.Record
+		want    []int
+	}{
+		{
+			name:    "no_deletes",
+			records: []opencdc.Record{create("a"), update("b"), update("a")},
+			want:    []int{0, 1, 2},
+		},
+		{
+			name:    "deletes_after_other_keys",
+			records: []opencdc.Record{del("a"), create("b"), del("c"), update("d")},
+			want:    []int{1, 3, 0, 2},
+		},
+		{
+			name:    "delete_before_create_of_same_key",
+			records: []opencdc.Record{del("a"), del("b"), create("c"), create("a"), update("d")},
+			want:    []int{2, 0, 3, 4, 1},
+		},
+		{
+			name:    "delete_after_create_of_same_key",
+			records: []opencdc.Record{create("a"), del("a"), create("b")},
+			want:    []int{0, 2, 1},
+		},
+		{
+			name:    "record_without_key",
+			records: []opencdc.Record{del("a"), {Operation: opencdc.OperationCreate}, create("b")},
+			want:    []int{0, 1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(deletesLastOrder(tt.records), tt.want)
+		})
+	}
+}
+
+func TestWrittenPrefix(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	order := []int{1, 3, 0, 2}
+
+	is.Equal(writtenPrefix(order, 0), 0)
+	is.Equal(writtenPrefix(order, 2), 0)
+	is.Equal(writtenPrefix(order, 3), 2)
+	is.Equal(writtenPrefix(order, 4), 4)
+}
@@ -0,0 +1,127 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// writeConcurrently writes records with the configured number of workers. Records are distributed
+// among the workers by their keys, so records of the same key are written by the same worker in order,
+// while records of different keys are written in parallel. Once a write fails, the workers stop,
+// unless the connector continues on errors, and only the leading records of the batch that are written
+// are reported as such.
+func (d *Destination) writeConcurrently(ctx context.Context, records []opencdc.Record) (int, error) {
+	groups := workerGroups(records, d.config.WriteWorkers, d.config.BatchDeletesLast)
+
+	written := make([]bool, len(records))
+	errs := make([]error, len(records))
+
+	var (
+		failed atomic.Bool
+		wg     sync.WaitGroup
+	)
+
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for _, i := range group {
+				if failed.Load() {
+					return
+				}
+
+				if err := d.write(ctx, records[i]); err != nil {
+					errs[i] = err
+					if d.config.WriteContinueOnError {
+						continue
+					}
+
+					failed.Store(true)
+
+					return
+				}
+
+				written[i] = true
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if d.config.WriteContinueOnError {
+		if err := newBatchWriteError(errs); err != nil {
+			return leadingWritten(written), fmt.Errorf("write records: %w", err)
+		}
+
+		return len(records), nil
+	}
+
+	// the error of the earliest failed record is returned, as later records aren't reported as written anyway
+	for _, err := range errs {
+		if err != nil {
+			return leadingWritten(written), fmt.Errorf("write record: %w", err)
+		}
+	}
+
+	return len(records), nil
+}
+
+// workerGroups distributes indexes of the records among the workers by hashes of the records' keys.
+// Records without keys are all written by the same worker. If deletes are written last,
+// each worker's records are ordered the same way as by [deletesLastOrder].
+func workerGroups(records []opencdc.Record, workers int, deletesLast bool) [][]int {
+	groups := make([][]int, workers)
+	for i, record := range records {
+		hash := fnv.New32a()
+		// writing into a hash never fails
+		_, _ = hash.Write([]byte(recordKey(record)))
+
+		worker := hash.Sum32() % uint32(workers) //nolint:gosec // the number of workers is validated to be small
+		groups[worker] = append(groups[worker], i)
+	}
+
+	if !deletesLast {
+		return groups
+	}
+
+	for w, group := range groups {
+		groupRecords := make([]opencdc.Record, len(group))
+		for j, i := range group {
+			groupRecords[j] = records[i]
+		}
+
+		ordered := make([]int, len(group))
+		for j, k := range deletesLastOrder(groupRecords) {
+			ordered[j] = group[k]
+		}
+
+		groups[w] = ordered
+	}
+
+	return groups
+}
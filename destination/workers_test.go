@@ -0,0 +1,166 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package destination
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/destination/mock"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.uber.org/mock/gomock"
+)
+
+func TestWorkerGroups(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	records := make([]opencdc.Record, 0, 20)
+	for i := range 20 {
+		records = append(records, opencdc.Record{Key: opencdc.RawData(fmt.Sprintf("key-%d", i%5))})
+	}
+
+	groups := workerGroups(records, 3, false)
+	is.Equal(len(groups), 3)
+
+	total := 0
+	worker := make(map[string]int)
+
+	for w, group := range groups {
+		total += len(group)
+
+		for j, i := range group {
+			// records of the same key are written by the same worker in order
+			key := recordKey(records[i])
+			if prev, ok := worker[key]; ok {
+				is.Equal(prev, w)
+			}
+			worker[key] = w
+
+			if j > 0 {
+				is.True(group[j-1] < i)
+			}
+		}
+	}
+
+	is.Equal(total, len(records))
+}
+
+func TestWorkerGroups_deletesLast(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	records := []opencdc.Record{
+		{Operation: opencdc.OperationDelete, Key: opencdc.RawData("1")},
+		{Operation: opencdc.OperationCreate, Key: opencdc.RawData("1")},
+		{Operation: opencdc.OperationDelete, Key: opencdc.RawData("1")},
+	}
+
+	// the records share the key, so the only group is ordered the same way as the whole batch
+	groups := workerGroups(records, 2, true)
+	is.Equal(append(groups[0], groups[1]...), deletesLastOrder(records))
+}
+
+func TestDestination_Write_workers(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	records := make([]opencdc.Record, 0, 10)
+	for i := range 10 {
+		records = append(records, opencdc.Record{Key: opencdc.RawData(fmt.Sprintf("key-%d", i))})
+	}
+
+	it := mock.NewMockWriter(ctrl)
+	for _, record := range records {
+		it.EXPECT().Write(ctx, record).Return(nil)
+	}
+
+	d := Destination{
+		writer: it,
+		config: Config{WriteWorkers: 4},
+	}
+
+	count, err := d.Write(ctx, records)
+	is.NoErr(err)
+	is.Equal(count, len(records))
+}
+
+func TestDestination_Write_workersFail(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	// the records share the key, so they're written by the same worker in order
+	first := opencdc.Record{Key: opencdc.RawData("1"), Position: opencdc.Position("1")}
+	second := opencdc.Record{Key: opencdc.RawData("1"), Position: opencdc.Position("2")}
+	third := opencdc.Record{Key: opencdc.RawData("1"), Position: opencdc.Position("3")}
+
+	it := mock.NewMockWriter(ctrl)
+	gomock.InOrder(
+		it.EXPECT().Write(ctx, first).Return(nil),
+		it.EXPECT().Write(ctx, second).Return(errors.New("insert record: fail")),
+	)
+
+	d := Destination{
+		writer: it,
+		config: Config{WriteWorkers: 4},
+	}
+
+	count, err := d.Write(ctx, []opencdc.Record{first, second, third})
+	is.Equal(err.Error(), "write record: insert record: fail")
+	is.Equal(count, 1)
+}
+
+func TestDestination_Write_workersContinueOnError(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	// the records share the key, so they're written by the same worker in order
+	first := opencdc.Record{Key: opencdc.RawData("1"), Position: opencdc.Position("1")}
+	second := opencdc.Record{Key: opencdc.RawData("1"), Position: opencdc.Position("2")}
+	third := opencdc.Record{Key: opencdc.RawData("1"), Position: opencdc.Position("3")}
+
+	it := mock.NewMockWriter(ctrl)
+	gomock.InOrder(
+		it.EXPECT().Write(ctx, first).Return(errors.New("insert record: fail")),
+		it.EXPECT().Write(ctx, second).Return(nil),
+		it.EXPECT().Write(ctx, third).Return(nil),
+	)
+
+	d := Destination{
+		writer: it,
+		config: Config{WriteWorkers: 4, WriteContinueOnError: true},
+	}
+
+	count, err := d.Write(ctx, []opencdc.Record{first, second, third})
+	is.Equal(err.Error(), "write records: 1 record(s) failed: record 0: insert record: fail")
+	is.Equal(count, 0)
+}
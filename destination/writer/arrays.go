@@ -0,0 +1,98 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ArrayStrategy defines how the writer applies array fields of updates to documents.
+type ArrayStrategy string
+
+// The list of available array strategies is listed below.
+const (
+	// ArrayStrategyReplace overwrites the array with the one of the payload.
+	ArrayStrategyReplace ArrayStrategy = "replace"
+	// ArrayStrategyPush appends the elements of the payload's array to the array.
+	ArrayStrategyPush ArrayStrategy = "push"
+	// ArrayStrategyAddToSet appends the elements of the payload's array that the array doesn't contain yet.
+	ArrayStrategyAddToSet ArrayStrategy = "addToSet"
+	// ArrayStrategyPositional sets the elements of the array at the positions of the payload's array elements,
+	// leaving the elements beyond them as they are.
+	ArrayStrategyPositional ArrayStrategy = "positional"
+)
+
+const (
+	// pushCommand contains command, that used to append array elements during Update query.
+	pushCommand = "$push"
+	// addToSetCommand contains command, that used to append missing array elements during Update query.
+	addToSetCommand = "$addToSet"
+	// eachModifier makes the push and addToSet commands append every element of an array.
+	eachModifier = "$each"
+)
+
+// ArrayStrategies lists the available array strategies.
+var ArrayStrategies = []ArrayStrategy{
+	ArrayStrategyReplace, ArrayStrategyPush, ArrayStrategyAddToSet, ArrayStrategyPositional,
+}
+
+// arrayUpdate builds an update setting the fields, except for the arrays of fields with an array strategy,
+// which are applied according to it. Fields are matched by their names, which are dot-notation paths
+// of nested fields if the fields are flattened. Values of other types are set as they are.
+func (w *Writer) arrayUpdate(fields map[string]any) bson.M {
+	set := make(bson.M, len(fields))
+	update := bson.M{}
+
+	for field, value := range fields {
+		values, ok := value.([]any)
+		strategy := w.arrayStrategies[field]
+
+		switch {
+		case !ok || strategy == "" || strategy == ArrayStrategyReplace:
+			set[field] = value
+
+		case strategy == ArrayStrategyPush:
+			addCommandField(update, pushCommand, field, bson.M{eachModifier: values})
+
+		case strategy == ArrayStrategyAddToSet:
+			addCommandField(update, addToSetCommand, field, bson.M{eachModifier: values})
+
+		case strategy == ArrayStrategyPositional:
+			for i, element := range values {
+				set[field+"."+strconv.Itoa(i)] = element
+			}
+		}
+	}
+
+	// the set is kept if there's nothing else to apply, the same as without array strategies
+	if len(set) > 0 || len(update) == 0 {
+		update[setCommand] = set
+	}
+
+	return update
+}
+
+// addCommandField puts the field with the value into the command of the update, creating the command if needed.
+func addCommandField(update bson.M, command, field string, value any) {
+	fields, ok := update[command].(bson.M)
+	if !ok {
+		fields = bson.M{}
+		update[command] = fields
+	}
+
+	fields[field] = value
+}
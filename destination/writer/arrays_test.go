@@ -0,0 +1,90 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestWriter_arrayUpdate(t *testing.T) {
+	t.Parallel()
+
+	w := &Writer{arrayStrategies: map[string]ArrayStrategy{
+		"tags":     ArrayStrategyAddToSet,
+		"events":   ArrayStrategyPush,
+		"scores":   ArrayStrategyPositional,
+		"names":    ArrayStrategyReplace,
+		"nickname": ArrayStrategyPush,
+	}}
+
+	tests := []struct {
+		name   string
+		fields map[string]any
+		want   bson.M
+	}{
+		{
+			name: "all_strategies",
+			fields: map[string]any{
+				"tags":     []any{"a", "b"},
+				"events":   []any{"created"},
+				"scores":   []any{1, 2},
+				"names":    []any{"bob"},
+				"nickname": "bobby",
+				"age":      42,
+			},
+			want: bson.M{
+				setCommand: bson.M{
+					"scores.0": 1,
+					"scores.1": 2,
+					"names":    []any{"bob"},
+					"nickname": "bobby",
+					"age":      42,
+				},
+				addToSetCommand: bson.M{"tags": bson.M{eachModifier: []any{"a", "b"}}},
+				pushCommand:     bson.M{"events": bson.M{eachModifier: []any{"created"}}},
+			},
+		},
+		{
+			name:   "only_push",
+			fields: map[string]any{"events": []any{"created"}},
+			want: bson.M{
+				pushCommand: bson.M{"events": bson.M{eachModifier: []any{"created"}}},
+			},
+		},
+		{
+			name:   "no_strategies",
+			fields: map[string]any{"age": 42},
+			want:   bson.M{setCommand: bson.M{"age": 42}},
+		},
+		{
+			name:   "empty",
+			fields: map[string]any{},
+			want:   bson.M{setCommand: bson.M{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := w.arrayUpdate(tt.fields); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("arrayUpdate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// metadataFieldBinarySubtypes is a name of a record metadata field that holds a JSON object
+// with the subtypes of the payload's binary fields encoded as base64 strings, by dot-separated paths of the fields.
+// The source connector puts it on records if it's configured to convert binaries into base64 strings.
+const metadataFieldBinarySubtypes = "mongo.binarySubtypes"
+
+// decodeBinaries replaces the base64 strings of the payload's fields listed in the record's
+// binary subtypes metadata with binaries of their subtypes. Fields missing from the payload
+// or holding other values than strings, e.g. because of transforms, are left as they are.
+func decodeBinaries(payload opencdc.StructuredData, record opencdc.Record) error {
+	subtypesStr, ok := record.Metadata[metadataFieldBinarySubtypes]
+	if !ok {
+		return nil
+	}
+
+	var subtypes map[string]byte
+	if err := json.Unmarshal([]byte(subtypesStr), &subtypes); err != nil {
+		return fmt.Errorf("unmarshal %s metadata: %w", metadataFieldBinarySubtypes, err)
+	}
+
+	for path, subtype := range subtypes {
+		parentPath, field := "", path
+		if i := strings.LastIndexByte(path, '.'); i != -1 {
+			parentPath, field = path[:i], path[i+1:]
+		}
+
+		var parent any = map[string]any(payload)
+		if parentPath != "" {
+			if parent, ok = lookupPath(payload, parentPath); !ok {
+				continue
+			}
+		}
+
+		if err := decodeBinary(parent, field, subtype); err != nil {
+			return fmt.Errorf("decode %q binary field: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// decodeBinary replaces the base64 string of the field of the parent document or array
+// with a binary of the subtype. Array elements are addressed by their indexes.
+func decodeBinary(parent any, field string, subtype byte) error {
+	decode := func(value any) (any, error) {
+		str, ok := value.(string)
+		if !ok {
+			return value, nil
+		}
+
+		data, err := base64.StdEncoding.DecodeString(str)
+		if err != nil {
+			return nil, fmt.Errorf("decode base64: %w", err)
+		}
+
+		return primitive.Binary{Subtype: subtype, Data: data}, nil
+	}
+
+	switch container := parent.(type) {
+	case map[string]any:
+		value, ok := container[field]
+		if !ok {
+			return nil
+		}
+
+		decoded, err := decode(value)
+		if err != nil {
+			return err
+		}
+
+		container[field] = decoded
+
+	case []any:
+		index, err := strconv.Atoi(field)
+		if err != nil || index < 0 || index >= len(container) {
+			return nil //nolint:nilerr // elements out of the array are missing from the payload
+		}
+
+		decoded, err := decode(container[index])
+		if err != nil {
+			return err
+		}
+
+		container[index] = decoded
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecodeBinaries(t *testing.T) {
+	t.Parallel()
+
+	payload := opencdc.StructuredData{
+		"name":  "bob",
+		"photo": "ZGF0YQ==",
+		"files": []any{map[string]any{"data": "YQ=="}},
+		"tags":  []any{"YQ==", 1},
+	}
+
+	record := opencdc.Record{Metadata: opencdc.Metadata{
+		metadataFieldBinarySubtypes: `{"photo":128,"files.0.data":0,"tags.1":0,"missing.field":0,"tags.5":0}`,
+	}}
+
+	if err := decodeBinaries(payload, record); err != nil {
+		t.Fatalf("decodeBinaries() error = %v", err)
+	}
+
+	want := opencdc.StructuredData{
+		"name":  "bob",
+		"photo": primitive.Binary{Subtype: 0x80, Data: []byte("data")},
+		"files": []any{map[string]any{"data": primitive.Binary{Data: []byte("a")}}},
+		"tags":  []any{"YQ==", 1},
+	}
+
+	if !reflect.DeepEqual(payload, want) {
+		t.Errorf("decodeBinaries() payload = %v, want %v", payload, want)
+	}
+
+	record.Metadata[metadataFieldBinarySubtypes] = `{"name":0}`
+	if err := decodeBinaries(payload, record); err == nil {
+		t.Errorf("decodeBinaries() error = nil for a field that isn't base64, want error")
+	}
+
+	record.Metadata[metadataFieldBinarySubtypes] = "{"
+	if err := decodeBinaries(payload, record); err == nil {
+		t.Errorf("decodeBinaries() error = nil for invalid metadata, want error")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// metadataFieldUpdateDescription is a name of a record metadata field the source connector puts
+	// the JSON description of the fields changed by an update into.
+	metadataFieldUpdateDescription = "mongo.updateDescription"
+
+	// unsetCommand contains command, that used to remove fields during Update query.
+	unsetCommand = "$unset"
+)
+
+// updateDescription describes the fields changed by an update, as the source connector puts it into metadata.
+type updateDescription struct {
+	// UpdatedFields contains the updated fields with their new values, keyed by their dotted paths.
+	UpdatedFields map[string]any `json:"updatedFields"`
+	// RemovedFields is the list of dotted paths of the removed fields.
+	RemovedFields []string `json:"removedFields"`
+	// TruncatedArrays is the list of arrays truncated by the update.
+	TruncatedArrays []struct {
+		// Field is the dotted path of the array.
+		Field string `json:"field"`
+	} `json:"truncatedArrays"`
+}
+
+// parseUpdateDescription returns the update description of the record. It returns false if the record has none.
+// Numbers of the updated fields are decoded according to the integer decoding.
+func parseUpdateDescription(
+	record opencdc.Record, integers codec.IntegerDecoding,
+) (updateDescription, bool, error) {
+	value, ok := record.Metadata[metadataFieldUpdateDescription]
+	if !ok {
+		return updateDescription{}, false, nil
+	}
+
+	var description updateDescription
+	if err := codec.UnmarshalJSON([]byte(value), &description); err != nil {
+		return updateDescription{}, false, fmt.Errorf("unmarshal %s metadata: %w", metadataFieldUpdateDescription, err)
+	}
+
+	integers.Decode(description.UpdatedFields)
+
+	return description, true, nil
+}
+
+// update builds an update setting the updated fields and unsetting the removed ones.
+// Truncating an array along with setting its elements would conflict, so truncated arrays
+// are set as a whole from the payload. It returns false if the payload misses a truncated array.
+func (d updateDescription) update(payload opencdc.StructuredData) (bson.M, bool) {
+	set := make(map[string]any, len(d.UpdatedFields))
+	for path, value := range d.UpdatedFields {
+		if !d.truncates(path) {
+			set[path] = value
+		}
+	}
+
+	for _, array := range d.TruncatedArrays {
+		value, ok := lookupPath(payload, array.Field)
+		if !ok {
+			return nil, false
+		}
+
+		set[array.Field] = value
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update[setCommand] = bson.M(set)
+	}
+
+	if len(d.RemovedFields) > 0 {
+		unset := make(bson.M, len(d.RemovedFields))
+		for _, path := range d.RemovedFields {
+			unset[path] = ""
+		}
+
+		update[unsetCommand] = unset
+	}
+
+	return update, true
+}
+
+// truncates checks whether the path is an element of one of the truncated arrays.
+func (d updateDescription) truncates(path string) bool {
+	for _, array := range d.TruncatedArrays {
+		if path == array.Field || strings.HasPrefix(path, array.Field+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lookupPath returns the value of the dotted path in the payload. Array elements are addressed by their indexes.
+func lookupPath(payload map[string]any, path string) (any, bool) {
+	var value any = payload
+	for _, field := range strings.Split(path, ".") {
+		switch container := value.(type) {
+		case map[string]any:
+			fieldValue, ok := container[field]
+			if !ok {
+				return nil, false
+			}
+
+			value = fieldValue
+
+		case []any:
+			index, err := strconv.Atoi(field)
+			if err != nil || index < 0 || index >= len(container) {
+				return nil, false
+			}
+
+			value = container[index]
+
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
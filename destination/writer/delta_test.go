@@ -0,0 +1,156 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestParseUpdateDescription(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{Metadata: opencdc.Metadata{
+		metadataFieldUpdateDescription: `{"updatedFields":{"name":"bob"},"removedFields":["nickname"],` +
+			`"truncatedArrays":[{"field":"tags","newSize":1}]}`,
+	}}
+
+	description, ok, err := parseUpdateDescription(record, codec.IntegerDecodingInt64)
+	if err != nil {
+		t.Fatalf("parseUpdateDescription() error = %v", err)
+	}
+
+	if !ok {
+		t.Fatalf("parseUpdateDescription() ok = false, want true")
+	}
+
+	if len(description.TruncatedArrays) != 1 || description.TruncatedArrays[0].Field != "tags" {
+		t.Errorf("parseUpdateDescription() truncated arrays = %v, want the tags field", description.TruncatedArrays)
+	}
+
+	if _, ok, _ := parseUpdateDescription(opencdc.Record{}, codec.IntegerDecodingInt64); ok {
+		t.Errorf("parseUpdateDescription() ok = true for a record without the description, want false")
+	}
+
+	record.Metadata[metadataFieldUpdateDescription] = "{"
+	if _, _, err := parseUpdateDescription(record, codec.IntegerDecodingInt64); err == nil {
+		t.Errorf("parseUpdateDescription() error = nil for an invalid description, want error")
+	}
+}
+
+func TestUpdateDescription_update(t *testing.T) {
+	t.Parallel()
+
+	payload := opencdc.StructuredData{
+		"name": "bob",
+		"tags": []any{"a"},
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		want        bson.M
+		wantOK      bool
+	}{
+		{
+			name:        "set_and_unset",
+			description: `{"updatedFields":{"name":"bob","address.city":"Kyiv"},"removedFields":["nickname"]}`,
+			want: bson.M{
+				setCommand:   bson.M{"name": "bob", "address.city": "Kyiv"},
+				unsetCommand: bson.M{"nickname": ""},
+			},
+			wantOK: true,
+		},
+		{
+			name: "truncated_array",
+			description: `{"updatedFields":{"tags.0":"a"},"removedFields":[],` +
+				`"truncatedArrays":[{"field":"tags","newSize":1}]}`,
+			want: bson.M{
+				setCommand: bson.M{"tags": []any{"a"}},
+			},
+			wantOK: true,
+		},
+		{
+			name:        "truncated_array_missing_in_payload",
+			description: `{"updatedFields":{},"truncatedArrays":[{"field":"labels","newSize":1}]}`,
+			want:        nil,
+			wantOK:      false,
+		},
+		{
+			name:        "nothing_changed",
+			description: `{"updatedFields":{},"removedFields":[],"truncatedArrays":[]}`,
+			want:        bson.M{},
+			wantOK:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			description, _, err := parseUpdateDescription(opencdc.Record{
+				Metadata: opencdc.Metadata{metadataFieldUpdateDescription: tt.description},
+			}, codec.IntegerDecodingInt64)
+			if err != nil {
+				t.Fatalf("parseUpdateDescription() error = %v", err)
+			}
+
+			got, ok := description.update(payload)
+			if ok != tt.wantOK {
+				t.Fatalf("updateDescription.update() ok = %v, want %v", ok, tt.wantOK)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("updateDescription.update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupPath(t *testing.T) {
+	t.Parallel()
+
+	payload := map[string]any{
+		"address": map[string]any{"city": "Kyiv"},
+		"tags":    []any{"a", map[string]any{"b": 1}},
+	}
+
+	tests := []struct {
+		path   string
+		want   any
+		wantOK bool
+	}{
+		{path: "address.city", want: "Kyiv", wantOK: true},
+		{path: "tags.1.b", want: 1, wantOK: true},
+		{path: "tags.2", want: nil, wantOK: false},
+		{path: "address.zip", want: nil, wantOK: false},
+		{path: "address.city.name", want: nil, wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := lookupPath(payload, tt.path)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lookupPath() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// pendingIndexesDelay is how long no snapshot record has to be written before the pending indexes
+	// are created, so the indexes of a collection that stays quiet after the snapshot are created too.
+	pendingIndexesDelay = time.Minute
+
+	// defaultIndexName is a name of the index MongoDB creates on the _id field of every collection.
+	defaultIndexName = "_id_"
+
+	// indexOptionsConflictCode is a code of the error MongoDB returns
+	// when an index with the same key pattern but different options already exists.
+	indexOptionsConflictCode = 85
+)
+
+// collectionMetadata is a payload of a collection metadata record.
+type collectionMetadata struct {
+	Indexes []bson.D `bson:"indexes"`
+}
+
+// isCollectionMetadataRecord checks whether the record describes a collection structure.
+func isCollectionMetadataRecord(record opencdc.Record) bool {
+	return record.Metadata[codec.MetadataFieldRecordType] == codec.RecordTypeCollectionMetadata
+}
+
+// collectIndexes stores index specifications of the collection metadata record
+// if the writer replicates indexes, so they're created after the snapshot is completed.
+func (w *Writer) collectIndexes(record opencdc.Record) error {
+	if !w.replicateIndexes || record.Payload.After == nil {
+		return nil
+	}
+
+	indexes, err := parseIndexes(record.Payload.After.Bytes())
+	if err != nil {
+		return fmt.Errorf("parse indexes: %w", err)
+	}
+
+	w.indexesMu.Lock()
+	defer w.indexesMu.Unlock()
+
+	w.pendingIndexes = append(w.pendingIndexes, indexes...)
+
+	return nil
+}
+
+// CreatePendingIndexes creates indexes collected from collection metadata records on the target collection.
+// It does nothing if there are no pending indexes or the context carries a session.
+// The indexes are created only once, so if it fails, they're not pending anymore.
+func (w *Writer) CreatePendingIndexes(ctx context.Context) error {
+	w.indexesMu.Lock()
+	defer w.indexesMu.Unlock()
+
+	// indexes can't be created on existing collections within transactions,
+	// so they're kept pending until the caller creates them after the commit
+	if len(w.pendingIndexes) == 0 || mongo.SessionFromContext(ctx) != nil {
+		return nil
+	}
+
+	if w.indexesTimer != nil {
+		w.indexesTimer.Stop()
+	}
+
+	indexes := w.pendingIndexes
+	w.pendingIndexes = nil
+
+	// the specifications are passed as they are in order to keep all their options,
+	// like uniqueness, partial filter expressions or collations
+	err := w.collection.Database().RunCommand(ctx, bson.D{
+		{Key: "createIndexes", Value: w.collection.Name()},
+		{Key: "indexes", Value: indexes},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("run createIndexes command: %w", err)
+	}
+
+	return nil
+}
+
+// createPendingIndexes creates the pending indexes and logs the failure,
+// so the record that completes the snapshot is written regardless.
+func (w *Writer) createPendingIndexes(ctx context.Context) {
+	if err := w.CreatePendingIndexes(ctx); err != nil {
+		sdk.Logger(ctx).Error().Err(err).Msg("failed to create replicated indexes")
+	}
+}
+
+// schedulePendingIndexes restarts the timer that creates the pending indexes, if there are any and the writer
+// replicates indexes. The last snapshot record may be followed by no other record for a long time,
+// so the snapshot is considered completed once no snapshot record is written for the indexes delay.
+func (w *Writer) schedulePendingIndexes(ctx context.Context) {
+	if !w.replicateIndexes {
+		return
+	}
+
+	w.indexesMu.Lock()
+	defer w.indexesMu.Unlock()
+
+	if len(w.pendingIndexes) == 0 {
+		return
+	}
+
+	if w.indexesTimer != nil {
+		w.indexesTimer.Reset(w.indexesDelay)
+
+		return
+	}
+
+	// the timer outlives the write, so it only keeps the logger of its context, but not the session or deadline
+	timerCtx := sdk.Logger(ctx).WithContext(context.Background())
+	w.indexesTimer = time.AfterFunc(w.indexesDelay, func() {
+		w.createPendingIndexes(timerCtx)
+	})
+}
+
+// EnsureTTLIndex creates a TTL index on the provided date field of the collection,
+// so its documents expire the provided number of seconds after the field's date.
+// If the TTL index already exists with another expiration, the expiration is updated.
+func EnsureTTLIndex(ctx context.Context, collection *mongo.Collection, field string, expireAfterSeconds int32) error {
+	keys := bson.D{{Key: field, Value: 1}}
+
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetExpireAfterSeconds(expireAfterSeconds),
+	})
+	if err == nil {
+		return nil
+	}
+
+	var commandErr mongo.CommandError
+	if !errors.As(err, &commandErr) || commandErr.Code != indexOptionsConflictCode {
+		return fmt.Errorf("create ttl index: %w", err)
+	}
+
+	// the index exists with another expiration, so it's modified instead,
+	// it fails if the index on the field is not a TTL index
+	err = collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: keys},
+			{Key: "expireAfterSeconds", Value: expireAfterSeconds},
+		}},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("run collMod command: %w", err)
+	}
+
+	return nil
+}
+
+// parseIndexes parses index specifications from a collection metadata record payload,
+// which is a relaxed Extended JSON document. The default _id index is skipped,
+// as well as the fields that are specific to the source collection.
+func parseIndexes(payload []byte) ([]bson.D, error) {
+	var metadata collectionMetadata
+	if err := bson.UnmarshalExtJSON(payload, false, &metadata); err != nil {
+		return nil, fmt.Errorf("unmarshal extended JSON: %w", err)
+	}
+
+	indexes := make([]bson.D, 0, len(metadata.Indexes))
+	for _, index := range metadata.Indexes {
+		specification := make(bson.D, 0, len(index))
+		skip := false
+
+		for _, element := range index {
+			switch element.Key {
+			case "name":
+				skip = element.Value == defaultIndexName
+			case "v", "ns":
+				// the index version and the namespace are chosen by the target server
+				continue
+			}
+
+			specification = append(specification, element)
+		}
+
+		if !skip {
+			indexes = append(indexes, specification)
+		}
+	}
+
+	return indexes, nil
+}
@@ -0,0 +1,158 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testCollectionMetadataPayload = `{
+	"db": "test",
+	"collection": "users",
+	"options": {},
+	"indexes": [
+		{"v": 2, "key": {"_id": 1}, "name": "_id_"},
+		{"v": 2, "key": {"tenant": 1, "email": -1}, "name": "tenant_1_email_-1", "unique": true}
+	]
+}`
+
+func TestParseIndexes(t *testing.T) {
+	t.Parallel()
+
+	got, err := parseIndexes([]byte(testCollectionMetadataPayload))
+	if err != nil {
+		t.Fatalf("parseIndexes() error = %v", err)
+	}
+
+	want := []bson.D{
+		{
+			{Key: "key", Value: bson.D{{Key: "tenant", Value: int32(1)}, {Key: "email", Value: int32(-1)}}},
+			{Key: "name", Value: "tenant_1_email_-1"},
+			{Key: "unique", Value: true},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseIndexes() = %v, want %v", got, want)
+	}
+}
+
+func TestParseIndexes_invalidPayload(t *testing.T) {
+	t.Parallel()
+
+	if _, err := parseIndexes([]byte("not a document")); err == nil {
+		t.Error("parseIndexes() error = nil, want error")
+	}
+}
+
+func TestWriter_Write_collectionMetadata(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Operation: opencdc.OperationSnapshot,
+		Metadata:  opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeCollectionMetadata},
+		Payload:   opencdc.Change{After: opencdc.RawData(testCollectionMetadataPayload)},
+	}
+
+	tests := []struct {
+		name             string
+		replicateIndexes bool
+		wantPending      int
+	}{
+		{
+			name:             "replicate_indexes",
+			replicateIndexes: true,
+			wantPending:      1,
+		},
+		{
+			name:             "skip_indexes",
+			replicateIndexes: false,
+			wantPending:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{ReplicateIndexes: tt.replicateIndexes})
+
+			// the record must not reach the collection, which is nil here
+			if err := w.Write(context.Background(), record); err != nil {
+				t.Fatalf("Writer.Write() error = %v", err)
+			}
+
+			if len(w.pendingIndexes) != tt.wantPending {
+				t.Errorf("len(Writer.pendingIndexes) = %d, want %d", len(w.pendingIndexes), tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestWriter_Write_pendingIndexesTimer(t *testing.T) {
+	t.Parallel()
+
+	// nothing listens on the port, so creating the indexes fails once the server selection times out
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://localhost:1").
+		SetServerSelectionTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("mongo.Connect() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+	})
+
+	w := NewWriter(Params{Collection: client.Database("test").Collection("users"), ReplicateIndexes: true})
+	w.indexesDelay = 10 * time.Millisecond
+
+	// the snapshot ends with the collection metadata record, and no other record follows it
+	record := opencdc.Record{
+		Operation: opencdc.OperationSnapshot,
+		Metadata:  opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeCollectionMetadata},
+		Payload:   opencdc.Change{After: opencdc.RawData(testCollectionMetadataPayload)},
+	}
+
+	if err := w.Write(context.Background(), record); err != nil {
+		t.Fatalf("Writer.Write() error = %v", err)
+	}
+
+	// the failed indexes are not pending anymore, so they're not created again on every record
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		w.indexesMu.Lock()
+		pending := len(w.pendingIndexes)
+		w.indexesMu.Unlock()
+
+		if pending == 0 {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("len(Writer.pendingIndexes) = %d after the indexes delay, want 0", pending)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"sync"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// keyCacheSize is the max number of raw keys the writer keeps parsed filters of.
+// Once it's reached, the cache is cleared, so it never grows unbounded.
+const keyCacheSize = 1024
+
+// keyCache caches filters parsed from raw record keys, as documents often repeat keys
+// during bursts of updates. Hex strings of cached filters are converted into ObjectIDs
+// upfront, so the conversion isn't repeated every time a filter is encoded.
+// It's safe for concurrent use, as the destination's workers share the writer.
+type keyCache struct {
+	mu      sync.Mutex
+	filters map[string]opencdc.StructuredData
+	// convertObjectIDs determines whether hex strings of filters are converted into ObjectIDs.
+	convertObjectIDs bool
+}
+
+// newKeyCache creates a new instance of the [keyCache].
+func newKeyCache(convertObjectIDs bool) *keyCache {
+	return &keyCache{
+		filters:          make(map[string]opencdc.StructuredData),
+		convertObjectIDs: convertObjectIDs,
+	}
+}
+
+// get returns a cached filter of the raw key, if any.
+func (c *keyCache) get(rawKey opencdc.RawData) (opencdc.StructuredData, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	filter, ok := c.filters[string(rawKey)]
+
+	return filter, ok
+}
+
+// put caches the filter of the raw key, converting its hex strings into ObjectIDs if the cache is configured to.
+// The filter is modified in place, so it must not be shared with a record.
+func (c *keyCache) put(rawKey opencdc.RawData, filter opencdc.StructuredData) opencdc.StructuredData {
+	for field, value := range filter {
+		if str, ok := value.(string); ok && c.convertObjectIDs {
+			if objectID, err := primitive.ObjectIDFromHex(str); err == nil {
+				filter[field] = objectID
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.filters) >= keyCacheSize {
+		clear(c.filters)
+	}
+
+	c.filters[string(rawKey)] = filter
+
+	return filter
+}
@@ -0,0 +1,100 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWriter_parseKey_cache(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	objectID := primitive.NewObjectID()
+	key := opencdc.RawData(fmt.Sprintf(`{"id":%q,"tenant":"acme"}`, objectID.Hex()))
+
+	w := NewWriter(Params{KeyMapping: map[string]string{"id": "_id"}})
+
+	filter := w.parseKey(key)
+	is.Equal(filter, opencdc.StructuredData{"_id": objectID, "tenant": "acme"})
+
+	// the repeated key results in the very same cached filter
+	cached := w.parseKey(opencdc.RawData(key.Bytes()))
+	is.Equal(fmt.Sprintf("%p", cached), fmt.Sprintf("%p", filter))
+
+	// structured keys are not cached and their values are not converted
+	structuredKey := opencdc.StructuredData{"_id": objectID.Hex()}
+	is.Equal(w.parseKey(structuredKey), structuredKey)
+	is.Equal(len(w.keyCache.filters), 1)
+}
+
+func TestWriter_parseKey_keepObjectIDStrings(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	objectID := primitive.NewObjectID()
+	key := opencdc.RawData(fmt.Sprintf(`{"_id":%q}`, objectID.Hex()))
+
+	w := NewWriter(Params{KeepObjectIDStrings: true})
+
+	is.Equal(w.parseKey(key), opencdc.StructuredData{"_id": objectID.Hex()})
+}
+
+func TestWriter_parseKey_idType(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	w := NewWriter(Params{IDType: codec.IDTypeInt})
+
+	// raw keys are parsed from JSON into floats
+	is.Equal(w.parseKey(opencdc.RawData("42")), opencdc.StructuredData{"_id": int64(42)})
+
+	// structured keys are converted into copies, so records are left unchanged
+	structuredKey := opencdc.StructuredData{"_id": "7"}
+	is.Equal(w.parseKey(structuredKey), opencdc.StructuredData{"_id": int64(7)})
+	is.Equal(structuredKey, opencdc.StructuredData{"_id": "7"})
+
+	// the string type disables the conversion of hex strings into ObjectIDs
+	objectID := primitive.NewObjectID()
+	w = NewWriter(Params{IDType: codec.IDTypeString})
+	is.Equal(w.parseKey(opencdc.RawData(objectID.Hex())), opencdc.StructuredData{"_id": objectID.Hex()})
+}
+
+func TestKeyCache_put_bounded(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	cache := newKeyCache(true)
+	for i := range keyCacheSize + 1 {
+		cache.put(opencdc.RawData(fmt.Sprint(i)), opencdc.StructuredData{"_id": i})
+	}
+
+	// the cache is cleared once it's full, so only the last key is left
+	is.Equal(len(cache.filters), 1)
+
+	filter, ok := cache.get(opencdc.RawData(fmt.Sprint(keyCacheSize)))
+	is.True(ok)
+	is.Equal(filter, opencdc.StructuredData{"_id": keyCacheSize})
+}
@@ -0,0 +1,154 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// metadataFieldCollection is a name of a record metadata field that stores a MongoDB collection name.
+const metadataFieldCollection = "mongo.collection"
+
+// namespaceWildcard matches any sequence of characters in a namespace pattern,
+// and is replaced with the matched sequence in a target collection template.
+const namespaceWildcard = "*"
+
+var (
+	// errEmptyNamespaceRule occurs when a namespace rule has an empty pattern or target.
+	errEmptyNamespaceRule = errors.New("pattern and target must not be empty")
+	// errTooManyTargetWildcards occurs when a target template has more wildcards than its pattern.
+	errTooManyTargetWildcards = errors.New("target has more wildcards than pattern")
+)
+
+// NamespaceRule routes records of a source namespace matching its pattern to its target collection.
+type NamespaceRule struct {
+	pattern *regexp.Regexp
+	target  []string
+}
+
+// NewNamespaceRule creates a rule from a pattern, where every wildcard matches any sequence of characters,
+// and a target collection template, where every wildcard is replaced with the sequence
+// the wildcard of the pattern at the same position matched, e.g. "tenant_*.users" and "*_users".
+func NewNamespaceRule(pattern, target string) (NamespaceRule, error) {
+	if pattern == "" || target == "" {
+		return NamespaceRule{}, errEmptyNamespaceRule
+	}
+
+	parts := strings.Split(pattern, namespaceWildcard)
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+
+	rule := NamespaceRule{
+		pattern: regexp.MustCompile("^" + strings.Join(parts, "(.*)") + "$"),
+		target:  strings.Split(target, namespaceWildcard),
+	}
+
+	if len(rule.target) > len(parts) {
+		return NamespaceRule{}, fmt.Errorf("%w: %q", errTooManyTargetWildcards, target)
+	}
+
+	return rule, nil
+}
+
+// apply returns the target collection of the namespace, reporting whether the namespace matches the pattern.
+func (r NamespaceRule) apply(namespace string) (string, bool) {
+	matches := r.pattern.FindStringSubmatch(namespace)
+	if matches == nil {
+		return "", false
+	}
+
+	var target strings.Builder
+	for i, part := range r.target {
+		if i > 0 {
+			target.WriteString(matches[i])
+		}
+
+		target.WriteString(part)
+	}
+
+	return target.String(), true
+}
+
+// namespaceRouter routes records to collections by their source namespaces.
+// The handles of the target collections are cached, as records of the same namespace usually come in batches.
+type namespaceRouter struct {
+	rules        []NamespaceRule
+	collections  sync.Map
+	writeConcern *writeconcern.WriteConcern
+}
+
+// newNamespaceRouter creates a new instance of the [namespaceRouter].
+// It returns nil if there are no rules, so records are always written to the configured collection.
+func newNamespaceRouter(rules []NamespaceRule, writeConcern *writeconcern.WriteConcern) *namespaceRouter {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	return &namespaceRouter{
+		rules:        rules,
+		writeConcern: writeConcern,
+	}
+}
+
+// recordNamespace returns the source namespace of the record, taken from the OpenCDC collection metadata field
+// that multi-collection sources set, or from the one the MongoDB source sets.
+func recordNamespace(record opencdc.Record) string {
+	if namespace, ok := record.Metadata[opencdc.MetadataCollection]; ok && namespace != "" {
+		return namespace
+	}
+
+	return record.Metadata[metadataFieldCollection]
+}
+
+// route returns the collection of the first rule the record's namespace matches,
+// or the fallback one if it matches none of them.
+func (r *namespaceRouter) route(record opencdc.Record, fallback *mongo.Collection) *mongo.Collection {
+	if r == nil {
+		return fallback
+	}
+
+	namespace := recordNamespace(record)
+	for _, rule := range r.rules {
+		target, ok := rule.apply(namespace)
+		if !ok {
+			continue
+		}
+
+		if collection, ok := r.collections.Load(target); ok {
+			return collection.(*mongo.Collection) //nolint:forcetypeassert // only collections are stored
+		}
+
+		opts := options.Collection()
+		if r.writeConcern != nil {
+			opts = opts.SetWriteConcern(r.writeConcern)
+		}
+
+		collection, _ := r.collections.LoadOrStore(target, fallback.Database().Collection(target, opts))
+
+		return collection.(*mongo.Collection) //nolint:forcetypeassert // only collections are stored
+	}
+
+	return fallback
+}
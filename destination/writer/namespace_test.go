@@ -0,0 +1,175 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestNamespaceRule_apply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		pattern   string
+		target    string
+		namespace string
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:      "exact",
+			pattern:   "shop.orders",
+			target:    "orders_archive",
+			namespace: "shop.orders",
+			want:      "orders_archive",
+			wantOK:    true,
+		},
+		{
+			name:      "exact_no_match",
+			pattern:   "shop.orders",
+			target:    "orders_archive",
+			namespace: "shop.orders_v2",
+		},
+		{
+			name:      "wildcard_substituted",
+			pattern:   "tenant_*.users",
+			target:    "*_users",
+			namespace: "tenant_acme.users",
+			want:      "acme_users",
+			wantOK:    true,
+		},
+		{
+			name:      "wildcards_substituted_in_order",
+			pattern:   "*.*",
+			target:    "*__*",
+			namespace: "shop.orders",
+			want:      "shop__orders",
+			wantOK:    true,
+		},
+		{
+			name:      "wildcard_dropped",
+			pattern:   "*.migrations",
+			target:    "migrations",
+			namespace: "shop.migrations",
+			want:      "migrations",
+			wantOK:    true,
+		},
+		{
+			name:      "special_characters_quoted",
+			pattern:   "a+b",
+			target:    "c",
+			namespace: "aab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := NewNamespaceRule(tt.pattern, tt.target)
+			if err != nil {
+				t.Fatalf("NewNamespaceRule() error = %v", err)
+			}
+
+			got, ok := rule.apply(tt.namespace)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("apply() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNewNamespaceRule_invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewNamespaceRule("", "users"); !errors.Is(err, errEmptyNamespaceRule) {
+		t.Errorf("NewNamespaceRule() error = %v, want %v", err, errEmptyNamespaceRule)
+	}
+
+	if _, err := NewNamespaceRule("shop.*", "*_*"); !errors.Is(err, errTooManyTargetWildcards) {
+		t.Errorf("NewNamespaceRule() error = %v, want %v", err, errTooManyTargetWildcards)
+	}
+}
+
+func TestNamespaceRouter_route(t *testing.T) {
+	t.Parallel()
+
+	// the client connects lazily, so no server is needed to get collection handles
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:27017"))
+	if err != nil {
+		t.Fatalf("mongo.Connect() error = %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Disconnect(context.Background())
+	})
+
+	fallback := client.Database("test").Collection("users")
+
+	rule, err := NewNamespaceRule("tenant_*", "*_users")
+	if err != nil {
+		t.Fatalf("NewNamespaceRule() error = %v", err)
+	}
+
+	router := newNamespaceRouter([]NamespaceRule{rule}, nil)
+
+	tests := []struct {
+		name     string
+		router   *namespaceRouter
+		metadata opencdc.Metadata
+		want     string
+	}{
+		{
+			name:     "no_rules",
+			metadata: opencdc.Metadata{opencdc.MetadataCollection: "tenant_acme"},
+			want:     "users",
+		},
+		{
+			name:     "opencdc_collection",
+			router:   router,
+			metadata: opencdc.Metadata{opencdc.MetadataCollection: "tenant_acme", metadataFieldCollection: "orders"},
+			want:     "acme_users",
+		},
+		{
+			name:     "mongo_collection",
+			router:   router,
+			metadata: opencdc.Metadata{metadataFieldCollection: "tenant_globex"},
+			want:     "globex_users",
+		},
+		{
+			name:     "no_match",
+			router:   router,
+			metadata: opencdc.Metadata{opencdc.MetadataCollection: "orders"},
+			want:     "users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := tt.router.route(opencdc.Record{Metadata: tt.metadata}, fallback)
+			if got.Name() != tt.want || got.Database().Name() != "test" {
+				t.Errorf("route() = %s.%s, want test.%s", got.Database().Name(), got.Name(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// normalizeStructured converts the values of a structured payload, e.g. one decoded by the schema middleware
+// from an Avro-encoded record, into the values a document is built of, without serializing it into JSON,
+// so binaries, timestamps and other BSON values keep their types.
+func (w *Writer) normalizeStructured(data opencdc.StructuredData) (opencdc.StructuredData, error) {
+	payload := make(opencdc.StructuredData, len(data))
+	for field, value := range data {
+		normalized, err := w.normalizeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("normalize %q field: %w", field, err)
+		}
+
+		payload[field] = normalized
+	}
+
+	return payload, nil
+}
+
+// normalizeValue converts a value of a structured payload into a document value:
+//
+//   - Nested maps are converted into documents and slices of any type into arrays.
+//   - Integers are converted according to the integer decoding, and other numbers into doubles.
+//   - Binaries, timestamps and BSON values are kept as they are.
+//   - Any other value, e.g. a struct, is normalized through JSON the way raw JSON payloads are.
+func (w *Writer) normalizeValue(value any) (any, error) {
+	switch value := value.(type) {
+	case nil, bool, string, float64, []byte, time.Time,
+		primitive.ObjectID, primitive.Decimal128, primitive.Binary, primitive.DateTime, primitive.Timestamp:
+		return value, nil
+
+	case json.Number:
+		return w.integers.Decode(value), nil
+
+	case opencdc.StructuredData:
+		return w.normalizeMap(value)
+
+	case map[string]any:
+		return w.normalizeMap(value)
+	}
+
+	reflectValue := reflect.ValueOf(value)
+
+	//nolint:exhaustive // the other kinds are normalized through JSON
+	switch reflectValue.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return w.normalizeInteger(reflectValue.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if integer := reflectValue.Uint(); integer <= math.MaxInt64 {
+			return w.normalizeInteger(int64(integer)), nil
+		}
+
+		// integers out of the 64-bit range are written as doubles
+		return float64(reflectValue.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		return reflectValue.Float(), nil
+
+	case reflect.Slice, reflect.Array:
+		array := make([]any, reflectValue.Len())
+		for i := range array {
+			element, err := w.normalizeValue(reflectValue.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+
+			array[i] = element
+		}
+
+		return array, nil
+
+	default:
+		return w.normalizeJSON(value)
+	}
+}
+
+// normalizeMap converts a nested map into a document.
+func (w *Writer) normalizeMap(value map[string]any) (map[string]any, error) {
+	document, err := w.normalizeStructured(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any(document), nil
+}
+
+// normalizeInteger converts an integer according to the integer decoding.
+func (w *Writer) normalizeInteger(integer int64) any {
+	if w.integers == codec.IntegerDecodingDouble {
+		return float64(integer)
+	}
+
+	return integer
+}
+
+// normalizeJSON serializes a value into JSON and unmarshals it back, the same way raw JSON payloads are.
+func (w *Writer) normalizeJSON(value any) (any, error) {
+	var normalized any
+	if err := w.buffers.WithJSON(value, func(data []byte) error {
+		return codec.UnmarshalJSON(data, &normalized) //nolint:wrapcheck // the error is wrapped below
+	}); err != nil {
+		return nil, fmt.Errorf("serialize %T value: %w", value, err)
+	}
+
+	return w.integers.Decode(normalized), nil
+}
@@ -0,0 +1,98 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWriter_normalizeStructured(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	objectID := primitive.NewObjectID()
+
+	type address struct {
+		City string `json:"city"`
+	}
+
+	tests := []struct {
+		name     string
+		integers codec.IntegerDecoding
+		payload  opencdc.StructuredData
+		want     opencdc.StructuredData
+	}{
+		{
+			name: "native_values",
+			payload: opencdc.StructuredData{
+				"avatar":    []byte{0x01, 0x02},
+				"createdAt": createdAt,
+				"ref":       objectID,
+				"score":     float32(0.5),
+			},
+			want: opencdc.StructuredData{
+				"avatar":    []byte{0x01, 0x02},
+				"createdAt": createdAt,
+				"ref":       objectID,
+				"score":     float64(0.5),
+			},
+		},
+		{
+			name: "nested_values",
+			payload: opencdc.StructuredData{
+				"profile": opencdc.StructuredData{"age": int32(42), "tags": []string{"a"}},
+				"items":   []map[string]any{{"qty": uint8(3)}},
+			},
+			want: opencdc.StructuredData{
+				"profile": map[string]any{"age": int64(42), "tags": []any{"a"}},
+				"items":   []any{map[string]any{"qty": int64(3)}},
+			},
+		},
+		{
+			name:     "double_integers",
+			integers: codec.IntegerDecodingDouble,
+			payload:  opencdc.StructuredData{"count": 7},
+			want:     opencdc.StructuredData{"count": float64(7)},
+		},
+		{
+			name:    "struct_value",
+			payload: opencdc.StructuredData{"address": address{City: "Kyiv"}},
+			want:    opencdc.StructuredData{"address": map[string]any{"city": "Kyiv"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{IntegerDecoding: tt.integers})
+
+			got, err := w.normalizeStructured(tt.payload)
+			if err != nil {
+				t.Fatalf("Writer.normalizeStructured() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Writer.normalizeStructured() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// metadataFieldContentType is a name of a record metadata field that holds the content type of its payload.
+	metadataFieldContentType = "mongo.contentType"
+	// contentTypeBSON is a content type of payloads that are raw BSON documents.
+	contentTypeBSON = "application/bson"
+)
+
+// rawDocument returns the payload as a raw BSON document if the record's content type is BSON.
+// The boolean is false if the payload is not raw BSON, structured payloads are never considered raw BSON.
+func rawDocument(record opencdc.Record, data opencdc.Data) (bson.Raw, bool, error) {
+	if record.Metadata[metadataFieldContentType] != contentTypeBSON {
+		return nil, false, nil
+	}
+
+	rawData, ok := data.(opencdc.RawData)
+	if !ok || len(rawData) == 0 {
+		return nil, false, nil
+	}
+
+	document := bson.Raw(rawData)
+	if err := document.Validate(); err != nil {
+		return nil, false, fmt.Errorf("validate raw bson payload: %w", err)
+	}
+
+	return document, true, nil
+}
+
+// insertRaw inserts the raw BSON document as it is, without decoding and re-encoding it.
+// Like for other payloads, the document is upserted if the record has no key, but it's built from the document.
+func (w *Writer) insertRaw(ctx context.Context, record opencdc.Record, original bson.Raw) error {
+	document, err := w.sidecar.attachRaw(original, record)
+	if err != nil {
+		return fmt.Errorf("attach sidecar: %w", err)
+	}
+
+	if len(w.parseKey(record.Key)) == 0 {
+		if keys := w.keyFromRawDocument(document); len(keys) != 0 {
+			opts := options.Replace().SetUpsert(true)
+			if _, err := w.collectionFor(record).ReplaceOne(ctx, bson.M(keys), document, opts); err != nil {
+				return fmt.Errorf("replace one: %w", err)
+			}
+
+			return nil
+		}
+	}
+
+	if _, err := w.collectionFor(record).InsertOne(ctx, document); err != nil {
+		// the sidecar is attached to the replacement again
+		return w.onDuplicateKey(ctx, record, fmt.Errorf("insert one: %w", err), func() (bool, error) {
+			return w.replaceRaw(ctx, record, original)
+		})
+	}
+
+	return nil
+}
+
+// replaceRaw replaces the document matching the record key with the raw BSON document as it is,
+// as the document is a full one and its fields can't be set without decoding it.
+// It returns whether a document matched the key.
+func (w *Writer) replaceRaw(ctx context.Context, record opencdc.Record, document bson.Raw) (bool, error) {
+	document, err := w.sidecar.attachRaw(document, record)
+	if err != nil {
+		return false, fmt.Errorf("attach sidecar: %w", err)
+	}
+
+	keys := w.parseKey(record.Key)
+	if len(keys) == 0 {
+		keys = w.keyFromRawDocument(document)
+	}
+	if len(keys) == 0 {
+		return false, ErrEmptyKey
+	}
+
+	result, err := w.collectionFor(record).ReplaceOne(ctx, bson.M(keys), document)
+	if err != nil {
+		return false, fmt.Errorf("replace one: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// keyFromRawDocument builds a key from the writer's key fields of the raw BSON document,
+// keeping their values raw. It returns nil if the key fields are empty or the document misses at least one of them.
+func (w *Writer) keyFromRawDocument(document bson.Raw) opencdc.StructuredData {
+	if len(w.keyFields) == 0 {
+		return nil
+	}
+
+	keys := make(opencdc.StructuredData, len(w.keyFields))
+	for _, field := range w.keyFields {
+		value, err := document.LookupErr(field)
+		if err != nil {
+			return nil
+		}
+
+		keys[field] = value
+	}
+
+	return keys
+}
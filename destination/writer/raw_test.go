@@ -0,0 +1,136 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRawDocument(t *testing.T) {
+	t.Parallel()
+
+	document, err := bson.Marshal(bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "name", Value: "alice"}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	bsonMetadata := opencdc.Metadata{metadataFieldContentType: contentTypeBSON}
+
+	tests := []struct {
+		name    string
+		record  opencdc.Record
+		data    opencdc.Data
+		wantOK  bool
+		wantErr bool
+	}{
+		{
+			name:   "raw_bson",
+			record: opencdc.Record{Metadata: bsonMetadata},
+			data:   opencdc.RawData(document),
+			wantOK: true,
+		},
+		{
+			name:   "no_content_type",
+			record: opencdc.Record{},
+			data:   opencdc.RawData(`{"name":"alice"}`),
+		},
+		{
+			name:   "structured_payload",
+			record: opencdc.Record{Metadata: bsonMetadata},
+			data:   opencdc.StructuredData{"name": "alice"},
+		},
+		{
+			name:   "empty_payload",
+			record: opencdc.Record{Metadata: bsonMetadata},
+			data:   opencdc.RawData(nil),
+		},
+		{
+			name:    "invalid_bson",
+			record:  opencdc.Record{Metadata: bsonMetadata},
+			data:    opencdc.RawData(`{"name":"alice"}`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok, err := rawDocument(tt.record, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("rawDocument() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOK {
+				t.Fatalf("rawDocument() ok = %v, want %v", ok, tt.wantOK)
+			}
+
+			// the document must be passed through without re-encoding
+			if ok && !bytes.Equal(got, document) {
+				t.Errorf("rawDocument() = %v, want %v", got, bson.Raw(document))
+			}
+		})
+	}
+}
+
+func TestWriter_keyFromRawDocument(t *testing.T) {
+	t.Parallel()
+
+	document, err := bson.Marshal(bson.D{{Key: "tenant", Value: "acme"}, {Key: "id", Value: int32(1)}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		keyFields []string
+		want      opencdc.StructuredData
+	}{
+		{
+			name: "no_key_fields",
+		},
+		{
+			name:      "all_fields",
+			keyFields: []string{"tenant", "id"},
+			want: opencdc.StructuredData{
+				"tenant": bson.Raw(document).Lookup("tenant"),
+				"id":     bson.Raw(document).Lookup("id"),
+			},
+		},
+		{
+			name:      "missing_field",
+			keyFields: []string{"tenant", "region"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{KeyFields: tt.keyFields})
+
+			got := w.keyFromRawDocument(document)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Writer.keyFromRawDocument() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
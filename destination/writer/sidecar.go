@@ -0,0 +1,144 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+const (
+	// opencdcMetadataPrefix is a prefix of the OpenCDC metadata keys,
+	// which is trimmed from names of the sidecar fields.
+	opencdcMetadataPrefix = "opencdc."
+	// sidecarPositionField is a name of the sidecar field holding a record position.
+	sidecarPositionField = "position"
+)
+
+// sidecar builds a sub-document with the selected metadata of records,
+// which is written along with the business fields of documents for lineage.
+type sidecar struct {
+	field    string
+	keys     []string
+	position bool
+}
+
+// newSidecar creates a new instance of the [sidecar].
+// It returns nil if the field is empty, which means no sidecar is written.
+func newSidecar(field string, keys []string, position bool) *sidecar {
+	if field == "" {
+		return nil
+	}
+
+	return &sidecar{
+		field:    field,
+		keys:     keys,
+		position: position,
+	}
+}
+
+// attach puts the sidecar built from the record into the payload.
+// A nil sidecar leaves the payload as it is.
+func (s *sidecar) attach(payload opencdc.StructuredData, record opencdc.Record) {
+	if s == nil {
+		return
+	}
+
+	document := make(map[string]any, len(s.keys)+1)
+	for _, key := range s.keys {
+		if value, ok := record.Metadata[key]; ok {
+			document[sidecarFieldName(key)] = value
+		}
+	}
+
+	if s.position && len(record.Position) != 0 {
+		// positions of the connector are JSON, others are kept as binary data
+		if utf8.Valid(record.Position) {
+			document[sidecarPositionField] = string(record.Position)
+		} else {
+			document[sidecarPositionField] = []byte(record.Position)
+		}
+	}
+
+	payload[s.field] = document
+}
+
+// attachUpdate puts the sidecar built from the record into the $set of the update.
+// A nil sidecar returns the update as it is.
+func (s *sidecar) attachUpdate(update bson.M, record opencdc.Record) bson.M {
+	if s == nil {
+		return update
+	}
+
+	set, ok := update[setCommand].(bson.M)
+	if !ok {
+		set = bson.M{}
+		update[setCommand] = set
+	}
+
+	s.attach(opencdc.StructuredData(set), record)
+
+	return update
+}
+
+// attachRaw puts the sidecar built from the record into the raw BSON document,
+// replacing the existing field of the same name. Other fields are copied as they are.
+// A nil sidecar returns the document as it is.
+func (s *sidecar) attachRaw(document bson.Raw, record opencdc.Record) (bson.Raw, error) {
+	if s == nil {
+		return document, nil
+	}
+
+	payload := make(opencdc.StructuredData, 1)
+	s.attach(payload, record)
+
+	sidecarDocument, err := bson.Marshal(payload[s.field])
+	if err != nil {
+		return nil, fmt.Errorf("marshal sidecar: %w", err)
+	}
+
+	elements, err := document.Elements()
+	if err != nil {
+		return nil, fmt.Errorf("get document elements: %w", err)
+	}
+
+	idx, raw := bsoncore.AppendDocumentStart(make([]byte, 0, len(document)+len(sidecarDocument)+len(s.field)+2))
+	for _, element := range elements {
+		if element.Key() != s.field {
+			raw = append(raw, element...)
+		}
+	}
+
+	raw = bsoncore.AppendDocumentElement(raw, s.field, sidecarDocument)
+
+	raw, err = bsoncore.AppendDocumentEnd(raw, idx)
+	if err != nil {
+		return nil, fmt.Errorf("append document end: %w", err)
+	}
+
+	return bson.Raw(raw), nil
+}
+
+// sidecarFieldName converts a metadata key into a name of the sidecar field,
+// e.g. "opencdc.collection" becomes "collection" and "mongo.retry.attempts" becomes "mongo_retry_attempts",
+// as dots are interpreted as paths to embedded documents.
+func sidecarFieldName(key string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(key, opencdcMetadataPrefix), ".", "_")
+}
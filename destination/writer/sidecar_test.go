@@ -0,0 +1,158 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSidecar_attach(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Position: opencdc.Position(`{"mode":"cdc"}`),
+		Metadata: opencdc.Metadata{
+			opencdc.MetadataCollection: "users",
+			opencdc.MetadataCreatedAt:  "1700000000000000000",
+			"mongo.retry.attempts":     "2",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		sidecar *sidecar
+		want    opencdc.StructuredData
+	}{
+		{
+			name:    "disabled",
+			sidecar: newSidecar("", []string{opencdc.MetadataCollection}, true),
+			want:    opencdc.StructuredData{"name": "alice"},
+		},
+		{
+			name: "metadata_and_position",
+			sidecar: newSidecar("_meta", []string{
+				opencdc.MetadataCollection, opencdc.MetadataCreatedAt, "mongo.retry.attempts", "missing",
+			}, true),
+			want: opencdc.StructuredData{
+				"name": "alice",
+				"_meta": map[string]any{
+					"collection":           "users",
+					"createdAt":            "1700000000000000000",
+					"mongo_retry_attempts": "2",
+					"position":             `{"mode":"cdc"}`,
+				},
+			},
+		},
+		{
+			name:    "metadata_only",
+			sidecar: newSidecar("_meta", []string{opencdc.MetadataCollection}, false),
+			want: opencdc.StructuredData{
+				"name":  "alice",
+				"_meta": map[string]any{"collection": "users"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			payload := opencdc.StructuredData{"name": "alice"}
+			tt.sidecar.attach(payload, record)
+
+			if !reflect.DeepEqual(payload, tt.want) {
+				t.Errorf("sidecar.attach() = %v, want %v", payload, tt.want)
+			}
+		})
+	}
+}
+
+func TestSidecar_attachUpdate(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Metadata: opencdc.Metadata{opencdc.MetadataCollection: "users"},
+	}
+
+	s := newSidecar("_meta", []string{opencdc.MetadataCollection}, false)
+
+	// an update that only unsets fields gets the sidecar set anyway
+	got := s.attachUpdate(bson.M{unsetCommand: bson.M{"nickname": ""}}, record)
+	want := bson.M{
+		unsetCommand: bson.M{"nickname": ""},
+		setCommand:   bson.M{"_meta": map[string]any{"collection": "users"}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sidecar.attachUpdate() = %v, want %v", got, want)
+	}
+}
+
+func TestSidecar_attachRaw(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Metadata: opencdc.Metadata{opencdc.MetadataCollection: "users"},
+	}
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "name", Value: "alice"},
+		{Key: "_meta", Value: "stale"},
+		{Key: "age", Value: int32(30)},
+	})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := newSidecar("", nil, false).attachRaw(document, record)
+		if err != nil {
+			t.Fatalf("sidecar.attachRaw() error = %v", err)
+		}
+
+		if !bytes.Equal(got, document) {
+			t.Errorf("sidecar.attachRaw() = %v, want %v", got, bson.Raw(document))
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := newSidecar("_meta", []string{opencdc.MetadataCollection}, false).attachRaw(document, record)
+		if err != nil {
+			t.Fatalf("sidecar.attachRaw() error = %v", err)
+		}
+
+		want, err := bson.Marshal(bson.D{
+			{Key: "name", Value: "alice"},
+			{Key: "age", Value: int32(30)},
+			{Key: "_meta", Value: bson.D{{Key: "collection", Value: "users"}}},
+		})
+		if err != nil {
+			t.Fatalf("bson.Marshal() error = %v", err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("sidecar.attachRaw() = %v, want %v", got, bson.Raw(want))
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ValidatorMode defines what the connector does with the $jsonSchema validator of the target collection.
+type ValidatorMode string
+
+// The available validator modes are listed below.
+const (
+	// ValidatorModeInstall installs the validator on the collection, replacing the existing one.
+	ValidatorModeInstall ValidatorMode = "install"
+	// ValidatorModeVerify makes sure the collection already has the validator, and fails otherwise.
+	ValidatorModeVerify ValidatorMode = "verify"
+)
+
+// documentValidationFailureCode is a code of the error MongoDB returns when a document fails validation.
+const documentValidationFailureCode = 121
+
+// errValidatorMismatch occurs in the verify mode if the collection doesn't have the configured validator.
+var errValidatorMismatch = errors.New("the collection doesn't have the configured $jsonSchema validator")
+
+// ValidationError occurs when a document fails the $jsonSchema validation of the target collection.
+type ValidationError struct {
+	// Paths are the dot-separated paths of the fields that don't satisfy the schema.
+	// It's empty if the server doesn't report them, which is the case before MongoDB 5.0.
+	Paths []string
+	Err   error
+}
+
+// Error returns a formatted error message for the [ValidationError].
+func (e *ValidationError) Error() string {
+	if len(e.Paths) == 0 {
+		return fmt.Sprintf("document failed validation: %s", e.Err)
+	}
+
+	return fmt.Sprintf("document failed validation of fields %s: %s", strings.Join(e.Paths, ", "), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// EnsureValidator installs the $jsonSchema validator on the collection, making the server reject
+// documents that don't satisfy the schema, or verifies that the collection already has it, depending on the mode.
+func EnsureValidator(ctx context.Context, collection *mongo.Collection, schema bson.D, mode ValidatorMode) error {
+	validator := bson.D{{Key: "$jsonSchema", Value: schema}}
+
+	if mode == ValidatorModeVerify {
+		return verifyValidator(ctx, collection, validator)
+	}
+
+	err := collection.Database().RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: collection.Name()},
+		{Key: "validator", Value: validator},
+		{Key: "validationLevel", Value: "strict"},
+		{Key: "validationAction", Value: "error"},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("run collMod command: %w", err)
+	}
+
+	return nil
+}
+
+// verifyValidator checks whether the collection's validator is the provided one, ignoring the order of fields.
+func verifyValidator(ctx context.Context, collection *mongo.Collection, validator bson.D) error {
+	specifications, err := collection.Database().ListCollectionSpecifications(ctx, bson.M{"name": collection.Name()})
+	if err != nil {
+		return fmt.Errorf("list collection specifications: %w", err)
+	}
+
+	if len(specifications) == 0 || specifications[0].Options == nil {
+		return errValidatorMismatch
+	}
+
+	actualValue, err := specifications[0].Options.LookupErr("validator")
+	if err != nil {
+		return errValidatorMismatch
+	}
+
+	var actual, expected bson.M
+	if err := actualValue.Unmarshal(&actual); err != nil {
+		return fmt.Errorf("unmarshal collection validator: %w", err)
+	}
+
+	expectedBytes, err := bson.Marshal(validator)
+	if err != nil {
+		return fmt.Errorf("marshal validator: %w", err)
+	}
+
+	if err := bson.Unmarshal(expectedBytes, &expected); err != nil {
+		return fmt.Errorf("unmarshal validator: %w", err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		return errValidatorMismatch
+	}
+
+	return nil
+}
+
+// asValidationError converts the error into a [ValidationError] if it's caused by a document failing validation,
+// otherwise the error is returned as it is.
+func asValidationError(err error) error {
+	var writeException mongo.WriteException
+	if !errors.As(err, &writeException) {
+		return err
+	}
+
+	for _, writeErr := range writeException.WriteErrors {
+		if writeErr.Code != documentValidationFailureCode {
+			continue
+		}
+
+		var paths []string
+		if writeErr.Details != nil {
+			collectFailedPaths(bson.RawValue{Type: bsontype.EmbeddedDocument, Value: writeErr.Details}, "", &paths)
+		}
+
+		slices.Sort(paths)
+
+		return &ValidationError{Paths: slices.Compact(paths), Err: err}
+	}
+
+	return err
+}
+
+// collectFailedPaths walks the validation error details reported by the server and collects
+// the paths of properties that don't satisfy the schema. Properties are nested into the details
+// of their parents, so the paths are built from the property names and item indexes the walk passes.
+func collectFailedPaths(value bson.RawValue, prefix string, paths *[]string) {
+	if value.Type == bsontype.Array {
+		values, _ := value.Array().Values()
+		for _, element := range values {
+			collectFailedPaths(element, prefix, paths)
+		}
+
+		return
+	}
+
+	// only documents and arrays contain nested details
+	if value.Type != bsontype.EmbeddedDocument {
+		return
+	}
+
+	document := value.Document()
+
+	current := prefix
+	name, isProperty := document.Lookup("propertyName").StringValueOK()
+	if isProperty {
+		current = joinPath(prefix, name)
+	}
+
+	index, isItem := document.Lookup("itemIndex").AsInt64OK()
+	if isItem {
+		current = joinPath(current, strconv.FormatInt(index, 10))
+	}
+
+	found := len(*paths)
+
+	// the required and additionalProperties rules list the names of the offending properties
+	for _, key := range []string{"missingProperties", "additionalProperties"} {
+		array, ok := document.Lookup(key).ArrayOK()
+		if !ok {
+			continue
+		}
+
+		names, _ := array.Values()
+		for _, nameValue := range names {
+			if propertyName, isString := nameValue.StringValueOK(); isString {
+				*paths = append(*paths, joinPath(current, propertyName))
+			}
+		}
+	}
+
+	elements, _ := document.Elements()
+	for _, element := range elements {
+		// the schema fragments and the values that failed the validation never contain details
+		if key := element.Key(); key != "specifiedAs" && key != "consideredValue" {
+			collectFailedPaths(element.Value(), current, paths)
+		}
+	}
+
+	// the property or the item itself failed if none of its nested properties did
+	if (isProperty || isItem) && len(*paths) == found {
+		*paths = append(*paths, current)
+	}
+}
+
+// joinPath joins the path of a parent field and the name of its nested field with a dot.
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
@@ -0,0 +1,143 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// testValidationDetails are the details MongoDB reports for a document
+// that misses the required email field and has an invalid nested address.
+const testValidationDetails = `{
+	"operatorName": "$jsonSchema",
+	"schemaRulesNotSatisfied": [
+		{
+			"operatorName": "properties",
+			"propertiesNotSatisfied": [
+				{
+					"propertyName": "address",
+					"details": [
+						{
+							"operatorName": "properties",
+							"propertiesNotSatisfied": [
+								{
+									"propertyName": "zip",
+									"details": [
+										{
+											"operatorName": "bsonType",
+											"specifiedAs": {"bsonType": "string"},
+											"reason": "type did not match",
+											"consideredValue": 12345,
+											"consideredType": "int"
+										}
+									]
+								}
+							]
+						}
+					]
+				},
+				{
+					"propertyName": "tags",
+					"details": [
+						{
+							"operatorName": "items",
+							"reason": "At least one item did not match the sub-schema",
+							"itemIndex": 1,
+							"details": [
+								{
+									"operatorName": "bsonType",
+									"specifiedAs": {"bsonType": "string"},
+									"reason": "type did not match",
+									"consideredValue": 7,
+									"consideredType": "int"
+								}
+							]
+						}
+					]
+				}
+			]
+		},
+		{
+			"operatorName": "required",
+			"specifiedAs": {"required": ["email", "name"]},
+			"missingProperties": ["email"]
+		}
+	]
+}`
+
+func TestAsValidationError(t *testing.T) {
+	t.Parallel()
+
+	var details bson.Raw
+	if err := bson.UnmarshalExtJSON([]byte(testValidationDetails), false, &details); err != nil {
+		t.Fatalf("unmarshal details: %v", err)
+	}
+
+	err := asValidationError(mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{
+			Code: documentValidationFailureCode, Message: "Document failed validation", Details: details,
+		}},
+	})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("asValidationError() = %v, want a ValidationError", err)
+	}
+
+	want := []string{"address.zip", "email", "tags.1"}
+	if !reflect.DeepEqual(validationErr.Paths, want) {
+		t.Errorf("asValidationError() paths = %v, want %v", validationErr.Paths, want)
+	}
+
+	var writeException mongo.WriteException
+	if !errors.As(err, &writeException) {
+		t.Errorf("asValidationError() = %v, want it to wrap the write exception", err)
+	}
+}
+
+func TestAsValidationError_noDetails(t *testing.T) {
+	t.Parallel()
+
+	err := asValidationError(mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: documentValidationFailureCode, Message: "Document failed validation"}},
+	})
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("asValidationError() = %v, want a ValidationError", err)
+	}
+
+	if len(validationErr.Paths) != 0 {
+		t.Errorf("asValidationError() paths = %v, want none", validationErr.Paths)
+	}
+}
+
+func TestAsValidationError_otherError(t *testing.T) {
+	t.Parallel()
+
+	writeException := mongo.WriteException{
+		WriteErrors: []mongo.WriteError{{Code: 11000, Message: "duplicate key"}},
+	}
+
+	var validationErr *ValidationError
+	if errors.As(asValidationError(writeException), &validationErr) {
+		t.Errorf("asValidationError() = %v, want the error as it is", validationErr)
+	}
+}
@@ -15,15 +15,23 @@
 package writer
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio-labs/conduit-connector-mongo/metrics"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 const (
@@ -32,20 +40,168 @@ const (
 
 	// setCommand contains command, that used during Update query.
 	setCommand = "$set"
+
+	// retryableWriteErrorLabel is an error label the server puts on errors of writes that are safe to retry.
+	retryableWriteErrorLabel = "RetryableWriteError"
+	// retryBackoff is a delay before the first retry, every next retry waits for one more backoff.
+	retryBackoff = 100 * time.Millisecond
+)
+
+// UpdateStrategy defines how the writer applies updates to documents.
+type UpdateStrategy string
+
+// The list of available update strategies is listed below.
+const (
+	// UpdateStrategySet sets the top-level payload fields, overwriting whole embedded documents.
+	UpdateStrategySet UpdateStrategy = "set"
+	// UpdateStrategyFlatten flattens embedded documents into dot-notation paths,
+	// so only the changed leaves are set.
+	UpdateStrategyFlatten UpdateStrategy = "flatten"
+	// UpdateStrategyDelta sets and unsets only the fields changed by the update, as described
+	// by the update description metadata of records. Records without it are applied the same way as by the set one.
+	UpdateStrategyDelta UpdateStrategy = "delta"
+)
+
+// DuplicateKeyPolicy defines how the writer handles inserts failing with duplicate key errors.
+type DuplicateKeyPolicy string
+
+// The list of available duplicate key policies is listed below.
+const (
+	// DuplicateKeyFail makes the writer return the error.
+	DuplicateKeyFail DuplicateKeyPolicy = "fail"
+	// DuplicateKeySkip makes the writer skip the record, keeping the existing document.
+	DuplicateKeySkip DuplicateKeyPolicy = "skip"
+	// DuplicateKeyUpdate makes the writer apply the record as an update of the existing document.
+	DuplicateKeyUpdate DuplicateKeyPolicy = "update"
+	// DuplicateKeyReplace makes the writer replace the existing document with the record's one.
+	DuplicateKeyReplace DuplicateKeyPolicy = "replace"
 )
 
 // ErrEmptyKey occurs when a record has an empty key and an operation is update or delete.
 var ErrEmptyKey = errors.New("empty key")
 
+// errDuplicateNotMatched occurs when a record whose insert fails with a duplicate key error is applied
+// to the existing document, but no document matches its key, as the duplicate is on another unique index.
+var errDuplicateNotMatched = errors.New("no document matches the record key, the duplicate is on another unique index")
+
 // Writer implements a writer logic for Mongo destination.
+// It's safe for concurrent use, so records of different documents can be written in parallel.
 type Writer struct {
-	collection *mongo.Collection
+	collection       *mongo.Collection
+	keyFields        []string
+	keyMapping       map[string]string
+	replicateIndexes bool
+	updateStrategy   UpdateStrategy
+	arrayStrategies  map[string]ArrayStrategy
+	buffers          *codec.BufferPool
+	keyCache         *keyCache
+	idType           codec.IDType
+	integers         codec.IntegerDecoding
+	maxRetries       int
+	sidecar          *sidecar
+	// namespaces routes records to collections by their source namespaces.
+	// If it's nil, records are written to the collection.
+	namespaces *namespaceRouter
+	// duplicateKeyPolicy determines how inserts failing with duplicate key errors are handled.
+	duplicateKeyPolicy DuplicateKeyPolicy
+	// metrics are the metrics of the writes. If it's nil, the writes are not observed.
+	metrics *metrics.Destination
+	// pendingIndexes are index specifications received from collection metadata records,
+	// which are created once the snapshot is completed. They're guarded by the indexesMu, as is the indexesTimer.
+	pendingIndexes []bson.D
+	// indexesTimer creates the pending indexes once no snapshot record is written for the indexesDelay.
+	indexesTimer *time.Timer
+	indexesDelay time.Duration
+	indexesMu    sync.Mutex
+}
+
+// Params is an incoming params for the [NewWriter] function.
+type Params struct {
+	Collection *mongo.Collection
+	// KeyFields is the list of payload fields the writer builds a key from
+	// if a record has no key. If it's empty, keys are never built from payloads.
+	KeyFields []string
+	// KeyMapping maps record key fields to document fields the writer filters documents by.
+	// Key fields that are not in the mapping are used as they are.
+	KeyMapping map[string]string
+	// ReplicateIndexes determines whether the writer creates indexes
+	// described by collection metadata records.
+	ReplicateIndexes bool
+	// UpdateStrategy determines how the writer applies updates to documents.
+	UpdateStrategy UpdateStrategy
+	// ArrayStrategies maps fields to the strategies their arrays are applied to documents with on updates.
+	// Arrays of other fields are replaced.
+	ArrayStrategies map[string]ArrayStrategy
+	// WriteConcern is the write concern documents are written with.
+	// If it's nil, the collection's one is used.
+	WriteConcern *writeconcern.WriteConcern
+	// Buffers is a pool of buffers structured payloads are serialized into.
+	// If it's nil, pooling is disabled.
+	Buffers *codec.BufferPool
+	// MaxRetries is the number of times the writer retries writing a record
+	// that failed with a transient error. If it's zero, writes are never retried.
+	MaxRetries int
+	// MetadataField is a name of the sub-document the writer puts the selected record metadata into.
+	// If it's empty, no metadata is written.
+	MetadataField string
+	// MetadataKeys is the list of metadata keys the writer puts into the metadata field.
+	MetadataKeys []string
+	// MetadataPosition determines whether the writer puts record positions into the metadata field.
+	MetadataPosition bool
+	// KeepObjectIDStrings determines whether hex strings of raw keys are kept as they are
+	// instead of being converted into ObjectIDs.
+	KeepObjectIDStrings bool
+	// IDType is the type _id values of record keys and payloads are converted into, see [codec.IDType.Encode].
+	IDType codec.IDType
+	// IntegerDecoding determines which type integral JSON numbers of record keys and payloads are decoded into.
+	// If it's empty, they're decoded into 64-bit integers.
+	IntegerDecoding codec.IntegerDecoding
+	// NamespaceRules route records to other collections of the database by their source namespaces.
+	// The first matching rule wins, records matching none of them are written to the collection.
+	NamespaceRules []NamespaceRule
+	// DuplicateKeyPolicy determines how inserts failing with duplicate key errors are handled.
+	// If it's empty, the errors are returned.
+	DuplicateKeyPolicy DuplicateKeyPolicy
+	// Metrics are the metrics the writer updates. If it's nil, the writes are not observed.
+	Metrics *metrics.Destination
 }
 
 // NewWriter creates new instance of the Writer.
-func NewWriter(collection *mongo.Collection) *Writer {
+func NewWriter(params Params) *Writer {
+	collection := params.Collection
+	if collection != nil && params.WriteConcern != nil {
+		collection = collection.Database().Collection(
+			collection.Name(), options.Collection().SetWriteConcern(params.WriteConcern),
+		)
+	}
+
+	idType := params.IDType
+	if idType == "" {
+		idType = codec.IDTypeAuto
+	}
+
+	integers := params.IntegerDecoding
+	if integers == "" {
+		integers = codec.IntegerDecodingInt64
+	}
+
 	writer := &Writer{
-		collection: collection,
+		collection:         collection,
+		keyFields:          params.KeyFields,
+		keyMapping:         params.KeyMapping,
+		replicateIndexes:   params.ReplicateIndexes,
+		updateStrategy:     params.UpdateStrategy,
+		arrayStrategies:    params.ArrayStrategies,
+		buffers:            params.Buffers,
+		keyCache:           newKeyCache(!params.KeepObjectIDStrings && idType == codec.IDTypeAuto),
+		idType:             idType,
+		integers:           integers,
+		maxRetries:         params.MaxRetries,
+		sidecar:            newSidecar(params.MetadataField, params.MetadataKeys, params.MetadataPosition),
+		namespaces:         newNamespaceRouter(params.NamespaceRules, params.WriteConcern),
+		duplicateKeyPolicy: params.DuplicateKeyPolicy,
+		metrics:            params.Metrics,
+		indexesDelay:       pendingIndexesDelay,
 	}
 
 	return writer
@@ -53,66 +209,477 @@ func NewWriter(collection *mongo.Collection) *Writer {
 
 // Write writes a opencdc.Record into a Destination.
 func (w *Writer) Write(ctx context.Context, record opencdc.Record) error {
-	if err := sdk.Util.Destination.Route(ctx, record,
-		w.insert,
-		w.update,
-		w.delete,
-		w.insert,
-	); err != nil {
-		return fmt.Errorf("route %s: %w", record.Operation, err)
+	// collection metadata records don't carry documents, so they're never written
+	if isCollectionMetadataRecord(record) {
+		if err := w.collectIndexes(record); err != nil {
+			return err
+		}
+
+		w.schedulePendingIndexes(ctx)
+
+		return nil
 	}
 
-	return nil
+	// the first record that is not a snapshot one, including heartbeats and collection events,
+	// means the snapshot is completed
+	if record.Operation != opencdc.OperationSnapshot {
+		w.createPendingIndexes(ctx)
+	} else {
+		w.schedulePendingIndexes(ctx)
+	}
+
+	// heartbeat records only advance the source position, so there's nothing to write
+	if record.Metadata[codec.MetadataFieldRecordType] == codec.RecordTypeHeartbeat {
+		return nil
+	}
+
+	// collection events are left to operators, so a dropped source collection doesn't drop the target one
+	if record.Metadata[codec.MetadataFieldRecordType] == codec.RecordTypeCollectionEvent {
+		return nil
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := sdk.Util.Destination.Route(ctx, record,
+			w.insert,
+			w.update,
+			w.delete,
+			w.insert,
+		)
+		if err == nil {
+			w.metrics.ObserveWrite(record)
+
+			return nil
+		}
+
+		// writes within a transaction are retried by the whole transaction, not one by one
+		if attempt >= w.maxRetries || mongo.SessionFromContext(ctx) != nil || !isTransientErr(err) {
+			w.metrics.ObserveError()
+
+			return fmt.Errorf("route %s: %w", record.Operation, asValidationError(err))
+		}
+
+		w.metrics.ObserveRetry()
+		sdk.Logger(ctx).Warn().Err(err).Int("attempt", attempt+1).Msg("retrying record write")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("wait for write retry: %w", ctx.Err())
+		case <-time.After(retryBackoff * time.Duration(attempt+1)):
+		}
+
+		if record.Metadata == nil {
+			record.Metadata = opencdc.Metadata{}
+		}
+		record.Metadata[codec.MetadataFieldRetryAttempts] = strconv.Itoa(attempt + 1)
+	}
+}
+
+// isTransientErr checks whether the error is a transient one, so a write is safe to retry.
+func isTransientErr(err error) bool {
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+
+	var labeledErr mongo.LabeledError
+	if errors.As(err, &labeledErr) {
+		return labeledErr.HasErrorLabel(retryableWriteErrorLabel)
+	}
+
+	return false
+}
+
+// collectionFor returns the collection the record is written to, routed by its source namespace.
+func (w *Writer) collectionFor(record opencdc.Record) *mongo.Collection {
+	return w.namespaces.route(record, w.collection)
 }
 
 func (w *Writer) insert(ctx context.Context, record opencdc.Record) error {
-	payload := make(opencdc.StructuredData)
-	if err := json.Unmarshal(record.Payload.After.Bytes(), &payload); err != nil {
+	document, ok, err := rawDocument(record, record.Payload.After)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return w.insertRaw(ctx, record, document)
+	}
+
+	payload, err := w.unmarshalPayload(record.Payload.After)
+	if err != nil {
 		return fmt.Errorf("unmarshal payload: %w", err)
 	}
 
-	if _, err := w.collection.InsertOne(ctx, bson.M(payload)); err != nil {
-		return fmt.Errorf("insert one: %w", err)
+	if err := decodeBinaries(payload, record); err != nil {
+		return err
+	}
+
+	// if a record has no key, but we're able to build it from the payload,
+	// we upsert the document in order to avoid duplicates
+	if len(w.parseKey(record.Key)) == 0 {
+		if keys := w.keyFromPayload(payload); len(keys) != 0 {
+			w.sidecar.attach(payload, record)
+
+			opts := options.Replace().SetUpsert(true)
+			if _, err := w.collectionFor(record).ReplaceOne(ctx, bson.M(keys), bson.M(payload), opts); err != nil {
+				return fmt.Errorf("replace one: %w", err)
+			}
+
+			return nil
+		}
+	}
+
+	w.sidecar.attach(payload, record)
+
+	if _, err := w.collectionFor(record).InsertOne(ctx, bson.M(payload)); err != nil {
+		return w.onDuplicateKey(ctx, record, fmt.Errorf("insert one: %w", err), func() (bool, error) {
+			return w.replace(ctx, record, payload)
+		})
+	}
+
+	return nil
+}
+
+// onDuplicateKey handles the error of the record's insert according to the duplicate key policy,
+// e.g. when a snapshot is replayed into a collection that already has its documents.
+// The replace function replaces the existing document with the record's one and returns whether it matched one.
+// Errors other than duplicate key ones are returned as they are.
+func (w *Writer) onDuplicateKey(
+	ctx context.Context, record opencdc.Record, err error, replace func() (bool, error),
+) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+
+	switch w.duplicateKeyPolicy {
+	case DuplicateKeySkip:
+		sdk.Logger(ctx).Debug().Err(err).Msg("skipping a record with a duplicate key")
+
+		return nil
+
+	case DuplicateKeyUpdate:
+		return resolveDuplicate(err, func() (bool, error) {
+			return w.applyUpdate(ctx, record)
+		})
+
+	case DuplicateKeyReplace:
+		return resolveDuplicate(err, replace)
+
+	case DuplicateKeyFail:
+		return err
+
+	default:
+		return err
+	}
+}
+
+// resolveDuplicate applies the record whose insert failed with the duplicate key error to the existing document.
+// If the record matches no document, the duplicate is on a unique index other than the one of the record key,
+// so the error is returned instead of dropping the record silently.
+func resolveDuplicate(duplicateErr error, apply func() (bool, error)) error {
+	matched, err := apply()
+	if err != nil {
+		return err
+	}
+
+	if !matched {
+		return fmt.Errorf("%w: %w", errDuplicateNotMatched, duplicateErr)
 	}
 
 	return nil
 }
 
+// replace replaces the document matching the record key with the payload.
+// It returns whether a document matched the key.
+func (w *Writer) replace(ctx context.Context, record opencdc.Record, payload opencdc.StructuredData) (bool, error) {
+	keys := w.parseKey(record.Key)
+	if len(keys) == 0 {
+		keys = w.keyFromPayload(payload)
+	}
+	if len(keys) == 0 {
+		return false, ErrEmptyKey
+	}
+
+	result, err := w.collectionFor(record).ReplaceOne(ctx, bson.M(keys), bson.M(payload))
+	if err != nil {
+		return false, fmt.Errorf("replace one: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
 func (w *Writer) update(ctx context.Context, record opencdc.Record) error {
-	payload := make(opencdc.StructuredData)
-	if err := json.Unmarshal(record.Payload.After.Bytes(), &payload); err != nil {
-		return fmt.Errorf("unmarshal payload: %w", err)
+	_, err := w.applyUpdate(ctx, record)
+
+	return err
+}
+
+// applyUpdate applies the record as an update of the document matching its key.
+// It returns whether a document matched the key, an empty update is considered matched, as it changes nothing.
+func (w *Writer) applyUpdate(ctx context.Context, record opencdc.Record) (bool, error) {
+	document, ok, err := rawDocument(record, record.Payload.After)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		return w.replaceRaw(ctx, record, document)
 	}
 
-	delete(payload, idFieldName) // deleting key from payload arguments
+	payload, err := w.unmarshalPayload(record.Payload.After)
+	if err != nil {
+		return false, fmt.Errorf("unmarshal payload: %w", err)
+	}
 
-	keys := make(opencdc.StructuredData)
-	if err := json.Unmarshal(record.Key.Bytes(), &keys); err != nil {
-		return fmt.Errorf("unmarshal keys: %w", err)
+	if err := decodeBinaries(payload, record); err != nil {
+		return false, err
 	}
+
+	keys := w.parseKey(record.Key)
 	if len(keys) == 0 {
-		return ErrEmptyKey
+		keys = w.keyFromPayload(payload)
+	}
+	if len(keys) == 0 {
+		return false, ErrEmptyKey
 	}
 
-	if _, err := w.collection.UpdateOne(ctx, bson.M(keys), bson.M{setCommand: bson.M(payload)}); err != nil {
-		return fmt.Errorf("update one: %w", err)
+	delete(payload, idFieldName) // deleting key from payload arguments
+	w.sidecar.attach(payload, record)
+
+	update, err := w.updateDocument(record, payload)
+	if err != nil {
+		return false, err
 	}
 
-	return nil
+	// the delta of an update that changed nothing, e.g. one setting fields to their current values, is empty
+	if len(update) == 0 {
+		return true, nil
+	}
+
+	result, err := w.collectionFor(record).UpdateOne(ctx, bson.M(keys), update)
+	if err != nil {
+		return false, fmt.Errorf("update one: %w", err)
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// updateDocument builds the update of the record's document according to the writer's update strategy.
+func (w *Writer) updateDocument(record opencdc.Record, payload opencdc.StructuredData) (bson.M, error) {
+	if w.updateStrategy == UpdateStrategyDelta {
+		description, ok, err := parseUpdateDescription(record, w.integers)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			// if the payload misses an array truncated by the update, the whole payload is set instead
+			if update, ok := description.update(payload); ok {
+				return w.sidecar.attachUpdate(update, record), nil
+			}
+		}
+	}
+
+	fields := payload
+	if w.updateStrategy == UpdateStrategyFlatten {
+		fields = flattenFields(payload)
+	}
+
+	return w.arrayUpdate(fields), nil
 }
 
 func (w *Writer) delete(ctx context.Context, record opencdc.Record) error {
-	keys := make(opencdc.StructuredData)
-	if err := json.Unmarshal(record.Key.Bytes(), &keys); err != nil {
-		return fmt.Errorf("unmarshal keys: %w", err)
+	keys := w.parseKey(record.Key)
+	if len(keys) == 0 && record.Payload.Before != nil && len(record.Payload.Before.Bytes()) != 0 {
+		var err error
+		if keys, err = w.keyFromBefore(record); err != nil {
+			return err
+		}
 	}
 	if len(keys) == 0 {
 		return ErrEmptyKey
 	}
 
-	if _, err := w.collection.DeleteOne(ctx, bson.M(keys)); err != nil {
+	if _, err := w.collectionFor(record).DeleteOne(ctx, bson.M(keys)); err != nil {
 		return fmt.Errorf("delete one: %w", err)
 	}
 
 	return nil
 }
+
+// keyFromBefore builds a key from the writer's key fields of the record's payload before the change.
+func (w *Writer) keyFromBefore(record opencdc.Record) (opencdc.StructuredData, error) {
+	document, ok, err := rawDocument(record, record.Payload.Before)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return w.keyFromRawDocument(document), nil
+	}
+
+	payload, err := w.unmarshalPayload(record.Payload.Before)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal payload: %w", err)
+	}
+
+	return w.keyFromPayload(payload), nil
+}
+
+// unmarshalPayload unmarshals a record payload into a set of document fields.
+// Raw payloads are unmarshalled from JSON, while the values of structured payloads,
+// including the ones decoded by the schema middleware, are normalized as they are.
+// Numbers are decoded according to the writer's integer decoding.
+func (w *Writer) unmarshalPayload(data opencdc.Data) (opencdc.StructuredData, error) {
+	if structuredData, ok := data.(opencdc.StructuredData); ok {
+		payload, err := w.normalizeStructured(structuredData)
+		if err != nil {
+			return nil, fmt.Errorf("normalize structured payload: %w", err)
+		}
+
+		return w.encodeID(payload), nil
+	}
+
+	payload := make(opencdc.StructuredData)
+	if err := codec.UnmarshalJSON(data.Bytes(), &payload); err != nil {
+		return nil, err //nolint:wrapcheck // the error is wrapped by the caller
+	}
+
+	w.integers.Decode(map[string]any(payload))
+
+	return w.encodeID(payload), nil
+}
+
+// parseKey converts a record key into a set of fields used to filter documents.
+//
+//   - If the key is [opencdc.StructuredData] it's used as it is.
+//   - If the key is a JSON object it's unmarshalled into a set of fields.
+//   - If the key is any other JSON value or it's not JSON at all
+//     (e.g. a plain string ID), the value is used as the _id field.
+//
+// Numbers of JSON keys are decoded according to the integer decoding.
+func parseKey(key opencdc.Data, integers codec.IntegerDecoding) opencdc.StructuredData {
+	if key == nil {
+		return nil
+	}
+
+	if structuredKey, ok := key.(opencdc.StructuredData); ok {
+		return structuredKey
+	}
+
+	rawKey := bytes.TrimSpace(key.Bytes())
+	if len(rawKey) == 0 {
+		return nil
+	}
+
+	var value any
+	if err := codec.UnmarshalJSON(rawKey, &value); err != nil {
+		return opencdc.StructuredData{idFieldName: string(rawKey)}
+	}
+
+	value = integers.Decode(value)
+
+	if fields, ok := value.(map[string]any); ok {
+		return fields
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	return opencdc.StructuredData{idFieldName: value}
+}
+
+// parseKey converts a record key into a filter, mapping its fields to document fields.
+// A key with multiple fields results in a compound filter matching all of them.
+// Filters of raw keys are cached, so repeated keys are parsed only once.
+func (w *Writer) parseKey(key opencdc.Data) opencdc.StructuredData {
+	rawKey, ok := key.(opencdc.RawData)
+	if !ok {
+		return w.encodeID(w.mapKey(parseKey(key, w.integers)))
+	}
+
+	if filter, ok := w.keyCache.get(rawKey); ok {
+		return filter
+	}
+
+	filter := w.encodeID(w.mapKey(parseKey(rawKey, w.integers)))
+	if len(filter) == 0 {
+		return filter
+	}
+
+	return w.keyCache.put(rawKey, filter)
+}
+
+// encodeID returns the fields with the _id field converted into the writer's _id type.
+// The fields are copied if they're changed, so they can be shared with a record.
+func (w *Writer) encodeID(fields opencdc.StructuredData) opencdc.StructuredData {
+	id, ok := fields[idFieldName]
+	if !ok || w.idType == codec.IDTypeAuto {
+		return fields
+	}
+
+	fields = maps.Clone(fields)
+	fields[idFieldName] = w.idType.Encode(id)
+
+	return fields
+}
+
+// mapKey maps fields of the parsed key to document fields.
+func (w *Writer) mapKey(keys opencdc.StructuredData) opencdc.StructuredData {
+	if len(w.keyMapping) == 0 || len(keys) == 0 {
+		return keys
+	}
+
+	filter := make(opencdc.StructuredData, len(keys))
+	for field, value := range keys {
+		if documentField, ok := w.keyMapping[field]; ok {
+			field = documentField
+		}
+
+		filter[field] = value
+	}
+
+	return filter
+}
+
+// keyFromPayload builds a key from the writer's key fields of the provided payload.
+// It returns nil if the key fields are empty or the payload misses at least one of them.
+func (w *Writer) keyFromPayload(payload opencdc.StructuredData) opencdc.StructuredData {
+	if len(w.keyFields) == 0 {
+		return nil
+	}
+
+	keys := make(opencdc.StructuredData, len(w.keyFields))
+	for _, field := range w.keyFields {
+		value, ok := payload[field]
+		if !ok {
+			return nil
+		}
+
+		keys[field] = value
+	}
+
+	return keys
+}
+
+// flattenFields flattens embedded documents of the payload into dot-notation paths,
+// e.g. {"address": {"city": "x"}} becomes {"address.city": "x"}.
+// Arrays and empty embedded documents are kept as they are.
+func flattenFields(payload map[string]any) map[string]any {
+	fields := make(map[string]any, len(payload))
+	for field, value := range payload {
+		flattenField(fields, field, value)
+	}
+
+	return fields
+}
+
+// flattenField puts the value under the path into the fields, flattening it if it's an embedded document.
+func flattenField(fields map[string]any, path string, value any) {
+	document, ok := value.(map[string]any)
+	if !ok || len(document) == 0 {
+		fields[path] = value
+
+		return
+	}
+
+	for field, fieldValue := range document {
+		flattenField(fields, path+"."+field, fieldValue)
+	}
+}
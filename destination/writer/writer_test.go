@@ -0,0 +1,449 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package writer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		key  opencdc.Data
+		want opencdc.StructuredData
+	}{
+		{
+			name: "structured_data",
+			key:  opencdc.StructuredData{"_id": 1, "name": "Bob"},
+			want: opencdc.StructuredData{"_id": 1, "name": "Bob"},
+		},
+		{
+			name: "raw_json_object",
+			key:  opencdc.RawData(`{"_id": "63bd5ee3ad5b1d4c6ad2b7e0", "name": "Bob"}`),
+			want: opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0", "name": "Bob"},
+		},
+		{
+			name: "raw_json_string",
+			key:  opencdc.RawData(`"63bd5ee3ad5b1d4c6ad2b7e0"`),
+			want: opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0"},
+		},
+		{
+			name: "raw_json_number",
+			key:  opencdc.RawData(`42`),
+			want: opencdc.StructuredData{"_id": int64(42)},
+		},
+		{
+			name: "raw_json_large_number",
+			key:  opencdc.RawData(`{"_id": 9007199254740993}`),
+			want: opencdc.StructuredData{"_id": int64(9007199254740993)},
+		},
+		{
+			name: "raw_plain_string",
+			key:  opencdc.RawData(`63bd5ee3ad5b1d4c6ad2b7e0`),
+			want: opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0"},
+		},
+		{
+			name: "raw_empty",
+			key:  opencdc.RawData(` `),
+			want: nil,
+		},
+		{
+			name: "raw_json_null",
+			key:  opencdc.RawData(`null`),
+			want: nil,
+		},
+		{
+			name: "nil",
+			key:  nil,
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := parseKey(tt.key, codec.IntegerDecodingInt64); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_keyFromPayload(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		keyFields []string
+		payload   opencdc.StructuredData
+		want      opencdc.StructuredData
+	}{
+		{
+			name:      "id_field",
+			keyFields: []string{"_id"},
+			payload:   opencdc.StructuredData{"_id": "1", "name": "Bob"},
+			want:      opencdc.StructuredData{"_id": "1"},
+		},
+		{
+			name:      "multiple_fields",
+			keyFields: []string{"tenant_id", "email"},
+			payload:   opencdc.StructuredData{"tenant_id": 1, "email": "bob@example.com", "name": "Bob"},
+			want:      opencdc.StructuredData{"tenant_id": 1, "email": "bob@example.com"},
+		},
+		{
+			name:      "missing_field",
+			keyFields: []string{"tenant_id", "email"},
+			payload:   opencdc.StructuredData{"tenant_id": 1, "name": "Bob"},
+			want:      nil,
+		},
+		{
+			name:      "disabled",
+			keyFields: nil,
+			payload:   opencdc.StructuredData{"_id": "1"},
+			want:      nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{KeyFields: tt.keyFields})
+			if got := w.keyFromPayload(tt.payload); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Writer.keyFromPayload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_parseKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		keyMapping map[string]string
+		key        opencdc.Data
+		want       opencdc.StructuredData
+	}{
+		{
+			name: "compound_key_without_mapping",
+			key:  opencdc.StructuredData{"tenant_id": 1, "email": "bob@example.com"},
+			want: opencdc.StructuredData{"tenant_id": 1, "email": "bob@example.com"},
+		},
+		{
+			name:       "compound_key_with_mapping",
+			keyMapping: map[string]string{"tenantId": "tenant.id", "mail": "email"},
+			key:        opencdc.RawData(`{"tenantId":1,"mail":"bob@example.com","region":"eu"}`),
+			want:       opencdc.StructuredData{"tenant.id": int64(1), "email": "bob@example.com", "region": "eu"},
+		},
+		{
+			name:       "plain_id_with_mapping",
+			keyMapping: map[string]string{"_id": "code"},
+			key:        opencdc.RawData("abc"),
+			want:       opencdc.StructuredData{"code": "abc"},
+		},
+		{
+			name:       "empty_key",
+			keyMapping: map[string]string{"_id": "code"},
+			key:        nil,
+			want:       nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{KeyMapping: tt.keyMapping})
+			if got := w.parseKey(tt.key); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Writer.parseKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_unmarshalPayload(t *testing.T) {
+	t.Parallel()
+
+	want := opencdc.StructuredData{"_id": "abc", "count": int64(1), "tags": []any{"a", "b"}}
+
+	tests := []struct {
+		name    string
+		buffers *codec.BufferPool
+		payload opencdc.Data
+	}{
+		{
+			name:    "raw_payload",
+			payload: opencdc.RawData(`{"_id":"abc","count":1,"tags":["a","b"]}`),
+		},
+		{
+			name:    "structured_payload_pooled",
+			buffers: codec.NewBufferPool(),
+			payload: opencdc.StructuredData{"_id": "abc", "count": 1, "tags": []string{"a", "b"}},
+		},
+		{
+			name:    "structured_payload_unpooled",
+			payload: opencdc.StructuredData{"_id": "abc", "count": 1, "tags": []string{"a", "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{Buffers: tt.buffers})
+
+			got, err := w.unmarshalPayload(tt.payload)
+			if err != nil {
+				t.Fatalf("Writer.unmarshalPayload() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Writer.unmarshalPayload() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestWriter_unmarshalPayload_integerDecoding(t *testing.T) {
+	t.Parallel()
+
+	payload := opencdc.RawData(`{"id":9007199254740993,"price":1.5,"items":[{"qty":2}]}`)
+
+	tests := []struct {
+		name     string
+		integers codec.IntegerDecoding
+		want     opencdc.StructuredData
+	}{
+		{
+			name:     "int64",
+			integers: codec.IntegerDecodingInt64,
+			want: opencdc.StructuredData{
+				"id": int64(9007199254740993), "price": 1.5, "items": []any{map[string]any{"qty": int64(2)}},
+			},
+		},
+		{
+			name:     "double",
+			integers: codec.IntegerDecodingDouble,
+			want: opencdc.StructuredData{
+				"id": float64(9007199254740993), "price": 1.5, "items": []any{map[string]any{"qty": float64(2)}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := NewWriter(Params{IntegerDecoding: tt.integers})
+
+			got, err := w.unmarshalPayload(payload)
+			if err != nil {
+				t.Fatalf("Writer.unmarshalPayload() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Writer.unmarshalPayload() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlattenFields(t *testing.T) {
+	t.Parallel()
+
+	payload := map[string]any{
+		"name": "Bob",
+		"address": map[string]any{
+			"city": "Kyiv",
+			"geo": map[string]any{
+				"lat": 50.45,
+			},
+		},
+		"tags":     []any{"a", map[string]any{"b": 1}},
+		"settings": map[string]any{},
+	}
+
+	want := map[string]any{
+		"name":            "Bob",
+		"address.city":    "Kyiv",
+		"address.geo.lat": 50.45,
+		"tags":            []any{"a", map[string]any{"b": 1}},
+		"settings":        map[string]any{},
+	}
+
+	if got := flattenFields(payload); !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenFields() = %v, want %v", got, want)
+	}
+}
+
+func TestIsTransientErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "timeout",
+			err:  context.DeadlineExceeded,
+			want: true,
+		},
+		{
+			name: "retryable_write_error",
+			err:  mongo.WriteException{Labels: []string{retryableWriteErrorLabel}},
+			want: true,
+		},
+		{
+			name: "duplicate_key",
+			err:  mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}},
+			want: false,
+		},
+		{
+			name: "empty_key",
+			err:  ErrEmptyKey,
+			want: false,
+		},
+		{
+			name: "wrapped_retryable_write_error",
+			err:  errors.Join(ErrEmptyKey, mongo.CommandError{Labels: []string{retryableWriteErrorLabel}}),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isTransientErr(tt.err); got != tt.want {
+				t.Errorf("isTransientErr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_Write_heartbeat(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Operation: opencdc.OperationUpdate,
+		Metadata:  opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeHeartbeat},
+		Key:       opencdc.StructuredData{"collection": "users"},
+	}
+
+	// the record must not reach the collection, which is nil here
+	if err := NewWriter(Params{}).Write(context.Background(), record); err != nil {
+		t.Fatalf("Writer.Write() error = %v", err)
+	}
+}
+
+func TestWriter_Write_collectionEvent(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Operation: opencdc.OperationUpdate,
+		Metadata:  opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeCollectionEvent},
+		Key:       opencdc.StructuredData{"collection": "users"},
+		Payload: opencdc.Change{
+			After: opencdc.StructuredData{"operationType": "drop", "db": "test", "collection": "users"},
+		},
+	}
+
+	// the record must not reach the collection, which is nil here
+	if err := NewWriter(Params{}).Write(context.Background(), record); err != nil {
+		t.Fatalf("Writer.Write() error = %v", err)
+	}
+}
+
+func TestWriter_onDuplicateKey(t *testing.T) {
+	t.Parallel()
+
+	duplicateKeyErr := mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}
+	errReplaced := errors.New("replaced")
+
+	tests := []struct {
+		name   string
+		policy DuplicateKeyPolicy
+		err    error
+		want   error
+	}{
+		{
+			name:   "fail",
+			policy: DuplicateKeyFail,
+			err:    duplicateKeyErr,
+			want:   duplicateKeyErr,
+		},
+		{
+			name:   "skip",
+			policy: DuplicateKeySkip,
+			err:    duplicateKeyErr,
+			want:   nil,
+		},
+		{
+			name:   "replace",
+			policy: DuplicateKeyReplace,
+			err:    duplicateKeyErr,
+			want:   errReplaced,
+		},
+		{
+			name:   "skip_other_error",
+			policy: DuplicateKeySkip,
+			err:    ErrEmptyKey,
+			want:   ErrEmptyKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := &Writer{duplicateKeyPolicy: tt.policy}
+
+			err := w.onDuplicateKey(context.Background(), opencdc.Record{}, tt.err, func() (bool, error) {
+				return true, errReplaced
+			})
+			if !reflect.DeepEqual(err, tt.want) {
+				t.Errorf("onDuplicateKey() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriter_onDuplicateKey_notMatched(t *testing.T) {
+	t.Parallel()
+
+	duplicateKeyErr := mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000}}}
+
+	w := &Writer{duplicateKeyPolicy: DuplicateKeyReplace}
+
+	// the duplicate is on another unique index, so the replacement by the record key matches no document
+	err := w.onDuplicateKey(context.Background(), opencdc.Record{}, duplicateKeyErr, func() (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, errDuplicateNotMatched) || !mongo.IsDuplicateKeyError(err) {
+		t.Errorf("onDuplicateKey() error = %v, want %v wrapping the duplicate key error", err, errDuplicateNotMatched)
+	}
+}
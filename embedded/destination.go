@@ -0,0 +1,73 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/destination"
+	"github.com/conduitio-labs/conduit-connector-mongo/metrics"
+	"github.com/conduitio-labs/conduit-connector-mongo/validator"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Destination writes records into a MongoDB collection.
+type Destination struct {
+	destination *destination.Destination
+}
+
+// NewDestination validates the config and creates a new [Destination] configured with it.
+func NewDestination(cfg destination.Config) (*Destination, error) {
+	if err := validator.ValidateStruct(&cfg); err != nil {
+		return nil, fmt.Errorf("validate destination config: %w", err)
+	}
+
+	return &Destination{destination: destination.NewDestinationWithConfig(cfg)}, nil
+}
+
+// Open connects to MongoDB and prepares the destination to write records.
+func (d *Destination) Open(ctx context.Context) error {
+	if err := d.destination.Open(ctx); err != nil {
+		return fmt.Errorf("open destination: %w", err)
+	}
+
+	return nil
+}
+
+// Write writes the records and returns the number of the leading records that are written.
+// If it's less than the number of the records, the error says why the next record isn't written.
+func (d *Destination) Write(ctx context.Context, records []opencdc.Record) (int, error) {
+	n, err := d.destination.Write(ctx, records)
+	if err != nil {
+		return n, fmt.Errorf("write records: %w", err)
+	}
+
+	return n, nil
+}
+
+// Close disconnects from MongoDB.
+func (d *Destination) Close(ctx context.Context) error {
+	if err := d.destination.Teardown(ctx); err != nil {
+		return fmt.Errorf("teardown destination: %w", err)
+	}
+
+	return nil
+}
+
+// Metrics returns the metrics of the records the destination writes.
+func (d *Destination) Metrics() *metrics.Destination {
+	return d.destination.Metrics()
+}
@@ -0,0 +1,161 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/destination"
+	"github.com/conduitio-labs/conduit-connector-mongo/source"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	testEnvNameURI = "CONNECTION_URI"
+	testDB         = "test_embedded"
+)
+
+func TestNewSource_parsedConfig(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	cfg, err := source.ParseConfig(map[string]string{
+		config.KeyDB:         "test",
+		config.KeyCollection: "users",
+	})
+	is.NoErr(err)
+
+	_, err = NewSource(cfg)
+	is.NoErr(err)
+}
+
+func TestNewDestination_parsedConfig(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	cfg, err := destination.ParseConfig(map[string]string{
+		config.KeyDB:         "test",
+		config.KeyCollection: "users",
+	})
+	is.NoErr(err)
+
+	_, err = NewDestination(cfg)
+	is.NoErr(err)
+}
+
+func TestNewSource_invalidConfig(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	_, err := NewSource(source.Config{Config: config.Config{DB: "test"}})
+	is.True(err != nil)
+}
+
+func TestNewDestination_invalidConfig(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	_, err := NewDestination(destination.Config{Config: config.Config{Collection: "users"}})
+	is.True(err != nil)
+}
+
+func TestEmbedded_writeAndRead(t *testing.T) {
+	is := is.New(t)
+
+	uri := os.Getenv(testEnvNameURI)
+	if uri == "" {
+		t.Skipf("%s env var must be set", testEnvNameURI)
+	}
+
+	raw := map[string]string{
+		config.KeyURI:        uri,
+		config.KeyDB:         testDB,
+		config.KeyCollection: fmt.Sprintf("test_coll_%d", time.Now().UnixNano()),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	conn, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	is.NoErr(err)
+
+	col := conn.Database(testDB).Collection(raw[config.KeyCollection])
+
+	err = conn.Database(testDB).CreateCollection(ctx, raw[config.KeyCollection])
+	is.NoErr(err)
+
+	t.Cleanup(func() {
+		err = col.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	destinationConfig, err := destination.ParseConfig(raw)
+	is.NoErr(err)
+
+	dest, err := NewDestination(destinationConfig)
+	is.NoErr(err)
+
+	err = dest.Open(ctx)
+	is.NoErr(err)
+
+	n, err := dest.Write(ctx, []opencdc.Record{{
+		Operation: opencdc.OperationCreate,
+		Payload:   opencdc.Change{After: opencdc.StructuredData{"name": "Ada"}},
+	}})
+	is.NoErr(err)
+	is.Equal(n, 1)
+
+	err = dest.Close(ctx)
+	is.NoErr(err)
+
+	sourceConfig, err := source.ParseConfig(raw)
+	is.NoErr(err)
+
+	src, err := NewSource(sourceConfig)
+	is.NoErr(err)
+
+	err = src.Open(ctx, nil)
+	is.NoErr(err)
+
+	for record, err := range src.Records(ctx) {
+		is.NoErr(err)
+		is.Equal(record.Operation, opencdc.OperationSnapshot)
+
+		err = src.Ack(ctx, record.Position)
+		is.NoErr(err)
+
+		break
+	}
+
+	err = src.Close(ctx)
+	is.NoErr(err)
+
+	count, err := col.CountDocuments(ctx, bson.D{})
+	is.NoErr(err)
+	is.Equal(count, int64(1))
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embedded exposes the source and the destination of the MongoDB connector
+// for embedding into Go programs, e.g. testing harnesses and custom runners, without Conduit.
+//
+// The connectors are configured with typed configs, which are best created with [source.ParseConfig]
+// and [destination.ParseConfig], so the defaults are filled in, and then adjusted as needed.
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/metrics"
+	"github.com/conduitio-labs/conduit-connector-mongo/source"
+	"github.com/conduitio-labs/conduit-connector-mongo/validator"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// readRetryInterval is the time the source waits for before it checks for new records again.
+const readRetryInterval = 100 * time.Millisecond
+
+// Source reads records from a MongoDB collection.
+type Source struct {
+	source *source.Source
+}
+
+// NewSource validates the config and creates a new [Source] configured with it.
+func NewSource(cfg source.Config) (*Source, error) {
+	if err := validator.ValidateStruct(&cfg); err != nil {
+		return nil, fmt.Errorf("validate source config: %w", err)
+	}
+
+	return &Source{source: source.NewSourceWithConfig(cfg)}, nil
+}
+
+// Open connects to MongoDB and prepares the source to read records starting after the position.
+// If the position is nil, the source starts from scratch.
+func (s *Source) Open(ctx context.Context, position opencdc.Position) error {
+	if err := s.source.Open(ctx, position); err != nil {
+		return fmt.Errorf("open source: %w", err)
+	}
+
+	return nil
+}
+
+// Read returns the next record, waiting until there's one or the context is done.
+func (s *Source) Read(ctx context.Context) (opencdc.Record, error) {
+	for {
+		record, err := s.source.Read(ctx)
+		if err == nil {
+			return record, nil
+		}
+
+		if !errors.Is(err, sdk.ErrBackoffRetry) {
+			return opencdc.Record{}, fmt.Errorf("read record: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return opencdc.Record{}, fmt.Errorf("read record: %w", ctx.Err())
+		case <-time.After(readRetryInterval):
+		}
+	}
+}
+
+// Records returns an iterator over the records the source reads. The iteration stops without an error
+// once the context is done, and after the first error otherwise.
+func (s *Source) Records(ctx context.Context) iter.Seq2[opencdc.Record, error] {
+	return func(yield func(opencdc.Record, error) bool) {
+		for {
+			record, err := s.Read(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					yield(opencdc.Record{}, err)
+				}
+
+				return
+			}
+
+			if !yield(record, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Ack acknowledges that the record with the position has been processed.
+func (s *Source) Ack(ctx context.Context, position opencdc.Position) error {
+	if err := s.source.Ack(ctx, position); err != nil {
+		return fmt.Errorf("ack position: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops reading and disconnects from MongoDB.
+func (s *Source) Close(ctx context.Context) error {
+	if err := s.source.Teardown(ctx); err != nil {
+		return fmt.Errorf("teardown source: %w", err)
+	}
+
+	return nil
+}
+
+// Metrics returns the metrics of the records the source reads.
+func (s *Source) Metrics() *metrics.Source {
+	return s.source.Metrics()
+}
@@ -12,6 +12,7 @@ require (
 	go.mongodb.org/mongo-driver v1.17.2
 	go.uber.org/mock v0.5.0
 	go.uber.org/multierr v1.11.0
+	golang.org/x/time v0.8.0
 )
 
 require (
@@ -228,7 +229,6 @@ require (
 	golang.org/x/sync v0.10.0 // indirect
 	golang.org/x/sys v0.29.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
-	golang.org/x/time v0.8.0 // indirect
 	golang.org/x/tools v0.28.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240930140551-af27646dc61f // indirect
 	google.golang.org/grpc v1.68.0 // indirect
@@ -0,0 +1,85 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Destination contains the metrics of a destination connector. A nil Destination observes nothing.
+type Destination struct {
+	// Inserts is the number of documents written by create and snapshot records.
+	Inserts Counter
+	// Updates is the number of documents written by update records.
+	Updates Counter
+	// Deletes is the number of documents deleted by delete records.
+	Deletes Counter
+	// Retries is the number of times writes failed with transient errors were retried.
+	Retries Counter
+	// Errors is the number of records failed to be written.
+	Errors Counter
+	// LastEventTime is the time of the latest written record's event as Unix milliseconds.
+	LastEventTime Gauge
+}
+
+// ObserveWrite updates the metrics with the record written by the destination.
+func (d *Destination) ObserveWrite(record opencdc.Record) {
+	if d == nil || isControlRecord(record) {
+		return
+	}
+
+	switch record.Operation {
+	case opencdc.OperationCreate, opencdc.OperationSnapshot:
+		d.Inserts.Inc()
+	case opencdc.OperationUpdate:
+		d.Updates.Inc()
+	case opencdc.OperationDelete:
+		d.Deletes.Inc()
+	}
+
+	if createdAt, err := record.Metadata.GetCreatedAt(); err == nil {
+		d.LastEventTime.Set(createdAt.UnixMilli())
+	}
+}
+
+// ObserveRetry updates the metrics with a retried write.
+func (d *Destination) ObserveRetry() {
+	if d == nil {
+		return
+	}
+
+	d.Retries.Inc()
+}
+
+// ObserveError updates the metrics with a failed write.
+func (d *Destination) ObserveError() {
+	if d == nil {
+		return
+	}
+
+	d.Errors.Inc()
+}
+
+// String returns a JSON object of the metrics, so they can be published as an [expvar.Var].
+func (d *Destination) String() string {
+	return marshal(map[string]int64{
+		"inserts":       d.Inserts.Value(),
+		"updates":       d.Updates.Value(),
+		"deletes":       d.Deletes.Value(),
+		"retries":       d.Retries.Value(),
+		"errors":        d.Errors.Value(),
+		"lastEventTime": d.LastEventTime.Value(),
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics contains the counters and gauges of the connector's reads and writes,
+// so operators can build dashboards without scraping logs.
+//
+// The SDK doesn't provide connectors with metric hooks, so the metrics of every connector are published
+// as an [expvar] variable of the "mongo" map, keyed by the connector ID and its type, e.g. "pipeline:mongo.source".
+// Programs embedding the connectors can read the metrics directly instead.
+package metrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// connectors is the map the metrics of connectors are published to.
+var connectors = expvar.NewMap("mongo")
+
+// Publish publishes the metrics as an [expvar] variable of the "mongo" map under the provided name.
+// The metrics published under the same name before are replaced.
+func Publish(name string, metrics expvar.Var) {
+	connectors.Set(name, metrics)
+}
+
+// Unpublish removes the metrics published under the provided name.
+func Unpublish(name string) {
+	connectors.Delete(name)
+}
+
+// Counter is a monotonically increasing value that's safe for concurrent use.
+type Counter struct {
+	value atomic.Int64
+}
+
+// Add increases the counter by the delta.
+func (c *Counter) Add(delta int64) {
+	c.value.Add(delta)
+}
+
+// Inc increases the counter by one.
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+// String returns the current value of the counter, so it can be published as an [expvar.Var].
+func (c *Counter) String() string {
+	return strconv.FormatInt(c.Value(), 10)
+}
+
+// Gauge is a value that can go up and down, safe for concurrent use.
+type Gauge struct {
+	value atomic.Int64
+}
+
+// Set sets the value of the gauge.
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+// String returns the current value of the gauge, so it can be published as an [expvar.Var].
+func (g *Gauge) String() string {
+	return strconv.FormatInt(g.Value(), 10)
+}
+
+// isControlRecord checks whether the record is a control one, which carries no document.
+func isControlRecord(record opencdc.Record) bool {
+	return record.Metadata[codec.MetadataFieldRecordType] != ""
+}
+
+// marshal returns a JSON object of the metrics by their names.
+func marshal(metrics map[string]int64) string {
+	// a map of integers is always marshaled successfully
+	metricsBytes, _ := json.Marshal(metrics) //nolint:errchkjson // see the comment above
+
+	return string(metricsBytes)
+}
@@ -0,0 +1,182 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestSource_ObserveRecord(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	createdAt := time.UnixMilli(1700000000000)
+
+	record := func(operation opencdc.Operation, metadata opencdc.Metadata) opencdc.Record {
+		if metadata == nil {
+			metadata = make(opencdc.Metadata)
+		}
+		metadata.SetCreatedAt(createdAt)
+
+		return opencdc.Record{Operation: operation, Metadata: metadata}
+	}
+
+	var metrics Source
+	metrics.ObserveRecord(record(opencdc.OperationSnapshot, nil))
+	metrics.ObserveRecord(record(opencdc.OperationSnapshot, nil))
+	metrics.ObserveRecord(record(opencdc.OperationCreate, nil))
+	metrics.ObserveRecord(record(opencdc.OperationUpdate, opencdc.Metadata{codec.MetadataFieldRetryAttempts: "2"}))
+	metrics.ObserveRecord(record(opencdc.OperationDelete, nil))
+	metrics.ObserveRecord(record(opencdc.OperationCreate, opencdc.Metadata{codec.MetadataFieldRecordType: "heartbeat"}))
+	metrics.ObserveError()
+
+	is.Equal(metrics.SnapshotRecords.Value(), int64(2))
+	is.Equal(metrics.CDCCreates.Value(), int64(1))
+	is.Equal(metrics.CDCUpdates.Value(), int64(1))
+	is.Equal(metrics.CDCDeletes.Value(), int64(1))
+	is.Equal(metrics.Retries.Value(), int64(2))
+	is.Equal(metrics.Errors.Value(), int64(1))
+	is.Equal(metrics.LastEventTime.Value(), createdAt.UnixMilli())
+
+	var got map[string]int64
+	is.NoErr(json.Unmarshal([]byte(metrics.String()), &got))
+	is.Equal(got["snapshotRecords"], int64(2))
+	is.Equal(got["lastEventTime"], createdAt.UnixMilli())
+}
+
+func TestDestination_ObserveWrite(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	var metrics Destination
+	metrics.ObserveWrite(opencdc.Record{Operation: opencdc.OperationSnapshot})
+	metrics.ObserveWrite(opencdc.Record{Operation: opencdc.OperationCreate})
+	metrics.ObserveWrite(opencdc.Record{Operation: opencdc.OperationUpdate})
+	metrics.ObserveWrite(opencdc.Record{Operation: opencdc.OperationDelete})
+	metrics.ObserveWrite(opencdc.Record{
+		Operation: opencdc.OperationCreate,
+		Metadata:  opencdc.Metadata{codec.MetadataFieldRecordType: "collectionMetadata"},
+	})
+	metrics.ObserveRetry()
+	metrics.ObserveError()
+
+	is.Equal(metrics.Inserts.Value(), int64(2))
+	is.Equal(metrics.Updates.Value(), int64(1))
+	is.Equal(metrics.Deletes.Value(), int64(1))
+	is.Equal(metrics.Retries.Value(), int64(1))
+	is.Equal(metrics.Errors.Value(), int64(1))
+	is.Equal(metrics.LastEventTime.Value(), int64(0))
+}
+
+func TestNilMetrics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		source      *Source
+		destination *Destination
+	)
+
+	// nil metrics observe nothing instead of panicking
+	source.ObserveRecord(opencdc.Record{Operation: opencdc.OperationCreate})
+	source.ObserveError()
+	destination.ObserveWrite(opencdc.Record{Operation: opencdc.OperationCreate})
+	destination.ObserveRetry()
+	destination.ObserveError()
+}
+
+func TestSource_observeRecord_replicationLag(t *testing.T) {
+	t.Parallel()
+
+	emittedAt := time.Unix(1700000010, 0)
+
+	tests := []struct {
+		name     string
+		metadata opencdc.Metadata
+		want     int64
+	}{
+		{
+			name: "wall_time",
+			metadata: opencdc.Metadata{
+				codec.MetadataFieldClusterTime: "1700000005.3",
+				opencdc.MetadataCreatedAt: strconv.FormatInt(
+					time.Unix(1700000005, 0).Add(250*time.Millisecond).UnixNano(), 10,
+				),
+			},
+			want: 4750,
+		},
+		{
+			name: "no_wall_time",
+			metadata: opencdc.Metadata{
+				codec.MetadataFieldClusterTime: "1700000005.3",
+				opencdc.MetadataCreatedAt:      strconv.FormatInt(time.Unix(1700000009, 0).UnixNano(), 10),
+			},
+			want: 5000,
+		},
+		{
+			name: "oplog",
+			metadata: opencdc.Metadata{
+				opencdc.MetadataCreatedAt: strconv.FormatInt(time.Unix(1700000008, 0).UnixNano(), 10),
+			},
+			want: 2000,
+		},
+		{
+			name: "clock_drift",
+			metadata: opencdc.Metadata{
+				opencdc.MetadataCreatedAt: strconv.FormatInt(time.Unix(1700000020, 0).UnixNano(), 10),
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			tt.metadata[codec.MetadataFieldCaptureMode] = codec.CaptureModeCDC
+
+			// a negative lag makes sure the lag is set by the record
+			var metrics Source
+			metrics.ReplicationLag.Set(-1)
+			metrics.observeRecord(opencdc.Record{Operation: opencdc.OperationUpdate, Metadata: tt.metadata}, emittedAt)
+
+			is.Equal(metrics.ReplicationLag.Value(), tt.want)
+		})
+	}
+}
+
+func TestSource_observeRecord_replicationLagSnapshot(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	metadata := opencdc.Metadata{codec.MetadataFieldCaptureMode: "snapshot"}
+	metadata.SetCreatedAt(time.Unix(1700000000, 0))
+
+	var metrics Source
+	metrics.observeRecord(opencdc.Record{Operation: opencdc.OperationSnapshot, Metadata: metadata}, time.Now())
+
+	is.Equal(metrics.ReplicationLag.Value(), int64(0))
+}
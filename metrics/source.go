@@ -0,0 +1,140 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Source contains the metrics of a source connector. A nil Source observes nothing.
+type Source struct {
+	// SnapshotRecords is the number of documents read by snapshots.
+	SnapshotRecords Counter
+	// CDCCreates is the number of insert events read from the collection.
+	CDCCreates Counter
+	// CDCUpdates is the number of update and replace events read from the collection.
+	CDCUpdates Counter
+	// CDCDeletes is the number of delete events read from the collection.
+	CDCDeletes Counter
+	// Retries is the number of times reading changes was retried, e.g. the Change Stream was recreated.
+	Retries Counter
+	// Errors is the number of reads failed with an error.
+	Errors Counter
+	// LastEventTime is the time of the latest read event as Unix milliseconds.
+	LastEventTime Gauge
+	// ReplicationLag is the time in milliseconds between the latest change event happened on the server
+	// and the source emitted it. Heartbeats reset it, so it doesn't stay stale on idle collections.
+	ReplicationLag Gauge
+}
+
+// ObserveRecord updates the metrics with the record the source is emitting.
+func (s *Source) ObserveRecord(record opencdc.Record) {
+	s.observeRecord(record, time.Now())
+}
+
+// observeRecord updates the metrics with the record emitted at the provided time.
+func (s *Source) observeRecord(record opencdc.Record, emittedAt time.Time) {
+	if s == nil {
+		return
+	}
+
+	if record.Metadata[codec.MetadataFieldCaptureMode] == codec.CaptureModeCDC {
+		if occurredAt, ok := eventTime(record); ok {
+			// the clocks of the server and the connector can drift apart, but the lag is never negative
+			s.ReplicationLag.Set(max(emittedAt.Sub(occurredAt).Milliseconds(), 0))
+		}
+	}
+
+	if attempts, err := strconv.ParseInt(record.Metadata[codec.MetadataFieldRetryAttempts], 10, 64); err == nil {
+		s.Retries.Add(attempts)
+	}
+
+	if isControlRecord(record) {
+		return
+	}
+
+	switch record.Operation {
+	case opencdc.OperationSnapshot:
+		s.SnapshotRecords.Inc()
+	case opencdc.OperationCreate:
+		s.CDCCreates.Inc()
+	case opencdc.OperationUpdate:
+		s.CDCUpdates.Inc()
+	case opencdc.OperationDelete:
+		s.CDCDeletes.Inc()
+	}
+
+	if createdAt, err := record.Metadata.GetCreatedAt(); err == nil {
+		s.LastEventTime.Set(createdAt.UnixMilli())
+	}
+}
+
+// eventTime returns the time the change event of the record happened on the server.
+// The wall time the record is created at is preferred, as it's precise to milliseconds,
+// but events of old servers have none and are created at the time they're read,
+// so the cluster time, precise to seconds, is used if the creation time doesn't match it.
+func eventTime(record opencdc.Record) (time.Time, bool) {
+	createdAt, err := record.Metadata.GetCreatedAt()
+
+	clusterTime, ok := parseClusterTime(record.Metadata[codec.MetadataFieldClusterTime])
+	switch {
+	case !ok:
+		return createdAt, err == nil
+	case err == nil && !createdAt.Before(clusterTime) && createdAt.Before(clusterTime.Add(time.Second)):
+		return createdAt, true
+	default:
+		return clusterTime, true
+	}
+}
+
+// parseClusterTime parses the seconds of a "<seconds>.<increment>" cluster time.
+func parseClusterTime(value string) (time.Time, bool) {
+	seconds, _, _ := strings.Cut(value, ".")
+
+	t, err := strconv.ParseUint(seconds, 10, 32)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(t), 0), true
+}
+
+// ObserveError updates the metrics with a failed read.
+func (s *Source) ObserveError() {
+	if s == nil {
+		return
+	}
+
+	s.Errors.Inc()
+}
+
+// String returns a JSON object of the metrics, so they can be published as an [expvar.Var].
+func (s *Source) String() string {
+	return marshal(map[string]int64{
+		"snapshotRecords": s.SnapshotRecords.Value(),
+		"cdcCreates":      s.CDCCreates.Value(),
+		"cdcUpdates":      s.CDCUpdates.Value(),
+		"cdcDeletes":      s.CDCDeletes.Value(),
+		"retries":         s.Retries.Value(),
+		"errors":          s.Errors.Value(),
+		"lastEventTime":   s.LastEventTime.Value(),
+		"replicationLag":  s.ReplicationLag.Value(),
+	})
+}
@@ -15,11 +15,27 @@
 package source
 
 import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/source/iterator"
+	"github.com/conduitio-labs/conduit-connector-mongo/transform"
 	"github.com/conduitio-labs/conduit-connector-mongo/validator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
 )
 
 const (
@@ -29,6 +45,82 @@ const (
 	defaultSnapshot = true
 	// defaultOrderingField is the default value for the orderingField field.
 	defaultOrderingField = "_id"
+	// defaultSnapshotOnStaleToken is the default value for the snapshot.onStaleToken field.
+	defaultSnapshotOnStaleToken = StaleTokenFail
+	// defaultPayloadFormat is the default value for the payload.format field.
+	defaultPayloadFormat = iterator.PayloadFormatJSON
+	// defaultKeyFormat is the default value for the key.format field.
+	defaultKeyFormat = iterator.KeyFormatStructured
+	// defaultConvertDateTime is the default value for the convert.dateTime field.
+	defaultConvertDateTime = codec.DateTimeFormatRFC3339
+	// defaultConvertTimestamp is the default value for the convert.timestamp field.
+	defaultConvertTimestamp = codec.TimestampFormatTimestamp
+	// defaultConvertDecimal is the default value for the convert.decimal field.
+	defaultConvertDecimal = codec.DecimalFormatString
+	// defaultConvertUUID is the default value for the convert.uuid field.
+	defaultConvertUUID = codec.UUIDFormatBytes
+	// defaultConvertBinary is the default value for the convert.binary field.
+	defaultConvertBinary = codec.BinaryFormatBytes
+	// defaultStrictTypes is the default value for the strictTypes field.
+	defaultStrictTypes = false
+	// defaultSnapshotCollectionMetadata is the default value for the snapshot.collectionMetadata field.
+	defaultSnapshotCollectionMetadata = false
+	// defaultSchemaMode is the default value for the schema.mode field.
+	defaultSchemaMode = iterator.SchemaModeNone
+	// defaultSchemaSampleSize is the default value for the schema.sampleSize field.
+	defaultSchemaSampleSize = 100
+	// defaultSchemaDrift is the default value for the schema.drift field.
+	defaultSchemaDrift = iterator.SchemaDriftModeNone
+	// defaultCDCVerifyResume is the default value for the cdc.verifyResume field.
+	defaultCDCVerifyResume = false
+	// defaultCDCCollectionEvents is the default value for the cdc.collectionEvents field.
+	defaultCDCCollectionEvents = false
+	// defaultSnapshotMode is the default value for the snapshot.mode field.
+	defaultSnapshotMode = iterator.SnapshotModeBlocking
+	// defaultSnapshotMaxBatchBytes is the default value for the snapshot.maxBatchBytes field.
+	defaultSnapshotMaxBatchBytes = 0
+	// defaultSnapshotAllowDiskUse is the default value for the snapshot.allowDiskUse field.
+	defaultSnapshotAllowDiskUse = true
+	// defaultSnapshotSharded is the default value for the snapshot.sharded field.
+	defaultSnapshotSharded = false
+	// defaultSnapshotConsistent is the default value for the snapshot.consistent field.
+	defaultSnapshotConsistent = false
+	// defaultSnapshotParallelism is the default value for the snapshot.parallelism field.
+	defaultSnapshotParallelism = 4
+	// defaultSnapshotResumeBoundary is the default value for the snapshot.resumeBoundary field.
+	defaultSnapshotResumeBoundary = iterator.ResumeBoundaryExclusive
+	// defaultRateLimit is the default value for the rateLimit field.
+	defaultRateLimit = 0
+	// defaultCDCMaxRetries is the default value for the cdc.maxRetries field.
+	defaultCDCMaxRetries = 0
+	// defaultCDCHeartbeatInterval is the default value for the cdc.heartbeatInterval field.
+	defaultCDCHeartbeatInterval = time.Duration(0)
+	// defaultCDCMaxAwaitTime is the default value for the cdc.maxAwaitTime field.
+	defaultCDCMaxAwaitTime = time.Duration(0)
+	// defaultCDCPrefetchSize is the default value for the cdc.prefetchSize field.
+	defaultCDCPrefetchSize = 0
+	// defaultCDCSuppressUnchanged is the default value for the cdc.suppressUnchanged field.
+	defaultCDCSuppressUnchanged = false
+	// defaultCDCSuppressCacheSize is the default value for the cdc.suppressCacheSize field.
+	defaultCDCSuppressCacheSize = 10000
+	// defaultCDCMode is the default value for the cdc.mode field.
+	defaultCDCMode = iterator.CDCModeAuto
+	// defaultCDCCoalesceWindow is the default value for the cdc.coalesceWindow field.
+	defaultCDCCoalesceWindow = time.Duration(0)
+	// defaultCDCCoalesceMaxSize is the default value for the cdc.coalesceMaxSize field.
+	defaultCDCCoalesceMaxSize = 1000
+	// defaultPollingDeleteStrategy is the default value for the polling.deleteStrategy field.
+	defaultPollingDeleteStrategy = iterator.DeleteStrategyNone
+	// defaultPollingDeleteCheckInterval is the default value for the polling.deleteCheckInterval field.
+	defaultPollingDeleteCheckInterval = time.Minute
+	// defaultCompatibility is the default value for the compatibility field.
+	defaultCompatibility = iterator.CompatibilityNone
+	// defaultDBRefMode is the default value for the dbref.mode field.
+	defaultDBRefMode = iterator.DBRefModeNone
+	// defaultDBRefMaxDepth is the default value for the dbref.maxDepth field.
+	defaultDBRefMaxDepth = 1
+	// defaultPartitionKey is the default value for the partitionKey field.
+	defaultPartitionKey = iterator.PartitionKeyModeNone
 )
 
 const (
@@ -38,6 +130,142 @@ const (
 	ConfigKeySnapshot = "snapshot"
 	// ConfigKeyOrderingField is a config name for a orderingField field.
 	ConfigKeyOrderingField = "orderingField"
+	// ConfigKeySnapshotOnStaleToken is a config name for a snapshot.onStaleToken field.
+	ConfigKeySnapshotOnStaleToken = "snapshot.onStaleToken"
+	// ConfigKeyPayloadFormat is a config name for a payload.format field.
+	ConfigKeyPayloadFormat = "payload.format"
+	// ConfigKeyKeyFormat is a config name for a key.format field.
+	ConfigKeyKeyFormat = "key.format"
+	// ConfigKeyCDCStartAtOperationTime is a config name for a cdc.startAtOperationTime field.
+	ConfigKeyCDCStartAtOperationTime = "cdc.startAtOperationTime"
+	// ConfigKeyCDCStopAtOperationTime is a config name for a cdc.stopAtOperationTime field.
+	ConfigKeyCDCStopAtOperationTime = "cdc.stopAtOperationTime"
+	// ConfigKeyCDCResumeToken is a config name for a cdc.resumeToken field.
+	ConfigKeyCDCResumeToken = "cdc.resumeToken"
+	// ConfigKeyConvertDateTime is a config name for a convert.dateTime field.
+	ConfigKeyConvertDateTime = "convert.dateTime"
+	// ConfigKeyConvertTimestamp is a config name for a convert.timestamp field.
+	ConfigKeyConvertTimestamp = "convert.timestamp"
+	// ConfigKeyConvertDecimal is a config name for a convert.decimal field.
+	ConfigKeyConvertDecimal = "convert.decimal"
+	// ConfigKeyConvertUUID is a config name for a convert.uuid field.
+	ConfigKeyConvertUUID = "convert.uuid"
+	// ConfigKeyConvertBinary is a config name for a convert.binary field.
+	ConfigKeyConvertBinary = "convert.binary"
+	// ConfigKeyStrictTypes is a config name for a strictTypes field.
+	ConfigKeyStrictTypes = "strictTypes"
+	// ConfigKeySnapshotCollectionMetadata is a config name for a snapshot.collectionMetadata field.
+	ConfigKeySnapshotCollectionMetadata = "snapshot.collectionMetadata"
+	// ConfigKeySchemaMode is a config name for a schema.mode field.
+	ConfigKeySchemaMode = "schema.mode"
+	// ConfigKeySchemaSampleSize is a config name for a schema.sampleSize field.
+	ConfigKeySchemaSampleSize = "schema.sampleSize"
+	// ConfigKeySchemaDrift is a config name for a schema.drift field.
+	ConfigKeySchemaDrift = "schema.drift"
+	// ConfigKeyCDCVerifyResume is a config name for a cdc.verifyResume field.
+	ConfigKeyCDCVerifyResume = "cdc.verifyResume"
+	// ConfigKeyCDCCollectionEvents is a config name for a cdc.collectionEvents field.
+	ConfigKeyCDCCollectionEvents = "cdc.collectionEvents"
+	// ConfigKeySnapshotMode is a config name for a snapshot.mode field.
+	ConfigKeySnapshotMode = "snapshot.mode"
+	// ConfigKeySnapshotTrigger is a config name for a snapshot.trigger field.
+	ConfigKeySnapshotTrigger = "snapshot.trigger"
+	// ConfigKeySnapshotMaxBatchBytes is a config name for a snapshot.maxBatchBytes field.
+	ConfigKeySnapshotMaxBatchBytes = "snapshot.maxBatchBytes"
+	// ConfigKeySnapshotCheckpointRecords is a config name for a snapshot.checkpointRecords field.
+	ConfigKeySnapshotCheckpointRecords = "snapshot.checkpointRecords"
+	// ConfigKeySnapshotCheckpointInterval is a config name for a snapshot.checkpointInterval field.
+	ConfigKeySnapshotCheckpointInterval = "snapshot.checkpointInterval"
+	// ConfigKeySnapshotMaxDocsPerSecond is a config name for a snapshot.maxDocsPerSecond field.
+	ConfigKeySnapshotMaxDocsPerSecond = "snapshot.maxDocsPerSecond"
+	// ConfigKeySnapshotAllowDiskUse is a config name for a snapshot.allowDiskUse field.
+	ConfigKeySnapshotAllowDiskUse = "snapshot.allowDiskUse"
+	// ConfigKeySnapshotResumeBoundary is a config name for a snapshot.resumeBoundary field.
+	ConfigKeySnapshotResumeBoundary = "snapshot.resumeBoundary"
+	// ConfigKeySnapshotSharded is a config name for a snapshot.sharded field.
+	ConfigKeySnapshotSharded = "snapshot.sharded"
+	// ConfigKeySnapshotConsistent is a config name for a snapshot.consistent field.
+	ConfigKeySnapshotConsistent = "snapshot.consistent"
+	// ConfigKeySnapshotParallelism is a config name for a snapshot.parallelism field.
+	ConfigKeySnapshotParallelism = "snapshot.parallelism"
+	// ConfigKeySnapshotURI is a config name for a snapshot.uri field.
+	ConfigKeySnapshotURI = "snapshot.uri"
+	// ConfigKeyReadConcernLevel is a config name for a readConcern.level field.
+	ConfigKeyReadConcernLevel = "readConcern.level"
+	// ConfigKeyReadPreferenceMode is a config name for a readPreference.mode field.
+	ConfigKeyReadPreferenceMode = "readPreference.mode"
+	// ConfigKeyReadPreferenceTags is a config name for a readPreference.tags field.
+	ConfigKeyReadPreferenceTags = "readPreference.tags"
+	// ConfigKeySignalCollection is a config name for a signal.collection field.
+	ConfigKeySignalCollection = "signal.collection"
+	// ConfigKeyRateLimit is a config name for a rateLimit field.
+	ConfigKeyRateLimit = "rateLimit"
+	// ConfigKeyCDCMaxRetries is a config name for a cdc.maxRetries field.
+	ConfigKeyCDCMaxRetries = "cdc.maxRetries"
+	// ConfigKeyCDCHeartbeatInterval is a config name for a cdc.heartbeatInterval field.
+	ConfigKeyCDCHeartbeatInterval = "cdc.heartbeatInterval"
+	// ConfigKeyCDCMaxAwaitTime is a config name for a cdc.maxAwaitTime field.
+	ConfigKeyCDCMaxAwaitTime = "cdc.maxAwaitTime"
+	// ConfigKeyCDCPrefetchSize is a config name for a cdc.prefetchSize field.
+	ConfigKeyCDCPrefetchSize = "cdc.prefetchSize"
+	// ConfigKeyCDCSuppressUnchanged is a config name for a cdc.suppressUnchanged field.
+	ConfigKeyCDCSuppressUnchanged = "cdc.suppressUnchanged"
+	// ConfigKeyCDCSuppressCacheSize is a config name for a cdc.suppressCacheSize field.
+	ConfigKeyCDCSuppressCacheSize = "cdc.suppressCacheSize"
+	// ConfigKeyCDCMode is a config name for a cdc.mode field.
+	ConfigKeyCDCMode = "cdc.mode"
+	// ConfigKeyCDCCoalesceWindow is a config name for a cdc.coalesceWindow field.
+	ConfigKeyCDCCoalesceWindow = "cdc.coalesceWindow"
+	// ConfigKeyCDCCoalesceMaxSize is a config name for a cdc.coalesceMaxSize field.
+	ConfigKeyCDCCoalesceMaxSize = "cdc.coalesceMaxSize"
+	// ConfigKeyPollingUpdatedAtField is a config name for a polling.updatedAtField field.
+	ConfigKeyPollingUpdatedAtField = "polling.updatedAtField"
+	// ConfigKeyPollingDeleteStrategy is a config name for a polling.deleteStrategy field.
+	ConfigKeyPollingDeleteStrategy = "polling.deleteStrategy"
+	// ConfigKeyPollingSoftDeleteField is a config name for a polling.softDeleteField field.
+	ConfigKeyPollingSoftDeleteField = "polling.softDeleteField"
+	// ConfigKeyPollingDeleteCheckInterval is a config name for a polling.deleteCheckInterval field.
+	ConfigKeyPollingDeleteCheckInterval = "polling.deleteCheckInterval"
+	// ConfigKeyCompatibility is a config name for a compatibility field.
+	ConfigKeyCompatibility = "compatibility"
+	// ConfigKeyCSFLEKeyVaultNamespace is a config name for a csfle.keyVaultNamespace field.
+	ConfigKeyCSFLEKeyVaultNamespace = "csfle.keyVaultNamespace"
+	// ConfigKeyCSFLEKMSProviders is a config name for a csfle.kmsProviders field.
+	ConfigKeyCSFLEKMSProviders = "csfle.kmsProviders"
+	// ConfigKeyCSFLESchemaMap is a config name for a csfle.schemaMap field.
+	ConfigKeyCSFLESchemaMap = "csfle.schemaMap"
+	// ConfigKeyTransformFilter is a config name for a transform.filter field.
+	ConfigKeyTransformFilter = "transform.filter"
+	// ConfigKeyTransformFields is a config name for a transform.fields field.
+	ConfigKeyTransformFields = "transform.fields"
+	// ConfigKeyDBRefMode is a config name for a dbref.mode field.
+	ConfigKeyDBRefMode = "dbref.mode"
+	// ConfigKeyDBRefMaxDepth is a config name for a dbref.maxDepth field.
+	ConfigKeyDBRefMaxDepth = "dbref.maxDepth"
+	// ConfigKeyPartitionKey is a config name for a partitionKey field.
+	ConfigKeyPartitionKey = "partitionKey"
+)
+
+// errEmptyOrderingField occurs when the orderingField field contains an empty field name.
+var errEmptyOrderingField = errors.New("must not contain an empty field name")
+
+// errInvalidKeyVaultNamespace occurs when the csfle.keyVaultNamespace field is not a namespace of a collection.
+var errInvalidKeyVaultNamespace = errors.New("must be in the <db>.<collection> format")
+
+// errInvalidReadPreferenceTag occurs when the readPreference.tags field contains a tag not in the name:value format.
+var errInvalidReadPreferenceTag = errors.New("must contain tags in the <name>:<value> format")
+
+// StaleTokenStrategy defines what the connector does when a stored resume token
+// is no longer present in the oplog and the Change Stream cannot be resumed.
+type StaleTokenStrategy string
+
+// The list of available stale token strategies is listed below.
+const (
+	// StaleTokenFail makes the connector fail to open.
+	StaleTokenFail StaleTokenStrategy = "fail"
+	// StaleTokenResnapshot makes the connector discard the stored position,
+	// take a fresh snapshot and start CDC from the current time.
+	StaleTokenResnapshot StaleTokenStrategy = "resnapshot"
 )
 
 // Config contains source-specific configurable values.
@@ -51,10 +279,159 @@ type Config struct {
 	Snapshot bool `key:"snapshot"`
 	// OrderingField is the name of a field that is used for ordering
 	// collection documents when capturing a snapshot.
+	// It may be a comma-separated list of fields forming a compound sort key.
 	OrderingField string `key:"orderingField"`
+	// SnapshotOnStaleToken determines what the connector does
+	// when a stored resume token has aged out of the oplog.
+	SnapshotOnStaleToken StaleTokenStrategy `key:"snapshot.onStaleToken" validate:"oneof=fail resnapshot"`
+	// PayloadFormat is the format of records' payloads.
+	PayloadFormat iterator.PayloadFormat `key:"payload.format" validate:"oneof=json extjson debezium"`
+	// KeyFormat is the format of records' keys.
+	KeyFormat iterator.KeyFormat `key:"key.format" validate:"oneof=structured json string"`
+	// CDCStartAtOperationTime is a cluster time the Change Stream starts from
+	// if there's no resume token to resume from.
+	CDCStartAtOperationTime *primitive.Timestamp `key:"cdc.startAtOperationTime"`
+	// CDCStopAtOperationTime is a cluster time CDC stops at, so changes after it aren't captured.
+	CDCStopAtOperationTime *primitive.Timestamp `key:"cdc.stopAtOperationTime"`
+	// CDCResumeToken is a Change Stream resume token supplied by an operator
+	// the Change Stream is resumed after, unless the position is already past it.
+	CDCResumeToken bson.Raw `key:"cdc.resumeToken"`
+	// ConvertDateTime is the representation BSON dates are converted to.
+	ConvertDateTime codec.DateTimeFormat `key:"convert.dateTime" validate:"oneof=rfc3339 millis native"`
+	// ConvertTimestamp is the representation BSON timestamps are converted to.
+	ConvertTimestamp codec.TimestampFormat `key:"convert.timestamp" validate:"oneof=timestamp date"`
+	// ConvertDecimal is the representation BSON decimals are converted to.
+	ConvertDecimal codec.DecimalFormat `key:"convert.decimal" validate:"oneof=string float"`
+	// ConvertUUID is the representation BSON binary UUIDs are converted to.
+	ConvertUUID codec.UUIDFormat `key:"convert.uuid" validate:"oneof=bytes string"`
+	// ConvertBinary is the representation BSON binaries are converted to.
+	ConvertBinary codec.BinaryFormat `key:"convert.binary" validate:"oneof=bytes base64"`
+	// StrictTypes determines whether or not the connector fails on BSON values
+	// that can't be represented faithfully in the json payload format, instead of converting them.
+	StrictTypes bool `key:"strictTypes"`
+	// SnapshotCollectionMetadata determines whether or not the connector emits a record
+	// describing the collection structure at the start of a snapshot.
+	SnapshotCollectionMetadata bool `key:"snapshot.collectionMetadata"`
+	// SchemaMode determines how the connector generates a payload schema of the collection.
+	SchemaMode iterator.SchemaMode `key:"schema.mode" validate:"oneof=none sample validator"`
+	// SchemaSampleSize is the number of documents sampled to generate a payload schema.
+	SchemaSampleSize int `key:"schema.sampleSize" validate:"gte=1"`
+	// SchemaDrift determines how the connector reports documents drifting from the first-seen schema.
+	SchemaDrift iterator.SchemaDriftMode `key:"schema.drift" validate:"oneof=none log metadata"`
+	// CDCVerifyResume determines whether or not the connector verifies
+	// that the Change Stream can be resumed before starting to read.
+	CDCVerifyResume bool `key:"cdc.verifyResume"`
+	// CDCCollectionEvents determines whether or not the connector emits records
+	// describing drops and renames of the collection.
+	CDCCollectionEvents bool `key:"cdc.collectionEvents"`
+	// SnapshotMode determines whether the snapshot blocks CDC or is captured incrementally along with it.
+	SnapshotMode iterator.SnapshotMode `key:"snapshot.mode" validate:"oneof=blocking incremental"`
+	// SnapshotTrigger is an identifier of an incremental snapshot.
+	// Changing it makes the connector capture a new incremental snapshot without pausing CDC.
+	SnapshotTrigger string `key:"snapshot.trigger"`
+	// SnapshotMaxBatchBytes is the max total size of documents in a snapshot batch in bytes.
+	// Zero means no limit.
+	SnapshotMaxBatchBytes int `key:"snapshot.maxBatchBytes" validate:"gte=0"`
+	// SnapshotCheckpointRecords is the number of blocking snapshot records the position is checkpointed after.
+	// The records in between repeat the latest checkpoint. Zero means it's not limited by records.
+	SnapshotCheckpointRecords int `key:"snapshot.checkpointRecords" validate:"gte=0"`
+	// SnapshotCheckpointInterval is the time the blocking snapshot position is checkpointed after.
+	// Zero means it's not limited by time. If both are zero, every snapshot record gets its own position.
+	SnapshotCheckpointInterval time.Duration `key:"snapshot.checkpointInterval" validate:"gte=0"`
+	// SnapshotMaxDocsPerSecond is the max number of documents per second snapshots read,
+	// so they don't starve application traffic of production primaries. Zero means no limit.
+	SnapshotMaxDocsPerSecond int `key:"snapshot.maxDocsPerSecond" validate:"gte=0"`
+	// SnapshotAllowDiskUse determines whether or not the connector retries a snapshot query
+	// that exceeded the memory limit of sorts with sorting on disk allowed.
+	SnapshotAllowDiskUse bool `key:"snapshot.allowDiskUse"`
+	// SnapshotResumeBoundary determines whether a resumed snapshot emits the last emitted document once again.
+	SnapshotResumeBoundary iterator.ResumeBoundary `key:"snapshot.resumeBoundary" validate:"oneof=exclusive inclusive"`
+	// SnapshotSharded determines whether or not the blocking snapshot of a sharded collection
+	// reads its chunks in parallel instead of sorting all documents by the ordering field.
+	SnapshotSharded bool `key:"snapshot.sharded"`
+	// SnapshotConsistent determines whether or not the blocking snapshot reads all documents
+	// at the cluster time it starts at, and the Change Stream starts right after that time.
+	SnapshotConsistent bool `key:"snapshot.consistent"`
+	// SnapshotParallelism is the max number of chunks the sharded snapshot reads at the same time.
+	SnapshotParallelism int `key:"snapshot.parallelism" validate:"gte=1,lte=64"`
+	// SnapshotURI is the connection string snapshots read documents through, e.g. a federated connection string
+	// of an Atlas Online Archive, while CDC runs against the cluster. If it's nil, the uri is used for both.
+	SnapshotURI *url.URL `key:"snapshot.uri"`
+	// ReadConcernLevel is the read concern level of snapshot queries and the Change Stream.
+	// If it's empty, the read concern of the connection string or the server default is used.
+	ReadConcernLevel iterator.ReadConcernLevel `key:"readConcern.level" validate:"omitempty,oneof=local majority snapshot"`
+	// ReadPreferenceMode is the read preference mode of snapshot queries and the Change Stream.
+	// It's one of primary, primaryPreferred, secondary, secondaryPreferred and nearest, matched case-insensitively.
+	// If it's empty, the read preference of the connection string is used.
+	ReadPreferenceMode string `key:"readPreference.mode"`
+	// ReadPreferenceTags is a semicolon-separated list of tag sets of the read preference,
+	// each one being a comma-separated list of name:value tags.
+	ReadPreferenceTags string `key:"readPreference.tags"`
+	// SignalCollection is the name of a collection the connector reads control documents from.
+	SignalCollection string `key:"signal.collection"`
+	// RateLimit is the max number of records per second the connector reads,
+	// both during a snapshot and CDC. Zero means no limit.
+	RateLimit int `key:"rateLimit" validate:"gte=0"`
+	// CDCMaxRetries is the max number of times the connector recreates a failed Change Stream
+	// before returning the error. Zero means no retries.
+	CDCMaxRetries int `key:"cdc.maxRetries" validate:"gte=0"`
+	// CDCHeartbeatInterval is how long the Change Stream has to stay quiet before the connector
+	// emits a heartbeat record carrying its latest resume token. Zero means no heartbeats.
+	CDCHeartbeatInterval time.Duration `key:"cdc.heartbeatInterval" validate:"gte=0"`
+	// CDCMaxAwaitTime is how long the server waits for new events before responding to a request
+	// of the next batch of the Change Stream. Zero means the server default is used.
+	CDCMaxAwaitTime time.Duration `key:"cdc.maxAwaitTime" validate:"gte=0"`
+	// CDCPrefetchSize is the number of Change Stream records the connector reads ahead in the background.
+	// Zero means records are read when they're requested.
+	CDCPrefetchSize int `key:"cdc.prefetchSize" validate:"gte=0,lte=100000"`
+	// CDCSuppressUnchanged determines whether or not the connector skips update events
+	// whose full document is byte-identical to the previously emitted version of the document.
+	CDCSuppressUnchanged bool `key:"cdc.suppressUnchanged"`
+	// CDCSuppressCacheSize is the max number of documents whose hashes the connector keeps
+	// to suppress unchanged updates.
+	CDCSuppressCacheSize int `key:"cdc.suppressCacheSize" validate:"gte=1"`
+	// CDCMode determines whether the connector captures changes by a Change Stream, by tailing the oplog,
+	// or by tailing the capped collection itself.
+	CDCMode iterator.CDCMode `key:"cdc.mode" validate:"oneof=auto changestream oplog tailable"`
+	// CDCCoalesceWindow is how long the connector buffers Change Stream records to coalesce
+	// records of the same documents into their latest states. Zero means records are not coalesced.
+	CDCCoalesceWindow time.Duration `key:"cdc.coalesceWindow" validate:"gte=0"`
+	// CDCCoalesceMaxSize is the max number of distinct documents the connector buffers within the coalesce window.
+	CDCCoalesceMaxSize int `key:"cdc.coalesceMaxSize" validate:"gte=1"`
+	// PollingUpdatedAtField is the name of a field containing the time of a document's last update.
+	// If it's set, the connector also polls for updated documents when CDC is not possible.
+	PollingUpdatedAtField string `key:"polling.updatedAtField"`
+	// PollingDeleteStrategy determines how the connector detects deleted documents when CDC is not possible.
+	PollingDeleteStrategy iterator.DeleteStrategy `key:"polling.deleteStrategy" validate:"oneof=none idset softdelete"`
+	// PollingSoftDeleteField is the name of a field marking a document as deleted.
+	PollingSoftDeleteField string `key:"polling.softDeleteField"`
+	// PollingDeleteCheckInterval is how often the connector compares the _ids of documents with the known ones.
+	PollingDeleteCheckInterval time.Duration `key:"polling.deleteCheckInterval" validate:"gte=0"`
+	// Compatibility determines the MongoDB-compatible database the connector adapts the Change Stream to.
+	Compatibility iterator.Compatibility `key:"compatibility" validate:"oneof=none cosmosdb ferretdb"`
+	// CSFLEKeyVaultNamespace is the namespace of the key vault collection data encryption keys are stored in,
+	// in the <db>.<collection> format.
+	CSFLEKeyVaultNamespace string `key:"csfle.keyVaultNamespace"`
+	// CSFLEKMSProviders is an Extended JSON document with credentials of the KMS providers
+	// data encryption keys are encrypted with. If it's empty, encrypted fields are not decrypted.
+	CSFLEKMSProviders string `key:"csfle.kmsProviders"`
+	// CSFLESchemaMap is an Extended JSON document mapping namespaces to JSON schemas of their encrypted fields.
+	CSFLESchemaMap string `key:"csfle.schemaMap"`
+	// TransformFilter is an expression records are filtered by, records of documents it's false for are skipped.
+	TransformFilter string `key:"transform.filter"`
+	// TransformFields is a JSON object mapping names of derived fields to expressions they're computed by.
+	TransformFields string `key:"transform.fields"`
+	// DBRefMode determines whether the connector normalizes DBRefs or replaces them with the documents they refer to.
+	DBRefMode iterator.DBRefMode `key:"dbref.mode" validate:"oneof=none normalize resolve"`
+	// DBRefMaxDepth is the max depth of references the connector resolves, deeper references are normalized.
+	DBRefMaxDepth int `key:"dbref.maxDepth" validate:"gte=1"`
+	// PartitionKey determines the partition key the connector attaches to records' metadata.
+	PartitionKey iterator.PartitionKeyMode `key:"partitionKey" validate:"oneof=none hash shard"`
 }
 
 // ParseConfig maps the incoming map to the [Config] and validates it.
+//
+//nolint:funlen,nolintlint // yeah, this function can become long at some point.
 func ParseConfig(raw map[string]string) (Config, error) {
 	commonConfig, err := config.Parse(raw)
 	if err != nil {
@@ -62,40 +439,771 @@ func ParseConfig(raw map[string]string) (Config, error) {
 	}
 
 	sourceConfig := Config{
-		Config:        commonConfig,
-		BatchSize:     defaultBatchSize,
-		Snapshot:      defaultSnapshot,
-		OrderingField: defaultOrderingField,
+		Config:                     commonConfig,
+		BatchSize:                  defaultBatchSize,
+		Snapshot:                   defaultSnapshot,
+		OrderingField:              defaultOrderingField,
+		SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+		PayloadFormat:              defaultPayloadFormat,
+		KeyFormat:                  defaultKeyFormat,
+		ConvertDateTime:            defaultConvertDateTime,
+		ConvertTimestamp:           defaultConvertTimestamp,
+		ConvertDecimal:             defaultConvertDecimal,
+		ConvertUUID:                defaultConvertUUID,
+		ConvertBinary:              defaultConvertBinary,
+		StrictTypes:                defaultStrictTypes,
+		SnapshotCollectionMetadata: defaultSnapshotCollectionMetadata,
+		SchemaMode:                 defaultSchemaMode,
+		SchemaSampleSize:           defaultSchemaSampleSize,
+		SchemaDrift:                defaultSchemaDrift,
+		CDCVerifyResume:            defaultCDCVerifyResume,
+		CDCCollectionEvents:        defaultCDCCollectionEvents,
+		SnapshotMode:               defaultSnapshotMode,
+		SnapshotTrigger:            raw[ConfigKeySnapshotTrigger],
+		SnapshotMaxBatchBytes:      defaultSnapshotMaxBatchBytes,
+		SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+		SnapshotSharded:            defaultSnapshotSharded,
+		SnapshotConsistent:         defaultSnapshotConsistent,
+		SnapshotParallelism:        defaultSnapshotParallelism,
+		SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+		SignalCollection:           raw[ConfigKeySignalCollection],
+		RateLimit:                  defaultRateLimit,
+		CDCMaxRetries:              defaultCDCMaxRetries,
+		CDCHeartbeatInterval:       defaultCDCHeartbeatInterval,
+		CDCMaxAwaitTime:            defaultCDCMaxAwaitTime,
+		CDCPrefetchSize:            defaultCDCPrefetchSize,
+		CDCSuppressUnchanged:       defaultCDCSuppressUnchanged,
+		CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+		CDCMode:                    defaultCDCMode,
+		CDCCoalesceWindow:          defaultCDCCoalesceWindow,
+		CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+		PollingUpdatedAtField:      raw[ConfigKeyPollingUpdatedAtField],
+		PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+		PollingSoftDeleteField:     raw[ConfigKeyPollingSoftDeleteField],
+		CSFLEKeyVaultNamespace:     raw[ConfigKeyCSFLEKeyVaultNamespace],
+		CSFLEKMSProviders:          raw[ConfigKeyCSFLEKMSProviders],
+		CSFLESchemaMap:             raw[ConfigKeyCSFLESchemaMap],
+		ReadPreferenceMode:         strings.TrimSpace(raw[ConfigKeyReadPreferenceMode]),
+		ReadPreferenceTags:         strings.TrimSpace(raw[ConfigKeyReadPreferenceTags]),
+		TransformFilter:            strings.TrimSpace(raw[ConfigKeyTransformFilter]),
+		TransformFields:            strings.TrimSpace(raw[ConfigKeyTransformFields]),
+		PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+		Compatibility:              defaultCompatibility,
+		DBRefMode:                  defaultDBRefMode,
+		DBRefMaxDepth:              defaultDBRefMaxDepth,
+		PartitionKey:               defaultPartitionKey,
+	}
+
+	// set the compatibility if it's not empty
+	if compatibility := raw[ConfigKeyCompatibility]; compatibility != "" {
+		sourceConfig.Compatibility = iterator.Compatibility(strings.ToLower(compatibility))
+	}
+
+	// set the cdc.mode if it's not empty
+	if cdcMode := raw[ConfigKeyCDCMode]; cdcMode != "" {
+		sourceConfig.CDCMode = iterator.CDCMode(strings.ToLower(cdcMode))
 	}
 
 	// parse batch size if it's not empty
-	if batchSizeStr := raw[ConfigKeyBatchSize]; batchSizeStr != "" {
-		batchSize, err := strconv.Atoi(batchSizeStr)
+	if err := parseInt(raw, ConfigKeyBatchSize, &sourceConfig.BatchSize); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot if it's not empty
+	if err := parseBool(raw, ConfigKeySnapshot, &sourceConfig.Snapshot); err != nil {
+		return Config{}, err
+	}
+
+	// set the orderingField if it's not empty
+	if orderingField := raw[ConfigKeyOrderingField]; orderingField != "" {
+		sourceConfig.OrderingField = orderingField
+
+		if slices.Contains(sourceConfig.OrderingFields(), "") {
+			return Config{}, validator.NewFormatError(ConfigKeyOrderingField, errEmptyOrderingField)
+		}
+	}
+
+	// set the snapshot.onStaleToken if it's not empty
+	if onStaleToken := raw[ConfigKeySnapshotOnStaleToken]; onStaleToken != "" {
+		sourceConfig.SnapshotOnStaleToken = StaleTokenStrategy(strings.ToLower(onStaleToken))
+	}
+
+	// set the payload.format if it's not empty
+	if payloadFormat := raw[ConfigKeyPayloadFormat]; payloadFormat != "" {
+		sourceConfig.PayloadFormat = iterator.PayloadFormat(strings.ToLower(payloadFormat))
+	}
+
+	// set the key.format if it's not empty
+	if keyFormat := raw[ConfigKeyKeyFormat]; keyFormat != "" {
+		sourceConfig.KeyFormat = iterator.KeyFormat(strings.ToLower(keyFormat))
+	}
+
+	// parse the cdc.startAtOperationTime if it's not empty
+	if startAtOperationTimeStr := raw[ConfigKeyCDCStartAtOperationTime]; startAtOperationTimeStr != "" {
+		startAtOperationTime, err := parseOperationTime(startAtOperationTimeStr)
 		if err != nil {
-			return Config{}, fmt.Errorf("parse %q: %w", ConfigKeyBatchSize, err)
+			return Config{}, validator.NewFormatError(ConfigKeyCDCStartAtOperationTime, err)
 		}
 
-		sourceConfig.BatchSize = batchSize
+		sourceConfig.CDCStartAtOperationTime = startAtOperationTime
 	}
 
-	// parse snapshot if it's not empty
-	if snapshotStr := raw[ConfigKeySnapshot]; snapshotStr != "" {
-		snapshot, err := strconv.ParseBool(snapshotStr)
+	// parse the cdc.stopAtOperationTime if it's not empty
+	if stopAtOperationTimeStr := raw[ConfigKeyCDCStopAtOperationTime]; stopAtOperationTimeStr != "" {
+		stopAtOperationTime, err := parseOperationTime(stopAtOperationTimeStr)
 		if err != nil {
-			return Config{}, fmt.Errorf("parse %q: %w", ConfigKeySnapshot, err)
+			return Config{}, validator.NewFormatError(ConfigKeyCDCStopAtOperationTime, err)
 		}
 
-		sourceConfig.Snapshot = snapshot
+		sourceConfig.CDCStopAtOperationTime = stopAtOperationTime
 	}
 
-	// set the orderingField if it's not empty
-	if orderingField := raw[ConfigKeyOrderingField]; orderingField != "" {
-		sourceConfig.OrderingField = orderingField
+	// parse the cdc.resumeToken if it's not empty
+	if resumeTokenStr := raw[ConfigKeyCDCResumeToken]; resumeTokenStr != "" {
+		resumeToken, err := parseResumeToken(resumeTokenStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeyCDCResumeToken, err)
+		}
+
+		sourceConfig.CDCResumeToken = resumeToken
+	}
+
+	// set the convert.dateTime if it's not empty
+	if convertDateTime := raw[ConfigKeyConvertDateTime]; convertDateTime != "" {
+		sourceConfig.ConvertDateTime = codec.DateTimeFormat(strings.ToLower(convertDateTime))
+	}
+
+	// set the convert.timestamp if it's not empty
+	if convertTimestamp := raw[ConfigKeyConvertTimestamp]; convertTimestamp != "" {
+		sourceConfig.ConvertTimestamp = codec.TimestampFormat(strings.ToLower(convertTimestamp))
+	}
+
+	// set the convert.decimal if it's not empty
+	if convertDecimal := raw[ConfigKeyConvertDecimal]; convertDecimal != "" {
+		sourceConfig.ConvertDecimal = codec.DecimalFormat(strings.ToLower(convertDecimal))
+	}
+
+	// set the convert.uuid if it's not empty
+	if convertUUID := raw[ConfigKeyConvertUUID]; convertUUID != "" {
+		sourceConfig.ConvertUUID = codec.UUIDFormat(strings.ToLower(convertUUID))
+	}
+
+	// set the convert.binary if it's not empty
+	if convertBinary := raw[ConfigKeyConvertBinary]; convertBinary != "" {
+		sourceConfig.ConvertBinary = codec.BinaryFormat(strings.ToLower(convertBinary))
+	}
+
+	// parse strictTypes if it's not empty
+	if err := parseBool(raw, ConfigKeyStrictTypes, &sourceConfig.StrictTypes); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.collectionMetadata if it's not empty
+	if err := parseBool(raw, ConfigKeySnapshotCollectionMetadata, &sourceConfig.SnapshotCollectionMetadata); err != nil {
+		return Config{}, err
+	}
+
+	// set the schema.mode if it's not empty
+	if schemaMode := raw[ConfigKeySchemaMode]; schemaMode != "" {
+		sourceConfig.SchemaMode = iterator.SchemaMode(strings.ToLower(schemaMode))
+	}
+
+	// set the schema.drift if it's not empty
+	if schemaDrift := raw[ConfigKeySchemaDrift]; schemaDrift != "" {
+		sourceConfig.SchemaDrift = iterator.SchemaDriftMode(strings.ToLower(schemaDrift))
+	}
+
+	// parse schema.sampleSize if it's not empty
+	if err := parseInt(raw, ConfigKeySchemaSampleSize, &sourceConfig.SchemaSampleSize); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.verifyResume if it's not empty
+	if err := parseBool(raw, ConfigKeyCDCVerifyResume, &sourceConfig.CDCVerifyResume); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.collectionEvents if it's not empty
+	if err := parseBool(raw, ConfigKeyCDCCollectionEvents, &sourceConfig.CDCCollectionEvents); err != nil {
+		return Config{}, err
+	}
+
+	// set the snapshot.mode if it's not empty
+	if snapshotMode := raw[ConfigKeySnapshotMode]; snapshotMode != "" {
+		sourceConfig.SnapshotMode = iterator.SnapshotMode(strings.ToLower(snapshotMode))
+	}
+
+	// set the readConcern.level if it's not empty
+	if readConcernLevel := raw[ConfigKeyReadConcernLevel]; readConcernLevel != "" {
+		sourceConfig.ReadConcernLevel = iterator.ReadConcernLevel(strings.ToLower(readConcernLevel))
+	}
+
+	// parse snapshot.maxBatchBytes if it's not empty
+	if err := parseInt(raw, ConfigKeySnapshotMaxBatchBytes, &sourceConfig.SnapshotMaxBatchBytes); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.checkpointRecords if it's not empty
+	if err := parseInt(raw, ConfigKeySnapshotCheckpointRecords, &sourceConfig.SnapshotCheckpointRecords); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.checkpointInterval if it's not empty
+	err = parseDuration(raw, ConfigKeySnapshotCheckpointInterval, &sourceConfig.SnapshotCheckpointInterval)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.maxDocsPerSecond if it's not empty
+	if err := parseInt(raw, ConfigKeySnapshotMaxDocsPerSecond, &sourceConfig.SnapshotMaxDocsPerSecond); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.allowDiskUse if it's not empty
+	if err := parseBool(raw, ConfigKeySnapshotAllowDiskUse, &sourceConfig.SnapshotAllowDiskUse); err != nil {
+		return Config{}, err
+	}
+
+	// set the snapshot.resumeBoundary if it's not empty
+	if resumeBoundary := raw[ConfigKeySnapshotResumeBoundary]; resumeBoundary != "" {
+		sourceConfig.SnapshotResumeBoundary = iterator.ResumeBoundary(strings.ToLower(resumeBoundary))
+	}
+
+	// parse snapshot.sharded if it's not empty
+	if err := parseBool(raw, ConfigKeySnapshotSharded, &sourceConfig.SnapshotSharded); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.consistent if it's not empty
+	if err := parseBool(raw, ConfigKeySnapshotConsistent, &sourceConfig.SnapshotConsistent); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.parallelism if it's not empty
+	if err := parseInt(raw, ConfigKeySnapshotParallelism, &sourceConfig.SnapshotParallelism); err != nil {
+		return Config{}, err
+	}
+
+	// parse snapshot.uri if it's not empty
+	if snapshotURIStr := strings.TrimSpace(raw[ConfigKeySnapshotURI]); snapshotURIStr != "" {
+		snapshotURI, err := url.Parse(snapshotURIStr)
+		if err != nil {
+			return Config{}, validator.NewFormatError(ConfigKeySnapshotURI, err)
+		}
+
+		if snapshotURI.Scheme != config.SchemeMongoDB && snapshotURI.Scheme != config.SchemeMongoDBSRV {
+			return Config{}, validator.NewFormatError(ConfigKeySnapshotURI,
+				&config.InvalidURISchemeError{Scheme: snapshotURI.Scheme})
+		}
+
+		sourceConfig.SnapshotURI = snapshotURI
+	}
+
+	// parse rateLimit if it's not empty
+	if err := parseInt(raw, ConfigKeyRateLimit, &sourceConfig.RateLimit); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.maxRetries if it's not empty
+	if err := parseInt(raw, ConfigKeyCDCMaxRetries, &sourceConfig.CDCMaxRetries); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.heartbeatInterval if it's not empty
+	if err := parseDuration(raw, ConfigKeyCDCHeartbeatInterval, &sourceConfig.CDCHeartbeatInterval); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.maxAwaitTime if it's not empty
+	if err := parseDuration(raw, ConfigKeyCDCMaxAwaitTime, &sourceConfig.CDCMaxAwaitTime); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.prefetchSize if it's not empty
+	if err := parseInt(raw, ConfigKeyCDCPrefetchSize, &sourceConfig.CDCPrefetchSize); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.suppressUnchanged if it's not empty
+	if err := parseBool(raw, ConfigKeyCDCSuppressUnchanged, &sourceConfig.CDCSuppressUnchanged); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.suppressCacheSize if it's not empty
+	if err := parseInt(raw, ConfigKeyCDCSuppressCacheSize, &sourceConfig.CDCSuppressCacheSize); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.coalesceWindow if it's not empty
+	if err := parseDuration(raw, ConfigKeyCDCCoalesceWindow, &sourceConfig.CDCCoalesceWindow); err != nil {
+		return Config{}, err
+	}
+
+	// parse cdc.coalesceMaxSize if it's not empty
+	if err := parseInt(raw, ConfigKeyCDCCoalesceMaxSize, &sourceConfig.CDCCoalesceMaxSize); err != nil {
+		return Config{}, err
+	}
+
+	// set the polling.deleteStrategy if it's not empty
+	if deleteStrategy := raw[ConfigKeyPollingDeleteStrategy]; deleteStrategy != "" {
+		sourceConfig.PollingDeleteStrategy = iterator.DeleteStrategy(strings.ToLower(deleteStrategy))
+	}
+
+	// parse polling.deleteCheckInterval if it's not empty
+	err = parseDuration(raw, ConfigKeyPollingDeleteCheckInterval, &sourceConfig.PollingDeleteCheckInterval)
+	if err != nil {
+		return Config{}, err
+	}
+
+	// set the dbref.mode if it's not empty
+	if dbRefMode := raw[ConfigKeyDBRefMode]; dbRefMode != "" {
+		sourceConfig.DBRefMode = iterator.DBRefMode(strings.ToLower(dbRefMode))
+	}
+
+	// parse dbref.maxDepth if it's not empty
+	if err := parseInt(raw, ConfigKeyDBRefMaxDepth, &sourceConfig.DBRefMaxDepth); err != nil {
+		return Config{}, err
+	}
+
+	// set the partitionKey if it's not empty
+	if partitionKey := raw[ConfigKeyPartitionKey]; partitionKey != "" {
+		sourceConfig.PartitionKey = iterator.PartitionKeyMode(strings.ToLower(partitionKey))
 	}
 
 	if err := validator.ValidateStruct(&sourceConfig); err != nil {
 		return Config{}, fmt.Errorf("validate source config: %w", err)
 	}
 
+	// soft deleted documents are detected only among updated ones, as they're already polled
+	if sourceConfig.PollingDeleteStrategy == iterator.DeleteStrategySoftDelete &&
+		(sourceConfig.PollingSoftDeleteField == "" || sourceConfig.PollingUpdatedAtField == "") {
+		return Config{}, validator.NewFieldError(ConfigKeyPollingDeleteStrategy, validator.ConstraintCompatible,
+			fmt.Errorf("%q and %q must be set if %q is %q",
+				ConfigKeyPollingSoftDeleteField, ConfigKeyPollingUpdatedAtField,
+				ConfigKeyPollingDeleteStrategy, sourceConfig.PollingDeleteStrategy))
+	}
+
+	// encrypted fields can't be decrypted without both, the key vault and the KMS providers
+	if (sourceConfig.CSFLEKeyVaultNamespace == "") != (sourceConfig.CSFLEKMSProviders == "") {
+		return Config{}, validator.NewFieldError(ConfigKeyCSFLEKMSProviders, validator.ConstraintCompatible,
+			fmt.Errorf("%q and %q must be set together", ConfigKeyCSFLEKeyVaultNamespace, ConfigKeyCSFLEKMSProviders))
+	}
+
+	// make sure the encryption documents are valid before connecting
+	if _, err := sourceConfig.AutoEncryptionOptions(); err != nil {
+		return Config{}, err
+	}
+
+	// make sure the read preference is valid before connecting
+	if _, err := sourceConfig.ReadPreference(); err != nil {
+		return Config{}, err
+	}
+
+	// payload schemas describe plain documents, so they can't be used with other payload formats
+	if sourceConfig.SchemaMode != iterator.SchemaModeNone && sourceConfig.PayloadFormat != iterator.PayloadFormatJSON {
+		return Config{}, validator.NewFieldError(ConfigKeyPayloadFormat, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q if %q is %q",
+				ConfigKeyPayloadFormat, iterator.PayloadFormatJSON, ConfigKeySchemaMode, sourceConfig.SchemaMode))
+	}
+
+	// native dates are only kept in structured payloads encoded with a payload schema,
+	// as structured data without a schema can't carry them
+	if sourceConfig.ConvertDateTime == codec.DateTimeFormatNative && sourceConfig.SchemaMode == iterator.SchemaModeNone {
+		return Config{}, validator.NewFieldError(ConfigKeyConvertDateTime, validator.ConstraintCompatible,
+			fmt.Errorf("%q can't be %q if %q is %q",
+				ConfigKeyConvertDateTime, codec.DateTimeFormatNative, ConfigKeySchemaMode, iterator.SchemaModeNone))
+	}
+
+	// references are replaced in plain documents, so they can't be replaced in other payload formats
+	if sourceConfig.DBRefMode != iterator.DBRefModeNone && sourceConfig.PayloadFormat != iterator.PayloadFormatJSON {
+		return Config{}, validator.NewFieldError(ConfigKeyPayloadFormat, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q if %q is %q",
+				ConfigKeyPayloadFormat, iterator.PayloadFormatJSON, ConfigKeyDBRefMode, sourceConfig.DBRefMode))
+	}
+
+	if err := validateSnapshotConsistent(sourceConfig); err != nil {
+		return Config{}, err
+	}
+
+	if err := validateCDCResumeToken(sourceConfig); err != nil {
+		return Config{}, err
+	}
+
+	if err := validateCDCStopAtOperationTime(sourceConfig); err != nil {
+		return Config{}, err
+	}
+
+	if err := validateCDCCollectionEvents(sourceConfig); err != nil {
+		return Config{}, err
+	}
+
+	// make sure the expressions compile before connecting
+	transformation, err := sourceConfig.Transform()
+	if err != nil {
+		return Config{}, err
+	}
+
+	// expressions are evaluated against plain documents, so they can't be used with other payload formats
+	if transformation != nil && sourceConfig.PayloadFormat != iterator.PayloadFormatJSON {
+		return Config{}, validator.NewFieldError(ConfigKeyPayloadFormat, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q if the documents are transformed", ConfigKeyPayloadFormat, iterator.PayloadFormatJSON))
+	}
+
 	return sourceConfig, nil
 }
+
+// validateSnapshotConsistent checks whether the consistent snapshot can be used with the rest of the config.
+// It's anchored to a single snapshot session and a Change Stream starting at its cluster time,
+// so it's incompatible with the other snapshot modes, CDC modes and start times.
+func validateSnapshotConsistent(sourceConfig Config) error {
+	if !sourceConfig.SnapshotConsistent {
+		return nil
+	}
+
+	switch {
+	case sourceConfig.SnapshotMode != iterator.SnapshotModeBlocking:
+		return validator.NewFieldError(ConfigKeySnapshotConsistent, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q", ConfigKeySnapshotMode, iterator.SnapshotModeBlocking))
+
+	case sourceConfig.SnapshotSharded:
+		return validator.NewFieldError(ConfigKeySnapshotConsistent, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be false", ConfigKeySnapshotSharded))
+
+	case sourceConfig.SnapshotURI != nil:
+		return validator.NewFieldError(ConfigKeySnapshotConsistent, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be empty", ConfigKeySnapshotURI))
+
+	case sourceConfig.CDCMode == iterator.CDCModeOplog || sourceConfig.CDCMode == iterator.CDCModeTailable:
+		return validator.NewFieldError(ConfigKeySnapshotConsistent, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q or %q", ConfigKeyCDCMode, iterator.CDCModeAuto, iterator.CDCModeChangeStream))
+
+	case sourceConfig.CDCStartAtOperationTime != nil:
+		return validator.NewFieldError(ConfigKeySnapshotConsistent, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be empty", ConfigKeyCDCStartAtOperationTime))
+
+	default:
+		return nil
+	}
+}
+
+// validateCDCResumeToken checks whether the resume token can be used with the rest of the config.
+// It resumes a Change Stream, so it's incompatible with the other CDC modes, start times
+// and discarding stale positions.
+func validateCDCResumeToken(sourceConfig Config) error {
+	if sourceConfig.CDCResumeToken == nil {
+		return nil
+	}
+
+	switch {
+	case sourceConfig.CDCMode == iterator.CDCModeOplog || sourceConfig.CDCMode == iterator.CDCModeTailable:
+		return validator.NewFieldError(ConfigKeyCDCResumeToken, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q or %q", ConfigKeyCDCMode, iterator.CDCModeAuto, iterator.CDCModeChangeStream))
+
+	case sourceConfig.Compatibility == iterator.CompatibilityFerretDB:
+		return validator.NewFieldError(ConfigKeyCDCResumeToken, validator.ConstraintCompatible,
+			fmt.Errorf("%q must not be %q", ConfigKeyCompatibility, iterator.CompatibilityFerretDB))
+
+	case sourceConfig.CDCStartAtOperationTime != nil:
+		return validator.NewFieldError(ConfigKeyCDCResumeToken, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be empty", ConfigKeyCDCStartAtOperationTime))
+
+	case sourceConfig.SnapshotConsistent:
+		return validator.NewFieldError(ConfigKeyCDCResumeToken, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be false", ConfigKeySnapshotConsistent))
+
+	// the stale token would be applied again after the fresh snapshot
+	case sourceConfig.SnapshotOnStaleToken == StaleTokenResnapshot:
+		return validator.NewFieldError(ConfigKeyCDCResumeToken, validator.ConstraintCompatible,
+			fmt.Errorf("%q must not be %q", ConfigKeySnapshotOnStaleToken, StaleTokenResnapshot))
+
+	default:
+		return nil
+	}
+}
+
+// validateCDCStopAtOperationTime checks whether the stop time can be used with the rest of the config.
+// Changes are compared with it by their cluster times, so it's incompatible with the modes
+// that don't capture them, and it must be after the start time.
+func validateCDCStopAtOperationTime(sourceConfig Config) error {
+	stopAt := sourceConfig.CDCStopAtOperationTime
+	if stopAt == nil {
+		return nil
+	}
+
+	switch startAt := sourceConfig.CDCStartAtOperationTime; {
+	case sourceConfig.CDCMode == iterator.CDCModeTailable:
+		return validator.NewFieldError(ConfigKeyCDCStopAtOperationTime, validator.ConstraintCompatible,
+			fmt.Errorf("%q must not be %q", ConfigKeyCDCMode, iterator.CDCModeTailable))
+
+	case sourceConfig.Compatibility == iterator.CompatibilityFerretDB:
+		return validator.NewFieldError(ConfigKeyCDCStopAtOperationTime, validator.ConstraintCompatible,
+			fmt.Errorf("%q must not be %q", ConfigKeyCompatibility, iterator.CompatibilityFerretDB))
+
+	case startAt != nil && !stopAt.After(*startAt):
+		return validator.NewFieldError(ConfigKeyCDCStopAtOperationTime, validator.ConstraintCompatible,
+			fmt.Errorf("must be after %q", ConfigKeyCDCStartAtOperationTime))
+
+	default:
+		return nil
+	}
+}
+
+// validateCDCCollectionEvents checks whether collection events can be used with the rest of the config.
+// They're reported by Change Streams only, so they're incompatible with the other CDC modes
+// and the databases that don't report them.
+func validateCDCCollectionEvents(sourceConfig Config) error {
+	if !sourceConfig.CDCCollectionEvents {
+		return nil
+	}
+
+	switch {
+	case sourceConfig.CDCMode == iterator.CDCModeOplog || sourceConfig.CDCMode == iterator.CDCModeTailable:
+		return validator.NewFieldError(ConfigKeyCDCCollectionEvents, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q or %q", ConfigKeyCDCMode, iterator.CDCModeAuto, iterator.CDCModeChangeStream))
+
+	case sourceConfig.Compatibility != iterator.CompatibilityNone:
+		return validator.NewFieldError(ConfigKeyCDCCollectionEvents, validator.ConstraintCompatible,
+			fmt.Errorf("%q must be %q", ConfigKeyCompatibility, iterator.CompatibilityNone))
+
+	default:
+		return nil
+	}
+}
+
+// Transform returns the transformation of documents computing derived fields and filtering records,
+// or nil if neither of them is configured.
+func (c Config) Transform() (*transform.Transform, error) {
+	if c.TransformFilter == "" && c.TransformFields == "" {
+		return nil, nil //nolint:nilnil // a nil transformation means documents are not transformed
+	}
+
+	var fields map[string]string
+	if c.TransformFields != "" {
+		if err := json.Unmarshal([]byte(c.TransformFields), &fields); err != nil {
+			return nil, validator.NewFormatError(ConfigKeyTransformFields, err)
+		}
+	}
+
+	transformation, err := transform.New(c.TransformFilter, fields)
+	if err != nil {
+		// the filter is compiled first, so the error is caused by the fields only if the filter compiles alone
+		key := ConfigKeyTransformFields
+		if _, filterErr := transform.New(c.TransformFilter, nil); filterErr != nil {
+			key = ConfigKeyTransformFilter
+		}
+
+		return nil, validator.NewFormatError(key, err)
+	}
+
+	return transformation, nil
+}
+
+// OrderingFields returns the list of fields the snapshot is ordered by.
+func (c Config) OrderingFields() []string {
+	fields := strings.Split(c.OrderingField, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	return fields
+}
+
+// AutoEncryptionOptions returns options of the client-side field level encryption
+// the client transparently decrypts encrypted fields with, or nil if it's not configured.
+// Without a schema map, commands are never encrypted, as the connector only reads documents.
+func (c Config) AutoEncryptionOptions() (*options.AutoEncryptionOptions, error) {
+	if c.CSFLEKMSProviders == "" {
+		return nil, nil //nolint:nilnil // nil options mean the encryption is not configured
+	}
+
+	if db, collection, ok := strings.Cut(c.CSFLEKeyVaultNamespace, "."); !ok || db == "" || collection == "" {
+		return nil, validator.NewFormatError(ConfigKeyCSFLEKeyVaultNamespace, errInvalidKeyVaultNamespace)
+	}
+
+	var kmsProviders map[string]map[string]any
+	if err := bson.UnmarshalExtJSON([]byte(c.CSFLEKMSProviders), false, &kmsProviders); err != nil {
+		return nil, validator.NewFormatError(ConfigKeyCSFLEKMSProviders, err)
+	}
+
+	opts := options.AutoEncryption().
+		SetKeyVaultNamespace(c.CSFLEKeyVaultNamespace).
+		SetKmsProviders(kmsProviders)
+
+	if c.CSFLESchemaMap == "" {
+		return opts.SetBypassAutoEncryption(true), nil
+	}
+
+	var schemaMap map[string]any
+	if err := bson.UnmarshalExtJSON([]byte(c.CSFLESchemaMap), false, &schemaMap); err != nil {
+		return nil, validator.NewFormatError(ConfigKeyCSFLESchemaMap, err)
+	}
+
+	return opts.SetSchemaMap(schemaMap), nil
+}
+
+// ReadPreference returns the read preference the client reads documents and changes with,
+// or nil if it's not configured, so the read preference of the connection string is used.
+func (c Config) ReadPreference() (*readpref.ReadPref, error) {
+	if c.ReadPreferenceMode == "" {
+		if c.ReadPreferenceTags != "" {
+			return nil, validator.NewFieldError(ConfigKeyReadPreferenceTags, validator.ConstraintCompatible,
+				fmt.Errorf("%q must be set if %q is set", ConfigKeyReadPreferenceMode, ConfigKeyReadPreferenceTags))
+		}
+
+		return nil, nil //nolint:nilnil // nil read preference means the one of the connection string is used
+	}
+
+	mode, err := readpref.ModeFromString(c.ReadPreferenceMode)
+	if err != nil {
+		return nil, validator.NewFormatError(ConfigKeyReadPreferenceMode, err)
+	}
+
+	tagSets, err := parseTagSets(c.ReadPreferenceTags)
+	if err != nil {
+		return nil, validator.NewFormatError(ConfigKeyReadPreferenceTags, err)
+	}
+
+	var opts []readpref.Option
+	if len(tagSets) != 0 {
+		opts = append(opts, readpref.WithTagSets(tagSets...))
+	}
+
+	// the primary mode doesn't allow tag sets
+	preference, err := readpref.New(mode, opts...)
+	if err != nil {
+		return nil, validator.NewFieldError(ConfigKeyReadPreferenceTags, validator.ConstraintCompatible, err)
+	}
+
+	return preference, nil
+}
+
+// parseTagSets parses a semicolon-separated list of tag sets, each one being a comma-separated list
+// of name:value tags, e.g. "dc:east,use:analytics;dc:west". An empty tag set matches any member.
+func parseTagSets(value string) ([]tag.Set, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	sets := strings.Split(value, ";")
+	tagSets := make([]tag.Set, 0, len(sets))
+	for _, set := range sets {
+		tagSet := tag.Set{}
+		for _, pair := range strings.Split(set, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+
+			name, value, ok := strings.Cut(pair, ":")
+			if name, value = strings.TrimSpace(name), strings.TrimSpace(value); !ok || name == "" || value == "" {
+				return nil, errInvalidReadPreferenceTag
+			}
+
+			tagSet = append(tagSet, tag.Tag{Name: name, Value: value})
+		}
+
+		tagSets = append(tagSets, tagSet)
+	}
+
+	return tagSets, nil
+}
+
+// parseInt parses an integer value of the key into the destination if the value is not empty.
+func parseInt(raw map[string]string, key string, dst *int) error {
+	value := raw[key]
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return validator.NewFormatError(key, err)
+	}
+
+	*dst = parsed
+
+	return nil
+}
+
+// parseBool parses a bool value of the key into the destination if the value is not empty.
+func parseBool(raw map[string]string, key string, dst *bool) error {
+	value := raw[key]
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return validator.NewFormatError(key, err)
+	}
+
+	*dst = parsed
+
+	return nil
+}
+
+// parseDuration parses a duration value of the key into the destination if the value is not empty.
+func parseDuration(raw map[string]string, key string, dst *time.Duration) error {
+	value := raw[key]
+	if value == "" {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return validator.NewFormatError(key, err)
+	}
+
+	*dst = parsed
+
+	return nil
+}
+
+// parseResumeToken parses a Change Stream resume token, either the hex-encoded value of its _data field
+// or the base64-encoded BSON document of the whole token.
+func parseResumeToken(value string) (bson.Raw, error) {
+	if _, err := hex.DecodeString(value); err == nil {
+		resumeToken, err := bson.Marshal(bson.D{{Key: "_data", Value: value}})
+		if err != nil {
+			return nil, fmt.Errorf("marshal resume token: %w", err)
+		}
+
+		return resumeToken, nil
+	}
+
+	document, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("resume token is neither hex nor base64: %w", err)
+	}
+
+	if err := bson.Raw(document).Validate(); err != nil {
+		return nil, fmt.Errorf("validate resume token document: %w", err)
+	}
+
+	return document, nil
+}
+
+// parseOperationTime parses a cluster time represented either
+// as an RFC 3339 date and time or as "<seconds>[.<increment>]".
+func parseOperationTime(value string) (*primitive.Timestamp, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &primitive.Timestamp{T: uint32(t.Unix())}, nil //nolint:gosec // cluster times are uint32 seconds
+	}
+
+	secondsStr, incrementStr, _ := strings.Cut(value, ".")
+
+	seconds, err := strconv.ParseUint(secondsStr, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("parse seconds: %w", err)
+	}
+
+	var increment uint64
+	if incrementStr != "" {
+		increment, err = strconv.ParseUint(incrementStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("parse increment: %w", err)
+		}
+	}
+
+	return &primitive.Timestamp{T: uint32(seconds), I: uint32(increment)}, nil
+}
@@ -15,16 +15,30 @@
 package source
 
 import (
+	"encoding/base64"
 	"net/url"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/source/iterator"
+	"github.com/conduitio-labs/conduit-connector-mongo/validator"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/tag"
 )
 
 func TestParseConfig(t *testing.T) {
 	t.Parallel()
 
+	resumeToken, err := bson.Marshal(bson.D{{Key: "_data", Value: "826553F10000000003"}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
 	tests := []struct {
 		name    string
 		raw     map[string]string
@@ -44,12 +58,45 @@ func TestParseConfig(t *testing.T) {
 						Scheme: "mongodb",
 						Host:   "localhost:27017",
 					},
-					DB:         "test",
-					Collection: "users",
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				},
-				BatchSize:     defaultBatchSize,
-				Snapshot:      defaultSnapshot,
-				OrderingField: defaultOrderingField,
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
 			},
 			wantErr: false,
 		},
@@ -67,12 +114,45 @@ func TestParseConfig(t *testing.T) {
 						Scheme: "mongodb",
 						Host:   "localhost:27017",
 					},
-					DB:         "test",
-					Collection: "users",
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				},
-				BatchSize:     100,
-				Snapshot:      defaultSnapshot,
-				OrderingField: defaultOrderingField,
+				BatchSize:                  100,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
 			},
 			wantErr: false,
 		},
@@ -90,12 +170,45 @@ func TestParseConfig(t *testing.T) {
 						Scheme: "mongodb",
 						Host:   "localhost:27017",
 					},
-					DB:         "test",
-					Collection: "users",
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				},
-				BatchSize:     defaultBatchSize,
-				Snapshot:      false,
-				OrderingField: defaultOrderingField,
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   false,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
 			},
 			wantErr: false,
 		},
@@ -113,81 +226,3962 @@ func TestParseConfig(t *testing.T) {
 						Scheme: "mongodb",
 						Host:   "localhost:27017",
 					},
-					DB:         "test",
-					Collection: "users",
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
 				},
-				BatchSize:     defaultBatchSize,
-				Snapshot:      defaultSnapshot,
-				OrderingField: "created_at",
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              "created_at",
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
 			},
 			wantErr: false,
 		},
 		{
-			name: "fail_invalid_common_config_missing_required",
+			name: "success_custom_snapshot_on_stale_token",
 			raw: map[string]string{
-				config.KeyDB: "test",
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeySnapshotOnStaleToken: "RESNAPSHOT",
 			},
-			want:    Config{},
-			wantErr: true,
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       StaleTokenResnapshot,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
 		},
 		{
-			name: "fail_invalid_batch_size_gte",
+			name: "success_snapshot_collection_metadata",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeySnapshotCollectionMetadata: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotCollectionMetadata: true,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_schema_mode_sample",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeySchemaMode:       "Sample",
+				ConfigKeySchemaSampleSize: "500",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 iterator.SchemaModeSample,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           500,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_custom_key_format",
 			raw: map[string]string{
 				config.KeyURI:        "mongodb://localhost:27017",
 				config.KeyDB:         "test",
 				config.KeyCollection: "users",
-				ConfigKeyBatchSize:   "-1",
+				ConfigKeyKeyFormat:   "JSON",
 			},
-			want:    Config{},
-			wantErr: true,
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  iterator.KeyFormatJSON,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
 		},
 		{
-			name: "fail_invalid_batch_size_lte",
+			name: "success_cdc_verify_resume",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCVerifyResume: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				CDCVerifyResume:            true,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_collection_events",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyCDCCollectionEvents: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				CDCCollectionEvents:        true,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_rate_limit",
 			raw: map[string]string{
 				config.KeyURI:        "mongodb://localhost:27017",
 				config.KeyDB:         "test",
 				config.KeyCollection: "users",
-				ConfigKeyBatchSize:   "1000000000",
+				ConfigKeyRateLimit:   "500",
 			},
-			want:    Config{},
-			wantErr: true,
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				RateLimit:                  500,
+			},
+			wantErr: false,
 		},
 		{
-			name: "fail_invalid_batch_size_nan",
+			name: "success_cdc_max_retries",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCDCMaxRetries: "5",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCMaxRetries:              5,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_polling_updated_at_field",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeyPollingUpdatedAtField: "updatedAt",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				PollingUpdatedAtField:      "updatedAt",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_compatibility_cosmosdb",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCompatibility: "CosmosDB",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              iterator.CompatibilityCosmosDB,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_compatibility_ferretdb",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCompatibility: "ferretdb",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              iterator.CompatibilityFerretDB,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_disallow_disk_use",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeySnapshotAllowDiskUse: "false",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       false,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_strict_types",
 			raw: map[string]string{
 				config.KeyURI:        "mongodb://localhost:27017",
 				config.KeyDB:         "test",
 				config.KeyCollection: "users",
-				ConfigKeyBatchSize:   "two",
+				ConfigKeyStrictTypes: "true",
 			},
-			want:    Config{},
-			wantErr: true,
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                true,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
 		},
 		{
-			name: "fail_invalid_snapshot_mode",
+			name: "success_convert_uuid",
 			raw: map[string]string{
 				config.KeyURI:        "mongodb://localhost:27017",
 				config.KeyDB:         "test",
 				config.KeyCollection: "users",
-				ConfigKeySnapshot:    "no",
+				ConfigKeyConvertUUID: "String",
 			},
-			want:    Config{},
-			wantErr: true,
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                codec.UUIDFormatString,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-
-			got, err := ParseConfig(tt.raw)
-			if (err != nil) != tt.wantErr {
-				t.Fatalf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
-			}
-
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("ParseConfig() = %v, want %v", got, tt.want)
-			}
-		})
-	}
+		{
+			name: "success_convert_binary",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyConvertBinary: "Base64",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              codec.BinaryFormatBase64,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_consistent",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeySnapshotConsistent: "true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SnapshotConsistent:         true,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_partition_key_shard",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyPartitionKey: "Shard",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               iterator.PartitionKeyModeShard,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_dbref_resolve",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyDBRefMode:     "Resolve",
+				ConfigKeyDBRefMaxDepth: "3",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  iterator.DBRefModeResolve,
+				DBRefMaxDepth:              3,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_convert_native_timestamps",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyConvertDateTime:  "Native",
+				ConfigKeyConvertTimestamp: "Date",
+				ConfigKeySchemaMode:       "sample",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            codec.DateTimeFormatNative,
+				ConvertTimestamp:           codec.TimestampFormatDate,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 iterator.SchemaModeSample,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_sharded",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeySnapshotSharded:     "true",
+				ConfigKeySnapshotParallelism: "8",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        8,
+				SnapshotSharded:            true,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_uri",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySnapshotURI: "mongodb://archive.query.mongodb.net/?ssl=true",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				SnapshotURI: &url.URL{
+					Scheme:   "mongodb",
+					Host:     "archive.query.mongodb.net",
+					Path:     "/",
+					RawQuery: "ssl=true",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_transform",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyTransformFilter: ` total > 100 `,
+				ConfigKeyTransformFields: `{"fullName": "first + ' ' + last"}`,
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				TransformFilter:            "total > 100",
+				TransformFields:            `{"fullName": "first + ' ' + last"}`,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_resume_boundary",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeySnapshotResumeBoundary: "Inclusive",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     iterator.ResumeBoundaryInclusive,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_csfle",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyCSFLEKeyVaultNamespace: "encryption.__keyVault",
+				ConfigKeyCSFLEKMSProviders:      `{"local":{"key":"a2V5"}}`,
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				CSFLEKeyVaultNamespace:     "encryption.__keyVault",
+				CSFLEKMSProviders:          `{"local":{"key":"a2V5"}}`,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_polling_soft_delete",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyPollingUpdatedAtField:  "updatedAt",
+				ConfigKeyPollingDeleteStrategy:  "SOFTDELETE",
+				ConfigKeyPollingSoftDeleteField: "deleted",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      iterator.DeleteStrategySoftDelete,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				PollingUpdatedAtField:      "updatedAt",
+				PollingSoftDeleteField:     "deleted",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_polling_id_set",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeyPollingDeleteStrategy:      "idset",
+				ConfigKeyPollingDeleteCheckInterval: "30s",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      iterator.DeleteStrategyIDSet,
+				PollingDeleteCheckInterval: 30 * time.Second,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_coalesce",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyCDCCoalesceWindow:  "250ms",
+				ConfigKeyCDCCoalesceMaxSize: "50",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         50,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCCoalesceWindow:          250 * time.Millisecond,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_mode_oplog",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyCDCMode:     "Oplog",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    iterator.CDCModeOplog,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_mode_tailable",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyCDCMode:     "tailable",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    iterator.CDCModeTailable,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_suppress_unchanged",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyCDCSuppressUnchanged: "true",
+				ConfigKeyCDCSuppressCacheSize: "500",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       500,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCSuppressUnchanged:       true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_schema_drift",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySchemaDrift: "Metadata",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                iterator.SchemaDriftModeMetadata,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_heartbeat_interval",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyCDCHeartbeatInterval: "1m",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCHeartbeatInterval:       time.Minute,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_prefetch_size",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCPrefetchSize: "500",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCPrefetchSize:            500,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_max_await_time",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCMaxAwaitTime: "5s",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCMaxAwaitTime:            5 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_composite_ordering_field",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyOrderingField: "tenant, seq",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              "tenant, seq",
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_max_batch_bytes",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeySnapshotMaxBatchBytes: "16777216",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				SnapshotMaxBatchBytes:      16777216,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_checkpoint",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeySnapshotCheckpointRecords:  "1000",
+				ConfigKeySnapshotCheckpointInterval: "5s",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				SnapshotCheckpointRecords:  1000,
+				SnapshotCheckpointInterval: 5 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_snapshot_max_docs_per_second",
+			raw: map[string]string{
+				config.KeyURI:                     "mongodb://localhost:27017",
+				config.KeyDB:                      "test",
+				config.KeyCollection:              "users",
+				ConfigKeySnapshotMaxDocsPerSecond: "500",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				SnapshotMaxDocsPerSecond:   500,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_read_concern_level",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyReadConcernLevel: "Majority",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				ReadConcernLevel:           iterator.ReadConcernLevelMajority,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_read_preference",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyReadPreferenceMode: "secondaryPreferred",
+				ConfigKeyReadPreferenceTags: "nodeType:ANALYTICS, dc:east;",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				ReadPreferenceMode:         "secondaryPreferred",
+				ReadPreferenceTags:         "nodeType:ANALYTICS, dc:east;",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_incremental_snapshot",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeySnapshotMode:     "INCREMENTAL",
+				ConfigKeySnapshotTrigger:  "backfill",
+				ConfigKeySignalCollection: "signals",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               iterator.SnapshotModeIncremental,
+				SnapshotTrigger:            "backfill",
+				SignalCollection:           "signals",
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_custom_convert",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyConvertDateTime: "MILLIS",
+				ConfigKeyConvertDecimal:  "float",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            codec.DateTimeFormatMillis,
+				ConvertTimestamp:           codec.TimestampFormatTimestamp,
+				ConvertDecimal:             codec.DecimalFormatFloat,
+				ConvertUUID:                codec.UUIDFormatBytes,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_custom_payload_format",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyPayloadFormat: "debezium",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              iterator.PayloadFormatDebezium,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_custom_payload_format_extjson",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyPayloadFormat: "EXTJSON",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              iterator.PayloadFormatExtendedJSON,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_start_at_operation_time_timestamp",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeyCDCStartAtOperationTime: "1700000000.5",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCStartAtOperationTime:    &primitive.Timestamp{T: 1700000000, I: 5},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_stop_at_operation_time",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeyCDCStartAtOperationTime: "1700000000.5",
+				ConfigKeyCDCStopAtOperationTime:  "2023-11-15T00:00:00Z",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCStartAtOperationTime:    &primitive.Timestamp{T: 1700000000, I: 5},
+				CDCStopAtOperationTime:     &primitive.Timestamp{T: 1700006400},
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_resume_token_hex",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyCDCResumeToken: "826553F10000000003",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCResumeToken:             resumeToken,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_resume_token_base64",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyCDCResumeToken: base64.StdEncoding.EncodeToString(resumeToken),
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCResumeToken:             resumeToken,
+			},
+			wantErr: false,
+		},
+		{
+			name: "success_cdc_start_at_operation_time_rfc3339",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeyCDCStartAtOperationTime: "2023-11-14T22:13:20Z",
+			},
+			want: Config{
+				Config: config.Config{
+					URI: &url.URL{
+						Scheme: "mongodb",
+						Host:   "localhost:27017",
+					},
+					DB:                          "test",
+					Collection:                  "users",
+					Serverless:                  config.ServerlessAuto,
+					BufferPoolEnabled:           true,
+					TelemetryEnabled:            true,
+					ObjectIDCodecEnabled:        true,
+					IDType:                      codec.IDTypeAuto,
+					OpenMaxRetries:              3,
+					OpenRetryBackoff:            time.Second,
+					CommandMonitorSlowThreshold: 100 * time.Millisecond,
+				},
+				BatchSize:                  defaultBatchSize,
+				Snapshot:                   defaultSnapshot,
+				OrderingField:              defaultOrderingField,
+				SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+				PayloadFormat:              defaultPayloadFormat,
+				KeyFormat:                  defaultKeyFormat,
+				ConvertDateTime:            defaultConvertDateTime,
+				ConvertTimestamp:           defaultConvertTimestamp,
+				ConvertDecimal:             defaultConvertDecimal,
+				ConvertUUID:                defaultConvertUUID,
+				ConvertBinary:              defaultConvertBinary,
+				SchemaMode:                 defaultSchemaMode,
+				SchemaDrift:                defaultSchemaDrift,
+				CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+				CDCMode:                    defaultCDCMode,
+				CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+				PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+				PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+				Compatibility:              defaultCompatibility,
+				DBRefMode:                  defaultDBRefMode,
+				DBRefMaxDepth:              defaultDBRefMaxDepth,
+				PartitionKey:               defaultPartitionKey,
+				SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+				SnapshotParallelism:        defaultSnapshotParallelism,
+				StrictTypes:                defaultStrictTypes,
+				SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+				SchemaSampleSize:           defaultSchemaSampleSize,
+				SnapshotMode:               defaultSnapshotMode,
+				CDCStartAtOperationTime:    &primitive.Timestamp{T: 1700000000},
+			},
+			wantErr: false,
+		},
+		{
+			name: "fail_invalid_common_config_missing_required",
+			raw: map[string]string{
+				config.KeyDB: "test",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_mode",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeySnapshotMode: "lazy",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_max_batch_bytes_gte",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeySnapshotMaxBatchBytes: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_checkpoint_records",
+			raw: map[string]string{
+				config.KeyURI:                      "mongodb://localhost:27017",
+				config.KeyDB:                       "test",
+				config.KeyCollection:               "users",
+				ConfigKeySnapshotCheckpointRecords: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_max_docs_per_second",
+			raw: map[string]string{
+				config.KeyURI:                     "mongodb://localhost:27017",
+				config.KeyDB:                      "test",
+				config.KeyCollection:              "users",
+				ConfigKeySnapshotMaxDocsPerSecond: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_checkpoint_interval",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeySnapshotCheckpointInterval: "often",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_checkpoint_interval_gte",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeySnapshotCheckpointInterval: "-1s",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_read_preference_mode",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyReadPreferenceMode: "closest",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_read_preference_tags",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyReadPreferenceMode: "nearest",
+				ConfigKeyReadPreferenceTags: "dc",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_read_preference_tags_without_mode",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyReadPreferenceTags: "dc:east",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_read_preference_tags_with_primary",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyReadPreferenceMode: "primary",
+				ConfigKeyReadPreferenceTags: "dc:east",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_read_concern_level",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyReadConcernLevel: "linearizable",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_batch_size_gte",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyBatchSize:   "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_batch_size_lte",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyBatchSize:   "1000000000",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_batch_size_nan",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyBatchSize:   "two",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_mode",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySnapshot:    "no",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_on_stale_token",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeySnapshotOnStaleToken: "ignore",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_collection_metadata",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeySnapshotCollectionMetadata: "sometimes",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_schema_mode",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySchemaMode:  "guess",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_schema_sample_size",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeySchemaMode:       "sample",
+				ConfigKeySchemaSampleSize: "0",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_schema_mode_with_extjson_payload_format",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeySchemaMode:    "validator",
+				ConfigKeyPayloadFormat: "extjson",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_convert_native_without_schema_mode",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyConvertDateTime: "native",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_key_format",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyKeyFormat:   "avro",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_heartbeat_interval",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyCDCHeartbeatInterval: "often",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_cdc_heartbeat_interval",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyCDCHeartbeatInterval: "-1s",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_cdc_prefetch_size",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCPrefetchSize: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_prefetch_size",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCPrefetchSize: "many",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_max_await_time",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCMaxAwaitTime: "often",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_cdc_max_await_time",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCMaxAwaitTime: "-1s",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_schema_drift",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySchemaDrift: "strict",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_zero_cdc_suppress_cache_size",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyCDCSuppressCacheSize: "0",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_mode",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyCDCMode:     "polling",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_zero_cdc_coalesce_max_size",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeyCDCCoalesceMaxSize: "0",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_polling_delete_strategy",
+			raw: map[string]string{
+				config.KeyURI:                  "mongodb://localhost:27017",
+				config.KeyDB:                   "test",
+				config.KeyCollection:           "users",
+				ConfigKeyPollingDeleteStrategy: "tombstone",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_polling_soft_delete_without_updated_at_field",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyPollingDeleteStrategy:  "softdelete",
+				ConfigKeyPollingSoftDeleteField: "deleted",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_polling_delete_check_interval",
+			raw: map[string]string{
+				config.KeyURI:                       "mongodb://localhost:27017",
+				config.KeyDB:                        "test",
+				config.KeyCollection:                "users",
+				ConfigKeyPollingDeleteCheckInterval: "often",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_compatibility",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCompatibility: "documentdb",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_allow_disk_use",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeySnapshotAllowDiskUse: "maybe",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_strict_types",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyStrictTypes: "maybe",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_csfle_missing_key_vault_namespace",
+			raw: map[string]string{
+				config.KeyURI:              "mongodb://localhost:27017",
+				config.KeyDB:               "test",
+				config.KeyCollection:       "users",
+				ConfigKeyCSFLEKMSProviders: `{"local":{"key":"a2V5"}}`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_csfle_invalid_key_vault_namespace",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyCSFLEKeyVaultNamespace: "keyVault",
+				ConfigKeyCSFLEKMSProviders:      `{"local":{"key":"a2V5"}}`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_csfle_invalid_kms_providers",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyCSFLEKeyVaultNamespace: "encryption.__keyVault",
+				ConfigKeyCSFLEKMSProviders:      `{"local":`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_csfle_invalid_schema_map",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyCSFLEKeyVaultNamespace: "encryption.__keyVault",
+				ConfigKeyCSFLEKMSProviders:      `{"local":{"key":"a2V5"}}`,
+				ConfigKeyCSFLESchemaMap:         "[]",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_resume_boundary",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeySnapshotResumeBoundary: "overlapping",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_transform_filter",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyTransformFilter: "total >",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_transform_fields",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyTransformFields: `["first"]`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_transform_field_expression",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyTransformFields: `{"fullName": "first +"}`,
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_transform_with_extjson_payload",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyTransformFilter: "total > 100",
+				ConfigKeyPayloadFormat:   "extjson",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_uri_scheme",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySnapshotURI: "https://archive.query.mongodb.net",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_snapshot_sharded",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeySnapshotSharded: "sometimes",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_zero_snapshot_parallelism",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeySnapshotParallelism: "0",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_uuid",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyConvertUUID: "hex",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_timestamp",
+			raw: map[string]string{
+				config.KeyURI:             "mongodb://localhost:27017",
+				config.KeyDB:              "test",
+				config.KeyCollection:      "users",
+				ConfigKeyConvertTimestamp: "seconds",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_binary",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyConvertBinary: "hex",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_dbref_mode",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyDBRefMode:   "inline",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_zero_dbref_max_depth",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyDBRefMaxDepth: "0",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_dbref_with_extjson_payload",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyDBRefMode:     "normalize",
+				ConfigKeyPayloadFormat: "extjson",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_partition_key",
+			raw: map[string]string{
+				config.KeyURI:         "mongodb://localhost:27017",
+				config.KeyDB:          "test",
+				config.KeyCollection:  "users",
+				ConfigKeyPartitionKey: "random",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_snapshot_consistent_with_incremental",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeySnapshotConsistent: "true",
+				ConfigKeySnapshotMode:       "incremental",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_snapshot_consistent_with_sharded",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeySnapshotConsistent: "true",
+				ConfigKeySnapshotSharded:    "true",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_snapshot_consistent_with_oplog",
+			raw: map[string]string{
+				config.KeyURI:               "mongodb://localhost:27017",
+				config.KeyDB:                "test",
+				config.KeyCollection:        "users",
+				ConfigKeySnapshotConsistent: "true",
+				ConfigKeyCDCMode:            "oplog",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_snapshot_consistent_with_start_time",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeySnapshotConsistent:      "true",
+				ConfigKeyCDCStartAtOperationTime: "1700000000",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_resume_token",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyCDCResumeToken: "not a token",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_resume_token_with_oplog",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyCDCResumeToken: "826553F10000000003",
+				ConfigKeyCDCMode:        "oplog",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_stop_at_operation_time",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyCDCStopAtOperationTime: "tomorrow",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_stop_at_operation_time_with_tailable",
+			raw: map[string]string{
+				config.KeyURI:                   "mongodb://localhost:27017",
+				config.KeyDB:                    "test",
+				config.KeyCollection:            "users",
+				ConfigKeyCDCStopAtOperationTime: "1700000000",
+				ConfigKeyCDCMode:                "tailable",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_stop_at_operation_time_before_start",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeyCDCStopAtOperationTime:  "1700000000",
+				ConfigKeyCDCStartAtOperationTime: "1700000000.1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_resume_token_with_start_time",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeyCDCResumeToken:          "826553F10000000003",
+				ConfigKeyCDCStartAtOperationTime: "1700000000",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_resume_token_with_resnapshot",
+			raw: map[string]string{
+				config.KeyURI:                 "mongodb://localhost:27017",
+				config.KeyDB:                  "test",
+				config.KeyCollection:          "users",
+				ConfigKeyCDCResumeToken:       "826553F10000000003",
+				ConfigKeySnapshotOnStaleToken: "resnapshot",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_cdc_max_retries",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyCDCMaxRetries: "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_negative_rate_limit",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyRateLimit:   "-1",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_empty_ordering_field_name",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyOrderingField: "tenant,,seq",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_verify_resume",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyCDCVerifyResume: "yes please",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_collection_events",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyCDCCollectionEvents: "maybe",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_collection_events_with_oplog",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyCDCCollectionEvents: "true",
+				ConfigKeyCDCMode:             "oplog",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_cdc_collection_events_with_cosmosdb",
+			raw: map[string]string{
+				config.KeyURI:                "mongodb://localhost:27017",
+				config.KeyDB:                 "test",
+				config.KeyCollection:         "users",
+				ConfigKeyCDCCollectionEvents: "true",
+				ConfigKeyCompatibility:       "cosmosdb",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_date_time",
+			raw: map[string]string{
+				config.KeyURI:            "mongodb://localhost:27017",
+				config.KeyDB:             "test",
+				config.KeyCollection:     "users",
+				ConfigKeyConvertDateTime: "unix",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_convert_decimal",
+			raw: map[string]string{
+				config.KeyURI:           "mongodb://localhost:27017",
+				config.KeyDB:            "test",
+				config.KeyCollection:    "users",
+				ConfigKeyConvertDecimal: "int",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_payload_format",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyPayloadFormat: "avro",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+		{
+			name: "fail_invalid_cdc_start_at_operation_time",
+			raw: map[string]string{
+				config.KeyURI:                    "mongodb://localhost:27017",
+				config.KeyDB:                     "test",
+				config.KeyCollection:             "users",
+				ConfigKeyCDCStartAtOperationTime: "yesterday",
+			},
+			want:    Config{},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ParseConfig(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfig_fieldErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		raw            map[string]string
+		wantKey        string
+		wantConstraint string
+	}{
+		{
+			name: "missing_required",
+			raw: map[string]string{
+				config.KeyURI: "mongodb://localhost:27017",
+				config.KeyDB:  "test",
+			},
+			wantKey:        config.KeyCollection,
+			wantConstraint: validator.ConstraintRequired,
+		},
+		{
+			name: "invalid_format",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeySnapshot:    "maybe",
+			},
+			wantKey:        ConfigKeySnapshot,
+			wantConstraint: validator.ConstraintFormat,
+		},
+		{
+			name: "out_of_range",
+			raw: map[string]string{
+				config.KeyURI:        "mongodb://localhost:27017",
+				config.KeyDB:         "test",
+				config.KeyCollection: "users",
+				ConfigKeyBatchSize:   "0",
+			},
+			wantKey:        ConfigKeyBatchSize,
+			wantConstraint: validator.ConstraintGTE,
+		},
+		{
+			name: "incompatible",
+			raw: map[string]string{
+				config.KeyURI:          "mongodb://localhost:27017",
+				config.KeyDB:           "test",
+				config.KeyCollection:   "users",
+				ConfigKeyPayloadFormat: "extjson",
+				ConfigKeySchemaMode:    "sample",
+			},
+			wantKey:        ConfigKeyPayloadFormat,
+			wantConstraint: validator.ConstraintCompatible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseConfig(tt.raw)
+
+			fieldErrs := validator.FieldErrors(err)
+			if len(fieldErrs) != 1 {
+				t.Fatalf("FieldErrors(%v) = %v, want a single error", err, fieldErrs)
+			}
+
+			if fieldErrs[0].Key != tt.wantKey || fieldErrs[0].Constraint != tt.wantConstraint {
+				t.Errorf("FieldErrors() = %s %s, want %s %s",
+					fieldErrs[0].Key, fieldErrs[0].Constraint, tt.wantKey, tt.wantConstraint)
+			}
+		})
+	}
+}
+
+func TestConfig_OrderingFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		orderingField string
+		want          []string
+	}{
+		{
+			name:          "single_field",
+			orderingField: "_id",
+			want:          []string{"_id"},
+		},
+		{
+			name:          "composite_fields",
+			orderingField: "tenant, seq,createdAt",
+			want:          []string{"tenant", "seq", "createdAt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Config{OrderingField: tt.orderingField}.OrderingFields()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Config.OrderingFields() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_AutoEncryptionOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not_configured", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := Config{}.AutoEncryptionOptions()
+		if err != nil {
+			t.Fatalf("Config.AutoEncryptionOptions() error = %v", err)
+		}
+
+		if got != nil {
+			t.Errorf("Config.AutoEncryptionOptions() = %v, want nil", got)
+		}
+	})
+
+	t.Run("decryption_only", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := Config{
+			CSFLEKeyVaultNamespace: "encryption.__keyVault",
+			CSFLEKMSProviders:      `{"local":{"key":"a2V5"}}`,
+		}.AutoEncryptionOptions()
+		if err != nil {
+			t.Fatalf("Config.AutoEncryptionOptions() error = %v", err)
+		}
+
+		if got.KeyVaultNamespace != "encryption.__keyVault" {
+			t.Errorf("Config.AutoEncryptionOptions().KeyVaultNamespace = %q, want %q",
+				got.KeyVaultNamespace, "encryption.__keyVault")
+		}
+
+		if want := map[string]map[string]any{"local": {"key": "a2V5"}}; !reflect.DeepEqual(got.KmsProviders, want) {
+			t.Errorf("Config.AutoEncryptionOptions().KmsProviders = %v, want %v", got.KmsProviders, want)
+		}
+
+		if got.BypassAutoEncryption == nil || !*got.BypassAutoEncryption {
+			t.Errorf("Config.AutoEncryptionOptions().BypassAutoEncryption = %v, want true", got.BypassAutoEncryption)
+		}
+	})
+
+	t.Run("schema_map", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := Config{
+			CSFLEKeyVaultNamespace: "encryption.__keyVault",
+			CSFLEKMSProviders:      `{"local":{"key":"a2V5"}}`,
+			CSFLESchemaMap:         `{"test.users":{"bsonType":"object"}}`,
+		}.AutoEncryptionOptions()
+		if err != nil {
+			t.Fatalf("Config.AutoEncryptionOptions() error = %v", err)
+		}
+
+		if got.BypassAutoEncryption != nil {
+			t.Errorf("Config.AutoEncryptionOptions().BypassAutoEncryption = %v, want nil", *got.BypassAutoEncryption)
+		}
+
+		if _, ok := got.SchemaMap["test.users"]; !ok {
+			t.Errorf("Config.AutoEncryptionOptions().SchemaMap = %v, want the test.users schema", got.SchemaMap)
+		}
+	})
+}
+
+func TestConfig_ReadPreference(t *testing.T) {
+	t.Parallel()
+
+	t.Run("not_configured", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := Config{}.ReadPreference()
+		if err != nil {
+			t.Fatalf("Config.ReadPreference() error = %v", err)
+		}
+
+		if got != nil {
+			t.Errorf("Config.ReadPreference() = %v, want nil", got)
+		}
+	})
+
+	t.Run("tag_sets", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := Config{
+			ReadPreferenceMode: "SecondaryPreferred",
+			ReadPreferenceTags: "nodeType:ANALYTICS, dc:east;",
+		}.ReadPreference()
+		if err != nil {
+			t.Fatalf("Config.ReadPreference() error = %v", err)
+		}
+
+		if got.Mode() != readpref.SecondaryPreferredMode {
+			t.Errorf("Config.ReadPreference().Mode() = %v, want %v", got.Mode(), readpref.SecondaryPreferredMode)
+		}
+
+		want := []tag.Set{{{Name: "nodeType", Value: "ANALYTICS"}, {Name: "dc", Value: "east"}}, {}}
+		if !reflect.DeepEqual(got.TagSets(), want) {
+			t.Errorf("Config.ReadPreference().TagSets() = %v, want %v", got.TagSets(), want)
+		}
+	})
 }
@@ -0,0 +1,96 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResumeBoundary defines whether a resumed snapshot emits the document its position points to once again.
+type ResumeBoundary string
+
+// The available resume boundaries are listed below.
+const (
+	// ResumeBoundaryExclusive makes a resumed snapshot start right after the last emitted document.
+	ResumeBoundaryExclusive ResumeBoundary = "exclusive"
+	// ResumeBoundaryInclusive makes a resumed snapshot start from the last emitted document,
+	// so it's emitted twice.
+	ResumeBoundaryInclusive ResumeBoundary = "inclusive"
+)
+
+// positionBounds holds the snapshot bounds of a [position] with their original BSON types.
+type positionBounds struct {
+	Element    any `bson:"element"`
+	ElementID  any `bson:"elementId"`
+	MaxElement any `bson:"maxElement"`
+	TailID     any `bson:"tailId,omitempty"`
+}
+
+// setBounds encodes the element, its _id, the max element and the tail _id of the position into its bounds.
+// The bounds are nil if the position has none of them.
+func (p *position) setBounds() error {
+	p.Bounds = nil
+	if p.Element == nil && p.ElementID == nil && p.MaxElement == nil && p.TailID == nil {
+		return nil
+	}
+
+	bounds, err := bson.Marshal(positionBounds{
+		Element:    p.Element,
+		ElementID:  p.ElementID,
+		MaxElement: p.MaxElement,
+		TailID:     p.TailID,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal position bounds: %w", err)
+	}
+
+	p.Bounds = bounds
+
+	return nil
+}
+
+// restoreBounds replaces the element, its _id and the max element decoded from JSON with the typed
+// values of the position bounds, and restores the tail _id, which is stored in the bounds only.
+// Positions stored before the bounds were introduced are left as they are.
+func (p *position) restoreBounds() error {
+	if p.Bounds == nil {
+		return nil
+	}
+
+	var bounds positionBounds
+	if err := bson.Unmarshal(p.Bounds, &bounds); err != nil {
+		return fmt.Errorf("unmarshal position bounds: %w", err)
+	}
+
+	p.Element = boundValue(bounds.Element)
+	p.ElementID = bounds.ElementID
+	p.MaxElement = boundValue(bounds.MaxElement)
+	p.TailID = bounds.TailID
+
+	return nil
+}
+
+// boundValue converts a decoded tuple of ordering field values back into a slice,
+// as elements of multiple ordering fields are built as slices.
+func boundValue(value any) any {
+	if array, ok := value.(primitive.A); ok {
+		return []any(array)
+	}
+
+	return value
+}
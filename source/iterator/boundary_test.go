@@ -0,0 +1,128 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPosition_bounds(t *testing.T) {
+	t.Parallel()
+
+	id := primitive.NewObjectID()
+	createdAt := primitive.NewDateTimeFromTime(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		position *position
+	}{
+		{
+			name: "object_id",
+			position: &position{
+				Mode:       modeSnapshot,
+				Element:    id,
+				MaxElement: id,
+			},
+		},
+		{
+			name: "date_with_id",
+			position: &position{
+				Mode:       modeSnapshot,
+				Element:    createdAt,
+				ElementID:  id,
+				MaxElement: createdAt,
+			},
+		},
+		{
+			name: "tuple",
+			position: &position{
+				Mode:       modeSnapshot,
+				Element:    []any{"a", int64(1) << 60},
+				MaxElement: []any{"b", int64(1) << 61},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			sdkPosition, err := tt.position.marshalSDKPosition(nil)
+			is.NoErr(err)
+
+			got, err := parsePosition(sdkPosition)
+			is.NoErr(err)
+
+			// the values keep their types, which plain JSON loses
+			is.Equal(got.Element, tt.position.Element)
+			is.Equal(got.ElementID, tt.position.ElementID)
+			is.Equal(got.MaxElement, tt.position.MaxElement)
+		})
+	}
+}
+
+func TestPosition_tailID(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	id := primitive.NewObjectID()
+
+	document, err := bson.Marshal(bson.M{idFieldName: id})
+	is.NoErr(err)
+
+	// tailed documents' _ids are raw values, which are decoded into their typed values
+	pos := &position{Mode: modeCDC, TailID: bson.Raw(document).Lookup(idFieldName)}
+
+	sdkPosition, err := pos.marshalSDKPosition(nil)
+	is.NoErr(err)
+
+	got, err := parsePosition(sdkPosition)
+	is.NoErr(err)
+	is.Equal(got.TailID, id)
+	is.Equal(got.Element, nil)
+}
+
+func TestParsePosition_withoutBounds(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// positions stored before the bounds were introduced keep the values decoded from JSON
+	got, err := parsePosition([]byte(`{"mode":"snapshot","element":"63bd5ee3ad5b1d4c6ad2b7e0"}`))
+	is.NoErr(err)
+	is.Equal(got.Element, "63bd5ee3ad5b1d4c6ad2b7e0")
+}
+
+func TestParsePosition_integers(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// values stored as plain JSON only keep the precision of integers larger than 2^53
+	got, err := parsePosition([]byte(`{"mode":"cdc","updatedAt":1.5,"updatedId":9007199254740993,` +
+		`"incremental":{"element":[9007199254740993,"bob"]}}`))
+	is.NoErr(err)
+	is.Equal(got.UpdatedAt, 1.5)
+	is.Equal(got.UpdatedID, int64(9007199254740993))
+	is.Equal(got.Incremental.Element, []any{int64(9007199254740993), "bob"})
+}
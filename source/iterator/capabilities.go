@@ -0,0 +1,116 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CheckCapabilities checks whether the collection can be read and its changes can be captured
+// in the provided CDC mode, so misconfigured permissions or deployments are reported before the pipeline runs.
+// Change Streams are checked by opening and closing one, the oplog by reading its oldest entry,
+// and tailable cursors by checking whether the collection is capped. Views are only checked to be read.
+func CheckCapabilities(
+	ctx context.Context, collection *mongo.Collection, mode CDCMode, compatibility Compatibility,
+) error {
+	opts := options.FindOne().SetProjection(bson.M{idFieldName: 1})
+
+	err := collection.FindOne(ctx, bson.M{}, opts).Err()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("read the %q collection: %w", collection.Name(), err)
+	}
+
+	if compatibility == CompatibilityFerretDB {
+		// FerretDB doesn't support Change Streams, new documents are polled with regular reads
+		return nil
+	}
+
+	view, err := isView(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case view && mode == CDCModeAuto:
+		// views don't support Change Streams, new documents are polled with regular reads
+		return nil
+
+	case view:
+		return errViewChangeCapture
+
+	case mode == CDCModeOplog:
+		return checkOplog(ctx, collection)
+
+	case mode == CDCModeTailable:
+		capped, err := isCapped(ctx, collection)
+		if err != nil {
+			return err
+		}
+
+		if !capped {
+			return errNotCappedCollection
+		}
+
+		return nil
+
+	default:
+		return checkChangeStream(ctx, collection, mode, compatibility)
+	}
+}
+
+// checkChangeStream opens a Change Stream on the collection and closes it right away.
+// In the auto mode it checks the oplog instead if the deployment doesn't support Change Streams.
+func checkChangeStream(
+	ctx context.Context, collection *mongo.Collection, mode CDCMode, compatibility Compatibility,
+) error {
+	changeStream, err := createChangeStream(ctx, cdcParams{collection: collection, compatibility: compatibility})
+	if err != nil {
+		switch {
+		case mode != CDCModeChangeStream && isChangeStreamUnsupportedErr(err):
+			return checkOplog(ctx, collection)
+
+		case strings.Contains(err.Error(), matchProjectStageErrMessage):
+			// Azure CosmosDB for MongoDB doesn't support the pipeline, so new documents are polled instead
+			return nil
+
+		default:
+			return err
+		}
+	}
+
+	if err := changeStream.Close(ctx); err != nil {
+		return fmt.Errorf("close change stream: %w", err)
+	}
+
+	return nil
+}
+
+// checkOplog reads the oldest entry of the oplog the collection's changes are tailed from.
+func checkOplog(ctx context.Context, collection *mongo.Collection) error {
+	oplogCollection := collection.Database().Client().Database(oplogDatabaseName).Collection(oplogCollectionName)
+
+	if _, err := oldestOplogTimestamp(ctx, oplogCollection); err != nil {
+		return err
+	}
+
+	return nil
+}
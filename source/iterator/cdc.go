@@ -15,14 +15,18 @@
 package iterator
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -34,6 +38,20 @@ const (
 	operationTypeDelete = "delete"
 )
 
+// The list of metadata fields describing the transaction of a change is listed below,
+// its cluster time is stored in the [codec.MetadataFieldClusterTime] field.
+const (
+	// metadataFieldTxnNumber is a metadata field that holds the number of the transaction the change belongs to.
+	metadataFieldTxnNumber = "mongo.txnNumber"
+	// metadataFieldLSID is a metadata field that holds the UUID of the logical session
+	// the transaction the change belongs to was run in.
+	metadataFieldLSID = "mongo.lsid"
+)
+
+// metadataFieldUpdateDescription is a metadata field that holds the JSON description of the fields
+// changed by an update, so consumers don't have to diff full documents.
+const metadataFieldUpdateDescription = "mongo.updateDescription"
+
 // changeStreamMatchPipeline is a MongoDB Change Stream pipeline that
 // filters and returns only insert, update and delete events.
 var changeStreamMatchPipeline = bson.D{
@@ -55,28 +73,120 @@ type changeStreamEvent struct {
 	// This value is used as the resumeToken.
 	ID bson.Raw `bson:"_id"`
 	// DocumentKey contains the _id field of a document.
-	DocumentKey map[string]any `bson:"documentKey"`
+	DocumentKey bson.Raw `bson:"documentKey"`
 	// OperationType is the type of an operation that the Change Stream reports.
 	OperationType string `bson:"operationType"`
 	// WallTime is the server date and time of the database operation.
 	WallTime time.Time `bson:"wallTime"`
 	// FullDocument contains all fields of a document.
-	FullDocument map[string]any `bson:"fullDocument"`
+	FullDocument bson.Raw `bson:"fullDocument"`
+	// ClusterTime is the cluster time of the operation.
+	ClusterTime primitive.Timestamp `bson:"clusterTime"`
+	// TxnNumber is the number of the transaction the operation belongs to.
+	// It's nil if the operation isn't part of a multi-document transaction.
+	TxnNumber *int64 `bson:"txnNumber"`
+	// LSID identifies the logical session the transaction was run in.
+	// It's nil if the operation isn't part of a multi-document transaction.
+	LSID *logicalSessionID `bson:"lsid"`
+	// UpdateDescription describes the fields changed by an update. It's nil for other operations.
+	UpdateDescription *updateDescription `bson:"updateDescription"`
 	// Namespace is a namespace affected by the event.
 	Namespace struct {
+		// DB is the name of a database where the event occurred.
+		DB string `bson:"db"`
 		// Collection is the name of a collection where the event occurred.
 		Collection string `bson:"coll"`
 	} `bson:"ns"`
+	// To is a namespace the collection is renamed to. It's only set for rename events.
+	To struct {
+		// DB is the name of a database the collection is renamed to.
+		DB string `bson:"db"`
+		// Collection is the new name of the collection.
+		Collection string `bson:"coll"`
+	} `bson:"to"`
+}
+
+// setUpdateDescription puts the description of the fields changed by the update into the record metadata.
+// Values of the updated fields are converted the same way documents are. Events without the description,
+// e.g. ones adapted from replacements, are left as they are.
+func (e changeStreamEvent) setUpdateDescription(
+	metadata opencdc.Metadata, converter codec.Converter, buffers *codec.BufferPool,
+) error {
+	if e.UpdateDescription == nil {
+		return nil
+	}
+
+	// the lists are never null, so consumers don't have to tell them apart from empty ones
+	description := updateDescriptionMetadata{
+		UpdatedFields:   map[string]any{},
+		RemovedFields:   append([]string{}, e.UpdateDescription.RemovedFields...),
+		TruncatedArrays: append([]truncatedArray{}, e.UpdateDescription.TruncatedArrays...),
+	}
+
+	if len(e.UpdateDescription.UpdatedFields) > 0 {
+		updatedFields, err := converter.ConvertRaw(e.UpdateDescription.UpdatedFields)
+		if err != nil {
+			return fmt.Errorf("convert updated fields: %w", err)
+		}
+
+		description.UpdatedFields = updatedFields
+	}
+
+	descriptionBytes, err := buffers.EncodeJSON(description)
+	if err != nil {
+		return fmt.Errorf("marshal update description: %w", err)
+	}
+
+	metadata[metadataFieldUpdateDescription] = string(descriptionBytes)
+
+	return nil
+}
+
+// logicalSessionID identifies a logical session of a Change Stream event.
+type logicalSessionID struct {
+	// ID is the UUID of the session.
+	ID primitive.Binary `bson:"id"`
+}
+
+// updateDescription describes the fields changed by an update.
+type updateDescription struct {
+	// UpdatedFields contains the updated fields with their new values, keyed by their dotted paths.
+	UpdatedFields bson.Raw `bson:"updatedFields"`
+	// RemovedFields is the list of dotted paths of the removed fields.
+	RemovedFields []string `bson:"removedFields"`
+	// TruncatedArrays is the list of arrays truncated by the update.
+	TruncatedArrays []truncatedArray `bson:"truncatedArrays"`
+}
+
+// truncatedArray describes an array truncated by an update.
+type truncatedArray struct {
+	// Field is the dotted path of the array.
+	Field string `bson:"field" json:"field"`
+	// NewSize is the number of elements left in the array.
+	NewSize int32 `bson:"newSize" json:"newSize"`
+}
+
+// updateDescriptionMetadata is the update description put into the record metadata as JSON.
+type updateDescriptionMetadata struct {
+	UpdatedFields   map[string]any   `json:"updatedFields"`
+	RemovedFields   []string         `json:"removedFields"`
+	TruncatedArrays []truncatedArray `json:"truncatedArrays"`
 }
 
 // toRecord converts the underlying [changeStreamEvent] to an [opencdc.Record].
-func (e changeStreamEvent) toRecord() (opencdc.Record, error) {
+// The converter is used to convert the raw document key and full document straight into structured data.
+// The buffers are used to serialize the record position.
+// The incremental is a progress of an incremental snapshot that is stored in the record position.
+func (e changeStreamEvent) toRecord(
+	converter codec.Converter, buffers *codec.BufferPool, incremental *incrementalPosition,
+) (opencdc.Record, error) {
 	position := &position{
 		Mode:        modeCDC,
 		ResumeToken: e.ID,
+		Incremental: incremental,
 	}
 
-	sdkPosition, err := position.marshalSDKPosition()
+	sdkPosition, err := position.marshalSDKPosition(buffers)
 	if err != nil {
 		return opencdc.Record{}, fmt.Errorf("marshal position into opencdc.Position: %w", err)
 	}
@@ -84,26 +194,43 @@ func (e changeStreamEvent) toRecord() (opencdc.Record, error) {
 	// set the record metadata
 	metadata := make(opencdc.Metadata)
 	metadata[metadataFieldCollection] = e.Namespace.Collection
-	metadata.SetCreatedAt(e.WallTime)
 
-	docJSON, err := json.Marshal(e.FullDocument)
+	// events of MongoDB versions older than 6.0 and of CosmosDB have no wall time
+	createdAt := e.WallTime
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	metadata.SetCreatedAt(createdAt)
+	e.setTransactionMetadata(metadata)
+
+	key, err := converter.ConvertRaw(e.DocumentKey)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document key: %w", err)
+	}
+
+	fullDocument, err := converter.ConvertRaw(e.FullDocument)
 	if err != nil {
-		return opencdc.Record{}, fmt.Errorf("failed marshalling into JSON: %w", err)
+		return opencdc.Record{}, fmt.Errorf("convert full document: %w", err)
 	}
 	switch e.OperationType {
 	case operationTypeInsert:
 		return sdk.Util.Source.NewRecordCreate(
-			sdkPosition, metadata, opencdc.StructuredData(e.DocumentKey), opencdc.RawData(docJSON),
+			sdkPosition, metadata, opencdc.StructuredData(key), opencdc.StructuredData(fullDocument),
 		), nil
 
 	case operationTypeUpdate:
+		if err := e.setUpdateDescription(metadata, converter, buffers); err != nil {
+			return opencdc.Record{}, err
+		}
+
 		return sdk.Util.Source.NewRecordUpdate(
-			sdkPosition, metadata, opencdc.StructuredData(e.DocumentKey), nil, opencdc.RawData(docJSON),
+			sdkPosition, metadata, opencdc.StructuredData(key), nil, opencdc.StructuredData(fullDocument),
 		), nil
 
 	case operationTypeDelete:
 		return sdk.SourceUtil{}.NewRecordDelete(
-			sdkPosition, metadata, opencdc.StructuredData(e.DocumentKey), nil,
+			sdkPosition, metadata, opencdc.StructuredData(key), nil,
 		), nil
 
 	default:
@@ -113,46 +240,328 @@ func (e changeStreamEvent) toRecord() (opencdc.Record, error) {
 	}
 }
 
+// setTransactionMetadata puts the cluster time of the event and, if the operation is part of
+// a multi-document transaction, the transaction number and the session UUID into the record metadata.
+// Records of the same transaction share the same pair of the transaction number and the session UUID.
+func (e changeStreamEvent) setTransactionMetadata(metadata opencdc.Metadata) {
+	if !e.ClusterTime.IsZero() {
+		metadata[codec.MetadataFieldClusterTime] = fmt.Sprintf("%d.%d", e.ClusterTime.T, e.ClusterTime.I)
+	}
+
+	if e.TxnNumber != nil {
+		metadata[metadataFieldTxnNumber] = strconv.FormatInt(*e.TxnNumber, 10)
+	}
+
+	if e.LSID != nil {
+		metadata[metadataFieldLSID] = codec.FormatUUID(e.LSID.ID.Data)
+	}
+}
+
 // cdc implements a Change Data Capture iterator for the MongoDB.
 // It works by creating and listening to a MongoDB [Change Stream].
 //
 // [Change Stream]: https://www.mongodb.com/docs/manual/changeStreams/.
 type cdc struct {
-	changeStream *mongo.ChangeStream
+	changeStream  *mongo.ChangeStream
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+	suppressor    *changeSuppressor
+	// incremental is a progress of an incremental snapshot
+	// that is stored in positions of the returned records.
+	incremental *incrementalPosition
+	// params are params the Change Stream is recreated with after a failure.
+	params cdcParams
+	// retryAttempts is the number of times the Change Stream has been recreated
+	// since the last returned record.
+	retryAttempts int
+	// heartbeatInterval is how long the Change Stream has to stay quiet
+	// before a heartbeat record is returned. Zero means no heartbeats.
+	heartbeatInterval time.Duration
+	// heartbeatPending defines if the next record is a heartbeat one.
+	heartbeatPending bool
+	// lastReturnedAt is the time the last record, including a heartbeat one, was returned at.
+	lastReturnedAt time.Time
+	// lastResumeToken is the resume token of the last returned record.
+	lastResumeToken bson.Raw
+	// stopped defines if an event past the stop time has been reached, so no more events are returned.
+	stopped bool
+}
+
+// cdcParams is an incoming params for the [newCDC] function.
+type cdcParams struct {
+	collection    *mongo.Collection
+	position      *position
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+	// suppressor suppresses update events that don't change documents. If it's nil, no events are suppressed.
+	suppressor *changeSuppressor
+	// startAtOperationTime is a cluster time the Change Stream starts from
+	// if the position contains neither a resume token nor an operation time.
+	startAtOperationTime *primitive.Timestamp
+	// verifyResume defines if the Change Stream must be reopened from its initial resume token
+	// right after it's created, to make sure it can be resumed after a pause.
+	verifyResume bool
+	// maxRetries is the max number of times the Change Stream is recreated after a failure
+	// before the error is returned. The budget is restored once a record is returned.
+	maxRetries int
+	// heartbeatInterval is how long the Change Stream has to stay quiet
+	// before a heartbeat record is returned. Zero means no heartbeats.
+	heartbeatInterval time.Duration
+	// maxAwaitTime is how long the server waits for new events before responding
+	// to a request of the next batch. Zero means the server default is used.
+	maxAwaitTime time.Duration
+	// stopAtOperationTime is a cluster time the Change Stream stops at, so events after it aren't returned.
+	// If it's nil, the Change Stream is never stopped.
+	stopAtOperationTime *primitive.Timestamp
+	// collectionEvents defines if the Change Stream returns collection lifecycle events, e.g. drops and renames.
+	collectionEvents bool
+	// compatibility determines the MongoDB-compatible database the Change Stream is adapted to.
+	compatibility Compatibility
 }
 
 // newCDC creates a new instance of the [cdc].
-func newCDC(ctx context.Context, collection *mongo.Collection, position *position) (*cdc, error) {
-	changeStream, err := createChangeStream(ctx, collection, position)
+func newCDC(ctx context.Context, params cdcParams) (*cdc, error) {
+	changeStream, err := createChangeStream(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("create change stream: %w", err)
 	}
 
+	if params.verifyResume {
+		changeStream, err = verifyResumability(ctx, params, changeStream)
+		if err != nil {
+			return nil, fmt.Errorf("verify change stream resumability: %w", err)
+		}
+	}
+
 	return &cdc{
-		changeStream: changeStream,
+		changeStream:  changeStream,
+		payloadFormat: params.payloadFormat,
+		keyFormat:     params.keyFormat,
+		converter:     params.converter,
+		buffers:       params.buffers,
+		payloadSchema: params.payloadSchema,
+		schemaDrift:   params.schemaDrift,
+		suppressor:    params.suppressor,
+		params:        params,
+		// the quiet period is measured from the start, so a heartbeat isn't returned right away
+		heartbeatInterval: params.heartbeatInterval,
+		lastReturnedAt:    time.Now(),
 	}, nil
 }
 
 // hasNext checks whether the [cdc] iterator has records to return or not.
+// If the Change Stream fails, it's recreated from its latest resume token within the retry budget.
+// Update events that don't change documents are skipped if the suppressor is set.
 func (c *cdc) hasNext(ctx context.Context) (bool, error) {
-	return c.changeStream.TryNext(ctx), c.changeStream.Err()
+	if c.stopped {
+		return false, nil
+	}
+
+	for {
+		if c.changeStream.TryNext(ctx) {
+			// unchanged documents are skipped, so the loop moves on to the next event
+			if c.suppressor.unchanged(c.changeStream.Current) {
+				continue
+			}
+
+			if stopAt := c.params.stopAtOperationTime; stopAt != nil && c.currentClusterTime().After(*stopAt) {
+				sdk.Logger(ctx).Info().Msg("reached the cdc stop time, no more changes are captured")
+
+				c.stopped = true
+
+				return false, nil
+			}
+
+			return true, nil
+		}
+
+		err := c.changeStream.Err()
+		if err == nil {
+			return false, nil
+		}
+
+		// there's no point in retrying if the context is done or the resume token is gone
+		if c.retryAttempts >= c.params.maxRetries || ctx.Err() != nil || isStaleResumeTokenErr(err) {
+			return false, err //nolint:wrapcheck // the error is wrapped by the caller
+		}
+
+		c.retryAttempts++
+
+		sdk.Logger(ctx).Warn().Err(err).Int("attempt", c.retryAttempts).Msg("recreating the change stream")
+
+		if err := c.recreate(ctx); err != nil {
+			return false, fmt.Errorf("recreate change stream: %w", err)
+		}
+	}
+}
+
+// recreate closes the failed Change Stream and creates a new one from its latest resume token.
+func (c *cdc) recreate(ctx context.Context) error {
+	params := c.params
+	params.verifyResume = false
+
+	if resumeToken := c.changeStream.ResumeToken(); resumeToken != nil {
+		params.position = &position{
+			Mode:        modeCDC,
+			ResumeToken: resumeToken,
+		}
+	}
+
+	// the Change Stream has already failed, so an error of closing it doesn't matter
+	_ = c.changeStream.Close(ctx)
+
+	changeStream, err := createChangeStream(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	c.changeStream = changeStream
+
+	return nil
 }
 
 // next returns the next record.
-func (c *cdc) next(_ context.Context) (opencdc.Record, error) {
+func (c *cdc) next(ctx context.Context) (opencdc.Record, error) {
 	var event changeStreamEvent
 	if err := c.changeStream.Decode(&event); err != nil {
 		return opencdc.Record{}, fmt.Errorf("decode change stream event: %w", err)
 	}
 
-	record, err := event.toRecord()
+	// collection events don't carry documents, so they're neither formatted nor checked against the schema
+	if isCollectionEvent(event.OperationType) {
+		// dropDatabase events don't have a collection in their namespaces
+		if event.Namespace.Collection == "" {
+			event.Namespace.Collection = c.params.collection.Name()
+		}
+
+		record, err := event.toCollectionEventRecord(c.buffers, c.incremental)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("convert collection event to opencdc.Record: %w", err)
+		}
+
+		c.lastReturnedAt = time.Now()
+		c.lastResumeToken = event.ID
+
+		return record, nil
+	}
+
+	if c.params.compatibility == CompatibilityCosmosDB {
+		if err := adaptCosmosDBEvent(ctx, c.params.collection, &event); err != nil {
+			return opencdc.Record{}, fmt.Errorf("adapt cosmosdb change stream event: %w", err)
+		}
+	}
+
+	record, err := event.toRecord(c.converter, c.buffers, c.incremental)
 	if err != nil {
 		return opencdc.Record{}, fmt.Errorf("convert event to opencdc.Record: %w", err)
 	}
 
+	// keep the converted document for the payload schema, as formatting may replace the payload
+	document, _ := record.Payload.After.(opencdc.StructuredData)
+
+	record, err = formatRecord(record, c.payloadFormat, event.Namespace.DB, event.FullDocument, c.converter, c.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record payload: %w", err)
+	}
+
+	if c.payloadSchema != nil {
+		record = c.payloadSchema.attach(record, document)
+	}
+
+	record = c.schemaDrift.check(ctx, record, event.FullDocument)
+
+	record, err = formatKey(record, c.keyFormat, c.converter.IDType, c.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record key: %w", err)
+	}
+
+	// the first record read after the Change Stream is recreated carries the number of attempts
+	if c.retryAttempts > 0 {
+		record.Metadata[codec.MetadataFieldRetryAttempts] = strconv.Itoa(c.retryAttempts)
+		c.retryAttempts = 0
+	}
+
+	c.lastReturnedAt = time.Now()
+	c.lastResumeToken = event.ID
+
 	return record, nil
 }
 
+// hasHeartbeat checks whether a heartbeat record has to be returned. It's the case if the Change Stream
+// has stayed quiet for the heartbeat interval and its post-batch resume token has advanced since the last record,
+// so the stored position doesn't go stale on idle collections.
+func (c *cdc) hasHeartbeat() bool {
+	// the resume token is past the stop time, so it isn't handed out
+	if c.stopped || c.heartbeatInterval == 0 || time.Since(c.lastReturnedAt) < c.heartbeatInterval {
+		return false
+	}
+
+	resumeToken := c.changeStream.ResumeToken()
+	if resumeToken == nil || bytes.Equal(resumeToken, c.lastResumeToken) {
+		return false
+	}
+
+	c.heartbeatPending = true
+
+	return true
+}
+
+// nextHeartbeat returns a heartbeat record carrying the latest resume token of the Change Stream.
+// The record doesn't carry any document, so it's marked with the heartbeat record type.
+func (c *cdc) nextHeartbeat() (opencdc.Record, error) {
+	resumeToken := c.changeStream.ResumeToken()
+
+	position := &position{
+		Mode:        modeCDC,
+		ResumeToken: resumeToken,
+		Incremental: c.incremental,
+	}
+
+	sdkPosition, err := position.marshalSDKPosition(c.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal position into opencdc.Position: %w", err)
+	}
+
+	now := time.Now()
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = c.params.collection.Name()
+	metadata[codec.MetadataFieldRecordType] = codec.RecordTypeHeartbeat
+	metadata.SetCreatedAt(now)
+
+	c.heartbeatPending = false
+	c.lastReturnedAt = now
+	c.lastResumeToken = resumeToken
+
+	return sdk.Util.Source.NewRecordUpdate(
+		sdkPosition,
+		metadata,
+		opencdc.StructuredData{"collection": c.params.collection.Name()},
+		nil,
+		nil,
+	), nil
+}
+
+// currentDocumentKey returns a string representation of the _id of the current event's document.
+func (c *cdc) currentDocumentKey() string {
+	return c.changeStream.Current.Lookup(documentKeyFieldName, idFieldName).String()
+}
+
+// currentClusterTime returns the cluster time of the current event.
+func (c *cdc) currentClusterTime() primitive.Timestamp {
+	t, i, _ := c.changeStream.Current.Lookup(clusterTimeFieldName).TimestampOK()
+
+	return primitive.Timestamp{T: t, I: i}
+}
+
 // stop stops the iterator.
 func (c *cdc) stop(ctx context.Context) error {
 	if c.changeStream != nil {
@@ -164,31 +573,86 @@ func (c *cdc) stop(ctx context.Context) error {
 	return nil
 }
 
+// isStaleResumeTokenErr checks whether the provided error is returned by MongoDB
+// because a resume token is no longer present in the oplog.
+func isStaleResumeTokenErr(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+
+	return serverErr.HasErrorCode(changeStreamHistoryLostErrCode) ||
+		serverErr.HasErrorCode(changeStreamFatalErrCode)
+}
+
 // createChangeStream creates a MongoDB Change Stream for a provided collection.
 // The resulting Change Stream will listen to events only with the following
 // operation types: insert, update and delete.
 //
 // If a provided [position] is not empty and it has a resumeToken, the Change Stream
 // will start listening to events from that particular position.
-func createChangeStream(
-	ctx context.Context,
-	collection *mongo.Collection,
-	position *position,
-) (*mongo.ChangeStream, error) {
+// Otherwise, if the position or the params have an operation time, the Change Stream
+// will start listening to events that occurred at or after that cluster time.
+func createChangeStream(ctx context.Context, params cdcParams) (*mongo.ChangeStream, error) {
 	// the UpdateLookup option includes a delta describing the changes to the document
 	// and a copy of the entire document that was changed
 	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
 
+	if params.maxAwaitTime > 0 {
+		opts = opts.SetMaxAwaitTime(params.maxAwaitTime)
+	}
+
+	switch position := params.position; {
 	// if a position is not nil and its resumeToken is not empty,
 	// we'll start listening to the Change Stream from that particular position
-	if position != nil && position.ResumeToken != nil {
+	case position != nil && position.ResumeToken != nil:
 		opts = opts.SetResumeAfter(position.ResumeToken)
+
+	case position != nil && position.OperationTime != nil:
+		opts = opts.SetStartAtOperationTime(position.OperationTime)
+
+	case params.startAtOperationTime != nil:
+		opts = opts.SetStartAtOperationTime(params.startAtOperationTime)
 	}
 
-	changeStream, err := collection.Watch(ctx, mongo.Pipeline{changeStreamMatchPipeline}, opts)
+	pipeline := changeStreamPipeline(params.compatibility, params.collectionEvents)
+
+	changeStream, err := params.collection.Watch(ctx, pipeline, opts)
 	if err != nil {
-		return nil, fmt.Errorf("create change stream on the %q collection: %w", collection.Name(), err)
+		return nil, fmt.Errorf("create change stream on the %q collection: %w", params.collection.Name(), err)
 	}
 
 	return changeStream, nil
 }
+
+// verifyResumability captures the initial resume token of the Change Stream, closes it
+// and reopens a new one with that token. This way, missing privileges or oplog issues
+// are caught at startup rather than after the first pause. No events are lost,
+// as the new Change Stream starts from the same point as the original one.
+func verifyResumability(
+	ctx context.Context, params cdcParams, changeStream *mongo.ChangeStream,
+) (*mongo.ChangeStream, error) {
+	resumeToken := changeStream.ResumeToken()
+	if resumeToken == nil {
+		// the original Change Stream is useless if it cannot be resumed
+		_ = changeStream.Close(ctx)
+
+		return nil, errNoResumeToken
+	}
+
+	if err := changeStream.Close(ctx); err != nil {
+		return nil, fmt.Errorf("close change stream: %w", err)
+	}
+
+	params.position = &position{
+		Mode:        modeCDC,
+		ResumeToken: resumeToken,
+	}
+
+	resumedChangeStream, err := createChangeStream(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("resume change stream: %w", err)
+	}
+
+	return resumedChangeStream, nil
+}
@@ -0,0 +1,130 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChangeStreamEvent_toRecord_transactionMetadata(t *testing.T) {
+	t.Parallel()
+
+	documentKey, err := bson.Marshal(bson.M{idFieldName: 1})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	txnNumber := int64(7)
+	sessionID := []byte{
+		0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+	}
+
+	tests := []struct {
+		name  string
+		event changeStreamEvent
+		want  opencdc.Metadata
+	}{
+		{
+			name: "outside_transaction",
+			event: changeStreamEvent{
+				ClusterTime: primitive.Timestamp{T: 1700000000, I: 3},
+			},
+			want: opencdc.Metadata{
+				codec.MetadataFieldClusterTime: "1700000000.3",
+			},
+		},
+		{
+			name: "within_transaction",
+			event: changeStreamEvent{
+				ClusterTime: primitive.Timestamp{T: 1700000000, I: 4},
+				TxnNumber:   &txnNumber,
+				LSID:        &logicalSessionID{ID: primitive.Binary{Subtype: bson.TypeBinaryUUID, Data: sessionID}},
+			},
+			want: opencdc.Metadata{
+				codec.MetadataFieldClusterTime: "1700000000.4",
+				metadataFieldTxnNumber:         "7",
+				metadataFieldLSID:              "123e4567-e89b-12d3-a456-426614174000",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			tt.event.OperationType = operationTypeDelete
+			tt.event.DocumentKey = documentKey
+
+			record, err := tt.event.toRecord(codec.Converter{}, nil, nil)
+			is.NoErr(err)
+
+			for _, field := range []string{codec.MetadataFieldClusterTime, metadataFieldTxnNumber, metadataFieldLSID} {
+				got, ok := record.Metadata[field]
+				want, wantOK := tt.want[field]
+				is.Equal(ok, wantOK)
+				is.Equal(got, want)
+			}
+		})
+	}
+}
+
+func TestChangeStreamEvent_toRecord_updateDescription(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	documentKey, err := bson.Marshal(bson.M{idFieldName: 1})
+	is.NoErr(err)
+
+	fullDocument, err := bson.Marshal(bson.D{{Key: idFieldName, Value: 1}, {Key: "name", Value: "bob"}})
+	is.NoErr(err)
+
+	updatedFields, err := bson.Marshal(bson.D{{Key: "name", Value: "bob"}, {Key: "address.city", Value: "Kyiv"}})
+	is.NoErr(err)
+
+	event := changeStreamEvent{
+		OperationType: operationTypeUpdate,
+		DocumentKey:   documentKey,
+		FullDocument:  fullDocument,
+		UpdateDescription: &updateDescription{
+			UpdatedFields:   updatedFields,
+			RemovedFields:   []string{"nickname"},
+			TruncatedArrays: []truncatedArray{{Field: "tags", NewSize: 2}},
+		},
+	}
+
+	record, err := event.toRecord(codec.Converter{}, nil, nil)
+	is.NoErr(err)
+	is.Equal(record.Metadata[metadataFieldUpdateDescription],
+		`{"updatedFields":{"address.city":"Kyiv","name":"bob"},"removedFields":["nickname"],`+
+			`"truncatedArrays":[{"field":"tags","newSize":2}]}`)
+
+	// events adapted from replacements have no description
+	event.UpdateDescription = nil
+
+	record, err = event.toRecord(codec.Converter{}, nil, nil)
+	is.NoErr(err)
+
+	_, ok := record.Metadata[metadataFieldUpdateDescription]
+	is.True(!ok)
+}
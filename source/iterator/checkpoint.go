@@ -0,0 +1,73 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// checkpointer limits how often snapshots marshal the positions of their records. Records between checkpoints
+// repeat the position of the latest checkpoint, so a snapshot resumed from any of them starts right after
+// the checkpoint and emits the documents following it once again. A zero checkpointer checkpoints every record.
+type checkpointer struct {
+	// records is the number of records a checkpoint is made after. Zero means it's not limited by records.
+	records int
+	// interval is the time a checkpoint is made after. Zero means it's not limited by time.
+	interval time.Duration
+	// pending is the number of records returned since the latest checkpoint.
+	pending int
+	// checkpointedAt is the time of the latest checkpoint.
+	checkpointedAt time.Time
+	// position is the position of the latest checkpoint. It's nil until the first record.
+	position opencdc.Position
+}
+
+// next returns the position of the next record. If a checkpoint is due, the position of the record
+// is marshaled and becomes the new checkpoint, otherwise the latest checkpoint is returned.
+func (c *checkpointer) next(pos *position, buffers *codec.BufferPool) (opencdc.Position, error) {
+	c.pending++
+
+	if c.position != nil && !c.due() {
+		return c.position, nil
+	}
+
+	sdkPosition, err := pos.marshalSDKPosition(buffers)
+	if err != nil {
+		return nil, err
+	}
+
+	c.position = sdkPosition
+	c.pending = 0
+	c.checkpointedAt = time.Now()
+
+	return sdkPosition, nil
+}
+
+// due checks whether a checkpoint must be made, i.e. either of the limits is reached or none is set.
+func (c *checkpointer) due() bool {
+	switch {
+	case c.records == 0 && c.interval == 0:
+		return true
+	case c.records > 0 && c.pending >= c.records:
+		return true
+	case c.interval > 0 && time.Since(c.checkpointedAt) >= c.interval:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestCheckpointer_next(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		checkpoints checkpointer
+		// want are the elements of the positions of five consecutive records
+		want []int
+	}{
+		{
+			name: "every_record",
+			want: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:        "records",
+			checkpoints: checkpointer{records: 2},
+			want:        []int{1, 1, 3, 3, 5},
+		},
+		{
+			name:        "interval",
+			checkpoints: checkpointer{interval: time.Hour},
+			want:        []int{1, 1, 1, 1, 1},
+		},
+		{
+			name:        "records_and_interval",
+			checkpoints: checkpointer{records: 3, interval: time.Hour},
+			want:        []int{1, 1, 1, 4, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			for i, want := range tt.want {
+				sdkPosition, err := tt.checkpoints.next(&position{Mode: modeSnapshot, Element: i + 1}, nil)
+				is.NoErr(err)
+
+				wantPosition, err := (&position{Mode: modeSnapshot, Element: want}).marshalSDKPosition(nil)
+				is.NoErr(err)
+				is.Equal(sdkPosition, wantPosition)
+			}
+		})
+	}
+}
+
+func TestCheckpointer_next_intervalElapsed(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	checkpoints := checkpointer{interval: time.Minute}
+
+	first, err := checkpoints.next(&position{Mode: modeSnapshot, Element: 1}, nil)
+	is.NoErr(err)
+
+	checkpoints.checkpointedAt = checkpoints.checkpointedAt.Add(-time.Minute)
+
+	second, err := checkpoints.next(&position{Mode: modeSnapshot, Element: 2}, nil)
+	is.NoErr(err)
+	is.True(string(first) != string(second))
+	is.Equal(checkpoints.position, opencdc.Position(second))
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// coalescer buffers Change Stream records for a short window and coalesces
+// multiple records of the same document into one carrying its latest state.
+// Coalesced records are returned in the order of their latest events, so their positions keep increasing.
+type coalescer struct {
+	window  time.Duration
+	maxSize int
+	// records are buffered records, ones replaced by later records of the same key are nil.
+	records []*opencdc.Record
+	// indexes are indexes of the latest buffered records by their keys.
+	indexes map[string]int
+}
+
+// newCoalescer creates a new instance of the [coalescer].
+// It returns nil if the window is zero, which means records are not coalesced.
+func newCoalescer(window time.Duration, maxSize int) *coalescer {
+	if window <= 0 {
+		return nil
+	}
+
+	return &coalescer{
+		window:  window,
+		maxSize: maxSize,
+		indexes: make(map[string]int),
+	}
+}
+
+// fill reads records of change events until the window since the first record expires,
+// the max number of distinct keys is buffered, or the Change Stream has no more events.
+// It returns the coalesced records.
+func (c *coalescer) fill(ctx context.Context, changes changeReader) ([]opencdc.Record, error) {
+	var deadline time.Time
+
+	for len(c.indexes) < c.maxSize && (deadline.IsZero() || time.Now().Before(deadline)) {
+		hasNext, err := changes.hasNext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cdc has next: %w", err)
+		}
+
+		if !hasNext {
+			break
+		}
+
+		record, err := changes.next(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("cdc next: %w", err)
+		}
+
+		if deadline.IsZero() {
+			deadline = time.Now().Add(c.window)
+		}
+
+		c.add(record)
+	}
+
+	return c.flush(), nil
+}
+
+// add buffers the record, replacing the buffered record of the same key.
+// A created document stays created, even if it's updated within the window.
+// The update description of a replacing record describes only its own event, so it's dropped,
+// and consumers fall back to the full document. Control records, e.g. collection events, are never coalesced.
+func (c *coalescer) add(record opencdc.Record) {
+	if record.Metadata[codec.MetadataFieldRecordType] != "" {
+		c.records = append(c.records, &record)
+
+		return
+	}
+
+	key := record.Metadata[metadataFieldCollection] + "/" + string(record.Key.Bytes())
+
+	if index, ok := c.indexes[key]; ok {
+		previous := c.records[index]
+		if previous.Operation == opencdc.OperationCreate && record.Operation == opencdc.OperationUpdate {
+			record.Operation = opencdc.OperationCreate
+		}
+
+		delete(record.Metadata, metadataFieldUpdateDescription)
+
+		c.records[index] = nil
+	}
+
+	c.indexes[key] = len(c.records)
+	c.records = append(c.records, &record)
+}
+
+// flush returns the coalesced records and resets the buffer.
+func (c *coalescer) flush() []opencdc.Record {
+	records := make([]opencdc.Record, 0, len(c.indexes))
+	for _, record := range c.records {
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+
+	clear(c.records)
+	c.records = c.records[:0]
+	clear(c.indexes)
+
+	return records
+}
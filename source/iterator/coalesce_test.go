@@ -0,0 +1,128 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+func TestCoalescer_add(t *testing.T) {
+	t.Parallel()
+
+	record := func(operation opencdc.Operation, id, position string) opencdc.Record {
+		return opencdc.Record{
+			Position:  opencdc.Position(position),
+			Operation: operation,
+			Metadata:  opencdc.Metadata{metadataFieldCollection: "users"},
+			Key:       opencdc.StructuredData{idFieldName: id},
+			Payload:   opencdc.Change{After: opencdc.StructuredData{"position": position}},
+		}
+	}
+
+	c := newCoalescer(time.Second, 10)
+	c.add(record(opencdc.OperationCreate, "a", "1"))
+	c.add(record(opencdc.OperationUpdate, "b", "2"))
+	c.add(record(opencdc.OperationUpdate, "a", "3"))
+	c.add(record(opencdc.OperationUpdate, "b", "4"))
+	c.add(record(opencdc.OperationDelete, "c", "5"))
+	c.add(record(opencdc.OperationUpdate, "b", "6"))
+
+	// the records are ordered by their latest events, the created document stays created
+	want := []opencdc.Record{
+		record(opencdc.OperationCreate, "a", "3"),
+		record(opencdc.OperationDelete, "c", "5"),
+		record(opencdc.OperationUpdate, "b", "6"),
+	}
+
+	if got := c.flush(); !reflect.DeepEqual(got, want) {
+		t.Errorf("coalescer.flush() = %v, want %v", got, want)
+	}
+
+	if got := c.flush(); len(got) != 0 {
+		t.Errorf("coalescer.flush() after flush = %v, want empty", got)
+	}
+
+	if newCoalescer(0, 10) != nil {
+		t.Errorf("newCoalescer(0) is not nil")
+	}
+}
+
+func TestCoalescer_add_updateDescription(t *testing.T) {
+	t.Parallel()
+
+	record := func(id, position, description string) opencdc.Record {
+		return opencdc.Record{
+			Position:  opencdc.Position(position),
+			Operation: opencdc.OperationUpdate,
+			Metadata: opencdc.Metadata{
+				metadataFieldCollection:        "users",
+				metadataFieldUpdateDescription: description,
+			},
+			Key:     opencdc.StructuredData{idFieldName: id},
+			Payload: opencdc.Change{After: opencdc.StructuredData{"position": position}},
+		}
+	}
+
+	c := newCoalescer(time.Second, 10)
+	c.add(record("a", "1", `{"updatedFields":{"name":"x"},"removedFields":[],"truncatedArrays":[]}`))
+	c.add(record("b", "2", `{"updatedFields":{"age":1},"removedFields":[],"truncatedArrays":[]}`))
+	c.add(record("a", "3", `{"updatedFields":{"city":"y"},"removedFields":[],"truncatedArrays":[]}`))
+
+	// the coalesced record misses the update description, as it misses the earlier change of the name
+	coalesced := record("a", "3", "")
+	delete(coalesced.Metadata, metadataFieldUpdateDescription)
+
+	want := []opencdc.Record{
+		record("b", "2", `{"updatedFields":{"age":1},"removedFields":[],"truncatedArrays":[]}`),
+		coalesced,
+	}
+
+	if got := c.flush(); !reflect.DeepEqual(got, want) {
+		t.Errorf("coalescer.flush() = %v, want %v", got, want)
+	}
+}
+
+func TestCoalescer_add_controlRecords(t *testing.T) {
+	t.Parallel()
+
+	event := func(operationType, position string) opencdc.Record {
+		return opencdc.Record{
+			Position:  opencdc.Position(position),
+			Operation: opencdc.OperationUpdate,
+			Metadata: opencdc.Metadata{
+				metadataFieldCollection:       "users",
+				codec.MetadataFieldRecordType: codec.RecordTypeCollectionEvent,
+			},
+			Key:     opencdc.StructuredData{"collection": "users"},
+			Payload: opencdc.Change{After: opencdc.StructuredData{"operationType": operationType}},
+		}
+	}
+
+	c := newCoalescer(time.Second, 10)
+	c.add(event(operationTypeRename, "1"))
+	c.add(event(operationTypeDrop, "2"))
+
+	// the rename and the drop of the same collection are both returned in their order
+	want := []opencdc.Record{event(operationTypeRename, "1"), event(operationTypeDrop, "2")}
+
+	if got := c.flush(); !reflect.DeepEqual(got, want) {
+		t.Errorf("coalescer.flush() = %v, want %v", got, want)
+	}
+}
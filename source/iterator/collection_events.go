@@ -0,0 +1,109 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The list of Change Stream operation types of collection lifecycle events is listed below.
+const (
+	operationTypeDrop         = "drop"
+	operationTypeRename       = "rename"
+	operationTypeDropDatabase = "dropDatabase"
+)
+
+// collectionEventsMatchPipeline is a MongoDB Change Stream pipeline that
+// filters and returns insert, update and delete events, as well as collection lifecycle ones.
+var collectionEventsMatchPipeline = bson.D{
+	{
+		Key: "$match", Value: bson.M{
+			"operationType": bson.M{"$in": []string{
+				operationTypeInsert,
+				operationTypeUpdate,
+				operationTypeDelete,
+				operationTypeDrop,
+				operationTypeRename,
+				operationTypeDropDatabase,
+			}},
+		},
+	},
+}
+
+// isCollectionEvent checks whether the operation type is one of a collection lifecycle event.
+func isCollectionEvent(operationType string) bool {
+	return operationType == operationTypeDrop ||
+		operationType == operationTypeRename ||
+		operationType == operationTypeDropDatabase
+}
+
+// toCollectionEventRecord converts the collection lifecycle [changeStreamEvent] to an [opencdc.Record].
+// The record doesn't carry any document, so it's marked with the collection event record type,
+// and its payload describes the operation and the affected namespaces instead.
+// The buffers are used to serialize the record position.
+// The incremental is a progress of an incremental snapshot that is stored in the record position.
+func (e changeStreamEvent) toCollectionEventRecord(
+	buffers *codec.BufferPool, incremental *incrementalPosition,
+) (opencdc.Record, error) {
+	position := &position{
+		Mode:        modeCDC,
+		ResumeToken: e.ID,
+		Incremental: incremental,
+	}
+
+	sdkPosition, err := position.marshalSDKPosition(buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal position into opencdc.Position: %w", err)
+	}
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = e.Namespace.Collection
+	metadata[codec.MetadataFieldRecordType] = codec.RecordTypeCollectionEvent
+
+	createdAt := e.WallTime
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	metadata.SetCreatedAt(createdAt)
+	e.setTransactionMetadata(metadata)
+
+	payload := opencdc.StructuredData{
+		"operationType": e.OperationType,
+		"db":            e.Namespace.DB,
+		"collection":    e.Namespace.Collection,
+	}
+
+	if e.OperationType == operationTypeRename {
+		payload["to"] = map[string]any{
+			"db":         e.To.DB,
+			"collection": e.To.Collection,
+		}
+	}
+
+	return sdk.Util.Source.NewRecordUpdate(
+		sdkPosition,
+		metadata,
+		opencdc.StructuredData{"collection": e.Namespace.Collection},
+		nil,
+		payload,
+	), nil
+}
@@ -0,0 +1,92 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChangeStreamEvent_toCollectionEventRecord(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		operationType string
+		want          opencdc.StructuredData
+	}{
+		{
+			name:          "drop",
+			operationType: operationTypeDrop,
+			want: opencdc.StructuredData{
+				"operationType": operationTypeDrop,
+				"db":            "test",
+				"collection":    "users",
+			},
+		},
+		{
+			name:          "rename",
+			operationType: operationTypeRename,
+			want: opencdc.StructuredData{
+				"operationType": operationTypeRename,
+				"db":            "test",
+				"collection":    "users",
+				"to":            map[string]any{"db": "archive", "collection": "users_2026"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			event := changeStreamEvent{
+				OperationType: tt.operationType,
+				ClusterTime:   primitive.Timestamp{T: 1700000000, I: 3},
+			}
+			event.Namespace.DB = "test"
+			event.Namespace.Collection = "users"
+			event.To.DB = "archive"
+			event.To.Collection = "users_2026"
+
+			record, err := event.toCollectionEventRecord(nil, nil)
+			is.NoErr(err)
+
+			is.Equal(record.Operation, opencdc.OperationUpdate)
+			is.Equal(record.Metadata[codec.MetadataFieldRecordType], codec.RecordTypeCollectionEvent)
+			is.Equal(record.Metadata[codec.MetadataFieldClusterTime], "1700000000.3")
+			is.Equal(record.Key, opencdc.StructuredData{"collection": "users"})
+			is.Equal(record.Payload.After, tt.want)
+		})
+	}
+}
+
+func TestIsCollectionEvent(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.True(isCollectionEvent(operationTypeDrop))
+	is.True(isCollectionEvent(operationTypeRename))
+	is.True(isCollectionEvent(operationTypeDropDatabase))
+	is.True(!isCollectionEvent(operationTypeInsert))
+	is.True(!isCollectionEvent(operationTypeUpdate))
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// collectionMetadataRecord creates a record describing the structure of the provided collection:
+// its options, including a document validator, and its indexes.
+// The payload is a relaxed Extended JSON document, so the order of index keys is preserved.
+func collectionMetadataRecord(
+	ctx context.Context, collection *mongo.Collection, sdkPosition opencdc.Position,
+) (opencdc.Record, error) {
+	specifications, err := collection.Database().ListCollectionSpecifications(ctx, bson.D{
+		{Key: "name", Value: collection.Name()},
+	})
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("list collection specifications: %w", err)
+	}
+
+	var collectionOptions any = bson.D{}
+	if len(specifications) > 0 && specifications[0].Options != nil {
+		collectionOptions = specifications[0].Options
+	}
+
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("list indexes: %w", err)
+	}
+
+	indexes := make([]bson.Raw, 0)
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return opencdc.Record{}, fmt.Errorf("decode indexes: %w", err)
+	}
+
+	payload, err := bson.MarshalExtJSON(bson.D{
+		{Key: "db", Value: collection.Database().Name()},
+		{Key: "collection", Value: collection.Name()},
+		{Key: "options", Value: collectionOptions},
+		{Key: "indexes", Value: indexes},
+	}, false, false)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal collection metadata into extended JSON: %w", err)
+	}
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = collection.Name()
+	metadata[codec.MetadataFieldRecordType] = codec.RecordTypeCollectionMetadata
+	metadata.SetCreatedAt(time.Now())
+
+	return sdk.Util.Source.NewRecordSnapshot(
+		sdkPosition,
+		metadata,
+		opencdc.StructuredData{"collection": collection.Name()},
+		opencdc.RawData(payload),
+	), nil
+}
@@ -21,10 +21,16 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/time/rate"
 )
 
 // metadataFieldCollection is a name of a record metadata field that stores a MongoDB collection name.
@@ -35,75 +41,691 @@ const metadataFieldCollection = "mongo.collection"
 // A snapshot is captured only if the snapshot is set to true.
 type Combined struct {
 	snapshot *snapshot
+	// shardedSnapshot is used instead of the snapshot to read chunks of a sharded collection in parallel.
+	shardedSnapshot *shardedSnapshot
 	// pollingSnapshot is used when CDC is not available.
 	// It supports insert operations only.
 	pollingSnapshot *snapshot
 	cdc             *cdc
+	// oplog is used instead of the cdc if changes are captured by tailing the oplog.
+	oplog *oplog
+	// tailable is used instead of the cdc if inserts are captured by tailing a capped collection.
+	tailable *tailable
+	// incremental is used to capture a snapshot without pausing CDC.
+	incremental *incrementalSnapshot
+	// queue contains records of Change Stream events and incremental snapshot chunks
+	// that are ready to be returned.
+	queue []opencdc.Record
+	// incrementalParams are params incremental snapshots are created with.
+	incrementalParams snapshotParams
+	// snapshotTrigger is an identifier of an incremental snapshot stored in its progress.
+	snapshotTrigger string
+	// signals reads control documents from the signal collection if it's configured.
+	signals *signals
+	// paused defines if the iterator is paused by a signal.
+	paused bool
+	// limiter limits the rate of returned records if the rate limit is configured.
+	limiter *rate.Limiter
+	// coalescer coalesces records of the same documents read from the Change Stream within a short window.
+	// If it's nil, records are returned as they are read.
+	coalescer *coalescer
+	// prefetchSize is the number of Change Stream records read ahead in the background.
+	// Zero means records are read when they're requested.
+	prefetchSize int
+	// prefetcher reads Change Stream records ahead once CDC starts, if the prefetch size is set.
+	prefetcher *prefetcher
+	// causalSession is the causally consistent session spanning the creation of the Change Stream
+	// and the blocking snapshot reads, or nil if the snapshot doesn't need one.
+	causalSession mongo.Session
 }
 
 // CombinedParams is an incoming params for the [NewCombined] function.
 type CombinedParams struct {
-	Collection    *mongo.Collection
-	BatchSize     int
-	Snapshot      bool
-	OrderingField string
-	SDKPosition   opencdc.Position
+	Collection *mongo.Collection
+	BatchSize  int
+	Snapshot   bool
+	// OrderingFields is the list of fields forming a sort key of the snapshot.
+	OrderingFields []string
+	SDKPosition    opencdc.Position
+	PayloadFormat  PayloadFormat
+	// KeyFormat is the format of records' keys.
+	KeyFormat KeyFormat
+	// Converter converts native BSON values of documents and keys.
+	Converter codec.Converter
+	// Buffers is a pool of buffers records are serialized into. If it's nil, pooling is disabled.
+	Buffers *codec.BufferPool
+	// StartAtOperationTime is a cluster time the Change Stream starts from
+	// if the position has neither a resume token nor an operation time.
+	StartAtOperationTime *primitive.Timestamp
+	// ResumeToken is a Change Stream resume token supplied by an operator. The Change Stream is resumed after it
+	// and the snapshot is skipped, unless the position already captures changes past it.
+	ResumeToken bson.Raw
+	// StopAtOperationTime is a cluster time CDC stops at, so changes after it aren't returned,
+	// which bounds replays. If it's nil, CDC is never stopped.
+	StopAtOperationTime *primitive.Timestamp
+	// CollectionEvents determines whether the Change Stream returns records describing
+	// collection lifecycle events, e.g. drops and renames of the collection.
+	CollectionEvents bool
+	// ResnapshotOnStaleToken determines whether the iterator should discard the position,
+	// take a fresh snapshot and start CDC from the current time if the position's resume token
+	// is no longer present in the oplog.
+	ResnapshotOnStaleToken bool
+	// CollectionMetadata determines whether the snapshot should start with a record
+	// describing the collection structure, i.e. its options, validator and indexes.
+	CollectionMetadata bool
+	// VerifyResume determines whether the CDC iterator should make sure the Change Stream
+	// can be resumed right after it's created.
+	VerifyResume bool
+	// SchemaMode determines how the payload schema of the collection is generated.
+	SchemaMode SchemaMode
+	// SchemaSampleSize is the number of documents sampled to generate the payload schema.
+	SchemaSampleSize int
+	// SchemaDriftMode determines how documents drifting from the first-seen schema are reported.
+	SchemaDriftMode SchemaDriftMode
+	// SnapshotMode determines whether the snapshot blocks CDC or is captured incrementally along with it.
+	SnapshotMode SnapshotMode
+	// SnapshotTrigger is an identifier of an incremental snapshot.
+	// Changing it makes the iterator capture a new incremental snapshot.
+	SnapshotTrigger string
+	// ReadConcernLevel is the read concern level of snapshot queries and the Change Stream.
+	// If it's empty, the collection's one is used.
+	ReadConcernLevel ReadConcernLevel
+	// MaxBatchBytes is the max total size of documents in a snapshot batch. Zero means no limit.
+	MaxBatchBytes int
+	// CheckpointRecords is the number of snapshot records the position is checkpointed after,
+	// the records in between repeat the latest checkpoint. Zero means it's not limited by records.
+	CheckpointRecords int
+	// CheckpointInterval is the time the snapshot position is checkpointed after.
+	// Zero means it's not limited by time. If both are zero, every snapshot record gets its own position.
+	CheckpointInterval time.Duration
+	// SnapshotMaxDocsPerSecond is the max number of documents per second the blocking and incremental snapshots
+	// read, so they don't starve application traffic. Zero means no limit.
+	SnapshotMaxDocsPerSecond int
+	// AllowDiskUse determines whether snapshots may sort documents on disk
+	// once their sorts exceed the memory limit.
+	AllowDiskUse bool
+	// ResumeBoundary defines whether a resumed snapshot emits the last emitted document once again.
+	ResumeBoundary ResumeBoundary
+	// SignalCollection is a collection the iterator reads control documents from.
+	// If it's nil, signals are not supported.
+	SignalCollection *mongo.Collection
+	// ConnectorID is the ID of the connector. Signals addressed to other connectors are not processed.
+	ConnectorID string
+	// SnapshotSharded determines whether the blocking snapshot of a sharded collection reads its chunks
+	// in parallel instead of sorting all documents by the ordering fields.
+	SnapshotSharded bool
+	// SnapshotParallelism is the max number of chunks the sharded snapshot reads at the same time.
+	SnapshotParallelism int
+	// SnapshotConsistent determines whether the blocking snapshot reads all documents at the cluster time
+	// it starts at, and the Change Stream starts right after that time.
+	SnapshotConsistent bool
+	// SnapshotCollection is the collection snapshots read documents from, e.g. through a federated
+	// connection of an Atlas Online Archive, while CDC watches the Collection. If it's nil, the Collection is used.
+	SnapshotCollection *mongo.Collection
+	// MaxRetries is the max number of times the Change Stream is recreated after a failure
+	// before the error is returned. Zero means no retries.
+	MaxRetries int
+	// RateLimit is the max number of records per second the iterator returns,
+	// regardless of whether they come from a snapshot or the Change Stream. Zero means no limit.
+	RateLimit int
+	// HeartbeatInterval is how long the Change Stream has to stay quiet before the iterator returns
+	// a heartbeat record carrying its latest resume token. Zero means no heartbeats.
+	HeartbeatInterval time.Duration
+	// PrefetchSize is the number of Change Stream records read ahead in the background.
+	// Zero means records are read when they're requested.
+	PrefetchSize int
+	// MaxAwaitTime is how long the server waits for new events before responding to a request
+	// of the next batch of the Change Stream. Zero means the server default is used.
+	MaxAwaitTime time.Duration
+	// SuppressUnchanged determines whether update events whose full document is byte-identical
+	// to the previously emitted version of the document are skipped.
+	SuppressUnchanged bool
+	// SuppressCacheSize is the max number of documents whose hashes are kept to suppress unchanged updates.
+	SuppressCacheSize int
+	// CDCMode determines whether changes are captured by a Change Stream, by tailing the oplog,
+	// or by tailing the capped collection itself.
+	CDCMode CDCMode
+	// CoalesceWindow is how long records read from the Change Stream are buffered to coalesce
+	// records of the same documents. Zero means records are not coalesced.
+	CoalesceWindow time.Duration
+	// CoalesceMaxSize is the max number of distinct documents buffered within the coalesce window.
+	CoalesceMaxSize int
+	// PollingUpdatedAtField is the name of a field containing the time of a document's last update.
+	// If it's not empty, the polling snapshot also detects updated documents.
+	PollingUpdatedAtField string
+	// PollingDeleteStrategy determines how the polling snapshot detects deleted documents.
+	PollingDeleteStrategy DeleteStrategy
+	// PollingSoftDeleteField is the name of a field marking a document as deleted,
+	// used if the delete strategy is soft delete.
+	PollingSoftDeleteField string
+	// PollingDeleteCheckInterval is how often the polling snapshot compares the _ids of documents
+	// with the known ones, if the delete strategy is the _id set.
+	PollingDeleteCheckInterval time.Duration
+	// Compatibility determines the MongoDB-compatible database the Change Stream is adapted to.
+	Compatibility Compatibility
 }
 
 // NewCombined creates a new instance of the [Combined].
 func NewCombined(ctx context.Context, params CombinedParams) (*Combined, error) {
-	combined := &Combined{}
+	combined := &Combined{
+		snapshotTrigger: params.SnapshotTrigger,
+		coalescer:       newCoalescer(params.CoalesceWindow, params.CoalesceMaxSize),
+		prefetchSize:    params.PrefetchSize,
+	}
+
+	if params.SignalCollection != nil {
+		combined.signals = newSignals(params.SignalCollection, params.ConnectorID)
+	}
+
+	// the token bucket holds a second's worth of records,
+	// so short bursts don't slow down the iterator unnecessarily
+	if params.RateLimit > 0 {
+		combined.limiter = rate.NewLimiter(rate.Limit(params.RateLimit), params.RateLimit)
+	}
+
+	snapshotThrottle := newSnapshotThrottle(params.SnapshotMaxDocsPerSecond, params.BatchSize)
+
+	var resnapshot bool
+
+	// the Change Stream uses its own collection, as it doesn't support all read concern levels
+	cdcCollection := withReadConcern(params.Collection, changeStreamReadConcernLevel(params.ReadConcernLevel))
+	params.Collection = withReadConcern(params.Collection, params.ReadConcernLevel)
+	snapshotCollection := snapshotCollectionOf(params)
 
 	position, err := parsePosition(params.SDKPosition)
 	if err != nil && !errors.Is(err, errNilSDKPosition) {
 		return nil, fmt.Errorf("parse sdk position: %w", err)
 	}
 
+	position, applied := withResumeToken(position, params.ResumeToken)
+	if applied {
+		sdk.Logger(ctx).Info().Msg("resuming the change stream after the configured resume token")
+	}
+
+	collectionSchema, err := initPayloadSchema(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("init payload schema: %w", err)
+	}
+
+	schemaDrift := newSchemaDrift(params.SchemaDriftMode)
+
+	snapshotTime, err := initSnapshotTime(ctx, params, snapshotCollection, position)
+	if err != nil {
+		return nil, fmt.Errorf("init snapshot time: %w", err)
+	}
+
+	// the Change Stream of a consistent snapshot starts right after the cluster time the snapshot is read at
+	startAtOperationTime := params.StartAtOperationTime
+	if snapshotTime != nil {
+		startAtOperationTime = nextTimestamp(snapshotTime)
+	}
+
+	// the snapshot reads observe everything the Change Stream creation did, even on lagging secondaries,
+	// so no document inserted between the two is missed by both
+	cdcCtx, err := combined.initCausalSession(ctx, params, cdcCollection, snapshotTime, position)
+	if err != nil {
+		return nil, err
+	}
+
+	var suppressor *changeSuppressor
+	if params.SuppressUnchanged {
+		suppressor = newChangeSuppressor(params.SuppressCacheSize)
+	}
+
+	combined.incrementalParams = snapshotParams{
+		collection:     snapshotCollection,
+		orderingFields: params.OrderingFields,
+		batchSize:      params.BatchSize,
+		payloadFormat:  params.PayloadFormat,
+		keyFormat:      params.KeyFormat,
+		converter:      params.Converter,
+		buffers:        params.Buffers,
+		payloadSchema:  collectionSchema,
+		schemaDrift:    schemaDrift,
+		maxBatchBytes:  params.MaxBatchBytes,
+		allowDiskUse:   params.AllowDiskUse,
+		throttle:       snapshotThrottle,
+	}
+
+	oplogParams := oplogParams{
+		collection:    cdcCollection,
+		position:      position,
+		payloadFormat: params.PayloadFormat,
+		keyFormat:     params.KeyFormat,
+		converter:     params.Converter,
+		buffers:       params.Buffers,
+		payloadSchema: collectionSchema,
+		schemaDrift:   schemaDrift,
+		stopAt:        params.StopAtOperationTime,
+	}
+
+	pollingParams := snapshotParams{
+		collection:          params.Collection,
+		orderingFields:      params.OrderingFields,
+		batchSize:           params.BatchSize,
+		position:            position,
+		payloadFormat:       params.PayloadFormat,
+		keyFormat:           params.KeyFormat,
+		converter:           params.Converter,
+		buffers:             params.Buffers,
+		payloadSchema:       collectionSchema,
+		schemaDrift:         schemaDrift,
+		updatedAtField:      params.PollingUpdatedAtField,
+		maxBatchBytes:       params.MaxBatchBytes,
+		allowDiskUse:        params.AllowDiskUse,
+		deleteStrategy:      params.PollingDeleteStrategy,
+		softDeleteField:     params.PollingSoftDeleteField,
+		deleteCheckInterval: params.PollingDeleteCheckInterval,
+	}
+
+	var view bool
+	if params.Compatibility != CompatibilityFerretDB {
+		view, err = isView(ctx, cdcCollection)
+		if err != nil {
+			return nil, fmt.Errorf("check whether collection is a view: %w", err)
+		}
+
+		if view && params.CDCMode != CDCModeAuto {
+			return nil, fmt.Errorf("capture changes of %q: %w", cdcCollection.Name(), errViewChangeCapture)
+		}
+	}
+
 	// create the CDC iterator in any case in order to properly
 	// switch after the snapshot and start consuming events starting from the current time
-	combined.cdc, err = newCDC(ctx, params.Collection, position)
-	if err != nil {
-		if !strings.Contains(err.Error(), matchProjectStageErrMessage) {
-			return nil, fmt.Errorf("init cdc iterator: %w", err)
+	switch {
+	case params.Compatibility == CompatibilityFerretDB:
+		// FerretDB doesn't support Change Streams, so new documents are polled right away
+		combined.pollingSnapshot, err = newPollingSnapshot(ctx, pollingParams)
+		if err != nil {
+			return nil, fmt.Errorf("init polling snapshot: %w", err)
 		}
 
-		combined.pollingSnapshot, err = newPollingSnapshot(ctx, snapshotParams{
-			collection:    params.Collection,
-			orderingField: params.OrderingField,
-			batchSize:     params.BatchSize,
-			position:      position,
-		})
+	case view:
+		sdk.Logger(ctx).Info().Str("collection", cdcCollection.Name()).
+			Msg("the collection is a view, which doesn't support change streams, polling it for new documents instead")
+
+		combined.pollingSnapshot, err = newPollingSnapshot(ctx, pollingParams)
 		if err != nil {
 			return nil, fmt.Errorf("init polling snapshot: %w", err)
 		}
+
+	case params.CDCMode == CDCModeOplog:
+		combined.oplog, err = newOplog(cdcCtx, oplogParams)
+
+	case params.CDCMode == CDCModeTailable:
+		combined.tailable, err = newTailable(cdcCtx, tailableParams{
+			collection:    cdcCollection,
+			position:      position,
+			payloadFormat: params.PayloadFormat,
+			keyFormat:     params.KeyFormat,
+			converter:     params.Converter,
+			buffers:       params.Buffers,
+			payloadSchema: collectionSchema,
+			schemaDrift:   schemaDrift,
+		})
+
+	default:
+		combined.cdc, err = newCDC(cdcCtx, cdcParams{
+			collection:           cdcCollection,
+			position:             position,
+			payloadFormat:        params.PayloadFormat,
+			keyFormat:            params.KeyFormat,
+			converter:            params.Converter,
+			buffers:              params.Buffers,
+			payloadSchema:        collectionSchema,
+			schemaDrift:          schemaDrift,
+			startAtOperationTime: startAtOperationTime,
+			verifyResume:         params.VerifyResume,
+			maxRetries:           params.MaxRetries,
+			heartbeatInterval:    params.HeartbeatInterval,
+			maxAwaitTime:         params.MaxAwaitTime,
+			stopAtOperationTime:  params.StopAtOperationTime,
+			collectionEvents:     params.CollectionEvents,
+			suppressor:           suppressor,
+			compatibility:        params.Compatibility,
+		})
+	}
+	if err != nil {
+		switch {
+		case params.CDCMode == CDCModeOplog:
+			return nil, fmt.Errorf("init oplog iterator: %w", err)
+
+		case params.CDCMode == CDCModeTailable:
+			return nil, fmt.Errorf("init tailable iterator: %w", err)
+
+		case params.CDCMode != CDCModeChangeStream && isChangeStreamUnsupportedErr(err):
+			sdk.Logger(ctx).Warn().Err(err).Msg("change streams are not supported, tailing the oplog instead")
+
+			combined.oplog, err = newOplog(cdcCtx, oplogParams)
+			if err != nil {
+				return nil, fmt.Errorf("init oplog iterator: %w", err)
+			}
+
+		case params.ResnapshotOnStaleToken && isStaleResumeTokenErr(err):
+			sdk.Logger(ctx).Warn().Err(err).
+				Msg("the resume token is no longer present in the oplog, taking a fresh snapshot")
+
+			// forget the stale position, so both the CDC and the snapshot start from scratch,
+			// the fresh snapshot is taken even if the initial one is disabled
+			position = nil
+			resnapshot = true
+			params.Snapshot = true
+
+			// the snapshot time and the causal session were initialized for the discarded position,
+			// so they're initialized once again the same way as for the first start
+			snapshotTime, err = initSnapshotTime(ctx, params, snapshotCollection, position)
+			if err != nil {
+				return nil, fmt.Errorf("init snapshot time: %w", err)
+			}
+
+			startAtOperationTime = nil
+			if snapshotTime != nil {
+				startAtOperationTime = nextTimestamp(snapshotTime)
+			}
+
+			cdcCtx, err = combined.initCausalSession(ctx, params, cdcCollection, snapshotTime, position)
+			if err != nil {
+				return nil, err
+			}
+
+			combined.cdc, err = newCDC(cdcCtx, cdcParams{
+				collection:           cdcCollection,
+				position:             position,
+				payloadFormat:        params.PayloadFormat,
+				keyFormat:            params.KeyFormat,
+				converter:            params.Converter,
+				buffers:              params.Buffers,
+				payloadSchema:        collectionSchema,
+				schemaDrift:          schemaDrift,
+				startAtOperationTime: startAtOperationTime,
+				verifyResume:         params.VerifyResume,
+				maxRetries:           params.MaxRetries,
+				heartbeatInterval:    params.HeartbeatInterval,
+				maxAwaitTime:         params.MaxAwaitTime,
+				stopAtOperationTime:  params.StopAtOperationTime,
+				collectionEvents:     params.CollectionEvents,
+				suppressor:           suppressor,
+				compatibility:        params.Compatibility,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("init cdc iterator: %w", err)
+			}
+
+		case strings.Contains(err.Error(), matchProjectStageErrMessage):
+			combined.pollingSnapshot, err = newPollingSnapshot(ctx, pollingParams)
+			if err != nil {
+				return nil, fmt.Errorf("init polling snapshot: %w", err)
+			}
+
+		default:
+			return nil, fmt.Errorf("init cdc iterator: %w", err)
+		}
 	}
 
+	switch {
+	case canSnapshotIncrementally(params, combined.cdc, position):
+		err = combined.initIncrementalSnapshot(ctx, position, params.Snapshot || resnapshot)
+		if err != nil {
+			return nil, fmt.Errorf("init incremental snapshot: %w", err)
+		}
+
 	// initialize the object only if the user has determined that it is required
-	// and if there is no position or the position mode is a snapshot
-	if params.Snapshot && (position == nil || position.Mode == modeSnapshot) {
-		var resumeToken bson.Raw
-		if combined.cdc != nil {
+	// (or the stored resume token has gone stale) and if there is no position or the position mode is a snapshot
+	case (params.Snapshot || resnapshot) && (position == nil || position.Mode == modeSnapshot):
+		var (
+			resumeToken    bson.Raw
+			oplogTimestamp *primitive.Timestamp
+			tailID         any
+		)
+
+		// positions of a consistent snapshot store the operation time the Change Stream starts at instead
+		switch {
+		case combined.cdc != nil && snapshotTime == nil:
 			resumeToken = combined.cdc.changeStream.ResumeToken()
+
+		case combined.oplog != nil:
+			oplogTimestamp = combined.oplog.currentTimestamp()
+
+		case combined.tailable != nil:
+			tailID = combined.tailable.currentID()
 		}
 
-		combined.snapshot, err = newSnapshot(ctx, snapshotParams{
-			collection:    params.Collection,
-			orderingField: params.OrderingField,
-			batchSize:     params.BatchSize,
-			position:      position,
-			resumeToken:   resumeToken,
+		// changes are captured from the current time if the Change Stream isn't supported
+		if combined.cdc == nil && snapshotTime != nil {
+			sdk.Logger(ctx).Warn().Msg("change streams are not supported, the snapshot is not anchored to a cluster time")
+
+			snapshotTime = nil
+		}
+
+		err = combined.initSnapshot(ctx, params, snapshotParams{
+			collection:         snapshotCollection,
+			orderingFields:     params.OrderingFields,
+			batchSize:          params.BatchSize,
+			position:           position,
+			resumeToken:        resumeToken,
+			oplogTimestamp:     oplogTimestamp,
+			tailID:             tailID,
+			snapshotTime:       snapshotTime,
+			payloadFormat:      params.PayloadFormat,
+			keyFormat:          params.KeyFormat,
+			converter:          params.Converter,
+			buffers:            params.Buffers,
+			payloadSchema:      collectionSchema,
+			schemaDrift:        schemaDrift,
+			collectionMetadata: params.CollectionMetadata,
+			maxBatchBytes:      params.MaxBatchBytes,
+			allowDiskUse:       params.AllowDiskUse,
+			resumeBoundary:     params.ResumeBoundary,
+			causalSession:      combined.causalSession,
+			checkpointRecords:  params.CheckpointRecords,
+			checkpointInterval: params.CheckpointInterval,
+			throttle:           snapshotThrottle,
 		})
 		if err != nil {
-			return nil, fmt.Errorf("init snapshot iterator: %w", err)
+			return nil, err
 		}
 	}
 
 	return combined, nil
 }
 
+// newSnapshotThrottle returns a limiter of the documents snapshots read per second, or nil if there's no limit.
+// Snapshots wait for whole batches, so the burst fits at least one of them.
+func newSnapshotThrottle(maxDocsPerSecond, batchSize int) *rate.Limiter {
+	if maxDocsPerSecond == 0 {
+		return nil
+	}
+
+	return rate.NewLimiter(rate.Limit(maxDocsPerSecond), max(maxDocsPerSecond, batchSize))
+}
+
+// initSnapshot creates the blocking snapshot. If it's enabled, a sharded collection is snapshotted
+// by its chunks, unless a snapshot ordered by the ordering fields is being resumed.
+func (c *Combined) initSnapshot(ctx context.Context, params CombinedParams, snapshotParams snapshotParams) error {
+	if params.SnapshotSharded && (snapshotParams.position == nil || snapshotParams.position.Sharded) {
+		sharded, err := newShardedSnapshot(ctx, snapshotParams, params.SnapshotParallelism)
+		if err == nil {
+			c.shardedSnapshot = sharded
+
+			return nil
+		}
+
+		if !errors.Is(err, errNotShardedCollection) {
+			return fmt.Errorf("init sharded snapshot iterator: %w", err)
+		}
+
+		sdk.Logger(ctx).Warn().Err(err).Msg("taking a snapshot ordered by the ordering fields instead")
+	}
+
+	snapshot, err := newSnapshot(ctx, snapshotParams)
+	if err != nil {
+		return fmt.Errorf("init snapshot iterator: %w", err)
+	}
+
+	c.snapshot = snapshot
+
+	return nil
+}
+
+// initSnapshotTime returns the cluster time the blocking snapshot reads all documents at, or nil if the snapshot
+// isn't consistent. A snapshot that's in progress keeps the time of its position, while a new one captures it.
+func initSnapshotTime(
+	ctx context.Context, params CombinedParams, collection *mongo.Collection, position *position,
+) (*primitive.Timestamp, error) {
+	if !params.SnapshotConsistent || !params.Snapshot || params.Compatibility == CompatibilityFerretDB {
+		return nil, nil //nolint:nilnil // a nil time means the snapshot isn't anchored
+	}
+
+	switch {
+	case position == nil:
+		snapshotTime, err := captureSnapshotTime(ctx, collection)
+		if err != nil {
+			return nil, fmt.Errorf("capture snapshot time: %w", err)
+		}
+
+		sdk.Logger(ctx).Info().Uint32("t", snapshotTime.T).Uint32("i", snapshotTime.I).
+			Msg("the snapshot is read at the cluster time, and changes are captured right after it")
+
+		return snapshotTime, nil
+
+	case position.Mode == modeSnapshot:
+		return position.SnapshotTime, nil
+
+	default:
+		return nil, nil //nolint:nilnil // the snapshot is completed, so there's nothing to anchor
+	}
+}
+
+// needsCausalSession checks whether the Change Stream is created in a causally consistent session the blocking
+// snapshot reads documents in afterwards. Consistent snapshots are anchored to a cluster time already,
+// and a session can't be shared with the client of another connection string the snapshot reads through.
+func needsCausalSession(params CombinedParams, snapshotTime *primitive.Timestamp, position *position) bool {
+	return params.Snapshot && params.SnapshotMode != SnapshotModeIncremental && snapshotTime == nil &&
+		params.SnapshotCollection == nil && params.Compatibility == CompatibilityNone &&
+		(position == nil || position.Mode == modeSnapshot)
+}
+
+// initCausalSession starts the causally consistent session the Change Stream is created in, if it's needed,
+// and returns the context of the session. A session started before is ended first,
+// so the session can be initialized once again for another position.
+func (c *Combined) initCausalSession(
+	ctx context.Context, params CombinedParams, collection *mongo.Collection,
+	snapshotTime *primitive.Timestamp, position *position,
+) (context.Context, error) {
+	if c.causalSession != nil {
+		c.causalSession.EndSession(ctx)
+		c.causalSession = nil
+	}
+
+	if !needsCausalSession(params, snapshotTime, position) {
+		return ctx, nil
+	}
+
+	session, err := collection.Database().Client().StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return nil, fmt.Errorf("start causally consistent session: %w", err)
+	}
+
+	c.causalSession = session
+
+	return mongo.NewSessionContext(ctx, session), nil
+}
+
+// snapshotCollectionOf returns the collection snapshots read documents from,
+// with the same read concern level as the collection CDC watches.
+func snapshotCollectionOf(params CombinedParams) *mongo.Collection {
+	if params.SnapshotCollection == nil {
+		return params.Collection
+	}
+
+	return withReadConcern(params.SnapshotCollection, params.ReadConcernLevel)
+}
+
+// canSnapshotIncrementally checks whether the snapshot can be captured incrementally.
+// An incremental snapshot requires CDC and doesn't take over a blocking snapshot that's in progress.
+func canSnapshotIncrementally(params CombinedParams, cdc *cdc, position *position) bool {
+	return params.SnapshotMode == SnapshotModeIncremental && cdc != nil &&
+		(position == nil || position.Mode == modeCDC)
+}
+
+// initIncrementalSnapshot creates the incremental snapshot if it has to be started or resumed.
+// The initial defines if the snapshot is required when there's no position.
+func (c *Combined) initIncrementalSnapshot(ctx context.Context, position *position, initial bool) error {
+	progress, required := resolveIncrementalPosition(position, c.snapshotTrigger, initial)
+	if !required {
+		// keep the progress of the completed snapshot, so it's not taken again
+		c.cdc.incremental = progress
+
+		return nil
+	}
+
+	return c.startIncrementalSnapshot(ctx, progress)
+}
+
+// startIncrementalSnapshot creates the incremental snapshot that starts from the provided progress.
+// Chunks of the snapshot are deduplicated against Change Stream events read directly,
+// so the prefetcher is stopped and the records it has read are queued first.
+func (c *Combined) startIncrementalSnapshot(ctx context.Context, progress *incrementalPosition) error {
+	if c.prefetcher != nil {
+		c.queue = append(c.queue, c.prefetcher.stop()...)
+		c.prefetcher = nil
+
+		if err := c.cdc.recreate(ctx); err != nil {
+			return fmt.Errorf("recreate change stream: %w", err)
+		}
+	}
+
+	incremental, err := newIncrementalSnapshot(ctx, c.incrementalParams, progress)
+	if err != nil {
+		return err
+	}
+
+	c.incremental = incremental
+	c.cdc.incremental = incremental.progress()
+
+	return nil
+}
+
+// initPayloadSchema generates the payload schema of the collection if the schema mode requires it.
+// It returns nil if there's nothing to generate the schema from, so records are sent without it.
+func initPayloadSchema(ctx context.Context, params CombinedParams) (*payloadSchema, error) {
+	if params.SchemaMode == "" || params.SchemaMode == SchemaModeNone {
+		return nil, nil //nolint:nilnil // no schema is a valid result here
+	}
+
+	collectionSchema, err := newPayloadSchema(ctx, payloadSchemaParams{
+		collection: params.Collection,
+		mode:       params.SchemaMode,
+		sampleSize: params.SchemaSampleSize,
+		converter:  params.Converter,
+	})
+	if err != nil {
+		if errors.Is(err, errNoDocuments) || errors.Is(err, errNoJSONSchema) {
+			sdk.Logger(ctx).Warn().Err(err).Msg("cannot generate the payload schema, records are sent without it")
+
+			return nil, nil //nolint:nilnil // no schema is a valid result here
+		}
+
+		return nil, fmt.Errorf("create payload schema: %w", err)
+	}
+
+	return collectionSchema, nil
+}
+
 // HasNext returns a bool indicating whether the iterator has the next record to return or not.
 // If the underlying snapshot iterator returns false, the combined iterator will try to switch to the cdc iterator.
+// It always returns false while the iterator is paused by a signal.
 func (c *Combined) HasNext(ctx context.Context) (bool, error) {
+	if c.signals != nil {
+		if err := c.processSignals(ctx); err != nil {
+			return false, fmt.Errorf("process signals: %w", err)
+		}
+	}
+
+	if c.paused {
+		return false, nil
+	}
+
 	switch {
 	case c.snapshot != nil:
 		hasNext, err := c.snapshot.hasNext(ctx)
@@ -117,11 +739,24 @@ func (c *Combined) HasNext(ctx context.Context) (bool, error) {
 			}
 			c.snapshot = nil
 
-			if c.pollingSnapshot != nil {
-				return c.pollingSnapshot.hasNext(ctx)
+			return c.hasNextAfterSnapshot(ctx)
+		}
+
+		return true, nil
+
+	case c.shardedSnapshot != nil:
+		hasNext, err := c.shardedSnapshot.hasNext(ctx)
+		if err != nil {
+			return false, fmt.Errorf("sharded snapshot has next: %w", err)
+		}
+
+		if !hasNext {
+			if err := c.shardedSnapshot.stop(ctx); err != nil {
+				return false, fmt.Errorf("stop sharded snapshot iterator: %w", err)
 			}
+			c.shardedSnapshot = nil
 
-			return c.cdc.hasNext(ctx)
+			return c.hasNextAfterSnapshot(ctx)
 		}
 
 		return true, nil
@@ -129,8 +764,28 @@ func (c *Combined) HasNext(ctx context.Context) (bool, error) {
 	case c.pollingSnapshot != nil:
 		return c.pollingSnapshot.hasNext(ctx)
 
+	case c.oplog != nil:
+		return c.oplog.hasNext(ctx)
+
+	case c.tailable != nil:
+		return c.tailable.hasNext(ctx)
+
+	case c.incremental != nil || len(c.queue) > 0:
+		for len(c.queue) == 0 && c.incremental != nil {
+			if err := c.loadIncrementalChunk(ctx); err != nil {
+				return false, fmt.Errorf("load incremental snapshot chunk: %w", err)
+			}
+		}
+
+		if len(c.queue) > 0 {
+			return true, nil
+		}
+
+		// the incremental snapshot is completed, so changes are read the same way as after any other snapshot
+		return c.hasNextCDC(ctx)
+
 	case c.cdc != nil:
-		return c.cdc.hasNext(ctx)
+		return c.hasNextCDC(ctx)
 
 	default:
 		// this shouldn't happen
@@ -138,21 +793,145 @@ func (c *Combined) HasNext(ctx context.Context) (bool, error) {
 	}
 }
 
+// hasNextAfterSnapshot checks whether the iterator capturing changes after the completed snapshot
+// has records to return or not.
+func (c *Combined) hasNextAfterSnapshot(ctx context.Context) (bool, error) {
+	if c.pollingSnapshot != nil {
+		return c.pollingSnapshot.hasNext(ctx)
+	}
+
+	if c.oplog != nil {
+		return c.oplog.hasNext(ctx)
+	}
+
+	if c.tailable != nil {
+		return c.tailable.hasNext(ctx)
+	}
+
+	return c.hasNextCDC(ctx)
+}
+
+// hasNextCDC checks whether the CDC iterator has records to return or not.
+// If records are coalesced, they're buffered into the queue first.
+// Heartbeats are returned only if the Change Stream is quiet. If records are prefetched,
+// the prefetcher is started on the first call, and it returns heartbeats along with other records.
+func (c *Combined) hasNextCDC(ctx context.Context) (bool, error) {
+	var changes changeReader = c.cdc
+	if c.prefetchSize > 0 {
+		if c.prefetcher == nil {
+			c.prefetcher = startPrefetcher(ctx, c.cdc, c.prefetchSize)
+		}
+
+		changes = c.prefetcher
+	}
+
+	if c.coalescer == nil {
+		hasNext, err := changes.hasNext(ctx)
+		if err != nil || hasNext || c.prefetcher != nil {
+			return hasNext, err
+		}
+
+		return c.cdc.hasHeartbeat(), nil
+	}
+
+	records, err := c.coalescer.fill(ctx, changes)
+	if err != nil {
+		return false, fmt.Errorf("coalesce cdc records: %w", err)
+	}
+
+	if len(records) == 0 {
+		return c.prefetcher == nil && c.cdc.hasHeartbeat(), nil
+	}
+
+	c.queue = append(c.queue, records...)
+
+	return true, nil
+}
+
 // Next returns the next record.
 func (c *Combined) Next(ctx context.Context) (opencdc.Record, error) {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return opencdc.Record{}, fmt.Errorf("wait for rate limiter: %w", err)
+		}
+	}
+
+	record, captureMode, err := c.next(ctx)
+	if err != nil {
+		return opencdc.Record{}, err
+	}
+
+	if record.Metadata == nil {
+		record.Metadata = make(opencdc.Metadata)
+	}
+
+	record.Metadata[codec.MetadataFieldCaptureMode] = captureMode
+
+	return record, nil
+}
+
+// next returns the next record from the active iterator along with the mode it was captured in.
+func (c *Combined) next(ctx context.Context) (opencdc.Record, string, error) {
+	var (
+		record opencdc.Record
+		err    error
+	)
+
 	switch {
 	case c.snapshot != nil:
-		return c.snapshot.next(ctx)
+		record, err = c.snapshot.next(ctx)
+
+		return record, codec.CaptureModeSnapshot, err
+
+	case c.shardedSnapshot != nil:
+		record, err = c.shardedSnapshot.next(ctx)
+
+		return record, codec.CaptureModeSnapshot, err
 
 	case c.pollingSnapshot != nil:
-		return c.pollingSnapshot.next(ctx)
+		record, err = c.pollingSnapshot.next(ctx)
+
+		return record, codec.CaptureModePolling, err
+
+	case c.oplog != nil:
+		record, err = c.oplog.next(ctx)
+
+		return record, codec.CaptureModeCDC, err
+
+	case c.tailable != nil:
+		record, err = c.tailable.next(ctx)
+
+		return record, codec.CaptureModeCDC, err
+
+	case len(c.queue) > 0:
+		record = c.queue[0]
+		c.queue = c.queue[1:]
+
+		// the queue mixes documents of incremental snapshot chunks with change events
+		if record.Operation == opencdc.OperationSnapshot {
+			return record, codec.CaptureModeSnapshot, nil
+		}
+
+		return record, codec.CaptureModeCDC, nil
+
+	case c.prefetcher != nil:
+		record, err = c.prefetcher.next(ctx)
+
+		return record, codec.CaptureModeCDC, err
+
+	case c.cdc != nil && c.cdc.heartbeatPending:
+		record, err = c.cdc.nextHeartbeat()
+
+		return record, codec.CaptureModeCDC, err
 
 	case c.cdc != nil:
-		return c.cdc.next(ctx)
+		record, err = c.cdc.next(ctx)
+
+		return record, codec.CaptureModeCDC, err
 
 	default:
 		// this shouldn't happen
-		return opencdc.Record{}, ErrNoIterator
+		return opencdc.Record{}, "", ErrNoIterator
 	}
 }
 
@@ -164,17 +943,133 @@ func (c *Combined) Stop(ctx context.Context) error {
 		}
 	}
 
+	if c.shardedSnapshot != nil {
+		if err := c.shardedSnapshot.stop(ctx); err != nil {
+			return fmt.Errorf("stop sharded snapshot: %w", err)
+		}
+	}
+
 	if c.pollingSnapshot != nil {
 		if err := c.pollingSnapshot.stop(ctx); err != nil {
 			return fmt.Errorf("stop polling snapshot: %w", err)
 		}
 	}
 
+	if c.incremental != nil {
+		if err := c.incremental.stop(ctx); err != nil {
+			return fmt.Errorf("stop incremental snapshot: %w", err)
+		}
+	}
+
+	// the prefetcher reads the Change Stream, so it's stopped before the stream is closed
+	if c.prefetcher != nil {
+		c.prefetcher.stop()
+		c.prefetcher = nil
+	}
+
 	if c.cdc != nil {
 		if err := c.cdc.stop(ctx); err != nil {
 			return fmt.Errorf("stop cdc: %w", err)
 		}
 	}
 
+	if c.oplog != nil {
+		if err := c.oplog.stop(ctx); err != nil {
+			return fmt.Errorf("stop oplog: %w", err)
+		}
+	}
+
+	if c.tailable != nil {
+		if err := c.tailable.stop(ctx); err != nil {
+			return fmt.Errorf("stop tailable: %w", err)
+		}
+	}
+
+	// the session is ended once the Change Stream created in it is closed
+	if c.causalSession != nil {
+		c.causalSession.EndSession(ctx)
+		c.causalSession = nil
+	}
+
 	return nil
 }
+
+// loadIncrementalChunk reads the next chunk of the incremental snapshot and Change Stream events
+// occurred up to the end of the read, and puts them into the queue, events first.
+// The chunk's documents changed by these events are discarded, as the events carry their newer states.
+func (c *Combined) loadIncrementalChunk(ctx context.Context) error {
+	progress := c.incremental.progress()
+
+	chunk, highWatermark, err := c.incremental.loadChunk(ctx)
+	if err != nil {
+		return fmt.Errorf("load chunk: %w", err)
+	}
+
+	if len(chunk) == 0 {
+		if err := c.incremental.stop(ctx); err != nil {
+			return fmt.Errorf("stop incremental snapshot: %w", err)
+		}
+
+		c.cdc.incremental = completedIncrementalPosition(c.incremental.trigger)
+		c.incremental = nil
+
+		return nil
+	}
+
+	// the events are read before the chunk is sent, so the snapshot must
+	// be resumed from the chunk's start if the connector stops after one of them
+	c.cdc.incremental = progress
+
+	var events []opencdc.Record
+	for {
+		hasNext, err := c.cdc.hasNext(ctx)
+		if err != nil {
+			return fmt.Errorf("cdc has next: %w", err)
+		}
+
+		if !hasNext {
+			break
+		}
+
+		record, err := c.cdc.next(ctx)
+		if err != nil {
+			return fmt.Errorf("cdc next: %w", err)
+		}
+
+		chunk = discardChunkDocument(chunk, c.cdc.currentDocumentKey())
+		events = append(events, record)
+
+		// the rest of events occurred after the chunk is read, so they can't be older than its documents
+		if highWatermark != nil && c.cdc.currentClusterTime().After(*highWatermark) {
+			break
+		}
+	}
+
+	records, err := c.incremental.chunkRecords(chunk, c.cdc.changeStream.ResumeToken())
+	if err != nil {
+		return fmt.Errorf("create chunk records: %w", err)
+	}
+
+	c.queueEvents(events)
+	c.queue = append(c.queue, records...)
+	c.cdc.incremental = c.incremental.progress()
+
+	return nil
+}
+
+// queueEvents puts the records of change events read along with an incremental snapshot chunk into the queue.
+// If records are coalesced, the events are coalesced too, so they're returned the same way as the ones
+// read outside of incremental snapshots. They're still returned before the chunk, so positions keep increasing.
+func (c *Combined) queueEvents(events []opencdc.Record) {
+	if c.coalescer == nil {
+		c.queue = append(c.queue, events...)
+
+		return
+	}
+
+	for _, event := range events {
+		c.coalescer.add(event)
+	}
+
+	c.queue = append(c.queue, c.coalescer.flush()...)
+}
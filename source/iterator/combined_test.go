@@ -0,0 +1,156 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/time/rate"
+)
+
+func TestCombined_Next_captureMode(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	combined := &Combined{
+		queue: []opencdc.Record{
+			{Operation: opencdc.OperationSnapshot},
+			{Operation: opencdc.OperationUpdate, Metadata: opencdc.Metadata{metadataFieldCollection: "users"}},
+		},
+	}
+
+	record, err := combined.Next(context.Background())
+	is.NoErr(err)
+	is.Equal(record.Metadata, opencdc.Metadata{codec.MetadataFieldCaptureMode: codec.CaptureModeSnapshot})
+
+	record, err = combined.Next(context.Background())
+	is.NoErr(err)
+	is.Equal(record.Metadata, opencdc.Metadata{
+		metadataFieldCollection:        "users",
+		codec.MetadataFieldCaptureMode: codec.CaptureModeCDC,
+	})
+}
+
+func TestCombined_queueEvents(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	event := func(id, position string) opencdc.Record {
+		return opencdc.Record{
+			Position:  opencdc.Position(position),
+			Operation: opencdc.OperationUpdate,
+			Metadata:  opencdc.Metadata{metadataFieldCollection: "users"},
+			Key:       opencdc.StructuredData{idFieldName: id},
+		}
+	}
+
+	chunk := opencdc.Record{Operation: opencdc.OperationSnapshot}
+
+	// events read along with incremental snapshot chunks are coalesced the same way as other ones
+	combined := &Combined{coalescer: newCoalescer(time.Second, 10), queue: []opencdc.Record{chunk}}
+	combined.queueEvents([]opencdc.Record{event("a", "1"), event("b", "2"), event("a", "3")})
+	is.Equal(combined.queue, []opencdc.Record{chunk, event("b", "2"), event("a", "3")})
+
+	combined = &Combined{}
+	combined.queueEvents([]opencdc.Record{event("a", "1"), event("a", "2")})
+	is.Equal(combined.queue, []opencdc.Record{event("a", "1"), event("a", "2")})
+}
+
+func TestNeedsCausalSession(t *testing.T) {
+	t.Parallel()
+
+	blocking := CombinedParams{Snapshot: true, SnapshotMode: SnapshotModeBlocking, Compatibility: CompatibilityNone}
+
+	tests := []struct {
+		name         string
+		params       CombinedParams
+		snapshotTime *primitive.Timestamp
+		position     *position
+		want         bool
+	}{
+		{
+			name:   "new_snapshot",
+			params: blocking,
+			want:   true,
+		},
+		{
+			name:     "resumed_snapshot",
+			params:   blocking,
+			position: &position{Mode: modeSnapshot},
+			want:     true,
+		},
+		{
+			name:     "completed_snapshot",
+			params:   blocking,
+			position: &position{Mode: modeCDC},
+		},
+		{
+			name:         "consistent_snapshot",
+			params:       blocking,
+			snapshotTime: &primitive.Timestamp{T: 10},
+		},
+		{
+			name:   "no_snapshot",
+			params: CombinedParams{SnapshotMode: SnapshotModeBlocking, Compatibility: CompatibilityNone},
+		},
+		{
+			name: "incremental_snapshot",
+			params: CombinedParams{
+				Snapshot: true, SnapshotMode: SnapshotModeIncremental, Compatibility: CompatibilityNone,
+			},
+		},
+		{
+			name: "cosmosdb",
+			params: CombinedParams{
+				Snapshot: true, SnapshotMode: SnapshotModeBlocking, Compatibility: CompatibilityCosmosDB,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			is.Equal(needsCausalSession(tt.params, tt.snapshotTime, tt.position), tt.want)
+		})
+	}
+}
+
+func TestNewSnapshotThrottle(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.True(newSnapshotThrottle(0, 1000) == nil)
+
+	// the burst fits a whole batch, so waiting for it never fails
+	throttle := newSnapshotThrottle(100, 1000)
+	is.Equal(throttle.Limit(), rate.Limit(100))
+	is.Equal(throttle.Burst(), 1000)
+
+	throttle = newSnapshotThrottle(5000, 1000)
+	is.Equal(throttle.Limit(), rate.Limit(5000))
+	is.Equal(throttle.Burst(), 5000)
+}
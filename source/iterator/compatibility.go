@@ -0,0 +1,102 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Compatibility defines a MongoDB-compatible database the iterators adapt the Change Stream to.
+type Compatibility string
+
+// The list of available compatibility modes is listed below.
+const (
+	// CompatibilityNone makes the iterators expect a MongoDB deployment.
+	CompatibilityNone Compatibility = "none"
+	// CompatibilityCosmosDB makes the iterators adapt the Change Stream to Azure CosmosDB for MongoDB.
+	CompatibilityCosmosDB Compatibility = "cosmosdb"
+	// CompatibilityFerretDB makes the iterators poll for new documents, as FerretDB doesn't support Change Streams.
+	CompatibilityFerretDB Compatibility = "ferretdb"
+)
+
+// operationTypeReplace is an operation type of Change Stream events of replaced documents.
+const operationTypeReplace = "replace"
+
+// cosmosDBChangeStreamPipeline is a Change Stream pipeline of the shape Azure CosmosDB for MongoDB requires,
+// a $match stage followed by a $project one. CosmosDB doesn't report deletes, and its events
+// don't carry operation types, so they're projected out.
+var cosmosDBChangeStreamPipeline = mongo.Pipeline{
+	{{Key: "$match", Value: bson.M{
+		"operationType": bson.M{"$in": []string{
+			operationTypeInsert,
+			operationTypeUpdate,
+			operationTypeReplace,
+		}},
+	}}},
+	{{Key: "$project", Value: bson.M{
+		idFieldName:    1,
+		"fullDocument": 1,
+		"ns":           1,
+		"documentKey":  1,
+	}}},
+}
+
+// changeStreamPipeline returns the Change Stream pipeline matching the compatibility mode.
+// Collection lifecycle events are matched too if they're requested, CosmosDB doesn't report them.
+func changeStreamPipeline(compatibility Compatibility, collectionEvents bool) mongo.Pipeline {
+	if compatibility == CompatibilityCosmosDB {
+		return cosmosDBChangeStreamPipeline
+	}
+
+	if collectionEvents {
+		return mongo.Pipeline{collectionEventsMatchPipeline}
+	}
+
+	return mongo.Pipeline{changeStreamMatchPipeline}
+}
+
+// adaptCosmosDBEvent fills in the fields of a CosmosDB Change Stream event that CosmosDB doesn't return.
+// Events without operation types are reported as updates, destinations upsert them anyway.
+// Full documents are looked up if they're missing, and events of documents that don't exist
+// anymore are reported as deletes.
+func adaptCosmosDBEvent(ctx context.Context, collection *mongo.Collection, event *changeStreamEvent) error {
+	if event.OperationType == "" || event.OperationType == operationTypeReplace {
+		event.OperationType = operationTypeUpdate
+	}
+
+	if event.FullDocument != nil {
+		return nil
+	}
+
+	fullDocument, err := collection.FindOne(ctx, oplogDocumentKey(event.DocumentKey)).Raw()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			event.OperationType = operationTypeDelete
+
+			return nil
+		}
+
+		return fmt.Errorf("find full document: %w", err)
+	}
+
+	event.FullDocument = fullDocument
+
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestChangeStreamPipeline(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(changeStreamPipeline(CompatibilityNone, false), mongo.Pipeline{changeStreamMatchPipeline})
+	is.Equal(changeStreamPipeline(CompatibilityCosmosDB, false), cosmosDBChangeStreamPipeline)
+	is.Equal(changeStreamPipeline(CompatibilityNone, true), mongo.Pipeline{collectionEventsMatchPipeline})
+
+	// CosmosDB requires a $match stage followed by a $project one
+	is.Equal(len(cosmosDBChangeStreamPipeline), 2)
+	is.Equal(cosmosDBChangeStreamPipeline[0][0].Key, "$match")
+	is.Equal(cosmosDBChangeStreamPipeline[1][0].Key, "$project")
+}
+
+func TestAdaptCosmosDBEvent(t *testing.T) {
+	t.Parallel()
+
+	fullDocument, err := bson.Marshal(bson.M{idFieldName: 1, "name": "bob"})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		operationType string
+	}{
+		{name: "no_operation_type", operationType: ""},
+		{name: "replace", operationType: operationTypeReplace},
+		{name: "update", operationType: operationTypeUpdate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			// the full document is present, so it's not looked up in the collection
+			event := changeStreamEvent{OperationType: tt.operationType, FullDocument: fullDocument}
+			is.NoErr(adaptCosmosDBEvent(context.Background(), nil, &event))
+			is.Equal(event.OperationType, operationTypeUpdate)
+			is.Equal(event.FullDocument, bson.Raw(fullDocument))
+		})
+	}
+}
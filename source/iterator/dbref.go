@@ -0,0 +1,227 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DBRefMode defines how the connector treats DBRefs, i.e. embedded documents referring to other documents
+// with their $ref, $id and optional $db fields.
+type DBRefMode string
+
+// The list of available DBRef modes is listed below.
+const (
+	// DBRefModeNone keeps DBRefs as they are.
+	DBRefModeNone DBRefMode = "none"
+	// DBRefModeNormalize replaces DBRefs with documents with the collection, id and db fields.
+	DBRefModeNormalize DBRefMode = "normalize"
+	// DBRefModeResolve replaces DBRefs with the documents they refer to.
+	DBRefModeResolve DBRefMode = "resolve"
+)
+
+// The names of DBRef fields and of the fields of normalized DBRefs are listed below.
+const (
+	dbRefCollectionFieldName = "$ref"
+	dbRefIDFieldName         = "$id"
+	dbRefDBFieldName         = "$db"
+
+	normalizedCollectionFieldName = "collection"
+	normalizedIDFieldName         = "id"
+	normalizedDBFieldName         = "db"
+)
+
+// dbRef is a reference to a document.
+type dbRef struct {
+	collection string
+	id         any
+	// db is the database of the collection, it's empty if the reference is within the same database.
+	db string
+}
+
+// DBRefResolver normalizes or resolves DBRefs of records' documents, for consumers that can't chase references.
+type DBRefResolver struct {
+	mode     DBRefMode
+	maxDepth int
+	// db is the database of references without the $db field.
+	db        string
+	converter codec.Converter
+	// find returns the raw document of the collection with the _id, or nil if there's no such document.
+	find func(ctx context.Context, db, collection string, id any) (bson.Raw, error)
+}
+
+// NewDBRefResolver creates a new instance of the [DBRefResolver] of references of the database's documents.
+// The referenced documents are converted with the converter and their own references are resolved
+// until the max depth is reached, deeper references are normalized. It returns nil if the mode is none.
+func NewDBRefResolver(
+	database *mongo.Database, converter codec.Converter, mode DBRefMode, maxDepth int,
+) *DBRefResolver {
+	if mode == DBRefModeNone || mode == "" {
+		return nil
+	}
+
+	client := database.Client()
+
+	return &DBRefResolver{
+		mode:      mode,
+		maxDepth:  maxDepth,
+		db:        database.Name(),
+		converter: converter,
+		find: func(ctx context.Context, db, collection string, id any) (bson.Raw, error) {
+			document, err := client.Database(db).Collection(collection).FindOne(ctx, dbRefFilter(id)).Raw()
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return nil, nil
+			}
+
+			return document, err //nolint:wrapcheck // the error is wrapped by the caller
+		},
+	}
+}
+
+// Apply replaces DBRefs of the record's structured payloads according to the resolver's mode.
+// References to missing documents are normalized. Other payloads and control records,
+// e.g. heartbeats or collection events, are returned as they are.
+func (r *DBRefResolver) Apply(ctx context.Context, record opencdc.Record) (opencdc.Record, error) {
+	if r == nil || record.Metadata[codec.MetadataFieldRecordType] != "" {
+		return record, nil
+	}
+
+	for _, data := range []*opencdc.Data{&record.Payload.Before, &record.Payload.After} {
+		document, ok := (*data).(opencdc.StructuredData)
+		if !ok {
+			continue
+		}
+
+		if _, err := r.resolve(ctx, map[string]any(document), 0); err != nil {
+			return opencdc.Record{}, err
+		}
+	}
+
+	return record, nil
+}
+
+// resolve replaces DBRefs of the value referenced from the provided depth.
+// Documents and arrays are changed in place.
+func (r *DBRefResolver) resolve(ctx context.Context, value any, depth int) (any, error) {
+	var err error
+
+	switch value := value.(type) {
+	case map[string]any:
+		if ref, ok := parseDBRef(value); ok {
+			return r.resolveRef(ctx, ref, depth)
+		}
+
+		for key, field := range value {
+			if value[key], err = r.resolve(ctx, field, depth); err != nil {
+				return nil, err
+			}
+		}
+
+		return value, nil
+
+	case []any:
+		for i, element := range value {
+			if value[i], err = r.resolve(ctx, element, depth); err != nil {
+				return nil, err
+			}
+		}
+
+		return value, nil
+
+	default:
+		return value, nil
+	}
+}
+
+// resolveRef returns the converted document the reference points to, with its own references resolved.
+// The reference is normalized instead if the resolver only normalizes references, the referenced document
+// is deeper than the max depth or it doesn't exist.
+func (r *DBRefResolver) resolveRef(ctx context.Context, ref dbRef, depth int) (any, error) {
+	if r.mode != DBRefModeResolve || depth >= r.maxDepth {
+		return ref.normalize(), nil
+	}
+
+	db := ref.db
+	if db == "" {
+		db = r.db
+	}
+
+	rawDocument, err := r.find(ctx, db, ref.collection, ref.id)
+	if err != nil {
+		return nil, fmt.Errorf("find %s.%s document %v: %w", db, ref.collection, ref.id, err)
+	}
+
+	if rawDocument == nil {
+		return ref.normalize(), nil
+	}
+
+	document, err := r.converter.ConvertRaw(rawDocument)
+	if err != nil {
+		return nil, fmt.Errorf("convert %s.%s document %v: %w", db, ref.collection, ref.id, err)
+	}
+
+	return r.resolve(ctx, document, depth+1)
+}
+
+// parseDBRef returns the reference of a converted document, and false if the document is not a DBRef.
+func parseDBRef(document map[string]any) (dbRef, bool) {
+	collection, ok := document[dbRefCollectionFieldName].(string)
+	if !ok {
+		return dbRef{}, false
+	}
+
+	id, ok := document[dbRefIDFieldName]
+	if !ok {
+		return dbRef{}, false
+	}
+
+	db, _ := document[dbRefDBFieldName].(string)
+
+	return dbRef{collection: collection, id: id, db: db}, true
+}
+
+// normalize returns the reference as a document with the collection, id and, if it's set, db fields.
+func (ref dbRef) normalize() map[string]any {
+	normalized := map[string]any{
+		normalizedCollectionFieldName: ref.collection,
+		normalizedIDFieldName:         ref.id,
+	}
+
+	if ref.db != "" {
+		normalized[normalizedDBFieldName] = ref.db
+	}
+
+	return normalized
+}
+
+// dbRefFilter returns a filter of the document with the converted _id.
+// ObjectIDs are converted into hex strings, so such strings match both, ObjectIDs and strings.
+func dbRefFilter(id any) bson.M {
+	if hex, ok := id.(string); ok {
+		if objectID, err := primitive.ObjectIDFromHex(hex); err == nil {
+			return bson.M{idFieldName: bson.M{"$in": bson.A{objectID, hex}}}
+		}
+	}
+
+	return bson.M{idFieldName: id}
+}
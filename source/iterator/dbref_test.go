@@ -0,0 +1,182 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDBRefResolver_Apply(t *testing.T) {
+	t.Parallel()
+
+	user, err := bson.Marshal(bson.D{{Key: "_id", Value: int32(1)}, {Key: "manager", Value: bson.D{
+		{Key: "$ref", Value: "users"}, {Key: "$id", Value: int32(2)},
+	}}})
+	if err != nil {
+		t.Fatalf("marshal user: %v", err)
+	}
+
+	manager, err := bson.Marshal(bson.D{{Key: "_id", Value: int32(2)}, {Key: "name", Value: "alice"}})
+	if err != nil {
+		t.Fatalf("marshal manager: %v", err)
+	}
+
+	documents := map[string]bson.Raw{"test.users.1": user, "test.users.2": manager}
+
+	find := func(_ context.Context, db, collection string, id any) (bson.Raw, error) {
+		return documents[fmt.Sprintf("%s.%s.%v", db, collection, id)], nil
+	}
+
+	payload := func() opencdc.StructuredData {
+		return opencdc.StructuredData{
+			"_id":    "order",
+			"author": map[string]any{"$ref": "users", "$id": int32(1)},
+			"items":  []any{map[string]any{"$ref": "items", "$id": "abc", "$db": "shop"}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		mode     DBRefMode
+		maxDepth int
+		want     opencdc.StructuredData
+	}{
+		{
+			name: "normalize",
+			mode: DBRefModeNormalize,
+			want: opencdc.StructuredData{
+				"_id":    "order",
+				"author": map[string]any{"collection": "users", "id": int32(1)},
+				"items":  []any{map[string]any{"collection": "items", "id": "abc", "db": "shop"}},
+			},
+		},
+		{
+			name:     "resolve_depth_2",
+			mode:     DBRefModeResolve,
+			maxDepth: 2,
+			want: opencdc.StructuredData{
+				"_id": "order",
+				"author": map[string]any{
+					"_id": int32(1), "manager": map[string]any{"_id": int32(2), "name": "alice"},
+				},
+				"items": []any{map[string]any{"collection": "items", "id": "abc", "db": "shop"}},
+			},
+		},
+		{
+			name:     "resolve_depth_1",
+			mode:     DBRefModeResolve,
+			maxDepth: 1,
+			want: opencdc.StructuredData{
+				"_id": "order",
+				"author": map[string]any{
+					"_id": int32(1), "manager": map[string]any{"collection": "users", "id": int32(2)},
+				},
+				"items": []any{map[string]any{"collection": "items", "id": "abc", "db": "shop"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			resolver := &DBRefResolver{mode: tt.mode, maxDepth: tt.maxDepth, db: "test", find: find}
+
+			got, err := resolver.Apply(context.Background(), opencdc.Record{Payload: opencdc.Change{After: payload()}})
+			is.NoErr(err)
+			is.Equal(got.Payload.After, tt.want)
+		})
+	}
+}
+
+func TestDBRefResolver_Apply_error(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	errFind := errors.New("connection lost")
+	resolver := &DBRefResolver{
+		mode:     DBRefModeResolve,
+		maxDepth: 1,
+		db:       "test",
+		find: func(context.Context, string, string, any) (bson.Raw, error) {
+			return nil, errFind
+		},
+	}
+
+	_, err := resolver.Apply(context.Background(), opencdc.Record{Payload: opencdc.Change{
+		After: opencdc.StructuredData{"author": map[string]any{"$ref": "users", "$id": int32(1)}},
+	}})
+	is.True(errors.Is(err, errFind))
+}
+
+func TestDBRefResolver_Apply_controlRecord(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	resolver := &DBRefResolver{
+		mode:     DBRefModeResolve,
+		maxDepth: 1,
+		db:       "test",
+		find: func(context.Context, string, string, any) (bson.Raw, error) {
+			return nil, errors.New("unexpected lookup")
+		},
+	}
+
+	record := opencdc.Record{
+		Metadata: opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeCollectionEvent},
+		Payload: opencdc.Change{
+			After: opencdc.StructuredData{"to": map[string]any{"$ref": "users", "$id": int32(1)}},
+		},
+	}
+
+	got, err := resolver.Apply(context.Background(), record)
+	is.NoErr(err)
+	is.Equal(got.Payload.After, opencdc.StructuredData{"to": map[string]any{"$ref": "users", "$id": int32(1)}})
+}
+
+func TestDBRefResolver_Apply_nil(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	record := opencdc.Record{Payload: opencdc.Change{
+		After: opencdc.StructuredData{"author": map[string]any{"$ref": "users", "$id": int32(1)}},
+	}}
+
+	got, err := (*DBRefResolver)(nil).Apply(context.Background(), record)
+	is.NoErr(err)
+	is.Equal(got, record)
+}
+
+func TestDBRefFilter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(dbRefFilter(int32(1)), bson.M{"_id": int32(1)})
+	is.Equal(len(dbRefFilter("63bd5ee3ad5b1d4c6ad2b7e0")["_id"].(bson.M)["$in"].(bson.A)), 2)
+}
@@ -0,0 +1,98 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// debeziumConnectorName is a connector name that Debezium puts into a source block of an envelope.
+const debeziumConnectorName = "mongodb"
+
+// debeziumOperations maps OpenCDC operations to Debezium operation codes.
+var debeziumOperations = map[opencdc.Operation]string{
+	opencdc.OperationCreate:   "c",
+	opencdc.OperationUpdate:   "u",
+	opencdc.OperationDelete:   "d",
+	opencdc.OperationSnapshot: "r",
+}
+
+// debeziumEnvelope mimics a change event value produced by the Debezium MongoDB connector.
+//
+//nolint:tagliatelle // Debezium uses snake case field names
+type debeziumEnvelope struct {
+	// After is a document state after the change, encoded as a relaxed Extended JSON string.
+	After  *string        `json:"after"`
+	Source debeziumSource `json:"source"`
+	Op     string         `json:"op"`
+	TsMs   int64          `json:"ts_ms"`
+}
+
+// debeziumSource is a source block of a [debeziumEnvelope].
+//
+//nolint:tagliatelle // Debezium uses snake case field names
+type debeziumSource struct {
+	Connector  string `json:"connector"`
+	TsMs       int64  `json:"ts_ms"`
+	Snapshot   string `json:"snapshot"`
+	DB         string `json:"db"`
+	Collection string `json:"collection"`
+}
+
+// toDebeziumRecord replaces the provided record's payload with a [debeziumEnvelope]
+// built from the record and a raw document. The document is nil for delete operations.
+func toDebeziumRecord(
+	record opencdc.Record, db string, document bson.Raw, buffers *codec.BufferPool,
+) (opencdc.Record, error) {
+	createdAt, err := record.Metadata.GetCreatedAt()
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("get record created at: %w", err)
+	}
+
+	envelope := debeziumEnvelope{
+		Source: debeziumSource{
+			Connector:  debeziumConnectorName,
+			TsMs:       createdAt.UnixMilli(),
+			Snapshot:   fmt.Sprint(record.Operation == opencdc.OperationSnapshot),
+			DB:         db,
+			Collection: record.Metadata[metadataFieldCollection],
+		},
+		Op:   debeziumOperations[record.Operation],
+		TsMs: createdAt.UnixMilli(),
+	}
+
+	if document != nil {
+		after, err := buffers.EncodeExtJSON(document, false)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("marshal document into extended json: %w", err)
+		}
+
+		afterStr := string(after)
+		envelope.After = &afterStr
+	}
+
+	envelopeBytes, err := buffers.EncodeJSON(envelope)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal debezium envelope: %w", err)
+	}
+
+	record.Payload.After = opencdc.RawData(envelopeBytes)
+
+	return record, nil
+}
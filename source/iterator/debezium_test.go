@@ -0,0 +1,89 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestToDebeziumRecord_create(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	objectID := primitive.NewObjectID()
+	document, err := bson.Marshal(bson.D{{Key: "_id", Value: objectID}, {Key: "name", Value: "Bob"}})
+	is.NoErr(err)
+
+	createdAt := time.UnixMilli(1700000000000)
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = "users"
+	metadata.SetCreatedAt(createdAt)
+
+	record := sdk.Util.Source.NewRecordCreate(
+		opencdc.Position("pos"), metadata, opencdc.StructuredData{idFieldName: objectID.Hex()}, nil,
+	)
+
+	got, err := toDebeziumRecord(record, "test", document, nil)
+	is.NoErr(err)
+
+	var envelope debeziumEnvelope
+	is.NoErr(json.Unmarshal(got.Payload.After.Bytes(), &envelope))
+
+	is.Equal(envelope.Op, "c")
+	is.Equal(envelope.TsMs, createdAt.UnixMilli())
+	is.Equal(envelope.Source, debeziumSource{
+		Connector:  debeziumConnectorName,
+		TsMs:       createdAt.UnixMilli(),
+		Snapshot:   "false",
+		DB:         "test",
+		Collection: "users",
+	})
+	is.True(envelope.After != nil)
+	is.Equal(*envelope.After, `{"_id":{"$oid":"`+objectID.Hex()+`"},"name":"Bob"}`)
+	is.Equal(got.Key, record.Key)
+}
+
+func TestToDebeziumRecord_delete(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = "users"
+	metadata.SetCreatedAt(time.Now())
+
+	record := sdk.Util.Source.NewRecordDelete(
+		opencdc.Position("pos"), metadata, opencdc.StructuredData{idFieldName: "1"}, nil,
+	)
+
+	got, err := toDebeziumRecord(record, "test", nil, nil)
+	is.NoErr(err)
+
+	var envelope debeziumEnvelope
+	is.NoErr(json.Unmarshal(got.Payload.After.Bytes(), &envelope))
+
+	is.Equal(envelope.Op, "d")
+	is.Equal(envelope.After, nil)
+}
@@ -0,0 +1,114 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeleteStrategy defines how the polling snapshot detects deleted documents.
+type DeleteStrategy string
+
+// The list of available delete strategies is listed below.
+const (
+	// DeleteStrategyNone makes the polling snapshot not detect deleted documents.
+	DeleteStrategyNone DeleteStrategy = "none"
+	// DeleteStrategyIDSet makes the polling snapshot periodically compare the _ids of documents
+	// in the collection with the known ones, and report the missing ones as deleted.
+	DeleteStrategyIDSet DeleteStrategy = "idset"
+	// DeleteStrategySoftDelete makes the polling snapshot report documents as deleted
+	// once their soft delete field is set.
+	DeleteStrategySoftDelete DeleteStrategy = "softdelete"
+)
+
+// idSet detects deleted documents by periodically comparing the _ids of documents
+// in a collection with the known ones.
+type idSet struct {
+	interval  time.Duration
+	checkedAt time.Time
+	// ids are the keys of the known documents, each containing only the document's _id.
+	ids map[string]bson.Raw
+	// deleted are the keys of deleted documents that are not returned yet.
+	deleted []bson.Raw
+}
+
+// newIDSet creates a new instance of the [idSet] comparing the _ids once per the interval.
+func newIDSet(interval time.Duration) *idSet {
+	return &idSet{
+		interval: interval,
+		ids:      make(map[string]bson.Raw),
+	}
+}
+
+// add adds the key of a document to the known ones.
+func (d *idSet) add(document bson.Raw) {
+	key := oplogDocumentKey(document)
+	d.ids[string(key)] = key
+}
+
+// due checks whether it's time to compare the _ids again.
+func (d *idSet) due() bool {
+	return time.Since(d.checkedAt) >= d.interval
+}
+
+// scan reads the _ids of all documents in the collection, queues the keys of the known documents
+// that are missing as deleted, and replaces the known keys with the read ones.
+func (d *idSet) scan(ctx context.Context, collection *mongo.Collection) error {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{idFieldName: 1}))
+	if err != nil {
+		return fmt.Errorf("execute find: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	ids := make(map[string]bson.Raw, len(d.ids))
+	for cursor.Next(ctx) {
+		key := oplogDocumentKey(cursor.Current)
+		ids[string(key)] = key
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("read _ids: %w", err)
+	}
+
+	for id, key := range d.ids {
+		if _, ok := ids[id]; !ok {
+			d.deleted = append(d.deleted, key)
+		}
+	}
+
+	d.ids = ids
+	d.checkedAt = time.Now()
+
+	return nil
+}
+
+// hasDeleted checks whether there are deleted documents that are not returned yet.
+func (d *idSet) hasDeleted() bool {
+	return d != nil && len(d.deleted) > 0
+}
+
+// nextDeleted returns the key of the next deleted document and removes it from the queue.
+func (d *idSet) nextDeleted() bson.Raw {
+	key := d.deleted[0]
+	d.deleted = d.deleted[1:]
+
+	return key
+}
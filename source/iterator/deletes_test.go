@@ -0,0 +1,82 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIDSet_add(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ids := newIDSet(0)
+	is.True(ids.due())
+	is.True(!ids.hasDeleted())
+
+	document, err := bson.Marshal(bson.D{{Key: idFieldName, Value: int32(1)}, {Key: "name", Value: "bob"}})
+	is.NoErr(err)
+
+	key, err := bson.Marshal(bson.D{{Key: idFieldName, Value: int32(1)}})
+	is.NoErr(err)
+
+	// documents are known by their keys only, so the same document is added once
+	ids.add(document)
+	ids.add(key)
+	is.Equal(len(ids.ids), 1)
+	is.Equal(ids.ids[string(key)], bson.Raw(key))
+
+	ids.deleted = append(ids.deleted, key)
+	is.True(ids.hasDeleted())
+	is.Equal(ids.nextDeleted(), bson.Raw(key))
+	is.True(!ids.hasDeleted())
+}
+
+func TestSnapshot_softDeleted(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		document bson.M
+		want     bool
+	}{
+		{name: "no_field", document: bson.M{idFieldName: 1}, want: false},
+		{name: "false", document: bson.M{idFieldName: 1, "deleted": false}, want: false},
+		{name: "null", document: bson.M{idFieldName: 1, "deleted": nil}, want: false},
+		{name: "true", document: bson.M{idFieldName: 1, "deleted": true}, want: true},
+		{name: "date", document: bson.M{idFieldName: 1, "deleted": "2026-01-02T03:04:05Z"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			cursor, err := mongo.NewCursorFromDocuments([]any{tt.document}, nil, nil)
+			is.NoErr(err)
+			is.True(cursor.Next(context.Background()))
+
+			s := &snapshot{cursor: cursor, softDeleteField: "deleted"}
+			is.Equal(s.softDeleted(), tt.want)
+		})
+	}
+}
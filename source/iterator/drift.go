@@ -0,0 +1,162 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+	"strconv"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// SchemaDriftMode defines how the iterators report documents that drift from the first-seen schema.
+type SchemaDriftMode string
+
+// The list of available schema drift modes is listed below.
+const (
+	// SchemaDriftModeNone disables schema drift detection.
+	SchemaDriftModeNone SchemaDriftMode = "none"
+	// SchemaDriftModeLog logs a warning when a document drifts from the first-seen schema.
+	SchemaDriftModeLog SchemaDriftMode = "log"
+	// SchemaDriftModeMetadata logs a warning and also puts the schema fingerprint of every document
+	// and a drift flag into record metadata.
+	SchemaDriftModeMetadata SchemaDriftMode = "metadata"
+)
+
+const (
+	// metadataFieldSchemaFingerprint is a metadata field that holds the schema fingerprint of a document.
+	metadataFieldSchemaFingerprint = "mongo.schema.fingerprint"
+	// metadataFieldSchemaDrift is a metadata field that marks documents
+	// whose schema differs from the first-seen one.
+	metadataFieldSchemaDrift = "mongo.schema.drift"
+	// maxReportedFingerprints is the max number of drifted fingerprints the detector remembers,
+	// so a warning is logged once per fingerprint, but memory doesn't grow unbounded.
+	maxReportedFingerprints = 1024
+)
+
+// schemaDrift detects documents whose field set or field types differ from the first-seen document.
+// It's shared by the snapshot and CDC iterators, so the baseline is the same for both of them.
+type schemaDrift struct {
+	mode SchemaDriftMode
+	// baseline is the fingerprint of the first-seen document.
+	baseline string
+	// reported are drifted fingerprints a warning has already been logged for.
+	reported map[string]struct{}
+}
+
+// newSchemaDrift creates a new instance of the [schemaDrift].
+// It returns nil if the mode disables schema drift detection.
+func newSchemaDrift(mode SchemaDriftMode) *schemaDrift {
+	if mode == "" || mode == SchemaDriftModeNone {
+		return nil
+	}
+
+	return &schemaDrift{
+		mode:     mode,
+		reported: make(map[string]struct{}),
+	}
+}
+
+// check compares the schema fingerprint of the raw document with the baseline and reports a drift.
+// A nil detector or a nil document leaves the record as it is.
+func (d *schemaDrift) check(ctx context.Context, record opencdc.Record, document bson.Raw) opencdc.Record {
+	if d == nil || document == nil {
+		return record
+	}
+
+	fingerprint := schemaFingerprint(document)
+	drifted := d.compare(ctx, fingerprint)
+
+	if d.mode == SchemaDriftModeMetadata {
+		if record.Metadata == nil {
+			record.Metadata = make(opencdc.Metadata)
+		}
+
+		record.Metadata[metadataFieldSchemaFingerprint] = fingerprint
+		if drifted {
+			record.Metadata[metadataFieldSchemaDrift] = "true"
+		}
+	}
+
+	return record
+}
+
+// compare compares the fingerprint with the baseline, which is set by the first fingerprint.
+// A warning is logged the first time every drifted fingerprint is seen.
+func (d *schemaDrift) compare(ctx context.Context, fingerprint string) bool {
+	if d.baseline == "" {
+		d.baseline = fingerprint
+
+		return false
+	}
+
+	if fingerprint == d.baseline {
+		return false
+	}
+
+	if _, ok := d.reported[fingerprint]; !ok && len(d.reported) < maxReportedFingerprints {
+		d.reported[fingerprint] = struct{}{}
+
+		sdk.Logger(ctx).Warn().
+			Str("baseline", d.baseline).
+			Str("fingerprint", fingerprint).
+			Msg("document schema drifted from the first-seen one")
+	}
+
+	return true
+}
+
+// schemaFingerprint returns a hash of the document's field paths and their BSON types.
+// Fields of embedded documents are included as dot-notation paths, elements of arrays aren't.
+// The order of fields doesn't matter.
+func schemaFingerprint(document bson.Raw) string {
+	fields := schemaFields(nil, "", document)
+	slices.Sort(fields)
+
+	hash := fnv.New64a()
+	for _, field := range fields {
+		// writing into a hash never fails
+		_, _ = hash.Write([]byte(field))
+		_, _ = hash.Write([]byte{0})
+	}
+
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// schemaFields appends "path:type" pairs of the document's fields to the fields.
+func schemaFields(fields []string, prefix string, document bson.Raw) []string {
+	elements, err := document.Elements()
+	if err != nil {
+		return fields
+	}
+
+	for _, element := range elements {
+		path := prefix + element.Key()
+		value := element.Value()
+
+		fields = append(fields, path+":"+value.Type.String())
+
+		if value.Type == bsontype.EmbeddedDocument {
+			fields = schemaFields(fields, path+".", value.Document())
+		}
+	}
+
+	return fields
+}
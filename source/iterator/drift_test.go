@@ -0,0 +1,118 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSchemaFingerprint(t *testing.T) {
+	t.Parallel()
+
+	mustMarshal := func(document any) bson.Raw {
+		raw, err := bson.Marshal(document)
+		if err != nil {
+			t.Fatalf("bson.Marshal() error = %v", err)
+		}
+
+		return raw
+	}
+
+	base := mustMarshal(bson.D{{Key: "a", Value: 1}, {Key: "b", Value: bson.D{{Key: "c", Value: "x"}}}})
+
+	tests := []struct {
+		name      string
+		document  bson.Raw
+		wantEqual bool
+	}{
+		{
+			name:      "same_fields_other_values",
+			document:  mustMarshal(bson.D{{Key: "a", Value: 2}, {Key: "b", Value: bson.D{{Key: "c", Value: "y"}}}}),
+			wantEqual: true,
+		},
+		{
+			name:      "same_fields_other_order",
+			document:  mustMarshal(bson.D{{Key: "b", Value: bson.D{{Key: "c", Value: "y"}}}, {Key: "a", Value: 2}}),
+			wantEqual: true,
+		},
+		{
+			name:     "other_type",
+			document: mustMarshal(bson.D{{Key: "a", Value: "1"}, {Key: "b", Value: bson.D{{Key: "c", Value: "x"}}}}),
+		},
+		{
+			name:     "other_embedded_field",
+			document: mustMarshal(bson.D{{Key: "a", Value: 1}, {Key: "b", Value: bson.D{{Key: "d", Value: "x"}}}}),
+		},
+		{
+			name:     "missing_field",
+			document: mustMarshal(bson.D{{Key: "a", Value: 1}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := schemaFingerprint(tt.document) == schemaFingerprint(base); got != tt.wantEqual {
+				t.Errorf("schemaFingerprint() equal = %v, want %v", got, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestSchemaDrift_check(t *testing.T) {
+	t.Parallel()
+
+	first, err := bson.Marshal(bson.D{{Key: "a", Value: 1}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	drifted, err := bson.Marshal(bson.D{{Key: "a", Value: 1}, {Key: "b", Value: true}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	ctx := context.Background()
+	detector := newSchemaDrift(SchemaDriftModeMetadata)
+
+	record := detector.check(ctx, opencdc.Record{Metadata: opencdc.Metadata{}}, first)
+	if _, ok := record.Metadata[metadataFieldSchemaDrift]; ok {
+		t.Errorf("the first-seen document is marked as drifted")
+	}
+
+	if record.Metadata[metadataFieldSchemaFingerprint] != schemaFingerprint(first) {
+		t.Errorf("fingerprint = %q, want %q", record.Metadata[metadataFieldSchemaFingerprint], schemaFingerprint(first))
+	}
+
+	record = detector.check(ctx, opencdc.Record{Metadata: opencdc.Metadata{}}, drifted)
+	if record.Metadata[metadataFieldSchemaDrift] != "true" {
+		t.Errorf("the drifted document is not marked as drifted")
+	}
+
+	// the log mode doesn't touch the metadata
+	record = newSchemaDrift(SchemaDriftModeLog).check(ctx, opencdc.Record{}, drifted)
+	if record.Metadata != nil {
+		t.Errorf("metadata = %v, want nil", record.Metadata)
+	}
+
+	if newSchemaDrift(SchemaDriftModeNone) != nil {
+		t.Errorf("newSchemaDrift(none) is not nil")
+	}
+}
@@ -16,6 +16,13 @@ package iterator
 
 import "errors"
 
+// The MongoDB server error codes returned when a Change Stream
+// cannot be resumed because its resume token is no longer present in the oplog.
+const (
+	changeStreamFatalErrCode       = 280
+	changeStreamHistoryLostErrCode = 286
+)
+
 var (
 	// ErrNoIterator occurs when the [Combined] has no any underlying iterators.
 	ErrNoIterator = errors.New("no iterator")
@@ -32,9 +39,43 @@ var (
 	// errMaxFieldValueNotFound occurs when it's impossible to find the maximum value of a field.
 	errMaxFieldValueNotFound = errors.New("max field value not found")
 
+	// errPositionOrderingMismatch occurs when a position element doesn't match the ordering fields,
+	// e.g. if the orderingField option is changed while the snapshot is in progress.
+	errPositionOrderingMismatch = errors.New("position element doesn't match ordering fields")
+
 	// errNoDocuments occurs when there're no documents in a collection.
 	errNoDocuments = errors.New("no documents in collection")
 
+	// errNoResumeToken occurs when a Change Stream has no resume token to verify its resumability.
+	errNoResumeToken = errors.New("change stream has no resume token")
+
+	// errNoSnapshotTime occurs when the cluster time of a snapshot session can't be read or set,
+	// e.g. if the server isn't a replica set or a sharded cluster.
+	errNoSnapshotTime = errors.New("snapshot session has no cluster time")
+
+	// errNoPrefetchedRecord occurs when the next prefetched record is requested before checking it's available.
+	errNoPrefetchedRecord = errors.New("no prefetched record")
+
+	// errIDLessOrdering occurs when a snapshot of a collection whose documents may lack the _id
+	// is ordered by the _id, which would return the same documents over and over again.
+	errIDLessOrdering = errors.New("collection has no _id index, so ordering fields must not include the _id")
+
+	// errIDLessPolling occurs when polling for updated or deleted documents of a collection
+	// whose documents may lack the _id, as they're told apart by their _id.
+	errIDLessPolling = errors.New("collection has no _id index, so updates and id set deletes can't be polled")
+
+	// errNotCappedCollection occurs when a collection that isn't capped is tailed with a tailable cursor.
+	errNotCappedCollection = errors.New("collection is not capped, so it can't be tailed")
+
+	// errViewChangeCapture occurs when changes of a view are captured in a mode other than the auto one.
+	errViewChangeCapture = errors.New("collection is a view, so its changes can only be polled in the auto CDC mode")
+
+	// errNotShardedCollection occurs when a collection that isn't sharded is snapshotted by its chunks.
+	errNotShardedCollection = errors.New("collection is not sharded, so it can't be snapshotted by its chunks")
+
+	// errInvalidResumeToken occurs when a resume token doesn't contain a cluster time it can be decoded from.
+	errInvalidResumeToken = errors.New("invalid resume token")
+
 	// matchProjectStageErrMessage contains an error text that Azure CosmosDB for MongoDB returns
 	// when you try to create a Change Stream.
 	// We use it to determine whether we should do snapshot polling instead of CDC.
@@ -0,0 +1,242 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SnapshotMode defines the way the iterators capture a snapshot.
+type SnapshotMode string
+
+// The list of available snapshot modes is listed below.
+const (
+	// SnapshotModeBlocking makes the iterators capture the whole snapshot before starting CDC.
+	SnapshotModeBlocking SnapshotMode = "blocking"
+	// SnapshotModeIncremental makes the iterators capture the snapshot in chunks
+	// interleaved with Change Stream events, so CDC is never paused.
+	SnapshotModeIncremental SnapshotMode = "incremental"
+)
+
+// The names of Change Stream event fields used to deduplicate incremental snapshot chunks are listed below.
+const (
+	documentKeyFieldName = "documentKey"
+	clusterTimeFieldName = "clusterTime"
+)
+
+// incrementalPosition is a progress of an incremental snapshot stored in the CDC position.
+type incrementalPosition struct {
+	// Trigger is an identifier of the incremental snapshot.
+	Trigger string `json:"trigger,omitempty"`
+	// Element is a value of the ordering field of the last document of the last emitted chunk.
+	Element any `json:"element,omitempty"`
+	// ElementID is the _id of the last document of the last emitted chunk.
+	ElementID any `json:"elementId,omitempty"`
+	// MaxElement is a max value of the ordering field at the start of the incremental snapshot.
+	MaxElement any `json:"maxElement,omitempty"`
+	// Completed defines if the incremental snapshot is completed.
+	// It's used to not trigger the snapshot with the same identifier again.
+	Completed bool `json:"completed,omitempty"`
+}
+
+// resolveIncrementalPosition returns the progress an incremental snapshot must start from and true,
+// or the progress of the completed snapshot to keep in positions and false if no snapshot is required.
+// A new incremental snapshot starts if there's no position and the initial snapshot is required,
+// or if the trigger differs from the one of the incremental snapshot stored in the position.
+// An incomplete incremental snapshot with the same trigger is resumed.
+func resolveIncrementalPosition(pos *position, trigger string, initial bool) (*incrementalPosition, bool) {
+	switch {
+	case pos == nil && initial:
+		return &incrementalPosition{Trigger: trigger}, true
+
+	case pos == nil:
+		// the trigger is remembered, so the snapshot isn't taken after a restart
+		return completedIncrementalPosition(trigger), false
+
+	case pos.Incremental != nil && pos.Incremental.Trigger == trigger:
+		return pos.Incremental, !pos.Incremental.Completed
+
+	case trigger != "":
+		return &incrementalPosition{Trigger: trigger}, true
+
+	case pos.Incremental != nil && !pos.Incremental.Completed:
+		// the trigger has been removed, but the started snapshot is still worth completing
+		return pos.Incremental, true
+
+	default:
+		return pos.Incremental, false
+	}
+}
+
+// completedIncrementalPosition returns the progress of a completed incremental snapshot,
+// or nil if the snapshot has no trigger to remember.
+func completedIncrementalPosition(trigger string) *incrementalPosition {
+	if trigger == "" {
+		return nil
+	}
+
+	return &incrementalPosition{
+		Trigger:   trigger,
+		Completed: true,
+	}
+}
+
+// chunkDocument is a document read by an incremental snapshot chunk.
+type chunkDocument struct {
+	// key is a string representation of the document's _id,
+	// used to match the document with Change Stream events.
+	key string
+	// element is a value of the ordering field of the document.
+	element any
+	// elementID is the _id of the document if the ordering field is not _id.
+	elementID any
+	record    opencdc.Record
+}
+
+// incrementalSnapshot captures a snapshot in chunks of the batch size. The combined iterator reads
+// Change Stream events occurred while a chunk is being read right after it, and the chunk's documents
+// changed by these events are discarded, as the events already carry newer states of the documents.
+type incrementalSnapshot struct {
+	snapshot *snapshot
+	trigger  string
+}
+
+// newIncrementalSnapshot creates a new instance of the [incrementalSnapshot]
+// that starts from the provided progress.
+func newIncrementalSnapshot(
+	ctx context.Context, params snapshotParams, progress *incrementalPosition,
+) (*incrementalSnapshot, error) {
+	params.position = nil
+	if progress.MaxElement != nil {
+		params.position = &position{
+			Mode:       modeSnapshot,
+			Element:    progress.Element,
+			ElementID:  progress.ElementID,
+			MaxElement: progress.MaxElement,
+		}
+	}
+
+	snapshot, err := newSnapshot(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("init snapshot: %w", err)
+	}
+
+	return &incrementalSnapshot{
+		snapshot: snapshot,
+		trigger:  progress.Trigger,
+	}, nil
+}
+
+// progress returns the current progress of the incremental snapshot.
+func (s *incrementalSnapshot) progress() *incrementalPosition {
+	progress := &incrementalPosition{
+		Trigger:    s.trigger,
+		MaxElement: s.snapshot.orderingFieldMaxValue,
+	}
+
+	if s.snapshot.position != nil {
+		progress.Element = s.snapshot.position.Element
+		progress.ElementID = s.snapshot.position.ElementID
+	}
+
+	return progress
+}
+
+// loadChunk reads the next chunk of documents. It also returns the cluster time of the read,
+// which is a high watermark Change Stream events are read to in order to deduplicate the chunk.
+func (s *incrementalSnapshot) loadChunk(ctx context.Context) ([]chunkDocument, *primitive.Timestamp, error) {
+	session, err := s.snapshot.collection.Database().Client().StartSession()
+	if err != nil {
+		return nil, nil, fmt.Errorf("start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	sessionCtx := mongo.NewSessionContext(ctx, session)
+
+	if err := s.snapshot.loadBatch(sessionCtx); err != nil {
+		return nil, nil, fmt.Errorf("load batch: %w", err)
+	}
+
+	var chunk []chunkDocument
+	for s.snapshot.tryNext(sessionCtx) {
+		key := s.snapshot.cursor.Current.Lookup(idFieldName).String()
+
+		record, err := s.snapshot.next(sessionCtx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("read document: %w", err)
+		}
+
+		chunk = append(chunk, chunkDocument{
+			key:       key,
+			element:   s.snapshot.position.Element,
+			elementID: s.snapshot.position.ElementID,
+			record:    record,
+		})
+	}
+
+	if err := s.snapshot.cursor.Err(); err != nil {
+		return nil, nil, fmt.Errorf("cursor: %w", err)
+	}
+
+	return chunk, session.OperationTime(), nil
+}
+
+// chunkRecords returns records of the chunk's documents with CDC positions,
+// so the Change Stream is resumed with the resume token after a restart,
+// and the incremental snapshot continues from the document.
+func (s *incrementalSnapshot) chunkRecords(chunk []chunkDocument, resumeToken bson.Raw) ([]opencdc.Record, error) {
+	records := make([]opencdc.Record, 0, len(chunk))
+	for _, document := range chunk {
+		position := &position{
+			Mode:        modeCDC,
+			ResumeToken: resumeToken,
+			Incremental: &incrementalPosition{
+				Trigger:    s.trigger,
+				Element:    document.element,
+				ElementID:  document.elementID,
+				MaxElement: s.snapshot.orderingFieldMaxValue,
+			},
+		}
+
+		sdkPosition, err := position.marshalSDKPosition(s.snapshot.buffers)
+		if err != nil {
+			return nil, fmt.Errorf("marshal sdk position: %w", err)
+		}
+
+		document.record.Position = sdkPosition
+		records = append(records, document.record)
+	}
+
+	return records, nil
+}
+
+// stop stops the incremental snapshot.
+func (s *incrementalSnapshot) stop(ctx context.Context) error {
+	return s.snapshot.stop(ctx)
+}
+
+// discardChunkDocument removes a document with the provided key from the chunk.
+func discardChunkDocument(chunk []chunkDocument, key string) []chunkDocument {
+	return slices.DeleteFunc(chunk, func(document chunkDocument) bool {
+		return document.key == key
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+)
+
+func TestResolveIncrementalPosition(t *testing.T) {
+	t.Parallel()
+
+	inProgress := &incrementalPosition{Trigger: "backfill", Element: int32(10), MaxElement: int32(20)}
+	completed := &incrementalPosition{Trigger: "backfill", Completed: true}
+
+	tests := []struct {
+		name         string
+		position     *position
+		trigger      string
+		initial      bool
+		want         *incrementalPosition
+		wantRequired bool
+	}{
+		{
+			name:         "no_position_initial",
+			initial:      true,
+			want:         &incrementalPosition{},
+			wantRequired: true,
+		},
+		{
+			name:         "no_position_not_initial_with_trigger",
+			trigger:      "backfill",
+			want:         completed,
+			wantRequired: false,
+		},
+		{
+			name:         "no_position_not_initial_without_trigger",
+			want:         nil,
+			wantRequired: false,
+		},
+		{
+			name:         "resume_in_progress",
+			position:     &position{Mode: modeCDC, Incremental: inProgress},
+			trigger:      "backfill",
+			want:         inProgress,
+			wantRequired: true,
+		},
+		{
+			name:         "same_trigger_completed",
+			position:     &position{Mode: modeCDC, Incremental: completed},
+			trigger:      "backfill",
+			want:         completed,
+			wantRequired: false,
+		},
+		{
+			name:         "new_trigger",
+			position:     &position{Mode: modeCDC, Incremental: completed},
+			trigger:      "backfill-2",
+			want:         &incrementalPosition{Trigger: "backfill-2"},
+			wantRequired: true,
+		},
+		{
+			name:         "new_trigger_without_incremental",
+			position:     &position{Mode: modeCDC},
+			trigger:      "backfill",
+			want:         &incrementalPosition{Trigger: "backfill"},
+			wantRequired: true,
+		},
+		{
+			name:         "trigger_removed_in_progress",
+			position:     &position{Mode: modeCDC, Incremental: inProgress},
+			want:         inProgress,
+			wantRequired: true,
+		},
+		{
+			name:         "no_trigger_no_incremental",
+			position:     &position{Mode: modeCDC},
+			want:         nil,
+			wantRequired: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, required := resolveIncrementalPosition(tt.position, tt.trigger, tt.initial)
+			is.Equal(required, tt.wantRequired)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestDiscardChunkDocument(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	chunk := []chunkDocument{
+		{key: `{"$oid":"63bd5ee3ad5b1d4c6ad2b7e0"}`, element: "a"},
+		{key: `{"$oid":"63bd5ee3ad5b1d4c6ad2b7e1"}`, element: "b"},
+	}
+
+	chunk = discardChunkDocument(chunk, `{"$oid":"63bd5ee3ad5b1d4c6ad2b7e0"}`)
+	is.Equal(len(chunk), 1)
+	is.Equal(chunk[0].element, "b")
+
+	chunk = discardChunkDocument(chunk, `{"$oid":"63bd5ee3ad5b1d4c6ad2b7e2"}`)
+	is.Equal(len(chunk), 1)
+}
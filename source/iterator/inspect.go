@@ -0,0 +1,168 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// resumeTokenDataField is a name of a resume token field holding its hex-encoded KeyString value.
+	resumeTokenDataField = "_data"
+	// resumeTokenTimestampType is a KeyString type byte of a timestamp, which every resume token starts with.
+	resumeTokenTimestampType = 130
+	// resumeTokenTimestampSize is the size of a KeyString timestamp, including its type byte.
+	resumeTokenTimestampSize = 9
+)
+
+// PositionInfo describes a position stored by the source, so operators can assess saved offsets.
+type PositionInfo struct {
+	// Mode is the mode of the position, either snapshot or cdc.
+	Mode string
+	// Timestamp is the cluster time changes are captured from after resuming, decoded from the resume token
+	// or taken from the operation time or the oplog timestamp. It's nil if the position has none of them,
+	// e.g. if it's a snapshot position and the connector doesn't capture changes afterwards.
+	Timestamp *primitive.Timestamp
+	// Emitted is the number of documents emitted by the snapshot so far.
+	Emitted int64
+	// Incremental determines whether an incremental snapshot is in progress along with CDC.
+	Incremental bool
+}
+
+// InspectPosition reports the mode and the cluster time of the provided position stored by the source.
+func InspectPosition(sdkPosition opencdc.Position) (PositionInfo, error) {
+	pos, err := parsePosition(sdkPosition)
+	if err != nil {
+		return PositionInfo{}, err
+	}
+
+	timestamp, err := pos.timestamp()
+	if err != nil {
+		return PositionInfo{}, err
+	}
+
+	return PositionInfo{
+		Mode:        string(pos.Mode),
+		Timestamp:   timestamp,
+		Emitted:     pos.Emitted,
+		Incremental: pos.Incremental != nil,
+	}, nil
+}
+
+// IsResumable checks whether the source can resume from the provided position against the collection's cluster.
+// Positions with a resume token or an operation time are checked by opening a Change Stream from them,
+// and positions with an oplog timestamp are compared with the oldest oplog entry.
+// Positions without any of them are always resumable, as the snapshot restarts from its last element.
+func IsResumable(ctx context.Context, collection *mongo.Collection, sdkPosition opencdc.Position) (bool, error) {
+	pos, err := parsePosition(sdkPosition)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case pos.ResumeToken != nil || pos.OperationTime != nil:
+		changeStream, err := createChangeStream(ctx, cdcParams{collection: collection, position: pos})
+		if err != nil {
+			if isStaleResumeTokenErr(err) {
+				return false, nil
+			}
+
+			return false, err
+		}
+
+		if err := changeStream.Close(ctx); err != nil {
+			return false, fmt.Errorf("close change stream: %w", err)
+		}
+
+		return true, nil
+
+	case pos.OplogTimestamp != nil:
+		oplogCollection := collection.Database().Client().Database(oplogDatabaseName).Collection(oplogCollectionName)
+
+		oldest, err := oldestOplogTimestamp(ctx, oplogCollection)
+		if err != nil {
+			return false, err
+		}
+
+		return !pos.OplogTimestamp.Before(oldest), nil
+
+	default:
+		return true, nil
+	}
+}
+
+// timestamp returns the cluster time changes are captured from after resuming from the position.
+// The resume token takes precedence, as the Change Stream is resumed after it.
+func (p *position) timestamp() (*primitive.Timestamp, error) {
+	switch {
+	case p.ResumeToken != nil:
+		timestamp, err := resumeTokenTimestamp(p.ResumeToken)
+		if err != nil {
+			return nil, err
+		}
+
+		return &timestamp, nil
+
+	case p.OperationTime != nil:
+		return p.OperationTime, nil
+
+	default:
+		return p.OplogTimestamp, nil
+	}
+}
+
+// resumeTokenTimestamp decodes the cluster time of the event the resume token belongs to.
+// The token's data is a hex-encoded KeyString starting with the timestamp in the big-endian order.
+func resumeTokenTimestamp(resumeToken bson.Raw) (primitive.Timestamp, error) {
+	data, ok := resumeToken.Lookup(resumeTokenDataField).StringValueOK()
+	if !ok {
+		return primitive.Timestamp{}, fmt.Errorf("%w: no %s string field", errInvalidResumeToken, resumeTokenDataField)
+	}
+
+	keyString, err := hex.DecodeString(data)
+	if err != nil {
+		return primitive.Timestamp{}, fmt.Errorf("%w: decode hex data: %w", errInvalidResumeToken, err)
+	}
+
+	if len(keyString) < resumeTokenTimestampSize || keyString[0] != resumeTokenTimestampType {
+		return primitive.Timestamp{}, fmt.Errorf("%w: data doesn't start with a timestamp", errInvalidResumeToken)
+	}
+
+	return primitive.Timestamp{
+		T: binary.BigEndian.Uint32(keyString[1:5]),
+		I: binary.BigEndian.Uint32(keyString[5:resumeTokenTimestampSize]),
+	}, nil
+}
+
+// oldestOplogTimestamp returns the cluster time of the oldest oplog entry.
+func oldestOplogTimestamp(ctx context.Context, oplogCollection *mongo.Collection) (primitive.Timestamp, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "$natural", Value: 1}}).SetProjection(bson.M{"ts": 1})
+
+	var entry oplogEntry
+	if err := oplogCollection.FindOne(ctx, bson.M{}, opts).Decode(&entry); err != nil {
+		return primitive.Timestamp{}, fmt.Errorf("find oldest oplog entry: %w", err)
+	}
+
+	return entry.Timestamp, nil
+}
@@ -0,0 +1,133 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestResumeTokenTimestamp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		data    any
+		want    primitive.Timestamp
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: "826553F100000000032B022C0100296E5A1004AB",
+			want: primitive.Timestamp{T: 1700000000, I: 3},
+		},
+		{name: "not_string", data: int32(1), wantErr: true},
+		{name: "not_hex", data: "zz", wantErr: true},
+		{name: "too_short", data: "8265", wantErr: true},
+		{name: "not_timestamp", data: "3C6553F10000000003", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			resumeToken, err := bson.Marshal(bson.D{{Key: resumeTokenDataField, Value: tt.data}})
+			is.NoErr(err)
+
+			got, err := resumeTokenTimestamp(resumeToken)
+			if tt.wantErr {
+				is.True(errors.Is(err, errInvalidResumeToken))
+
+				return
+			}
+
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestInspectPosition(t *testing.T) {
+	t.Parallel()
+
+	resumeToken, err := bson.Marshal(bson.D{{Key: resumeTokenDataField, Value: "826553F10000000003"}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	cdcPosition, err := (&position{
+		Mode:          modeCDC,
+		ResumeToken:   resumeToken,
+		OperationTime: &primitive.Timestamp{T: 1, I: 1},
+	}).marshalSDKPosition(nil)
+	if err != nil {
+		t.Fatalf("marshal position: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		sdkPosition opencdc.Position
+		want        PositionInfo
+	}{
+		{
+			name:        "snapshot",
+			sdkPosition: opencdc.Position(`{"mode":"snapshot","element":5,"emitted":5}`),
+			want:        PositionInfo{Mode: "snapshot", Emitted: 5},
+		},
+		{
+			name:        "cdc_resume_token",
+			sdkPosition: cdcPosition,
+			want:        PositionInfo{Mode: "cdc", Timestamp: &primitive.Timestamp{T: 1700000000, I: 3}},
+		},
+		{
+			name:        "cdc_operation_time",
+			sdkPosition: opencdc.Position(`{"mode":"cdc","operationTime":{"T":10,"I":2},"incremental":{}}`),
+			want:        PositionInfo{Mode: "cdc", Timestamp: &primitive.Timestamp{T: 10, I: 2}, Incremental: true},
+		},
+		{
+			name:        "snapshot_oplog_timestamp",
+			sdkPosition: opencdc.Position(`{"mode":"snapshot","oplogTimestamp":{"T":20,"I":1}}`),
+			want:        PositionInfo{Mode: "snapshot", Timestamp: &primitive.Timestamp{T: 20, I: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := InspectPosition(tt.sdkPosition)
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestInspectPosition_nil(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	_, err := InspectPosition(nil)
+	is.True(errors.Is(err, errNilSDKPosition))
+}
@@ -0,0 +1,126 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// KeyFormat defines the format of records' keys produced by the iterators.
+type KeyFormat string
+
+// The list of available key formats is listed below.
+const (
+	// KeyFormatStructured makes the iterators put keys into records as structured data.
+	// Keys don't carry schemas, so native date and time values are represented as RFC 3339 strings.
+	KeyFormatStructured KeyFormat = "structured"
+	// KeyFormatJSON makes the iterators put keys into records as canonical raw JSON objects,
+	// with fields sorted by their names.
+	KeyFormatJSON KeyFormat = "json"
+	// KeyFormatString makes the iterators put the value of the _id field into records' keys as a raw string.
+	// If the value is not a string, it's represented as canonical JSON.
+	// Keys without the _id field, e.g. the ones of _id-less collections, are represented as JSON objects.
+	KeyFormatString KeyFormat = "string"
+)
+
+// formatKey applies a key format to a record whose key is structured data,
+// converting its _id field according to the _id type first.
+// The buffers are used to serialize the key, a nil pool disables pooling.
+func formatKey(
+	record opencdc.Record, format KeyFormat, idType codec.IDType, buffers *codec.BufferPool,
+) (opencdc.Record, error) {
+	key, ok := record.Key.(opencdc.StructuredData)
+	if !ok {
+		return record, nil
+	}
+
+	if id, ok := key[idFieldName]; ok {
+		key[idFieldName] = idType.Convert(id)
+	}
+
+	switch format {
+	case KeyFormatStructured:
+		for field, value := range key {
+			key[field] = structuredKeyValue(value)
+		}
+
+		return record, nil
+
+	case KeyFormatJSON:
+		// maps are marshaled with sorted keys, so the same key always results in the same bytes
+		keyBytes, err := buffers.EncodeJSON(key)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("marshal key into json: %w", err)
+		}
+
+		record.Key = opencdc.RawData(keyBytes)
+
+		return record, nil
+
+	case KeyFormatString:
+		if _, ok := key[idFieldName]; !ok {
+			return formatKey(record, KeyFormatJSON, idType, buffers)
+		}
+
+		if id, ok := key[idFieldName].(string); ok {
+			record.Key = opencdc.RawData(id)
+
+			return record, nil
+		}
+
+		idBytes, err := buffers.EncodeJSON(key[idFieldName])
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("marshal key _id into json: %w", err)
+		}
+
+		record.Key = opencdc.RawData(idBytes)
+
+		return record, nil
+
+	default:
+		return record, nil
+	}
+}
+
+// structuredKeyValue replaces native date and time values of the key value, including nested ones,
+// with RFC 3339 strings, the same ones they're marshaled into JSON as. Documents and arrays are changed in place.
+func structuredKeyValue(value any) any {
+	switch value := value.(type) {
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+
+	case map[string]any:
+		for field, embedded := range value {
+			value[field] = structuredKeyValue(embedded)
+		}
+
+		return value
+
+	case []any:
+		for i, element := range value {
+			value[i] = structuredKeyValue(element)
+		}
+
+		return value
+
+	default:
+		return value
+	}
+}
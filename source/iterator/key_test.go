@@ -0,0 +1,113 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestFormatKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		key    opencdc.Data
+		format KeyFormat
+		idType codec.IDType
+		want   opencdc.Data
+	}{
+		{
+			name:   "structured",
+			key:    opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0"},
+			format: KeyFormatStructured,
+			want:   opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0"},
+		},
+		{
+			name: "structured_native_dates",
+			key: opencdc.StructuredData{
+				"_id":       map[string]any{"day": time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)},
+				"createdAt": []any{time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC)},
+			},
+			format: KeyFormatStructured,
+			want: opencdc.StructuredData{
+				"_id":       map[string]any{"day": "2026-01-02T00:00:00Z"},
+				"createdAt": []any{"2026-01-02T03:04:05.006Z"},
+			},
+		},
+		{
+			name:   "json_sorted_fields",
+			key:    opencdc.StructuredData{"tenant": "acme", "_id": int32(1)},
+			format: KeyFormatJSON,
+			want:   opencdc.RawData(`{"_id":1,"tenant":"acme"}`),
+		},
+		{
+			name:   "string_id",
+			key:    opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0", "tenant": "acme"},
+			format: KeyFormatString,
+			want:   opencdc.RawData("63bd5ee3ad5b1d4c6ad2b7e0"),
+		},
+		{
+			name:   "string_non_string_id",
+			key:    opencdc.StructuredData{"_id": int64(42)},
+			format: KeyFormatString,
+			want:   opencdc.RawData("42"),
+		},
+		{
+			name:   "string_without_id",
+			key:    opencdc.StructuredData{"seq": int64(7)},
+			format: KeyFormatString,
+			want:   opencdc.RawData(`{"seq":7}`),
+		},
+		{
+			name: "uuid_id",
+			key: opencdc.StructuredData{"_id": []byte{
+				0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+			}},
+			format: KeyFormatString,
+			idType: codec.IDTypeUUID,
+			want:   opencdc.RawData("123e4567-e89b-12d3-a456-426614174000"),
+		},
+		{
+			name:   "int_id",
+			key:    opencdc.StructuredData{"_id": int32(42)},
+			format: KeyFormatStructured,
+			idType: codec.IDTypeInt,
+			want:   opencdc.StructuredData{"_id": int64(42)},
+		},
+		{
+			name:   "raw_key_is_left_unchanged",
+			key:    opencdc.RawData("raw"),
+			format: KeyFormatJSON,
+			want:   opencdc.RawData("raw"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			record, err := formatKey(opencdc.Record{Key: tt.key}, tt.format, tt.idType, nil)
+			is.NoErr(err)
+			is.Equal(record.Key, tt.want)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// collectionOptionCapped is a name of a collection option that is true for capped collections.
+	collectionOptionCapped = "capped"
+	// collectionOptionClusteredIndex is a name of a collection option describing the clustered index
+	// of a clustered collection. Clustered collections are always clustered by the _id.
+	collectionOptionClusteredIndex = "clusteredIndex"
+	// collectionTypeView is a type of collection specifications describing views.
+	collectionTypeView = "view"
+)
+
+// isView checks whether the collection is a view, which can be read, but doesn't support Change Streams.
+func isView(ctx context.Context, collection *mongo.Collection) (bool, error) {
+	specs, err := collection.Database().ListCollectionSpecifications(ctx, bson.M{"name": collection.Name()})
+	if err != nil {
+		return false, fmt.Errorf("list collection specifications: %w", err)
+	}
+
+	return len(specs) != 0 && specs[0].Type == collectionTypeView, nil
+}
+
+// isIDLessCollection checks whether documents of the collection may lack the _id field, which is the case
+// for capped collections created without the _id index, like the oplog. Clustered collections store
+// documents in the order of their _id, whatever the type of its values, so they're never _id-less.
+func isIDLessCollection(ctx context.Context, collection *mongo.Collection) (bool, error) {
+	specs, err := collection.Database().ListCollectionSpecifications(ctx, bson.M{"name": collection.Name()})
+	if err != nil {
+		return false, fmt.Errorf("list collection specifications: %w", err)
+	}
+
+	// views and other types of collections are queried as they are
+	if len(specs) == 0 || specs[0].IDIndex != nil || specs[0].Options == nil {
+		return false, nil
+	}
+
+	if _, err := specs[0].Options.LookupErr(collectionOptionClusteredIndex); err == nil {
+		return false, nil
+	}
+
+	capped, ok := specs[0].Options.Lookup(collectionOptionCapped).BooleanOK()
+
+	return ok && capped, nil
+}
+
+// checkIDLessParams makes sure the snapshot of an _id-less collection doesn't rely on the _id,
+// which is missing in its documents. It returns whether the collection is _id-less.
+func checkIDLessParams(ctx context.Context, params snapshotParams, polling bool) (bool, error) {
+	idLess, err := isIDLessCollection(ctx, params.collection)
+	if err != nil {
+		return false, fmt.Errorf("check whether collection is _id-less: %w", err)
+	}
+
+	if !idLess {
+		return false, nil
+	}
+
+	if slices.Contains(params.orderingFields, idFieldName) {
+		return false, errIDLessOrdering
+	}
+
+	// polled updates and the id set deletion detection tell documents apart by their _id
+	if polling && (params.updatedAtField != "" || params.deleteStrategy == DeleteStrategyIDSet) {
+		return false, errIDLessPolling
+	}
+
+	return true, nil
+}
+
+// documentKey returns the key of the converted document. Documents of _id-less collections
+// are identified by the values of the ordering fields, as they're unique among returned documents.
+func documentKey(document map[string]any, idLess bool, orderingFields []string) opencdc.StructuredData {
+	if !idLess {
+		return opencdc.StructuredData{idFieldName: document[idFieldName]}
+	}
+
+	key := make(opencdc.StructuredData, len(orderingFields))
+	for _, field := range orderingFields {
+		key[field] = document[field]
+	}
+
+	return key
+}
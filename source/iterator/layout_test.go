@@ -0,0 +1,66 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDocumentKey(t *testing.T) {
+	t.Parallel()
+
+	document := map[string]any{"_id": "a", "ts": int64(5), "seq": int32(2), "name": "alice"}
+
+	tests := []struct {
+		name     string
+		snapshot *snapshot
+		want     opencdc.StructuredData
+	}{
+		{
+			name:     "id",
+			snapshot: &snapshot{orderingFields: []string{"ts"}},
+			want:     opencdc.StructuredData{"_id": "a"},
+		},
+		{
+			name:     "id_less",
+			snapshot: &snapshot{orderingFields: []string{"ts", "seq"}, idLess: true},
+			want:     opencdc.StructuredData{"ts": int64(5), "seq": int32(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(documentKey(document, tt.snapshot.idLess, tt.snapshot.orderingFields), tt.want)
+		})
+	}
+}
+
+func TestSnapshot_findOptions_idLess(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &snapshot{orderingFields: []string{"ts"}, batchSize: 10, idLess: true}
+
+	// documents of _id-less collections are never tie-broken by the _id
+	is.Equal(s.findOptions().Sort, bson.D{{Key: "ts", Value: 1}})
+}
@@ -0,0 +1,401 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CDCMode defines the way the iterators capture changes.
+type CDCMode string
+
+// The list of available CDC modes is listed below.
+const (
+	// CDCModeAuto makes the iterators use a Change Stream,
+	// falling back to tailing the oplog if the deployment doesn't support Change Streams.
+	CDCModeAuto CDCMode = "auto"
+	// CDCModeChangeStream makes the iterators use a Change Stream.
+	CDCModeChangeStream CDCMode = "changestream"
+	// CDCModeOplog makes the iterators tail the oplog directly.
+	CDCModeOplog CDCMode = "oplog"
+	// CDCModeTailable makes the iterators tail a capped collection with a tailable cursor, capturing inserts only.
+	CDCModeTailable CDCMode = "tailable"
+)
+
+const (
+	// oplogDatabaseName and oplogCollectionName are the names of the database and the collection
+	// the replica set oplog is stored in.
+	oplogDatabaseName   = "local"
+	oplogCollectionName = "oplog.rs"
+)
+
+// The supported oplog entry operations are listed below.
+const (
+	oplogOperationInsert = "i"
+	oplogOperationUpdate = "u"
+	oplogOperationDelete = "d"
+)
+
+// The MongoDB server error codes returned when a deployment doesn't support Change Streams are listed below.
+const (
+	// commandNotSupportedErrCode is returned by deployments that don't support the aggregation command options.
+	commandNotSupportedErrCode = 115
+	// unrecognizedPipelineStageErrCode is returned by MongoDB versions older than 3.6.
+	unrecognizedPipelineStageErrCode = 40324
+	// changeStreamReplicaSetOnlyErrCode is returned when Change Streams are not enabled, e.g. on MongoDB 3.6
+	// with the storage engine or the read concern that doesn't support them.
+	changeStreamReplicaSetOnlyErrCode = 40573
+)
+
+// oplogEntry defines an entry of the replica set oplog.
+// It consists of all fields sufficient to process inserts, updates, and deletes.
+type oplogEntry struct {
+	// Timestamp is the cluster time of the operation. It's used as the position.
+	Timestamp primitive.Timestamp `bson:"ts"`
+	// Operation is the type of the operation, i.e. "i", "u" or "d".
+	Operation string `bson:"op"`
+	// WallTime is the server date and time of the operation.
+	// It's not present in entries of MongoDB versions older than 3.6.
+	WallTime time.Time `bson:"wall"`
+	// Object is an inserted document, an update description or the _id of a deleted document.
+	Object bson.Raw `bson:"o"`
+	// Object2 contains the _id of an updated document.
+	Object2 bson.Raw `bson:"o2"`
+}
+
+// oplog implements a Change Data Capture iterator that tails the replica set oplog directly.
+// It's used for deployments where Change Streams are not available,
+// e.g. MongoDB versions older than 3.6 or ones where the changeStream action is restricted.
+// Updates carry full documents looked up at the time they're read, the same way a Change Stream does.
+type oplog struct {
+	collection *mongo.Collection
+	// oplogCollection is the collection the replica set oplog is stored in.
+	oplogCollection *mongo.Collection
+	cursor          *mongo.Cursor
+	payloadFormat   PayloadFormat
+	keyFormat       KeyFormat
+	converter       codec.Converter
+	buffers         *codec.BufferPool
+	payloadSchema   *payloadSchema
+	schemaDrift     *schemaDrift
+	// timestamp is the cluster time of the last read oplog entry.
+	timestamp primitive.Timestamp
+	// stopAt is a cluster time the tailing stops at, so entries after it aren't returned.
+	// If it's nil, the tailing is never stopped.
+	stopAt *primitive.Timestamp
+	// stopped defines if an entry past the stop time has been reached, so no more entries are returned.
+	stopped bool
+}
+
+// oplogParams is an incoming params for the [newOplog] function.
+type oplogParams struct {
+	collection    *mongo.Collection
+	position      *position
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+	// stopAt is a cluster time the tailing stops at. If it's nil, the tailing is never stopped.
+	stopAt *primitive.Timestamp
+}
+
+// newOplog creates a new instance of the [oplog] iterator. It starts after the oplog timestamp
+// of the position, or after the latest oplog entry if the position doesn't have the timestamp.
+func newOplog(ctx context.Context, params oplogParams) (*oplog, error) {
+	oplogCollection := params.collection.Database().Client().Database(oplogDatabaseName).Collection(oplogCollectionName)
+
+	tailer := &oplog{
+		collection:      params.collection,
+		oplogCollection: oplogCollection,
+		payloadFormat:   params.payloadFormat,
+		keyFormat:       params.keyFormat,
+		converter:       params.converter,
+		buffers:         params.buffers,
+		payloadSchema:   params.payloadSchema,
+		schemaDrift:     params.schemaDrift,
+		stopAt:          params.stopAt,
+	}
+
+	switch pos := params.position; {
+	case pos != nil && pos.OplogTimestamp != nil:
+		tailer.timestamp = *pos.OplogTimestamp
+
+	default:
+		timestamp, err := latestOplogTimestamp(ctx, oplogCollection)
+		if err != nil {
+			return nil, fmt.Errorf("get latest oplog timestamp: %w", err)
+		}
+
+		tailer.timestamp = timestamp
+	}
+
+	if err := tailer.tail(ctx); err != nil {
+		return nil, fmt.Errorf("tail oplog: %w", err)
+	}
+
+	return tailer, nil
+}
+
+// tail opens a tailable cursor returning oplog entries of the collection that occurred after the timestamp.
+func (o *oplog) tail(ctx context.Context) error {
+	filter := bson.M{
+		"ns": o.collection.Database().Name() + "." + o.collection.Name(),
+		"op": bson.M{"$in": []string{oplogOperationInsert, oplogOperationUpdate, oplogOperationDelete}},
+		"ts": bson.M{"$gt": o.timestamp},
+	}
+
+	opts := options.Find().SetCursorType(options.TailableAwait)
+
+	cursor, err := o.oplogCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("execute find: %w", err)
+	}
+
+	o.cursor = cursor
+
+	return nil
+}
+
+// hasNext checks whether the [oplog] iterator has records to return or not.
+// If the tailable cursor is closed by the server, it's reopened from the last read entry.
+func (o *oplog) hasNext(ctx context.Context) (bool, error) {
+	if o.stopped {
+		return false, nil
+	}
+
+	if o.cursor.TryNext(ctx) {
+		t, i, _ := o.cursor.Current.Lookup("ts").TimestampOK()
+		if o.stopAt != nil && (primitive.Timestamp{T: t, I: i}).After(*o.stopAt) {
+			sdk.Logger(ctx).Info().Msg("reached the cdc stop time, no more changes are captured")
+
+			o.stopped = true
+
+			return false, nil
+		}
+
+		return true, nil
+	}
+
+	if err := o.cursor.Err(); err != nil {
+		return false, fmt.Errorf("oplog cursor: %w", err)
+	}
+
+	if o.cursor.ID() == 0 {
+		// the cursor is already dead, so an error of closing it doesn't matter
+		_ = o.cursor.Close(ctx)
+
+		if err := o.tail(ctx); err != nil {
+			return false, fmt.Errorf("reopen oplog cursor: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// next returns the next record.
+func (o *oplog) next(ctx context.Context) (opencdc.Record, error) {
+	var entry oplogEntry
+	if err := o.cursor.Decode(&entry); err != nil {
+		return opencdc.Record{}, fmt.Errorf("decode oplog entry: %w", err)
+	}
+
+	// the full document of an update is looked up, as the entry only describes the changes
+	fullDocument := entry.Object
+	if entry.Operation == oplogOperationUpdate {
+		var err error
+		fullDocument, err = o.lookupDocument(ctx, entry.Object2)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("look up updated document: %w", err)
+		}
+	}
+
+	record, err := o.toRecord(entry, fullDocument)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert oplog entry to opencdc.Record: %w", err)
+	}
+
+	o.timestamp = entry.Timestamp
+
+	// keep the converted document for the payload schema, as formatting may replace the payload
+	document, _ := record.Payload.After.(opencdc.StructuredData)
+
+	db := o.collection.Database().Name()
+
+	record, err = formatRecord(record, o.payloadFormat, db, fullDocument, o.converter, o.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record payload: %w", err)
+	}
+
+	if o.payloadSchema != nil {
+		record = o.payloadSchema.attach(record, document)
+	}
+
+	record = o.schemaDrift.check(ctx, record, fullDocument)
+
+	record, err = formatKey(record, o.keyFormat, o.converter.IDType, o.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record key: %w", err)
+	}
+
+	return record, nil
+}
+
+// toRecord converts the oplog entry to an [opencdc.Record]. The full document is nil for deletes,
+// as well as for updates of documents that have been deleted before they're looked up.
+func (o *oplog) toRecord(entry oplogEntry, fullDocument bson.Raw) (opencdc.Record, error) {
+	timestamp := entry.Timestamp
+
+	position := &position{
+		Mode:           modeCDC,
+		OplogTimestamp: &timestamp,
+	}
+
+	sdkPosition, err := position.marshalSDKPosition(o.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal position into opencdc.Position: %w", err)
+	}
+
+	// entries of old MongoDB versions have no wall time, so the cluster time is used
+	createdAt := entry.WallTime
+	if createdAt.IsZero() {
+		createdAt = time.Unix(int64(entry.Timestamp.T), 0)
+	}
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = o.collection.Name()
+	metadata.SetCreatedAt(createdAt)
+
+	documentKey := entry.Object
+	if entry.Operation == oplogOperationUpdate {
+		documentKey = entry.Object2
+	}
+
+	key, err := o.converter.ConvertRaw(oplogDocumentKey(documentKey))
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document key: %w", err)
+	}
+
+	document, err := o.converter.ConvertRaw(fullDocument)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert full document: %w", err)
+	}
+
+	switch entry.Operation {
+	case oplogOperationInsert:
+		return sdk.Util.Source.NewRecordCreate(
+			sdkPosition, metadata, opencdc.StructuredData(key), opencdc.StructuredData(document),
+		), nil
+
+	case oplogOperationUpdate:
+		return sdk.Util.Source.NewRecordUpdate(
+			sdkPosition, metadata, opencdc.StructuredData(key), nil, opencdc.StructuredData(document),
+		), nil
+
+	case oplogOperationDelete:
+		return sdk.Util.Source.NewRecordDelete(
+			sdkPosition, metadata, opencdc.StructuredData(key), nil,
+		), nil
+
+	default:
+		// this shouldn't happen as we filter oplog entries by operation
+		return opencdc.Record{}, errUnsupportedOperationType
+	}
+}
+
+// lookupDocument returns the current version of the document with the provided key,
+// or nil if the document doesn't exist anymore.
+func (o *oplog) lookupDocument(ctx context.Context, documentKey bson.Raw) (bson.Raw, error) {
+	document, err := o.collection.FindOne(ctx, oplogDocumentKey(documentKey)).Raw()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil //nolint:nilnil // a deleted document is represented as nil
+		}
+
+		return nil, fmt.Errorf("find one: %w", err)
+	}
+
+	return document, nil
+}
+
+// stop stops the iterator.
+func (o *oplog) stop(ctx context.Context) error {
+	if o.cursor != nil {
+		if err := o.cursor.Close(ctx); err != nil {
+			return fmt.Errorf("close oplog cursor: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// currentTimestamp returns the cluster time the iterator has read the oplog up to.
+func (o *oplog) currentTimestamp() *primitive.Timestamp {
+	timestamp := o.timestamp
+
+	return &timestamp
+}
+
+// oplogDocumentKey returns a document containing only the _id of the provided document.
+func oplogDocumentKey(document bson.Raw) bson.Raw {
+	id, err := document.LookupErr(idFieldName)
+	if err != nil {
+		return document
+	}
+
+	key, err := bson.Marshal(bson.D{{Key: idFieldName, Value: id}})
+	if err != nil {
+		return document
+	}
+
+	return key
+}
+
+// latestOplogTimestamp returns the cluster time of the latest oplog entry.
+func latestOplogTimestamp(ctx context.Context, oplogCollection *mongo.Collection) (primitive.Timestamp, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "$natural", Value: -1}}).SetProjection(bson.M{"ts": 1})
+
+	var entry oplogEntry
+	if err := oplogCollection.FindOne(ctx, bson.M{}, opts).Decode(&entry); err != nil {
+		return primitive.Timestamp{}, fmt.Errorf("find latest oplog entry: %w", err)
+	}
+
+	return entry.Timestamp, nil
+}
+
+// isChangeStreamUnsupportedErr checks whether the provided error is returned by MongoDB
+// because the deployment doesn't support Change Streams.
+func isChangeStreamUnsupportedErr(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+
+	return serverErr.HasErrorCode(changeStreamReplicaSetOnlyErrCode) ||
+		serverErr.HasErrorCode(unrecognizedPipelineStageErrCode) ||
+		serverErr.HasErrorCode(commandNotSupportedErrCode)
+}
@@ -0,0 +1,84 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestOplogDocumentKey(t *testing.T) {
+	t.Parallel()
+
+	document, err := bson.Marshal(bson.D{{Key: "name", Value: "alice"}, {Key: idFieldName, Value: 1}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	want, err := bson.Marshal(bson.D{{Key: idFieldName, Value: 1}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	if got := oplogDocumentKey(document); !bytes.Equal(got, want) {
+		t.Errorf("oplogDocumentKey() = %v, want %v", got, bson.Raw(want))
+	}
+}
+
+func TestIsChangeStreamUnsupportedErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "replica_set_only",
+			err:  fmt.Errorf("watch: %w", mongo.CommandError{Code: changeStreamReplicaSetOnlyErrCode}),
+			want: true,
+		},
+		{
+			name: "unrecognized_pipeline_stage",
+			err:  mongo.CommandError{Code: unrecognizedPipelineStageErrCode},
+			want: true,
+		},
+		{
+			name: "history_lost",
+			err:  mongo.CommandError{Code: changeStreamHistoryLostErrCode},
+			want: false,
+		},
+		{
+			name: "not_a_server_error",
+			err:  errors.New("connection refused"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isChangeStreamUnsupportedErr(tt.err); got != tt.want {
+				t.Errorf("isChangeStreamUnsupportedErr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// orderingElement returns an element of a position made of the values of the ordering fields.
+// The element of a single ordering field is its value, and a tuple of the values otherwise.
+func orderingElement(values []any) any {
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return values
+}
+
+// orderingTuple returns the values of the ordering fields the element is made of.
+// It returns false if the element is nil or doesn't match the ordering fields.
+func orderingTuple(fields []string, element any) ([]any, bool) {
+	if element == nil {
+		return nil, false
+	}
+
+	if len(fields) == 1 {
+		return []any{element}, true
+	}
+
+	values, ok := element.([]any)
+	if !ok || len(values) != len(fields) {
+		return nil, false
+	}
+
+	return values, true
+}
+
+// tieBreaksOnID checks whether documents with equal values of the ordering fields
+// must be tie-broken by their _id, which is the case if the fields don't include the _id.
+func tieBreaksOnID(fields []string) bool {
+	return !slices.Contains(fields, idFieldName)
+}
+
+// tupleFilter returns a filter that compares the fields with the values lexicographically,
+// the same way the fields are sorted. The op compares all fields but the last one, which is compared
+// by the lastOp, e.g. "$gt" and "$gt" match greater tuples, "$lt" and "$lte" match less or equal ones.
+func tupleFilter(fields []string, values []any, op, lastOp string) bson.M {
+	if len(fields) == 1 {
+		return bson.M{fields[0]: bson.M{lastOp: values[0]}}
+	}
+
+	conditions := make(bson.A, 0, len(fields))
+	for i, field := range fields {
+		condition := make(bson.M, i+1)
+		for j := range i {
+			condition[fields[j]] = values[j]
+		}
+
+		fieldOp := op
+		if i == len(fields)-1 {
+			fieldOp = lastOp
+		}
+
+		condition[field] = bson.M{fieldOp: values[i]}
+		conditions = append(conditions, condition)
+	}
+
+	return bson.M{"$or": conditions}
+}
@@ -0,0 +1,187 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PartitionKeyMode defines the partition key the connector attaches to records' metadata,
+// so downstream systems can partition records by document and preserve their per-document ordering.
+type PartitionKeyMode string
+
+// The list of available partition key modes is listed below.
+const (
+	// PartitionKeyModeNone attaches no partition key.
+	PartitionKeyModeNone PartitionKeyMode = "none"
+	// PartitionKeyModeHash attaches a hash of the record key.
+	PartitionKeyModeHash PartitionKeyMode = "hash"
+	// PartitionKeyModeShard attaches the values of the shard key fields of the document,
+	// or a hash of the record key if the collection isn't sharded or the values are unknown.
+	PartitionKeyModeShard PartitionKeyMode = "shard"
+)
+
+// metadataFieldPartitionKey is a name of a record metadata field that holds the partition key of the document.
+const metadataFieldPartitionKey = "mongo.partitionKey"
+
+// PartitionKeyer attaches partition keys to records' metadata.
+type PartitionKeyer struct {
+	// shardKey is the list of the shard key fields. If it's empty, partition keys are hashes of record keys.
+	shardKey []string
+}
+
+// NewPartitionKeyer creates a new instance of the [PartitionKeyer] of records of the collection.
+// The shard key of the collection is read once, so the partition keys of its documents stay stable.
+// It returns nil if the mode is none.
+func NewPartitionKeyer(
+	ctx context.Context, collection *mongo.Collection, mode PartitionKeyMode,
+) (*PartitionKeyer, error) {
+	if mode != PartitionKeyModeHash && mode != PartitionKeyModeShard {
+		return nil, nil //nolint:nilnil // a nil keyer means no partition keys are attached
+	}
+
+	if mode == PartitionKeyModeHash {
+		return &PartitionKeyer{}, nil
+	}
+
+	metadata, err := getShardMetadata(ctx, collection)
+	if err != nil {
+		if errors.Is(err, errNotShardedCollection) {
+			sdk.Logger(ctx).Warn().Msg("collection is not sharded, partition keys are hashes of record keys")
+
+			return &PartitionKeyer{}, nil
+		}
+
+		return nil, fmt.Errorf("get shard key: %w", err)
+	}
+
+	shardKey := make([]string, len(metadata.Key))
+	for i, element := range metadata.Key {
+		shardKey[i] = element.Key
+	}
+
+	return &PartitionKeyer{shardKey: shardKey}, nil
+}
+
+// Apply puts the partition key of the record's document into its metadata.
+// Records that don't carry documents, like collection metadata records and heartbeats, are returned as they are.
+func (p *PartitionKeyer) Apply(record opencdc.Record) (opencdc.Record, error) {
+	if p == nil || record.Key == nil {
+		return record, nil
+	}
+
+	if _, ok := record.Metadata[codec.MetadataFieldRecordType]; ok {
+		return record, nil
+	}
+
+	partitionKey, ok, err := p.shardKeyValues(record)
+	if err != nil {
+		return opencdc.Record{}, err
+	}
+
+	if !ok {
+		partitionKey = hashKey(record.Key)
+	}
+
+	if record.Metadata == nil {
+		record.Metadata = make(opencdc.Metadata)
+	}
+
+	record.Metadata[metadataFieldPartitionKey] = partitionKey
+
+	return record, nil
+}
+
+// shardKeyValues returns a JSON array of the values of the shard key fields, looked up in the record key
+// and its structured payloads. It returns false if there's no shard key or any of its fields is missing.
+func (p *PartitionKeyer) shardKeyValues(record opencdc.Record) (string, bool, error) {
+	if len(p.shardKey) == 0 {
+		return "", false, nil
+	}
+
+	values := make([]any, len(p.shardKey))
+	for i, field := range p.shardKey {
+		value, ok := lookupRecordField(record, field)
+		if !ok {
+			return "", false, nil
+		}
+
+		values[i] = value
+	}
+
+	valuesBytes, err := json.Marshal(values)
+	if err != nil {
+		return "", false, fmt.Errorf("marshal shard key values: %w", err)
+	}
+
+	return string(valuesBytes), true, nil
+}
+
+// lookupRecordField returns the value of the dot-separated field of the record key or, if the key misses it,
+// of its structured payload after or before the change. It returns false if none of them has the field.
+func lookupRecordField(record opencdc.Record, field string) (any, bool) {
+	for _, data := range []opencdc.Data{record.Key, record.Payload.After, record.Payload.Before} {
+		document, ok := data.(opencdc.StructuredData)
+		if !ok {
+			continue
+		}
+
+		if value, ok := lookupField(document, field); ok {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// lookupField returns the value of the dot-separated field of the document, and false if there's no such field.
+func lookupField(document map[string]any, field string) (any, bool) {
+	var value any = document
+	for _, name := range strings.Split(field, ".") {
+		embedded, ok := value.(map[string]any)
+		if !ok {
+			if embedded, ok = value.(opencdc.StructuredData); !ok {
+				return nil, false
+			}
+		}
+
+		if value, ok = embedded[name]; !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// hashKey returns a hex-encoded FNV-1a hash of the record key.
+// Structured keys are marshaled into JSON with sorted fields, so the hash doesn't depend on their order.
+func hashKey(key opencdc.Data) string {
+	hash := fnv.New64a()
+	// writing into a hash never fails
+	_, _ = hash.Write(key.Bytes())
+
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
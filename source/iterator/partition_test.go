@@ -0,0 +1,104 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+func TestPartitionKeyer_Apply(t *testing.T) {
+	t.Parallel()
+
+	key := opencdc.StructuredData{"_id": "63bd5ee3ad5b1d4c6ad2b7e0"}
+	hash := hashKey(key)
+
+	tests := []struct {
+		name     string
+		shardKey []string
+		record   opencdc.Record
+		want     string
+	}{
+		{
+			name:   "hash",
+			record: opencdc.Record{Key: key},
+			want:   hash,
+		},
+		{
+			name:     "shard_key_from_payload",
+			shardKey: []string{"region", "address.zip"},
+			record: opencdc.Record{Key: key, Payload: opencdc.Change{After: opencdc.StructuredData{
+				"region": "eu", "address": map[string]any{"zip": int32(1010)},
+			}}},
+			want: `["eu",1010]`,
+		},
+		{
+			name:     "shard_key_from_key",
+			shardKey: []string{"region", "_id"},
+			record:   opencdc.Record{Key: opencdc.StructuredData{"region": "us", "_id": 7}},
+			want:     `["us",7]`,
+		},
+		{
+			name:     "missing_shard_key_field",
+			shardKey: []string{"region"},
+			record:   opencdc.Record{Key: key},
+			want:     hash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := (&PartitionKeyer{shardKey: tt.shardKey}).Apply(tt.record)
+			is.NoErr(err)
+			is.Equal(got.Metadata[metadataFieldPartitionKey], tt.want)
+		})
+	}
+}
+
+func TestPartitionKeyer_Apply_skipped(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	heartbeat := opencdc.Record{
+		Key:      opencdc.RawData("heartbeat"),
+		Metadata: opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeHeartbeat},
+	}
+
+	got, err := (&PartitionKeyer{}).Apply(heartbeat)
+	is.NoErr(err)
+	is.Equal(got, heartbeat)
+
+	got, err = (*PartitionKeyer)(nil).Apply(opencdc.Record{Key: opencdc.RawData("1")})
+	is.NoErr(err)
+	is.Equal(got.Metadata, nil)
+}
+
+func TestHashKey(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// structured keys are hashed regardless of the order their fields were set in
+	is.Equal(hashKey(opencdc.StructuredData{"a": 1, "b": 2}), hashKey(opencdc.StructuredData{"b": 2, "a": 1}))
+	is.True(hashKey(opencdc.RawData("1")) != hashKey(opencdc.RawData("2")))
+}
@@ -0,0 +1,136 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PayloadFormat defines the format of records' payloads produced by the iterators.
+type PayloadFormat string
+
+// The list of available payload formats is listed below.
+const (
+	// PayloadFormatJSON makes the iterators put documents into payloads as plain JSON.
+	PayloadFormatJSON PayloadFormat = "json"
+	// PayloadFormatExtendedJSON makes the iterators put documents into payloads
+	// as canonical Extended JSON, preserving BSON types like $oid, $date, $numberDecimal, etc.
+	PayloadFormatExtendedJSON PayloadFormat = "extjson"
+	// PayloadFormatDebezium makes the iterators wrap documents into envelopes
+	// compatible with the Debezium MongoDB connector.
+	PayloadFormatDebezium PayloadFormat = "debezium"
+)
+
+// metadataFieldBinarySubtypes is a name of a record metadata field that holds a JSON object
+// with the subtypes of the binary fields converted into base64 strings, by dot-separated paths of the fields.
+const metadataFieldBinarySubtypes = "mongo.binarySubtypes"
+
+// formatRecord applies a payload format to a record built from the provided raw document,
+// which is nil for delete operations. The db is the name of a database the document belongs to.
+// The converter defines the binary fields whose subtypes are recorded in the metadata of plain JSON payloads.
+// The buffers are used to serialize the payload, a nil pool disables pooling.
+func formatRecord(
+	record opencdc.Record, format PayloadFormat, db string, document bson.Raw,
+	converter codec.Converter, buffers *codec.BufferPool,
+) (opencdc.Record, error) {
+	switch format {
+	case PayloadFormatJSON:
+		return setBinarySubtypes(record, document, converter, buffers)
+
+	case PayloadFormatExtendedJSON:
+		if document == nil {
+			return record, nil
+		}
+
+		documentBytes, err := buffers.EncodeExtJSON(document, true)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("marshal document into extended json: %w", err)
+		}
+
+		record.Payload.After = opencdc.RawData(documentBytes)
+
+		return record, nil
+
+	case PayloadFormatDebezium:
+		return toDebeziumRecord(record, db, document, buffers)
+
+	default:
+		return record, nil
+	}
+}
+
+// setBinarySubtypes puts the subtypes of the document's binary fields converted into base64 strings
+// into the record metadata, so the destination connector can decode the fields back into binaries.
+func setBinarySubtypes(
+	record opencdc.Record, document bson.Raw, converter codec.Converter, buffers *codec.BufferPool,
+) (opencdc.Record, error) {
+	subtypes, err := converter.BinarySubtypes(document)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("collect binary subtypes: %w", err)
+	}
+
+	if len(subtypes) == 0 {
+		return record, nil
+	}
+
+	subtypesBytes, err := buffers.EncodeJSON(subtypes)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal binary subtypes: %w", err)
+	}
+
+	if record.Metadata == nil {
+		record.Metadata = make(opencdc.Metadata)
+	}
+
+	record.Metadata[metadataFieldBinarySubtypes] = string(subtypesBytes)
+
+	return record, nil
+}
+
+// EncodePayload marshals the structured payloads of a record in the plain JSON format into raw JSON,
+// unless a payload schema is attached to the record. Converted documents may hold values that
+// structured data can't carry through proto serialization as they are, e.g. dates or integers above 2^53.
+// The payloads are marshaled from the structured data, not the raw BSON, so BSON number types aren't preserved.
+// The buffers are used to serialize the payload, a nil pool disables pooling.
+func EncodePayload(record opencdc.Record, format PayloadFormat, buffers *codec.BufferPool) (opencdc.Record, error) {
+	if format != PayloadFormatJSON {
+		return record, nil
+	}
+
+	if _, err := record.Metadata.GetPayloadSchemaSubject(); !errors.Is(err, opencdc.ErrMetadataFieldNotFound) {
+		return record, nil
+	}
+
+	for _, data := range []*opencdc.Data{&record.Payload.Before, &record.Payload.After} {
+		document, ok := (*data).(opencdc.StructuredData)
+		if !ok {
+			continue
+		}
+
+		documentBytes, err := buffers.EncodeJSON(document)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("marshal document into json: %w", err)
+		}
+
+		*data = opencdc.RawData(documentBytes)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,135 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFormatRecord_extendedJSON(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	objectID := primitive.NewObjectID()
+	decimal, err := primitive.ParseDecimal128("10.25")
+	is.NoErr(err)
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: objectID},
+		{Key: "price", Value: decimal},
+		{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.UnixMilli(1700000000000))},
+	})
+	is.NoErr(err)
+
+	record := sdk.Util.Source.NewRecordSnapshot(
+		opencdc.Position("pos"), nil, opencdc.StructuredData{idFieldName: objectID.Hex()}, nil,
+	)
+
+	got, err := formatRecord(record, PayloadFormatExtendedJSON, "test", document, codec.Converter{}, codec.NewBufferPool())
+	is.NoErr(err)
+	is.Equal(string(got.Payload.After.Bytes()), `{"_id":{"$oid":"`+objectID.Hex()+`"},`+
+		`"price":{"$numberDecimal":"10.25"},"createdAt":{"$date":{"$numberLong":"1700000000000"}}}`)
+}
+
+func TestFormatRecord_json(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	record := sdk.Util.Source.NewRecordSnapshot(
+		opencdc.Position("pos"), nil, opencdc.StructuredData{idFieldName: "1"}, opencdc.RawData(`{"_id":"1"}`),
+	)
+
+	got, err := formatRecord(record, PayloadFormatJSON, "test", bson.Raw{}, codec.Converter{}, nil)
+	is.NoErr(err)
+	is.Equal(got, record)
+}
+
+func TestFormatRecord_binarySubtypes(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	document, err := bson.Marshal(bson.D{
+		{Key: "_id", Value: "1"},
+		{Key: "photo", Value: primitive.Binary{Subtype: bson.TypeBinaryUserDefined, Data: []byte("data")}},
+	})
+	is.NoErr(err)
+
+	record := sdk.Util.Source.NewRecordSnapshot(
+		opencdc.Position("pos"), nil, opencdc.StructuredData{idFieldName: "1"}, nil,
+	)
+
+	converter := codec.Converter{BinaryFormat: codec.BinaryFormatBase64}
+
+	got, err := formatRecord(record, PayloadFormatJSON, "test", document, converter, nil)
+	is.NoErr(err)
+	is.Equal(got.Metadata[metadataFieldBinarySubtypes], `{"photo":128}`)
+
+	// documents without binaries don't get the metadata field
+	document, err = bson.Marshal(bson.D{{Key: "_id", Value: "1"}})
+	is.NoErr(err)
+
+	got, err = formatRecord(sdk.Util.Source.NewRecordSnapshot(
+		opencdc.Position("pos"), nil, opencdc.StructuredData{idFieldName: "1"}, nil,
+	), PayloadFormatJSON, "test", document, converter, nil)
+	is.NoErr(err)
+
+	_, ok := got.Metadata[metadataFieldBinarySubtypes]
+	is.True(!ok)
+}
+
+func TestEncodePayload(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	record := sdk.Util.Source.NewRecordUpdate(
+		opencdc.Position("pos"),
+		nil,
+		opencdc.StructuredData{idFieldName: "1"},
+		opencdc.StructuredData{idFieldName: "1", "count": int64(9007199254740992)},
+		opencdc.StructuredData{idFieldName: "1", "count": int64(9007199254740993), "createdAt": createdAt},
+	)
+
+	got, err := EncodePayload(record, PayloadFormatJSON, codec.NewBufferPool())
+	is.NoErr(err)
+	is.Equal(got.Payload.Before, opencdc.RawData(`{"_id":"1","count":9007199254740992}`))
+	is.Equal(got.Payload.After, opencdc.RawData(`{"_id":"1","count":9007199254740993,"createdAt":"2026-01-02T03:04:05Z"}`))
+	is.Equal(got.Key, opencdc.StructuredData{idFieldName: "1"})
+
+	// other formats are left as they are
+	got, err = EncodePayload(record, PayloadFormatExtendedJSON, nil)
+	is.NoErr(err)
+	is.Equal(got, record)
+
+	// payloads with an attached schema are kept structured
+	record.Metadata.SetPayloadSchemaSubject("test.payload")
+
+	got, err = EncodePayload(record, PayloadFormatJSON, nil)
+	is.NoErr(err)
+	is.Equal(got, record)
+}
@@ -0,0 +1,252 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// initPollingUpdates sets the position's updated at value to the max value of the updated at field,
+// so only documents updated after polling starts are detected. It does nothing if the value is already set.
+func (s *snapshot) initPollingUpdates(ctx context.Context) error {
+	if s.updatedAtField == "" || s.position.UpdatedAt != nil {
+		return nil
+	}
+
+	updatedAt, err := getMaxFieldValue(ctx, s.collection, []string{s.updatedAtField})
+	if err != nil && !errors.Is(err, errNoDocuments) {
+		return fmt.Errorf("get updated at field max value: %w", err)
+	}
+
+	s.position.UpdatedAt = updatedAt
+
+	return nil
+}
+
+// initPollingDeletes prepares the snapshot to detect deleted documents with the configured strategy.
+func (s *snapshot) initPollingDeletes(ctx context.Context, params snapshotParams) error {
+	if params.deleteStrategy == DeleteStrategySoftDelete {
+		s.softDeleteField = params.softDeleteField
+	}
+
+	if params.deleteStrategy != DeleteStrategyIDSet {
+		return nil
+	}
+
+	// the first scan only collects the _ids of existing documents
+	s.idSet = newIDSet(params.deleteCheckInterval)
+	if err := s.idSet.scan(ctx, s.collection); err != nil {
+		return fmt.Errorf("scan _ids: %w", err)
+	}
+
+	return nil
+}
+
+// hasNextPolled checks whether there are updated or deleted documents to return,
+// once there are no new ones. Deleted documents are looked for only when there are no updated ones.
+func (s *snapshot) hasNextPolled(ctx context.Context) (bool, error) {
+	if s.updatedAtField != "" {
+		if err := s.loadUpdatesBatch(ctx); err != nil {
+			return false, fmt.Errorf("load updates batch: %w", err)
+		}
+
+		if s.tryNext(ctx) {
+			return true, nil
+		}
+
+		if err := s.cursor.Err(); err != nil {
+			return false, fmt.Errorf("read updates batch: %w", err)
+		}
+	}
+
+	if s.idSet == nil || !s.idSet.due() {
+		return false, nil
+	}
+
+	if err := s.idSet.scan(ctx, s.collection); err != nil {
+		return false, fmt.Errorf("scan _ids: %w", err)
+	}
+
+	return s.idSet.hasDeleted(), nil
+}
+
+// loadUpdatesBatch finds a batch of documents updated after the position's updated at value.
+// Only documents that have already been polled as inserted are looked for, as new ones are polled as inserts.
+func (s *snapshot) loadUpdatesBatch(ctx context.Context) error {
+	if err := s.cursor.Close(ctx); err != nil {
+		return fmt.Errorf("close cursor: %w", err)
+	}
+
+	// documents having the same updated at value are tie-broken by their _id
+	opts := options.Find().
+		SetSort(bson.D{{Key: s.updatedAtField, Value: 1}, {Key: idFieldName, Value: 1}}).
+		SetLimit(int64(s.batchSize))
+
+	cursor, err := s.collection.Find(ctx, s.updatesFilter(), opts)
+	if err != nil {
+		return fmt.Errorf("execute find: %w", err)
+	}
+
+	s.cursor = cursor
+	s.batchBytes = 0
+	s.pollingUpdates = true
+
+	return nil
+}
+
+// updatesFilter returns a filter of the next batch of updated documents.
+func (s *snapshot) updatesFilter() bson.M {
+	var conditions []bson.M
+
+	// documents that haven't been polled as inserted yet are left for the inserts batch
+	if values, ok := orderingTuple(s.orderingFields, s.position.Element); ok {
+		fields := s.orderingFields
+		if s.position.ElementID != nil {
+			fields = append(slices.Clone(fields), idFieldName)
+			values = append(slices.Clone(values), s.position.ElementID)
+		}
+
+		conditions = append(conditions, tupleFilter(fields, values, "$lt", "$lte"))
+	}
+
+	switch {
+	case s.position.UpdatedAt != nil && s.position.UpdatedID != nil:
+		conditions = append(conditions, tupleFilter(
+			[]string{s.updatedAtField, idFieldName}, []any{s.position.UpdatedAt, s.position.UpdatedID}, "$gt", "$gt",
+		))
+
+	case s.position.UpdatedAt != nil:
+		conditions = append(conditions, bson.M{s.updatedAtField: bson.M{"$gt": s.position.UpdatedAt}})
+
+	default:
+		// documents without the field can't be detected as updated
+		conditions = append(conditions, bson.M{s.updatedAtField: bson.M{"$exists": true}})
+	}
+
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+
+	return bson.M{"$and": conditions}
+}
+
+// setUpdatedAt sets the updated at value and the _id of the current document into the position,
+// if the document is polled as updated, or keeps the ones of the previous position otherwise.
+func (s *snapshot) setUpdatedAt(position *position) error {
+	if s.updatedAtField == "" {
+		return nil
+	}
+
+	if !s.pollingUpdates {
+		position.UpdatedAt = s.position.UpdatedAt
+		position.UpdatedID = s.position.UpdatedID
+
+		return nil
+	}
+
+	updatedAt, err := s.currentFieldValue(s.updatedAtField)
+	if err != nil {
+		return fmt.Errorf("get updated at value: %w", err)
+	}
+
+	updatedID, err := s.currentFieldValue(idFieldName)
+	if err != nil {
+		return fmt.Errorf("get _id value: %w", err)
+	}
+
+	position.UpdatedAt = updatedAt
+	position.UpdatedID = updatedID
+
+	return nil
+}
+
+// newPolledRecord creates a record of the current polled document, whose operation depends on
+// whether the document is new, updated or soft deleted.
+func (s *snapshot) newPolledRecord(
+	sdkPosition opencdc.Position, metadata opencdc.Metadata, document map[string]any,
+) opencdc.Record {
+	key := documentKey(document, s.idLess, s.orderingFields)
+
+	switch {
+	case s.softDeleted():
+		return sdk.Util.Source.NewRecordDelete(sdkPosition, metadata, key, nil)
+
+	case s.pollingUpdates:
+		return sdk.Util.Source.NewRecordUpdate(sdkPosition, metadata, key, nil, opencdc.StructuredData(document))
+
+	default:
+		return sdk.Util.Source.NewRecordCreate(sdkPosition, metadata, key, opencdc.StructuredData(document))
+	}
+}
+
+// softDeleted checks whether the current document is marked as deleted by the soft delete field.
+// The field marks the document if it's set to anything other than false or null, e.g. true or a date.
+func (s *snapshot) softDeleted() bool {
+	if s.softDeleteField == "" {
+		return false
+	}
+
+	value, err := s.cursor.Current.LookupErr(s.softDeleteField)
+	if err != nil || value.Type == bson.TypeNull || value.Type == bson.TypeUndefined {
+		return false
+	}
+
+	if value.Type == bson.TypeBoolean {
+		return value.Boolean()
+	}
+
+	return true
+}
+
+// nextDeleted returns a record of the next document detected as deleted by the _id set.
+// The position is not moved, as deleted documents are detected again by the next scan after a restart.
+func (s *snapshot) nextDeleted() (opencdc.Record, error) {
+	key, err := s.converter.ConvertRaw(s.idSet.nextDeleted())
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document key: %w", err)
+	}
+
+	sdkPosition, err := s.position.marshalSDKPosition(s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = s.collection.Name()
+	metadata.SetCreatedAt(time.Now())
+
+	record := sdk.Util.Source.NewRecordDelete(sdkPosition, metadata, opencdc.StructuredData(key), nil)
+
+	record, err = formatRecord(record, s.payloadFormat, s.collection.Database().Name(), nil, s.converter, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record payload: %w", err)
+	}
+
+	record, err = formatKey(record, s.keyFormat, s.converter.IDType, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record key: %w", err)
+	}
+
+	return record, nil
+}
@@ -0,0 +1,86 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSnapshot_updatesFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		snapshot *snapshot
+		want     bson.M
+	}{
+		{
+			name: "no_polled_documents",
+			snapshot: &snapshot{
+				orderingFields: []string{idFieldName},
+				updatedAtField: "updatedAt",
+				position:       &position{},
+			},
+			want: bson.M{"updatedAt": bson.M{"$exists": true}},
+		},
+		{
+			name: "no_updated_documents",
+			snapshot: &snapshot{
+				orderingFields: []string{idFieldName},
+				updatedAtField: "updatedAt",
+				position:       &position{Element: int32(10), UpdatedAt: int32(100)},
+			},
+			want: bson.M{"$and": []bson.M{
+				{idFieldName: bson.M{"$lte": int32(10)}},
+				{"updatedAt": bson.M{"$gt": int32(100)}},
+			}},
+		},
+		{
+			name: "updated_documents",
+			snapshot: &snapshot{
+				orderingFields: []string{"createdAt"},
+				updatedAtField: "updatedAt",
+				position: &position{
+					Element:   int32(10),
+					ElementID: "63bd5ee3ad5b1d4c6ad2b7e0",
+					UpdatedAt: int32(100),
+					UpdatedID: "63bd5ee3ad5b1d4c6ad2b7e1",
+				},
+			},
+			want: bson.M{"$and": []bson.M{
+				{"$or": bson.A{
+					bson.M{"createdAt": bson.M{"$lt": int32(10)}},
+					bson.M{"createdAt": int32(10), idFieldName: bson.M{"$lte": "63bd5ee3ad5b1d4c6ad2b7e0"}},
+				}},
+				{"$or": bson.A{
+					bson.M{"updatedAt": bson.M{"$gt": int32(100)}},
+					bson.M{"updatedAt": int32(100), idFieldName: bson.M{"$gt": "63bd5ee3ad5b1d4c6ad2b7e1"}},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(tt.snapshot.updatesFilter(), tt.want)
+		})
+	}
+}
@@ -15,11 +15,12 @@
 package iterator
 
 import (
-	"encoding/json"
 	"fmt"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio/conduit-commons/opencdc"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // positionMode defines the [position] mode.
@@ -40,18 +41,73 @@ type position struct {
 	// that allows resuming a Change Stream.
 	// This value is used if the mode is CDC.
 	ResumeToken bson.Raw `json:"resumeToken,omitempty"`
+	// OperationTime is a cluster time a Change Stream starts from
+	// if the position doesn't have a resume token, e.g. after restoring state from a backup.
+	// This value is used if the mode is CDC.
+	OperationTime *primitive.Timestamp `json:"operationTime,omitempty"`
+	// SnapshotTime is the cluster time a consistent snapshot reads all documents at,
+	// so it's read at the same point in time after a restart.
+	// This value is used if the mode is snapshot.
+	SnapshotTime *primitive.Timestamp `json:"snapshotTime,omitempty"`
+	// OplogTimestamp is the cluster time of the last processed oplog entry
+	// if changes are captured by tailing the oplog.
+	// This value is used if the mode is CDC, as well as by a snapshot followed by tailing the oplog.
+	OplogTimestamp *primitive.Timestamp `json:"oplogTimestamp,omitempty"`
 	// Element is a value of the last processed element by the snapshot capture.
 	// This value is used if the mode is snapshot.
 	Element any `json:"element,omitempty"`
+	// ElementID is the _id of the last processed element by the snapshot capture.
+	// It tie-breaks documents with equal values of a non-unique ordering field.
+	// This value is used if the mode is snapshot.
+	ElementID any `json:"elementId,omitempty"`
 	// MaxElement is a max value of an ordering field
 	// at the start of a snapshot.
 	// This value is used if the mode is snapshot.
 	MaxElement any `json:"maxElement,omitempty"`
+	// Bounds is the Element, ElementID and MaxElement encoded as a BSON document.
+	// Plain JSON loses their types, e.g. ObjectIDs and dates become strings,
+	// so resumed snapshots would compare documents with values of other types.
+	// This value is used if the mode is snapshot.
+	Bounds bson.Raw `json:"bounds,omitempty"`
+	// Emitted is the number of documents emitted by the snapshot capture so far,
+	// so its progress isn't reset after a restart.
+	// This value is used if the mode is snapshot.
+	Emitted int64 `json:"emitted,omitempty"`
+	// Sharded defines if the snapshot reads chunks of a sharded collection in parallel.
+	// This value is used if the mode is snapshot.
+	Sharded bool `json:"sharded,omitempty"`
+	// CompletedChunks is the list of identifiers of the chunks whose documents
+	// have all been emitted by a sharded snapshot.
+	// This value is used if the mode is snapshot and the snapshot is sharded.
+	CompletedChunks []uint64 `json:"completedChunks,omitempty"`
+	// SortStrategy is the sort strategy the snapshot fell back to after its sort exceeded the memory limit.
+	// It's empty if documents are sorted in memory.
+	SortStrategy sortStrategy `json:"sortStrategy,omitempty"`
+	// UpdatedAt is a value of the updated at field of the last document polled as updated,
+	// or its max value at the start of polling.
+	// This value is used if the mode is CDC and the snapshot polls for updates.
+	UpdatedAt any `json:"updatedAt,omitempty"`
+	// UpdatedID is the _id of the last document polled as updated.
+	// It tie-breaks documents with equal values of the updated at field.
+	// This value is used if the mode is CDC and the snapshot polls for updates.
+	UpdatedID any `json:"updatedId,omitempty"`
+	// TailID is the _id of the last document read by tailing a capped collection.
+	// It's stored in the Bounds only, as plain JSON loses its type.
+	// This value is used if the mode is CDC, as well as by a snapshot followed by tailing the collection.
+	TailID any `json:"-"`
+	// Incremental is a progress of an incremental snapshot taken along with CDC.
+	// This value is used if the mode is CDC.
+	Incremental *incrementalPosition `json:"incremental,omitempty"`
 }
 
 // marshalSDKPosition marshals the underlying [position] into a [opencdc.Position] as JSON bytes.
-func (p *position) marshalSDKPosition() (opencdc.Position, error) {
-	bytes, err := json.Marshal(p)
+// The buffers are used to serialize the position, a nil pool disables pooling.
+func (p *position) marshalSDKPosition(buffers *codec.BufferPool) (opencdc.Position, error) {
+	if err := p.setBounds(); err != nil {
+		return nil, err
+	}
+
+	bytes, err := buffers.EncodeJSON(p)
 	if err != nil {
 		return nil, fmt.Errorf("marshal position: %w", err)
 	}
@@ -59,16 +115,71 @@ func (p *position) marshalSDKPosition() (opencdc.Position, error) {
 	return bytes, nil
 }
 
+// withResumeToken returns a CDC position resuming after the resume token supplied by an operator,
+// unless the position already captures changes past it, so the token isn't replayed after every restart.
+// If the cluster time of the token or the position can't be decoded,
+// the token is applied only if the position has no resume token either.
+// It reports whether the token was applied.
+func withResumeToken(pos *position, resumeToken bson.Raw) (*position, bool) {
+	if resumeToken == nil {
+		return pos, false
+	}
+
+	if pos != nil {
+		tokenTime, tokenErr := resumeTokenTimestamp(resumeToken)
+		posTime, posErr := pos.timestamp()
+
+		switch {
+		case tokenErr != nil || posErr != nil:
+			if pos.ResumeToken != nil {
+				return pos, false
+			}
+
+		case posTime != nil && !posTime.Before(tokenTime):
+			return pos, false
+		}
+	}
+
+	return &position{
+		Mode:        modeCDC,
+		ResumeToken: resumeToken,
+	}, true
+}
+
 // parsePosition converts an [opencdc.Position] into a [position].
+// Integral values of its elements are decoded into 64-bit integers, so large IDs keep their precision.
 func parsePosition(sdkPosition opencdc.Position) (*position, error) {
 	if sdkPosition == nil {
 		return nil, errNilSDKPosition
 	}
 
 	var pos position
-	if err := json.Unmarshal(sdkPosition, &pos); err != nil {
+	if err := codec.UnmarshalJSON(sdkPosition, &pos); err != nil {
 		return nil, fmt.Errorf("unmarshal opencdc.Position into position: %w", err)
 	}
 
+	pos.decodeNumbers()
+
+	if err := pos.restoreBounds(); err != nil {
+		return nil, err
+	}
+
 	return &pos, nil
 }
+
+// decodeNumbers replaces the JSON numbers of the position's elements with 64-bit integers or floats.
+func (p *position) decodeNumbers() {
+	integers := codec.IntegerDecodingInt64
+
+	p.Element = integers.Decode(p.Element)
+	p.ElementID = integers.Decode(p.ElementID)
+	p.MaxElement = integers.Decode(p.MaxElement)
+	p.UpdatedAt = integers.Decode(p.UpdatedAt)
+	p.UpdatedID = integers.Decode(p.UpdatedID)
+
+	if p.Incremental != nil {
+		p.Incremental.Element = integers.Decode(p.Incremental.Element)
+		p.Incremental.ElementID = integers.Decode(p.Incremental.ElementID)
+		p.Incremental.MaxElement = integers.Decode(p.Incremental.MaxElement)
+	}
+}
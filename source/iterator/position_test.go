@@ -0,0 +1,110 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestWithResumeToken(t *testing.T) {
+	t.Parallel()
+
+	resumeToken, err := bson.Marshal(bson.D{{Key: resumeTokenDataField, Value: "826553F10000000003"}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	opaqueToken, err := bson.Marshal(bson.D{{Key: "_id", Value: int32(1)}})
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		position    *position
+		resumeToken bson.Raw
+		wantApplied bool
+	}{
+		{
+			name:        "no_token",
+			position:    &position{Mode: modeSnapshot},
+			wantApplied: false,
+		},
+		{
+			name:        "no_position",
+			resumeToken: resumeToken,
+			wantApplied: true,
+		},
+		{
+			name:        "snapshot_position",
+			position:    &position{Mode: modeSnapshot, Element: 5},
+			resumeToken: resumeToken,
+			wantApplied: true,
+		},
+		{
+			name:        "position_before_token",
+			position:    &position{Mode: modeCDC, OperationTime: &primitive.Timestamp{T: 10}},
+			resumeToken: resumeToken,
+			wantApplied: true,
+		},
+		{
+			name:        "position_past_token",
+			position:    &position{Mode: modeCDC, OperationTime: &primitive.Timestamp{T: 1800000000}},
+			resumeToken: resumeToken,
+			wantApplied: false,
+		},
+		{
+			name:        "position_at_token",
+			position:    &position{Mode: modeCDC, ResumeToken: resumeToken},
+			resumeToken: resumeToken,
+			wantApplied: false,
+		},
+		{
+			name:        "opaque_token_position_with_token",
+			position:    &position{Mode: modeCDC, ResumeToken: resumeToken},
+			resumeToken: opaqueToken,
+			wantApplied: false,
+		},
+		{
+			name:        "opaque_token_position_without_token",
+			position:    &position{Mode: modeSnapshot, Element: 5},
+			resumeToken: opaqueToken,
+			wantApplied: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, applied := withResumeToken(tt.position, tt.resumeToken)
+			is.Equal(applied, tt.wantApplied)
+
+			if !tt.wantApplied {
+				is.Equal(got, tt.position)
+
+				return
+			}
+
+			is.Equal(got, &position{Mode: modeCDC, ResumeToken: tt.resumeToken})
+		})
+	}
+}
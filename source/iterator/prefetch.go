@@ -0,0 +1,215 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"time"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// prefetchIdleBackoff is how long the prefetcher waits before reading the Change Stream again
+// if it had no events and no heartbeat was due.
+const prefetchIdleBackoff = 100 * time.Millisecond
+
+// changeReader reads records of change events.
+type changeReader interface {
+	hasNext(ctx context.Context) (bool, error)
+	next(ctx context.Context) (opencdc.Record, error)
+}
+
+// heartbeatReader reads records of change events and heartbeat records while the Change Stream is quiet.
+type heartbeatReader interface {
+	changeReader
+	hasHeartbeat() bool
+	nextHeartbeat() (opencdc.Record, error)
+}
+
+// prefetchResult is a record read by the prefetcher, or the error the Change Stream failed with.
+type prefetchResult struct {
+	record opencdc.Record
+	err    error
+}
+
+// prefetcher reads records of the CDC iterator in a background goroutine into a bounded buffer,
+// so the records are decoded while the previous ones are processed downstream.
+// Once it's started, the CDC iterator must not be used until the prefetcher is stopped.
+type prefetcher struct {
+	changes heartbeatReader
+	results chan prefetchResult
+	cancel  context.CancelFunc
+	done    chan struct{}
+	// pending is the record checked by hasNext, which is returned by next.
+	pending *opencdc.Record
+	// err is the error the Change Stream failed with, it's returned by all further calls.
+	err error
+	// leftover is a record that was read but not buffered, as the prefetcher was stopped meanwhile.
+	leftover *opencdc.Record
+}
+
+// startPrefetcher starts reading records of the CDC iterator into a buffer of the provided size.
+// The reads outlive the provided context, as they're stopped by the [prefetcher.stop] method.
+func startPrefetcher(ctx context.Context, changes heartbeatReader, size int) *prefetcher {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	p := &prefetcher{
+		changes: changes,
+		results: make(chan prefetchResult, size),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+
+	go p.run(ctx)
+
+	return p
+}
+
+// run reads records of the Change Stream, including heartbeats, until the context is canceled or the stream fails.
+func (p *prefetcher) run(ctx context.Context) {
+	defer close(p.done)
+
+	for {
+		record, ok, err := p.read(ctx)
+		if ctx.Err() != nil {
+			// the record may have been read before the prefetcher was stopped, so it's kept
+			if ok {
+				p.leftover = &record
+			}
+
+			return
+		}
+
+		if err != nil {
+			select {
+			case p.results <- prefetchResult{err: err}:
+			case <-ctx.Done():
+			}
+
+			return
+		}
+
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(prefetchIdleBackoff):
+			}
+
+			continue
+		}
+
+		select {
+		case p.results <- prefetchResult{record: record}:
+		case <-ctx.Done():
+			p.leftover = &record
+
+			return
+		}
+	}
+}
+
+// read reads the next record of the Change Stream, or a heartbeat record if it's quiet.
+func (p *prefetcher) read(ctx context.Context) (opencdc.Record, bool, error) {
+	hasNext, err := p.changes.hasNext(ctx)
+	if err != nil {
+		return opencdc.Record{}, false, err
+	}
+
+	if hasNext {
+		record, err := p.changes.next(ctx)
+
+		return record, err == nil, err
+	}
+
+	if p.changes.hasHeartbeat() {
+		record, err := p.changes.nextHeartbeat()
+
+		return record, err == nil, err
+	}
+
+	return opencdc.Record{}, false, nil
+}
+
+// hasNext checks whether a prefetched record is available. It doesn't wait for the Change Stream,
+// so it returns false if the buffer is empty.
+func (p *prefetcher) hasNext(ctx context.Context) (bool, error) {
+	if p.err != nil {
+		return false, p.err
+	}
+
+	if p.pending != nil {
+		return true, nil
+	}
+
+	select {
+	case result := <-p.results:
+		if result.err != nil {
+			p.err = result.err
+
+			return false, result.err
+		}
+
+		p.pending = &result.record
+
+		return true, nil
+
+	case <-ctx.Done():
+		return false, ctx.Err() //nolint:wrapcheck // the error is wrapped by the caller
+
+	default:
+		return false, nil
+	}
+}
+
+// next returns the prefetched record checked by hasNext.
+func (p *prefetcher) next(context.Context) (opencdc.Record, error) {
+	if p.pending == nil {
+		return opencdc.Record{}, errNoPrefetchedRecord
+	}
+
+	record := *p.pending
+	p.pending = nil
+
+	return record, nil
+}
+
+// stop stops the reads and returns the records that were read but not returned yet, in order.
+// The Change Stream may be interrupted in the middle of a read, so it must be recreated
+// before the CDC iterator is used directly again.
+func (p *prefetcher) stop() []opencdc.Record {
+	p.cancel()
+	<-p.done
+
+	var records []opencdc.Record
+	if p.pending != nil {
+		records = append(records, *p.pending)
+		p.pending = nil
+	}
+
+	for len(p.results) > 0 {
+		result := <-p.results
+		if result.err == nil {
+			records = append(records, result.record)
+		}
+	}
+
+	if p.leftover != nil {
+		records = append(records, *p.leftover)
+		p.leftover = nil
+	}
+
+	return records
+}
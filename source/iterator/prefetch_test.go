@@ -0,0 +1,159 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+)
+
+// newTestPrefetcher returns a stopped prefetcher whose buffer holds the provided results.
+func newTestPrefetcher(results ...prefetchResult) *prefetcher {
+	p := &prefetcher{
+		results: make(chan prefetchResult, len(results)),
+		cancel:  func() {},
+		done:    make(chan struct{}),
+	}
+
+	for _, result := range results {
+		p.results <- result
+	}
+
+	close(p.done)
+
+	return p
+}
+
+func TestPrefetcher_hasNext(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	first := opencdc.Record{Position: opencdc.Position("1")}
+	second := opencdc.Record{Position: opencdc.Position("2")}
+
+	p := newTestPrefetcher(prefetchResult{record: first}, prefetchResult{record: second})
+
+	// the checked record stays pending until it's returned
+	hasNext, err := p.hasNext(ctx)
+	is.NoErr(err)
+	is.True(hasNext)
+
+	hasNext, err = p.hasNext(ctx)
+	is.NoErr(err)
+	is.True(hasNext)
+
+	record, err := p.next(ctx)
+	is.NoErr(err)
+	is.Equal(record, first)
+
+	_, err = p.next(ctx)
+	is.Equal(err, errNoPrefetchedRecord)
+
+	hasNext, err = p.hasNext(ctx)
+	is.NoErr(err)
+	is.True(hasNext)
+
+	record, err = p.next(ctx)
+	is.NoErr(err)
+	is.Equal(record, second)
+
+	// an empty buffer doesn't block
+	hasNext, err = p.hasNext(ctx)
+	is.NoErr(err)
+	is.True(!hasNext)
+}
+
+func TestPrefetcher_hasNextErr(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	streamErr := errors.New("stream failed")
+
+	p := newTestPrefetcher(prefetchResult{err: streamErr})
+
+	_, err := p.hasNext(ctx)
+	is.Equal(err, streamErr)
+
+	// the error is sticky
+	_, err = p.hasNext(ctx)
+	is.Equal(err, streamErr)
+}
+
+func TestPrefetcher_stop(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	first := opencdc.Record{Position: opencdc.Position("1")}
+	second := opencdc.Record{Position: opencdc.Position("2")}
+	third := opencdc.Record{Position: opencdc.Position("3")}
+
+	p := newTestPrefetcher(prefetchResult{record: first}, prefetchResult{record: second})
+	p.leftover = &third
+
+	hasNext, err := p.hasNext(ctx)
+	is.NoErr(err)
+	is.True(hasNext)
+
+	// the pending, buffered and leftover records are returned in order
+	is.Equal(p.stop(), []opencdc.Record{first, second, third})
+}
+
+// stoppingReader returns a single record, whose read completes only once the prefetcher is stopped.
+type stoppingReader struct {
+	record opencdc.Record
+	read   bool
+}
+
+func (r *stoppingReader) hasNext(context.Context) (bool, error) {
+	return !r.read, nil
+}
+
+func (r *stoppingReader) next(ctx context.Context) (opencdc.Record, error) {
+	<-ctx.Done()
+	r.read = true
+
+	return r.record, nil
+}
+
+func (r *stoppingReader) hasHeartbeat() bool {
+	return false
+}
+
+func (r *stoppingReader) nextHeartbeat() (opencdc.Record, error) {
+	return opencdc.Record{}, nil
+}
+
+func TestPrefetcher_stopDuringRead(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	record := opencdc.Record{Position: opencdc.Position("1")}
+
+	p := startPrefetcher(context.Background(), &stoppingReader{record: record}, 1)
+
+	// the record read while the prefetcher is stopped isn't lost
+	is.Equal(p.stop(), []opencdc.Record{record})
+}
@@ -0,0 +1,66 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+)
+
+// The list of metadata fields describing a snapshot progress is listed below.
+const (
+	// metadataFieldSnapshotTotal is a metadata field that holds
+	// the estimated number of documents in the collection at the start of a snapshot.
+	metadataFieldSnapshotTotal = "mongo.snapshot.total"
+	// metadataFieldSnapshotEmitted is a metadata field that holds
+	// the number of documents the snapshot has emitted so far, including the current one.
+	metadataFieldSnapshotEmitted = "mongo.snapshot.emitted"
+	// metadataFieldSnapshotRemaining is a metadata field that holds
+	// the estimated number of documents the snapshot is yet to emit.
+	metadataFieldSnapshotRemaining = "mongo.snapshot.remaining"
+)
+
+// snapshotProgress tracks how far along a snapshot is.
+type snapshotProgress struct {
+	// total is the estimated number of documents in the collection at the start of the snapshot.
+	total int64
+	// emitted is the number of documents emitted by the snapshot, including ones emitted before a restart.
+	emitted int64
+}
+
+// remaining returns the estimated number of documents the snapshot is yet to emit.
+// As the total is an estimate, the remaining number never goes below zero.
+func (p snapshotProgress) remaining() int64 {
+	return max(p.total-p.emitted, 0)
+}
+
+// setMetadata puts the progress into the record metadata.
+func (p snapshotProgress) setMetadata(metadata opencdc.Metadata) {
+	metadata[metadataFieldSnapshotTotal] = strconv.FormatInt(p.total, 10)
+	metadata[metadataFieldSnapshotEmitted] = strconv.FormatInt(p.emitted, 10)
+	metadata[metadataFieldSnapshotRemaining] = strconv.FormatInt(p.remaining(), 10)
+}
+
+// log logs the progress at the debug level.
+func (p snapshotProgress) log(ctx context.Context) {
+	sdk.Logger(ctx).Debug().
+		Int64("total", p.total).
+		Int64("emitted", p.emitted).
+		Int64("remaining", p.remaining()).
+		Msg("snapshot progress")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+func TestSnapshotProgress_setMetadata(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		progress snapshotProgress
+		want     opencdc.Metadata
+	}{
+		{
+			name:     "in_progress",
+			progress: snapshotProgress{total: 10, emitted: 4},
+			want: opencdc.Metadata{
+				metadataFieldSnapshotTotal:     "10",
+				metadataFieldSnapshotEmitted:   "4",
+				metadataFieldSnapshotRemaining: "6",
+			},
+		},
+		{
+			name:     "emitted_more_than_estimated",
+			progress: snapshotProgress{total: 10, emitted: 12},
+			want: opencdc.Metadata{
+				metadataFieldSnapshotTotal:     "10",
+				metadataFieldSnapshotEmitted:   "12",
+				metadataFieldSnapshotRemaining: "0",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			metadata := make(opencdc.Metadata)
+			tt.progress.setMetadata(metadata)
+
+			if !reflect.DeepEqual(metadata, tt.want) {
+				t.Errorf("snapshotProgress.setMetadata() = %v, want %v", metadata, tt.want)
+			}
+		})
+	}
+}
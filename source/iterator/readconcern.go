@@ -0,0 +1,133 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	driversession "go.mongodb.org/mongo-driver/x/mongo/driver/session"
+)
+
+// ReadConcernLevel defines the consistency and isolation of the data the iterators read.
+type ReadConcernLevel string
+
+// The list of available read concern levels is listed below.
+const (
+	// ReadConcernLevelLocal makes the iterators read the most recent data of a node,
+	// which can be rolled back.
+	ReadConcernLevelLocal ReadConcernLevel = "local"
+	// ReadConcernLevelMajority makes the iterators read the data acknowledged by the majority of nodes.
+	ReadConcernLevelMajority ReadConcernLevel = "majority"
+	// ReadConcernLevelSnapshot makes the iterators read every snapshot batch from a single point in time.
+	// Change Streams don't support it, so they use the majority level instead.
+	ReadConcernLevelSnapshot ReadConcernLevel = "snapshot"
+)
+
+// withReadConcern returns the collection that reads data with the provided read concern level.
+// If the level is empty, the collection is returned as it is.
+func withReadConcern(collection *mongo.Collection, level ReadConcernLevel) *mongo.Collection {
+	if level == "" {
+		return collection
+	}
+
+	return collection.Database().Collection(
+		collection.Name(),
+		options.Collection().SetReadConcern(&readconcern.ReadConcern{Level: string(level)}),
+	)
+}
+
+// changeStreamReadConcernLevel returns the read concern level a Change Stream can use instead of the provided one.
+func changeStreamReadConcernLevel(level ReadConcernLevel) ReadConcernLevel {
+	if level == ReadConcernLevelSnapshot {
+		return ReadConcernLevelMajority
+	}
+
+	return level
+}
+
+// captureSnapshotTime returns the majority-committed cluster time the server serves a snapshot read
+// of the collection at, so all batches of a snapshot can be read at that point in time.
+func captureSnapshotTime(ctx context.Context, collection *mongo.Collection) (*primitive.Timestamp, error) {
+	session, err := collection.Database().Client().StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, fmt.Errorf("start snapshot session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	// the server picks the cluster time on the first read of a snapshot session and returns it with the result
+	err = collection.FindOne(mongo.NewSessionContext(ctx, session), bson.D{}).Err()
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, fmt.Errorf("execute find: %w", err)
+	}
+
+	clientSession := clientSessionOf(session)
+	if clientSession == nil || clientSession.SnapshotTime == nil {
+		return nil, errNoSnapshotTime
+	}
+
+	snapshotTime := *clientSession.SnapshotTime
+
+	return &snapshotTime, nil
+}
+
+// startSnapshotSession starts a session all reads of which are served at the provided cluster time.
+func startSnapshotSession(collection *mongo.Collection, snapshotTime *primitive.Timestamp) (mongo.Session, error) {
+	session, err := collection.Database().Client().StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, fmt.Errorf("start snapshot session: %w", err)
+	}
+
+	// the driver doesn't expose the cluster time of snapshot sessions,
+	// so it's set on the underlying session before the first read
+	clientSession := clientSessionOf(session)
+	if clientSession == nil {
+		session.EndSession(context.Background())
+
+		return nil, errNoSnapshotTime
+	}
+
+	snapshotTimeCopy := *snapshotTime
+	clientSession.SnapshotTime = &snapshotTimeCopy
+
+	return session, nil
+}
+
+// clientSessionOf returns the driver's underlying session of the provided session, or nil if it has none.
+func clientSessionOf(session mongo.Session) *driversession.Client {
+	//nolint:staticcheck // the underlying session is the only way to read and set the snapshot time
+	xSession, ok := session.(mongo.XSession)
+	if !ok {
+		return nil
+	}
+
+	return xSession.ClientSession()
+}
+
+// nextTimestamp returns the cluster time right after the provided one.
+func nextTimestamp(timestamp *primitive.Timestamp) *primitive.Timestamp {
+	if timestamp.I == math.MaxUint32 {
+		return &primitive.Timestamp{T: timestamp.T + 1}
+	}
+
+	return &primitive.Timestamp{T: timestamp.T, I: timestamp.I + 1}
+}
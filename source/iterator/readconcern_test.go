@@ -0,0 +1,90 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNextTimestamp(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal(nextTimestamp(&primitive.Timestamp{T: 10, I: 3}), &primitive.Timestamp{T: 10, I: 4})
+	is.Equal(nextTimestamp(&primitive.Timestamp{T: 10, I: math.MaxUint32}), &primitive.Timestamp{T: 11})
+}
+
+func TestInitSnapshotTime(t *testing.T) {
+	t.Parallel()
+
+	snapshotTime := &primitive.Timestamp{T: 10, I: 3}
+
+	tests := []struct {
+		name     string
+		params   CombinedParams
+		position *position
+		want     *primitive.Timestamp
+	}{
+		{
+			name:     "not_consistent",
+			params:   CombinedParams{Snapshot: true},
+			position: &position{Mode: modeSnapshot, SnapshotTime: snapshotTime},
+		},
+		{
+			name:     "no_snapshot",
+			params:   CombinedParams{SnapshotConsistent: true},
+			position: &position{Mode: modeSnapshot, SnapshotTime: snapshotTime},
+		},
+		{
+			name:     "resumed_snapshot",
+			params:   CombinedParams{Snapshot: true, SnapshotConsistent: true},
+			position: &position{Mode: modeSnapshot, SnapshotTime: snapshotTime},
+			want:     snapshotTime,
+		},
+		{
+			name:     "completed_snapshot",
+			params:   CombinedParams{Snapshot: true, SnapshotConsistent: true},
+			position: &position{Mode: modeCDC, OperationTime: nextTimestamp(snapshotTime)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			got, err := initSnapshotTime(context.Background(), tt.params, nil, tt.position)
+			is.NoErr(err)
+			is.Equal(got, tt.want)
+		})
+	}
+}
+
+func TestSnapshot_cdcStartTime(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	is.Equal((&snapshot{}).cdcStartTime(), nil)
+	is.Equal((&snapshot{snapshotTime: &primitive.Timestamp{T: 10, I: 3}}).cdcStartTime(),
+		&primitive.Timestamp{T: 10, I: 4})
+}
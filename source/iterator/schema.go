@@ -0,0 +1,321 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/conduitio/conduit-commons/schema/avro"
+	"github.com/conduitio/conduit-connector-sdk/schema"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SchemaMode defines how the connector generates a payload schema of a collection.
+type SchemaMode string
+
+// The list of available schema modes is listed below.
+const (
+	// SchemaModeNone disables generating a payload schema.
+	SchemaModeNone SchemaMode = "none"
+	// SchemaModeSample generates a payload schema by sampling collection documents.
+	SchemaModeSample SchemaMode = "sample"
+	// SchemaModeValidator generates a payload schema from the collection's $jsonSchema validator.
+	SchemaModeValidator SchemaMode = "validator"
+)
+
+const (
+	// payloadSchemaSubjectSuffix is a suffix of the payload schema subject,
+	// which is prefixed with a collection name the same way the SDK does.
+	payloadSchemaSubjectSuffix = "payload"
+	// validatorFieldName is a name of the collection option that contains a document validator.
+	validatorFieldName = "validator"
+	// jsonSchemaFieldName is a name of the validator operator that contains a JSON Schema.
+	jsonSchemaFieldName = "$jsonSchema"
+	// decimalZeroHighBits are the high bits of a zero decimal with the zero exponent.
+	decimalZeroHighBits = 0x3040000000000000
+)
+
+// errNoJSONSchema occurs when a collection has no $jsonSchema validator to generate a schema from.
+var errNoJSONSchema = errors.New("collection has no $jsonSchema validator")
+
+// payloadSchema is a registered schema all record payloads of a collection conform to.
+type payloadSchema struct {
+	schema schema.Schema
+	// fields are names of the top-level fields described by the schema.
+	fields []string
+}
+
+// payloadSchemaParams is an incoming params for the [newPayloadSchema] function.
+type payloadSchemaParams struct {
+	collection *mongo.Collection
+	mode       SchemaMode
+	sampleSize int
+	converter  codec.Converter
+}
+
+// newPayloadSchema generates an Avro payload schema of the collection and registers it.
+func newPayloadSchema(ctx context.Context, params payloadSchemaParams) (*payloadSchema, error) {
+	var (
+		document opencdc.StructuredData
+		err      error
+	)
+
+	switch params.mode {
+	case SchemaModeSample:
+		document, err = sampleDocument(ctx, params.collection, params.sampleSize, params.converter)
+	case SchemaModeValidator:
+		document, err = validatorDocument(ctx, params.collection, params.converter)
+	case SchemaModeNone:
+		fallthrough
+	default:
+		return nil, fmt.Errorf("unsupported schema mode %q", params.mode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("build %s document: %w", params.mode, err)
+	}
+
+	return registerPayloadSchema(ctx, params.collection.Name()+"."+payloadSchemaSubjectSuffix, document)
+}
+
+// registerPayloadSchema extracts an Avro schema from the document and registers it under the subject.
+func registerPayloadSchema(
+	ctx context.Context, subject string, document opencdc.StructuredData,
+) (*payloadSchema, error) {
+	fields := make([]string, 0, len(document))
+	for field, value := range document {
+		fields = append(fields, field)
+
+		// documents can miss any field, so all the top-level fields are nullable
+		document[field] = &value
+	}
+
+	serde, err := avro.SerdeForType(document)
+	if err != nil {
+		return nil, fmt.Errorf("extract avro schema: %w", err)
+	}
+
+	sch, err := schema.Create(ctx, schema.TypeAvro, subject, []byte(serde.String()))
+	if err != nil {
+		return nil, fmt.Errorf("create schema: %w", err)
+	}
+
+	return &payloadSchema{
+		schema: sch,
+		fields: fields,
+	}, nil
+}
+
+// attach replaces the raw payload of the record with the provided document as structured data,
+// so the SDK encodes it with the schema, and attaches the schema to the record metadata.
+// Fields missing in the document are set to nil, as the schema requires them to be present.
+func (p *payloadSchema) attach(record opencdc.Record, document map[string]any) opencdc.Record {
+	if document == nil {
+		return record
+	}
+
+	payload := make(opencdc.StructuredData, len(p.fields))
+	for _, field := range p.fields {
+		payload[field] = nil
+	}
+
+	for field, value := range document {
+		payload[field] = value
+	}
+
+	record.Payload.After = payload
+	schema.AttachPayloadSchemaToRecord(record, p.schema)
+
+	return record
+}
+
+// sampleDocument samples documents of the collection and merges them into a single document
+// that contains all their fields.
+func sampleDocument(
+	ctx context.Context, collection *mongo.Collection, sampleSize int, converter codec.Converter,
+) (opencdc.StructuredData, error) {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sample", Value: bson.D{{Key: "size", Value: sampleSize}}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("execute aggregate: %w", err)
+	}
+
+	var documents []map[string]any
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, fmt.Errorf("decode sampled documents: %w", err)
+	}
+
+	if len(documents) == 0 {
+		return nil, errNoDocuments
+	}
+
+	merged := make(map[string]any)
+	for _, document := range documents {
+		mergeSampledDocuments(merged, converter.ConvertDocument(document))
+	}
+
+	return merged, nil
+}
+
+// mergeSampledDocuments merges the fields of the document into the existing one.
+func mergeSampledDocuments(existing, document map[string]any) {
+	for field, value := range document {
+		existing[field] = mergeSampledValues(existing[field], value)
+	}
+}
+
+// mergeSampledValues merges two sampled values of the same field. Documents are merged field by field
+// and array elements are concatenated, so the schema covers all of them.
+// In other cases, the first non-nil value wins.
+func mergeSampledValues(existing, value any) any {
+	switch existing := existing.(type) {
+	case nil:
+		return value
+
+	case map[string]any:
+		if document, ok := value.(map[string]any); ok {
+			mergeSampledDocuments(existing, document)
+		}
+
+		return existing
+
+	case []any:
+		array, ok := value.([]any)
+		if !ok {
+			return existing
+		}
+
+		return append(existing, array...)
+
+	default:
+		return existing
+	}
+}
+
+// jsonSchema is a subset of the MongoDB $jsonSchema keywords that describe types.
+type jsonSchema struct {
+	BSONType   bson.RawValue         `bson:"bsonType"`
+	Type       bson.RawValue         `bson:"type"`
+	Properties map[string]jsonSchema `bson:"properties"`
+	Items      bson.RawValue         `bson:"items"`
+}
+
+// validatorDocument builds a document from the $jsonSchema validator of the collection,
+// where each field holds a value of the type the validator defines for it.
+func validatorDocument(
+	ctx context.Context, collection *mongo.Collection, converter codec.Converter,
+) (opencdc.StructuredData, error) {
+	specifications, err := collection.Database().ListCollectionSpecifications(ctx, bson.D{
+		{Key: "name", Value: collection.Name()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list collection specifications: %w", err)
+	}
+
+	if len(specifications) == 0 || specifications[0].Options == nil {
+		return nil, errNoJSONSchema
+	}
+
+	rawJSONSchema, err := specifications[0].Options.LookupErr(validatorFieldName, jsonSchemaFieldName)
+	if err != nil {
+		return nil, errNoJSONSchema
+	}
+
+	var js jsonSchema
+	if err := rawJSONSchema.Unmarshal(&js); err != nil {
+		return nil, fmt.Errorf("unmarshal $jsonSchema: %w", err)
+	}
+
+	document := make(opencdc.StructuredData, len(js.Properties))
+	for field, property := range js.Properties {
+		document[field] = property.value(converter)
+	}
+
+	return document, nil
+}
+
+// value returns a value of the type the JSON Schema defines, converted with the converter.
+// It returns nil if the type is unknown, so the field is represented as a nullable string.
+func (js jsonSchema) value(converter codec.Converter) any {
+	switch js.typeName() {
+	case "string", "objectId", "regex", "javascript", "symbol":
+		return ""
+	case "int":
+		return int32(0)
+	case "long", "integer":
+		return int64(0)
+	case "double", "number":
+		return float64(0)
+	case "bool", "boolean":
+		return false
+	case "date":
+		return converter.Convert(primitive.DateTime(0))
+	case "decimal":
+		return converter.Convert(primitive.NewDecimal128(decimalZeroHighBits, 0))
+	case "binData":
+		return []byte{}
+	case "object":
+		document := make(map[string]any, len(js.Properties))
+		for field, property := range js.Properties {
+			document[field] = property.value(converter)
+		}
+
+		return document
+	case "array":
+		var items jsonSchema
+		if js.Items.Type != bson.TypeEmbeddedDocument || js.Items.Unmarshal(&items) != nil {
+			return []any{}
+		}
+
+		return []any{items.value(converter)}
+	default:
+		return nil
+	}
+}
+
+// typeName returns the first non-null type name of the JSON Schema.
+// The bsonType keyword takes precedence over the type keyword,
+// and both of them can be either a string or an array of strings.
+func (js jsonSchema) typeName() string {
+	for _, typeValue := range []bson.RawValue{js.BSONType, js.Type} {
+		if name, ok := typeValue.StringValueOK(); ok {
+			return name
+		}
+
+		names, ok := typeValue.ArrayOK()
+		if !ok {
+			continue
+		}
+
+		values, err := names.Values()
+		if err != nil {
+			continue
+		}
+
+		for _, value := range values {
+			if name, ok := value.StringValueOK(); ok && name != "null" {
+				return name
+			}
+		}
+	}
+
+	return ""
+}
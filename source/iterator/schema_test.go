@@ -0,0 +1,139 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestMergeSampledValues(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	merged := make(map[string]any)
+	mergeSampledDocuments(merged, map[string]any{
+		"name":    "Bob",
+		"age":     nil,
+		"address": map[string]any{"city": "Kyiv"},
+		"tags":    []any{"a"},
+	})
+	mergeSampledDocuments(merged, map[string]any{
+		"name":    "Alice",
+		"age":     int32(30),
+		"address": map[string]any{"zip": "01001"},
+		"tags":    []any{int32(1)},
+		"email":   "alice@example.com",
+	})
+
+	is.Equal(merged, map[string]any{
+		"name":    "Bob",
+		"age":     int32(30),
+		"address": map[string]any{"city": "Kyiv", "zip": "01001"},
+		"tags":    []any{"a", int32(1)},
+		"email":   "alice@example.com",
+	})
+}
+
+func TestJSONSchema_value(t *testing.T) {
+	t.Parallel()
+
+	var js jsonSchema
+	err := bson.UnmarshalExtJSON([]byte(`{
+		"bsonType": "object",
+		"properties": {
+			"name": {"bsonType": "string"},
+			"age": {"bsonType": ["int", "null"]},
+			"balance": {"bsonType": "decimal"},
+			"createdAt": {"bsonType": "date"},
+			"active": {"type": "boolean"},
+			"tags": {"bsonType": "array", "items": {"bsonType": "string"}},
+			"address": {"bsonType": "object", "properties": {"zip": {"bsonType": "long"}}},
+			"unknown": {}
+		}
+	}`), false, &js)
+	if err != nil {
+		t.Fatalf("unmarshal json schema: %v", err)
+	}
+
+	got := js.value(codec.Converter{
+		DateTimeFormat: codec.DateTimeFormatMillis,
+		DecimalFormat:  codec.DecimalFormatString,
+	})
+
+	want := map[string]any{
+		"name":      "",
+		"age":       int32(0),
+		"balance":   "0",
+		"createdAt": int64(0),
+		"active":    false,
+		"tags":      []any{""},
+		"address":   map[string]any{"zip": int64(0)},
+		"unknown":   nil,
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("jsonSchema.value() = %v, want %v", got, want)
+	}
+}
+
+func TestPayloadSchema_attach(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+	ctx := context.Background()
+
+	ps, err := registerPayloadSchema(ctx, "test_attach.payload", opencdc.StructuredData{
+		"_id":   "63bd5ee3ad5b1d4c6ad2b7e0",
+		"name":  "Bob",
+		"age":   int32(30),
+		"email": nil,
+	})
+	is.NoErr(err)
+
+	record := ps.attach(opencdc.Record{
+		Metadata: opencdc.Metadata{},
+		Payload:  opencdc.Change{After: opencdc.RawData(`{}`)},
+	}, map[string]any{
+		"_id":  "63bd5ee3ad5b1d4c6ad2b7e1",
+		"name": "Alice",
+	})
+
+	// the fields missing in the document must be set to nil
+	is.Equal(record.Payload.After, opencdc.StructuredData{
+		"_id":   "63bd5ee3ad5b1d4c6ad2b7e1",
+		"name":  "Alice",
+		"age":   nil,
+		"email": nil,
+	})
+
+	subject, err := record.Metadata.GetPayloadSchemaSubject()
+	is.NoErr(err)
+	is.Equal(subject, ps.schema.Subject)
+
+	// the payload must be encodable with the attached schema
+	serde, err := ps.schema.Serde()
+	is.NoErr(err)
+
+	_, err = serde.Marshal(record.Payload.After)
+	is.NoErr(err)
+}
@@ -0,0 +1,512 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/time/rate"
+)
+
+// shardChunk is a range of shard key values owned by a single shard, as described in the config.chunks collection.
+type shardChunk struct {
+	Min   bson.Raw `bson:"min"`
+	Max   bson.Raw `bson:"max"`
+	Shard string   `bson:"shard"`
+}
+
+// id returns an identifier of the chunk derived from its bounds, so a chunk that's split or merged
+// after a restart doesn't match the identifiers of completed chunks and is read once again.
+func (c shardChunk) id() uint64 {
+	hash := fnv.New64a()
+	// writes to a hash never fail
+	_, _ = hash.Write(c.Min)
+	_, _ = hash.Write(c.Max)
+
+	return hash.Sum64()
+}
+
+// shardedDocument is a document read from a chunk, or a marker of the chunk having been completely read.
+type shardedDocument struct {
+	chunk    uint64
+	document bson.Raw
+	done     bool
+	err      error
+}
+
+// shardedSnapshot is a snapshot iterator of a sharded collection. It splits the collection by its chunks
+// and reads them in parallel, each one from the shard owning it, so mongos doesn't merge sorted results
+// of all shards. Documents aren't ordered, so the position keeps the chunks whose documents have all been
+// emitted, and a resumed snapshot reads the rest of the chunks from their beginning.
+type shardedSnapshot struct {
+	collection    *mongo.Collection
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	resumeToken   bson.Raw
+	// oplogTimestamp is the same as the resumeToken, but for the oplog iterator.
+	oplogTimestamp *primitive.Timestamp
+	// tailID is the same as the resumeToken, but for the tailable iterator.
+	tailID any
+	// snapshotTime is the cluster time all chunks are read at. If it's not nil, the Change Stream
+	// starts right after it.
+	snapshotTime *primitive.Timestamp
+	// causal is the cluster and operation time of the causally consistent session the Change Stream
+	// was created in, the sessions of the chunk readers are advanced to, if it's set.
+	causal        *causalTime
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+	progress      snapshotProgress
+	// collectionMetadataPending defines if the snapshot must return
+	// a record describing the collection structure before any document.
+	collectionMetadataPending bool
+	// completed is the list of identifiers of the chunks whose documents have all been emitted.
+	completed []uint64
+	// documents receives documents from the chunk readers, it's closed once all of them are done.
+	documents chan shardedDocument
+	current   bson.Raw
+	cancel    context.CancelFunc
+	// checkpoints limits how often the positions of records are marshaled.
+	checkpoints checkpointer
+	// throttle limits the number of documents the readers read per second. If it's nil, it's not limited.
+	throttle *rate.Limiter
+}
+
+// causalTime is the cluster and operation time of a causally consistent session.
+type causalTime struct {
+	clusterTime   bson.Raw
+	operationTime *primitive.Timestamp
+}
+
+// newShardedSnapshot creates a new instance of the [shardedSnapshot] iterator that reads chunks
+// with the provided number of parallel readers. It returns errNotShardedCollection if the collection isn't sharded.
+func newShardedSnapshot(ctx context.Context, params snapshotParams, parallelism int) (*shardedSnapshot, error) {
+	shardKey, chunks, err := getShardChunks(ctx, params.collection)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &shardedSnapshot{
+		collection:     params.collection,
+		payloadFormat:  params.payloadFormat,
+		keyFormat:      params.keyFormat,
+		converter:      params.converter,
+		buffers:        params.buffers,
+		resumeToken:    params.resumeToken,
+		oplogTimestamp: params.oplogTimestamp,
+		tailID:         params.tailID,
+		snapshotTime:   params.snapshotTime,
+		payloadSchema:  params.payloadSchema,
+		schemaDrift:    params.schemaDrift,
+		// the record is returned only once, at the very start of the snapshot
+		collectionMetadataPending: params.collectionMetadata && params.position == nil,
+		checkpoints:               checkpointer{records: params.checkpointRecords, interval: params.checkpointInterval},
+		throttle:                  params.throttle,
+	}
+
+	// the times are read before the readers start, as the session may be used by the Change Stream meanwhile
+	if params.causalSession != nil {
+		s.causal = &causalTime{
+			clusterTime:   params.causalSession.ClusterTime(),
+			operationTime: params.causalSession.OperationTime(),
+		}
+	}
+
+	if params.position != nil {
+		s.completed = params.position.CompletedChunks
+		s.progress.emitted = params.position.Emitted
+	}
+
+	// the estimate is read from the collection metadata, so it doesn't scan the collection
+	s.progress.total, err = params.collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("estimate collection document count: %w", err)
+	}
+
+	pending := slices.DeleteFunc(chunks, func(chunk shardChunk) bool {
+		return slices.Contains(s.completed, chunk.id())
+	})
+
+	sdk.Logger(ctx).Info().
+		Int("chunks", len(pending)).
+		Int("completedChunks", len(chunks)-len(pending)).
+		Msg("starting sharded snapshot")
+
+	// the readers outlive the context the iterator is created with, they're stopped along with the iterator
+	readersCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
+	s.cancel = cancel
+	s.documents = make(chan shardedDocument, params.batchSize)
+	s.startReaders(readersCtx, shardKey, pending, params.batchSize, parallelism)
+
+	return s, nil
+}
+
+// startReaders starts the readers of the chunks. The documents channel is closed once all of them are done.
+func (s *shardedSnapshot) startReaders(
+	ctx context.Context, shardKey bson.D, chunks []shardChunk, batchSize, parallelism int,
+) {
+	queue := make(chan shardChunk, len(chunks))
+	for _, chunk := range chunks {
+		queue <- chunk
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for range min(parallelism, len(chunks)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			readCtx, session, err := s.startReaderSession(ctx)
+			if err != nil {
+				select {
+				case s.documents <- shardedDocument{err: err}:
+				case <-ctx.Done():
+				}
+
+				return
+			}
+
+			if session != nil {
+				defer session.EndSession(context.WithoutCancel(ctx))
+			}
+
+			for chunk := range queue {
+				if err := s.readChunk(readCtx, shardKey, chunk, batchSize); err != nil {
+					select {
+					case s.documents <- shardedDocument{err: fmt.Errorf("read chunk of shard %q: %w", chunk.Shard, err)}:
+					case <-ctx.Done():
+					}
+
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(s.documents)
+	}()
+}
+
+// startReaderSession starts the session a chunk reader reads documents in and returns its context.
+// Sessions can't be used concurrently, so each reader gets its own one, either read at the snapshot time,
+// or advanced to the causally consistent session, so the chunks observe every write the Change Stream starts after.
+// It returns a nil session if there's neither of them.
+func (s *shardedSnapshot) startReaderSession(ctx context.Context) (context.Context, mongo.Session, error) {
+	switch {
+	case s.snapshotTime != nil:
+		session, err := startSnapshotSession(s.collection, s.snapshotTime)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return mongo.NewSessionContext(ctx, session), session, nil
+
+	case s.causal != nil:
+		session, err := s.collection.Database().Client().StartSession(options.Session().SetCausalConsistency(true))
+		if err != nil {
+			return nil, nil, fmt.Errorf("start causally consistent session: %w", err)
+		}
+
+		if err := session.AdvanceClusterTime(s.causal.clusterTime); err != nil {
+			session.EndSession(ctx)
+
+			return nil, nil, fmt.Errorf("advance cluster time: %w", err)
+		}
+
+		if err := session.AdvanceOperationTime(s.causal.operationTime); err != nil {
+			session.EndSession(ctx)
+
+			return nil, nil, fmt.Errorf("advance operation time: %w", err)
+		}
+
+		return mongo.NewSessionContext(ctx, session), session, nil
+
+	default:
+		return ctx, nil, nil
+	}
+}
+
+// cdcStartTime returns the cluster time the Change Stream starts at after the snapshot,
+// or nil if the snapshot isn't read at the snapshot time.
+func (s *shardedSnapshot) cdcStartTime() *primitive.Timestamp {
+	if s.snapshotTime == nil {
+		return nil
+	}
+
+	return nextTimestamp(s.snapshotTime)
+}
+
+// readChunk sends the documents of the chunk to the documents channel, followed by the chunk's done marker.
+// The chunk is read through the shard key index, bounded by the chunk's min and max keys,
+// so mongos routes the query to the shard owning the chunk only.
+func (s *shardedSnapshot) readChunk(ctx context.Context, shardKey bson.D, chunk shardChunk, batchSize int) error {
+	opts := options.Find().
+		SetHint(shardKey).
+		SetMin(chunk.Min).
+		SetMax(chunk.Max).
+		SetBatchSize(int32(batchSize)) //nolint:gosec // the batch size is validated to fit into int32
+
+	cursor, err := s.collection.Find(ctx, bson.D{}, opts)
+	if err != nil {
+		return fmt.Errorf("execute find: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	id := chunk.id()
+	for cursor.Next(ctx) {
+		// the readers share the throttle, so it limits the total rate of all of them
+		if s.throttle != nil {
+			if err := s.throttle.Wait(ctx); err != nil {
+				return fmt.Errorf("wait for snapshot throttle: %w", err)
+			}
+		}
+
+		// the cursor reuses its buffer, so the document is copied before it's sent
+		select {
+		case s.documents <- shardedDocument{chunk: id, document: slices.Clone(cursor.Current)}:
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck // the reader is stopped
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return fmt.Errorf("cursor: %w", err)
+	}
+
+	select {
+	case s.documents <- shardedDocument{chunk: id, done: true}:
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // the reader is stopped
+	}
+
+	return nil
+}
+
+// hasNext checks whether the sharded snapshot iterator has records to return or not.
+// It waits for the chunk readers until one of them sends a document or all of them are done.
+func (s *shardedSnapshot) hasNext(ctx context.Context) (bool, error) {
+	if s.collectionMetadataPending {
+		return true, nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err() //nolint:wrapcheck // the context error is returned as it is
+		case received, ok := <-s.documents:
+			switch {
+			case !ok:
+				return false, nil
+
+			case received.err != nil:
+				return false, received.err
+
+			case received.done:
+				s.completed = append(s.completed, received.chunk)
+
+			default:
+				s.current = received.document
+
+				return true, nil
+			}
+		}
+	}
+}
+
+// next returns the next record.
+func (s *shardedSnapshot) next(ctx context.Context) (opencdc.Record, error) {
+	if s.collectionMetadataPending {
+		return s.nextCollectionMetadata(ctx)
+	}
+
+	progress := s.progress
+	progress.emitted++
+
+	position := &position{
+		Mode:            modeSnapshot,
+		ResumeToken:     s.resumeToken,
+		OplogTimestamp:  s.oplogTimestamp,
+		TailID:          s.tailID,
+		SnapshotTime:    s.snapshotTime,
+		OperationTime:   s.cdcStartTime(),
+		Emitted:         progress.emitted,
+		Sharded:         true,
+		CompletedChunks: s.completed,
+	}
+
+	sdkPosition, err := s.checkpoints.next(position, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	s.progress = progress
+
+	document, err := s.converter.ConvertRaw(s.current)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document: %w", err)
+	}
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = s.collection.Name()
+	metadata.SetCreatedAt(time.Now())
+	s.progress.setMetadata(metadata)
+
+	record := sdk.Util.Source.NewRecordSnapshot(
+		sdkPosition,
+		metadata,
+		// sharded collections can't be capped, so they're never _id-less
+		documentKey(document, false, nil),
+		opencdc.StructuredData(document),
+	)
+
+	record, err = formatRecord(record, s.payloadFormat, s.collection.Database().Name(), s.current, s.converter, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record payload: %w", err)
+	}
+
+	if s.payloadSchema != nil {
+		record = s.payloadSchema.attach(record, document)
+	}
+
+	record = s.schemaDrift.check(ctx, record, s.current)
+
+	record, err = formatKey(record, s.keyFormat, s.converter.IDType, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record key: %w", err)
+	}
+
+	return record, nil
+}
+
+// nextCollectionMetadata returns the record describing the collection structure.
+// Its position has no completed chunks, but it's not nil, so the record isn't returned again after a restart.
+func (s *shardedSnapshot) nextCollectionMetadata(ctx context.Context) (opencdc.Record, error) {
+	position := &position{
+		Mode:           modeSnapshot,
+		ResumeToken:    s.resumeToken,
+		OplogTimestamp: s.oplogTimestamp,
+		TailID:         s.tailID,
+		SnapshotTime:   s.snapshotTime,
+		OperationTime:  s.cdcStartTime(),
+		Sharded:        true,
+	}
+
+	sdkPosition, err := position.marshalSDKPosition(s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	record, err := collectionMetadataRecord(ctx, s.collection, sdkPosition)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("create collection metadata record: %w", err)
+	}
+
+	s.collectionMetadataPending = false
+
+	return record, nil
+}
+
+// stop stops the chunk readers and waits for them to finish.
+func (s *shardedSnapshot) stop(context.Context) error {
+	s.cancel()
+
+	// the readers may be blocked on sending documents, so the channel is drained until they're done,
+	// their errors don't matter anymore
+	for range s.documents {
+	}
+
+	return nil
+}
+
+// shardMetadata is the sharding metadata of a collection, as described in the config.collections collection.
+type shardMetadata struct {
+	Key  bson.D            `bson:"key"`
+	UUID *primitive.Binary `bson:"uuid"`
+	// Unsplittable marks unsharded collections tracked by the config server since MongoDB 8.0.
+	Unsplittable bool `bson:"unsplittable"`
+}
+
+// getShardMetadata returns the sharding metadata of the collection.
+// It returns errNotShardedCollection if the collection isn't sharded.
+func getShardMetadata(ctx context.Context, collection *mongo.Collection) (shardMetadata, error) {
+	configDB := collection.Database().Client().Database("config")
+	namespace := collection.Database().Name() + "." + collection.Name()
+
+	var metadata shardMetadata
+
+	err := configDB.Collection("collections").FindOne(ctx, bson.M{idFieldName: namespace}).Decode(&metadata)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return shardMetadata{}, errNotShardedCollection
+		}
+
+		return shardMetadata{}, fmt.Errorf("find collection in config.collections: %w", err)
+	}
+
+	if metadata.Unsplittable || len(metadata.Key) == 0 {
+		return shardMetadata{}, errNotShardedCollection
+	}
+
+	return metadata, nil
+}
+
+// getShardChunks returns the shard key of the collection and its chunks ordered by their min keys,
+// as described in the config database. It returns errNotShardedCollection if the collection isn't sharded.
+func getShardChunks(ctx context.Context, collection *mongo.Collection) (bson.D, []shardChunk, error) {
+	configDB := collection.Database().Client().Database("config")
+	namespace := collection.Database().Name() + "." + collection.Name()
+
+	metadata, err := getShardMetadata(ctx, collection)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// chunks reference their collections by the UUID since MongoDB 5.0, and by the namespace before it
+	filter := bson.M{"ns": namespace}
+	if metadata.UUID != nil {
+		filter = bson.M{"uuid": *metadata.UUID}
+	}
+
+	cursor, err := configDB.Collection("chunks").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "min", Value: 1}}))
+	if err != nil {
+		return nil, nil, fmt.Errorf("find chunks in config.chunks: %w", err)
+	}
+
+	var chunks []shardChunk
+	if err := cursor.All(ctx, &chunks); err != nil {
+		return nil, nil, fmt.Errorf("decode chunks: %w", err)
+	}
+
+	return metadata.Key, chunks, nil
+}
@@ -0,0 +1,87 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestShardChunk_id(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	bound := func(value any) bson.Raw {
+		raw, err := bson.Marshal(bson.M{"userId": value})
+		is.NoErr(err)
+
+		return raw
+	}
+
+	chunk := shardChunk{Min: bound(0), Max: bound(100), Shard: "shard0"}
+
+	// the identifier depends on the bounds only, so a migrated chunk keeps it
+	is.Equal(chunk.id(), shardChunk{Min: bound(0), Max: bound(100), Shard: "shard1"}.id())
+
+	// a split chunk doesn't match the completed one, so it's read once again
+	is.True(chunk.id() != shardChunk{Min: bound(0), Max: bound(50), Shard: "shard0"}.id())
+}
+
+func TestShardedSnapshot_hasNext(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	document, err := bson.Marshal(bson.M{idFieldName: 1})
+	is.NoErr(err)
+
+	s := &shardedSnapshot{documents: make(chan shardedDocument, 3)}
+	s.documents <- shardedDocument{chunk: 1, document: document}
+	s.documents <- shardedDocument{chunk: 1, done: true}
+	s.documents <- shardedDocument{chunk: 2, done: true}
+	close(s.documents)
+
+	hasNext, err := s.hasNext(context.Background())
+	is.NoErr(err)
+	is.True(hasNext)
+	is.Equal(s.current, bson.Raw(document))
+	is.Equal(len(s.completed), 0)
+
+	// done markers complete their chunks without returning records
+	hasNext, err = s.hasNext(context.Background())
+	is.NoErr(err)
+	is.True(!hasNext)
+	is.Equal(s.completed, []uint64{1, 2})
+}
+
+func TestPosition_completedChunks(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	pos := &position{Mode: modeSnapshot, Sharded: true, CompletedChunks: []uint64{1, 18446744073709551615}}
+
+	sdkPosition, err := pos.marshalSDKPosition(nil)
+	is.NoErr(err)
+
+	got, err := parsePosition(sdkPosition)
+	is.NoErr(err)
+	is.True(got.Sharded)
+	is.Equal(got.CompletedChunks, pos.CompletedChunks)
+}
@@ -0,0 +1,177 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// signalPollInterval is the minimal interval between two reads of the signal collection.
+const signalPollInterval = time.Second
+
+// signalConnectorField is a name of a signal field holding the ID of the connector the signal is addressed to.
+// Signals without it are addressed to all connectors reading the signal collection.
+const signalConnectorField = "connector"
+
+// signalType defines the action a signal asks the iterator to take.
+type signalType string
+
+// The list of available signal types is listed below.
+const (
+	// signalTypeSnapshot makes the iterator capture an incremental snapshot without pausing CDC.
+	signalTypeSnapshot signalType = "snapshot"
+	// signalTypePause makes the iterator stop returning records until it's resumed.
+	signalTypePause signalType = "pause"
+	// signalTypeResume makes the paused iterator return records again.
+	signalTypeResume signalType = "resume"
+)
+
+// signal is a control document inserted into the signal collection.
+type signal struct {
+	ID   any        `bson:"_id"`
+	Type signalType `bson:"type"`
+	// Connector is the ID of the connector the signal is addressed to, it's empty for all connectors.
+	Connector string `bson:"connector,omitempty"`
+	// Data contains signal-specific arguments.
+	Data bson.Raw `bson:"data,omitempty"`
+}
+
+// signals reads control documents from the signal collection.
+// The documents are deleted once they're processed, so each signal is processed only once,
+// and signals inserted while the connector is stopped are processed after it starts.
+type signals struct {
+	collection *mongo.Collection
+	// connectorID is the ID of the connector, signals addressed to other connectors are left in the collection.
+	connectorID string
+	lastPoll    time.Time
+}
+
+// newSignals creates a new instance of the [signals] for the provided collection and connector ID.
+func newSignals(collection *mongo.Collection, connectorID string) *signals {
+	return &signals{collection: collection, connectorID: connectorID}
+}
+
+// signalFilter returns a filter matching the signals addressed to all connectors or to the one with the ID.
+func signalFilter(connectorID string) bson.M {
+	return bson.M{signalConnectorField: bson.M{"$in": bson.A{nil, connectorID}}}
+}
+
+// DeleteConnectorSignals deletes the signals addressed to the connector with the ID that are left unprocessed
+// in the signal collection, and returns their number. Signals addressed to all connectors are kept,
+// as other connectors may still process them, and nothing is deleted if the ID is empty.
+func DeleteConnectorSignals(ctx context.Context, collection *mongo.Collection, connectorID string) (int64, error) {
+	if connectorID == "" {
+		return 0, nil
+	}
+
+	result, err := collection.DeleteMany(ctx, bson.M{signalConnectorField: connectorID})
+	if err != nil {
+		return 0, fmt.Errorf("delete signals: %w", err)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// poll returns the signals inserted since the last call, oldest first.
+// It returns nothing if it's called earlier than the [signalPollInterval] after the last read.
+func (s *signals) poll(ctx context.Context) ([]signal, error) {
+	if time.Since(s.lastPoll) < signalPollInterval {
+		return nil, nil
+	}
+
+	s.lastPoll = time.Now()
+
+	cursor, err := s.collection.Find(ctx, signalFilter(s.connectorID), options.Find().SetSort(bson.M{idFieldName: 1}))
+	if err != nil {
+		return nil, fmt.Errorf("execute find: %w", err)
+	}
+
+	var polled []signal
+	if err := cursor.All(ctx, &polled); err != nil {
+		return nil, fmt.Errorf("decode signals: %w", err)
+	}
+
+	for i := range polled {
+		polled[i].Type = signalType(strings.ToLower(string(polled[i].Type)))
+	}
+
+	return polled, nil
+}
+
+// ack deletes the processed signal from the signal collection.
+func (s *signals) ack(ctx context.Context, processed signal) error {
+	if _, err := s.collection.DeleteOne(ctx, bson.M{idFieldName: processed.ID}); err != nil {
+		return fmt.Errorf("delete signal: %w", err)
+	}
+
+	return nil
+}
+
+// processSignals applies the signals inserted into the signal collection.
+func (c *Combined) processSignals(ctx context.Context) error {
+	polled, err := c.signals.poll(ctx)
+	if err != nil {
+		return fmt.Errorf("poll signals: %w", err)
+	}
+
+	for _, polledSignal := range polled {
+		logger := sdk.Logger(ctx).With().Interface("id", polledSignal.ID).Str("type", string(polledSignal.Type)).Logger()
+
+		switch polledSignal.Type {
+		case signalTypeSnapshot:
+			switch {
+			case c.cdc == nil:
+				logger.Warn().Msg("an incremental snapshot requires CDC, the signal is ignored")
+
+			case c.snapshot != nil || c.shardedSnapshot != nil || c.incremental != nil:
+				logger.Warn().Msg("a snapshot is already in progress, the signal is ignored")
+
+			default:
+				if err := c.startIncrementalSnapshot(ctx, &incrementalPosition{Trigger: c.snapshotTrigger}); err != nil {
+					return fmt.Errorf("start incremental snapshot: %w", err)
+				}
+
+				logger.Info().Msg("incremental snapshot started")
+			}
+
+		case signalTypePause:
+			c.paused = true
+
+			logger.Info().Msg("iterator paused")
+
+		case signalTypeResume:
+			c.paused = false
+
+			logger.Info().Msg("iterator resumed")
+
+		default:
+			logger.Warn().Msg("unknown signal type, the signal is ignored")
+		}
+
+		if err := c.signals.ack(ctx, polledSignal); err != nil {
+			return fmt.Errorf("ack signal: %w", err)
+		}
+	}
+
+	return nil
+}
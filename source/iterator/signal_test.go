@@ -0,0 +1,43 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSignalFilter(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// signals without the connector field match the nil element
+	is.Equal(signalFilter("pipeline:source"), bson.M{"connector": bson.M{"$in": bson.A{nil, "pipeline:source"}}})
+}
+
+func TestDeleteConnectorSignals_noConnectorID(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	// nothing is deleted, so the collection isn't used
+	deleted, err := DeleteConnectorSignals(context.Background(), nil, "")
+	is.NoErr(err)
+	is.Equal(deleted, int64(0))
+}
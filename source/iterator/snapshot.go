@@ -16,16 +16,19 @@ package iterator
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio/conduit-commons/opencdc"
 	sdk "github.com/conduitio/conduit-connector-sdk"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/time/rate"
 )
 
 // idFieldName is a reserved name for use as a primary key in MongoDB.
@@ -33,11 +36,11 @@ const idFieldName = "_id"
 
 // snapshot is a snapshot iterator for the MongoDB source connector.
 type snapshot struct {
-	collection    *mongo.Collection
-	orderingField string
-	batchSize     int
-	cursor        *mongo.Cursor
-	position      *position
+	collection     *mongo.Collection
+	orderingFields []string
+	batchSize      int
+	cursor         *mongo.Cursor
+	position       *position
 	// orderingFieldMaxValue is a max value of an ordering field
 	// at the start of the snapshot. The snapshot iterator will only
 	// grab fields with ordering field value less than or equal to this value.
@@ -46,22 +49,153 @@ type snapshot struct {
 	// after a pause that occurs just after the snapshot is completed.
 	// That's why this value is stored in a snapshot position.
 	resumeToken bson.Raw
+	// oplogTimestamp is the same as the resumeToken, but for the oplog iterator.
+	oplogTimestamp *primitive.Timestamp
+	// tailID is the same as the resumeToken, but for the tailable iterator.
+	tailID any
+	// snapshotTime is the cluster time all documents are read at. If it's not nil, the Change Stream
+	// starts right after it, so the snapshot positions store that time instead of the resume token.
+	snapshotTime *primitive.Timestamp
+	// session is the snapshot session documents are read in if the snapshot time is set.
+	session mongo.Session
+	// causalSession is the causally consistent session documents are read in otherwise, if it's set.
+	// It's shared with the Change Stream, so the snapshot doesn't end it.
+	causalSession mongo.Session
 	// polling defines if the snapshot is used to detect insertions
 	// by polling for new documents in case CDC is not possible.
 	polling bool
+	// updatedAtField is a name of a field containing the time of a document's last update.
+	// If it's not empty, the polling snapshot also polls for documents updated after the last polled value.
+	updatedAtField string
+	// pollingUpdates defines if the current batch contains updated documents instead of new ones.
+	pollingUpdates bool
+	// softDeleteField is a name of a field marking a document as deleted. If it's not empty,
+	// the polling snapshot reports documents having the field set as deleted.
+	softDeleteField string
+	// idSet detects deleted documents while polling. If it's nil, deleted documents are not detected.
+	idSet         *idSet
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+	// collectionMetadataPending defines if the snapshot must return
+	// a record describing the collection structure before any document.
+	collectionMetadataPending bool
+	// maxBatchBytes is the max total size of documents in a batch. Zero means no limit.
+	// Once it's exceeded, the rest of the batch is loaded by a new query.
+	maxBatchBytes int
+	// batchBytes is the total size of documents returned from the current batch.
+	batchBytes int
+	// progress tracks how far along the snapshot is. It's not tracked while polling.
+	progress snapshotProgress
+	// allowDiskUse defines if the snapshot may fall back to sorting documents on disk
+	// once the sort exceeds the memory limit.
+	allowDiskUse bool
+	// sortStrategy is the sort strategy the snapshot fell back to. It's empty if documents are sorted in memory.
+	sortStrategy sortStrategy
+	// idLess defines if documents of the collection may lack the _id field, so they're neither tie-broken
+	// nor identified by it. The ordering fields must be unique in this case.
+	idLess bool
+	// resumeInclusive defines if the first batch after a restart starts from the last emitted document,
+	// instead of right after it.
+	resumeInclusive bool
+	// checkpoints limits how often the positions of records are marshaled. It's not used while polling.
+	checkpoints checkpointer
+	// throttle paces loading batches by the documents of the previous ones. If it's nil, batches aren't paced.
+	throttle *rate.Limiter
+	// batchDocuments is the number of documents returned from the current batch.
+	batchDocuments int
 }
 
 // snapshotParams is an incoming params for the [newSnapshot] function.
 type snapshotParams struct {
-	collection    *mongo.Collection
-	orderingField string
-	batchSize     int
-	position      *position
-	resumeToken   bson.Raw
+	collection     *mongo.Collection
+	orderingFields []string
+	batchSize      int
+	position       *position
+	resumeToken    bson.Raw
+	oplogTimestamp *primitive.Timestamp
+	tailID         any
+	payloadFormat  PayloadFormat
+	keyFormat      KeyFormat
+	converter      codec.Converter
+	buffers        *codec.BufferPool
+	payloadSchema  *payloadSchema
+	schemaDrift    *schemaDrift
+	updatedAtField string
+	// snapshotTime is the cluster time all documents are read at. If it's nil, each batch is read
+	// at its own point in time.
+	snapshotTime *primitive.Timestamp
+	// causalSession is the causally consistent session the Change Stream was created in. If it's set
+	// and the snapshot time isn't, the max value and the documents are read in that session.
+	causalSession mongo.Session
+	// deleteStrategy, softDeleteField and deleteCheckInterval configure
+	// how the polling snapshot detects deleted documents.
+	deleteStrategy      DeleteStrategy
+	softDeleteField     string
+	deleteCheckInterval time.Duration
+	// collectionMetadata defines if the snapshot must start with
+	// a record describing the collection structure.
+	collectionMetadata bool
+	// maxBatchBytes is the max total size of documents in a batch. Zero means no limit.
+	maxBatchBytes int
+	// allowDiskUse defines if the snapshot may sort documents on disk once the sort exceeds the memory limit.
+	allowDiskUse bool
+	// resumeBoundary defines whether a resumed snapshot emits the last emitted document once again.
+	resumeBoundary ResumeBoundary
+	// checkpointRecords and checkpointInterval limit how often the blocking snapshot checkpoints its position.
+	// If both are zero, every record gets its own position.
+	checkpointRecords  int
+	checkpointInterval time.Duration
+	// throttle limits the number of documents the snapshot reads per second. If it's nil, it's not limited.
+	throttle *rate.Limiter
 }
 
 // newSnapshot creates a new instance of the [snapshot] iterator.
 func newSnapshot(ctx context.Context, params snapshotParams) (*snapshot, error) {
+	// the snapshot keeps the fallback sort strategy after a restart
+	var strategy sortStrategy
+	if params.position != nil {
+		strategy = params.position.SortStrategy
+	}
+
+	if strategy == sortStrategyID {
+		params.orderingFields = []string{idFieldName}
+	}
+
+	if err := checkPositionOrdering(params.position, params.orderingFields); err != nil {
+		return nil, err
+	}
+
+	idLess, err := checkIDLessParams(ctx, params, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var progress snapshotProgress
+	if params.position != nil {
+		progress.emitted = params.position.Emitted
+	}
+
+	// the estimate is read from the collection metadata, so it doesn't scan the collection,
+	// snapshot sessions don't support the count command, so it's read outside of one
+	total, err := params.collection.EstimatedDocumentCount(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("estimate collection document count: %w", err)
+	}
+
+	progress.total = total
+
+	var session mongo.Session
+	if params.snapshotTime != nil {
+		session, err = startSnapshotSession(params.collection, params.snapshotTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var orderingFieldMaxValue any
 
 	switch pos := params.position; {
@@ -69,28 +203,70 @@ func newSnapshot(ctx context.Context, params snapshotParams) (*snapshot, error)
 		orderingFieldMaxValue = params.position.MaxElement
 
 	default:
+		// the max value is read at the snapshot time too, so documents inserted later are left to CDC,
+		// or at least after the Change Stream was created, so documents inserted before are not missed
+		readCtx := ctx
+		switch {
+		case session != nil:
+			readCtx = mongo.NewSessionContext(ctx, session)
+
+		case params.causalSession != nil:
+			readCtx = mongo.NewSessionContext(ctx, params.causalSession)
+		}
+
 		var err error
-		orderingFieldMaxValue, err = getMaxFieldValue(ctx, params.collection, params.orderingField)
+		orderingFieldMaxValue, err = getMaxFieldValue(readCtx, params.collection, params.orderingFields)
 		if err != nil && !errors.Is(err, errNoDocuments) {
+			if session != nil {
+				session.EndSession(ctx)
+			}
+
 			return nil, fmt.Errorf("get ordering field max value: %w", err)
 		}
 	}
 
 	return &snapshot{
 		collection:            params.collection,
-		orderingField:         params.orderingField,
+		orderingFields:        params.orderingFields,
 		batchSize:             params.batchSize,
 		position:              params.position,
 		orderingFieldMaxValue: orderingFieldMaxValue,
 		resumeToken:           params.resumeToken,
+		oplogTimestamp:        params.oplogTimestamp,
+		tailID:                params.tailID,
+		snapshotTime:          params.snapshotTime,
+		session:               session,
+		causalSession:         params.causalSession,
+		payloadFormat:         params.payloadFormat,
+		keyFormat:             params.keyFormat,
+		converter:             params.converter,
+		buffers:               params.buffers,
+		payloadSchema:         params.payloadSchema,
+		schemaDrift:           params.schemaDrift,
+		maxBatchBytes:         params.maxBatchBytes,
+		progress:              progress,
+		allowDiskUse:          params.allowDiskUse,
+		sortStrategy:          strategy,
+		idLess:                idLess,
+		resumeInclusive: params.resumeBoundary == ResumeBoundaryInclusive &&
+			params.position != nil && params.position.Element != nil,
+		// the record is returned only once, at the very start of the snapshot
+		collectionMetadataPending: params.collectionMetadata && params.position == nil,
+		checkpoints:               checkpointer{records: params.checkpointRecords, interval: params.checkpointInterval},
+		throttle:                  params.throttle,
 	}, nil
 }
 
 // newPollingSnapshot creates a new instance of the [snapshot] iterator prepared for polling.
 func newPollingSnapshot(ctx context.Context, params snapshotParams) (*snapshot, error) {
+	idLess, err := checkIDLessParams(ctx, params, true)
+	if err != nil {
+		return nil, err
+	}
+
 	pos := params.position
 	if pos == nil || pos.Mode == modeSnapshot {
-		orderingFieldMaxValue, err := getMaxFieldValue(ctx, params.collection, params.orderingField)
+		orderingFieldMaxValue, err := getMaxFieldValue(ctx, params.collection, params.orderingFields)
 		if err != nil && !errors.Is(err, errNoDocuments) {
 			return nil, fmt.Errorf("get ordering field max value: %w", err)
 		}
@@ -101,18 +277,46 @@ func newPollingSnapshot(ctx context.Context, params snapshotParams) (*snapshot,
 		}
 	}
 
-	return &snapshot{
-		collection:    params.collection,
-		orderingField: params.orderingField,
-		batchSize:     params.batchSize,
-		position:      pos,
-		polling:       true,
-	}, nil
+	s := &snapshot{
+		collection:     params.collection,
+		orderingFields: params.orderingFields,
+		batchSize:      params.batchSize,
+		position:       pos,
+		polling:        true,
+		updatedAtField: params.updatedAtField,
+		payloadFormat:  params.payloadFormat,
+		keyFormat:      params.keyFormat,
+		converter:      params.converter,
+		buffers:        params.buffers,
+		payloadSchema:  params.payloadSchema,
+		schemaDrift:    params.schemaDrift,
+		maxBatchBytes:  params.maxBatchBytes,
+		allowDiskUse:   params.allowDiskUse,
+		idLess:         idLess,
+	}
+
+	if pos.SortStrategy == sortStrategyDisk {
+		s.sortStrategy = sortStrategyDisk
+	}
+
+	if err := s.initPollingUpdates(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := s.initPollingDeletes(ctx, params); err != nil {
+		return nil, err
+	}
+
+	return s, nil
 }
 
 // hasNext checks whether the snapshot iterator has records to return or not.
 func (s *snapshot) hasNext(ctx context.Context) (bool, error) {
-	if s.cursor != nil && s.cursor.TryNext(ctx) {
+	if s.collectionMetadataPending || s.idSet.hasDeleted() {
+		return true, nil
+	}
+
+	if s.cursor != nil && s.tryNext(ctx) {
 		return true, nil
 	}
 
@@ -120,14 +324,48 @@ func (s *snapshot) hasNext(ctx context.Context) (bool, error) {
 		return false, fmt.Errorf("load batch: %w", err)
 	}
 
-	return s.cursor.TryNext(ctx), s.cursor.Err()
+	if s.tryNext(ctx) {
+		return true, nil
+	}
+
+	if s.cursor.Err() != nil || !s.polling {
+		return false, s.cursor.Err()
+	}
+
+	return s.hasNextPolled(ctx)
+}
+
+// tryNext advances the cursor to the next document of the current batch. It returns false
+// if the batch is exhausted or the total size of its returned documents exceeds the max batch bytes.
+// The first document of a batch is always returned, so the snapshot progresses even if it's bigger than the limit.
+func (s *snapshot) tryNext(ctx context.Context) bool {
+	if s.maxBatchBytes > 0 && s.batchBytes >= s.maxBatchBytes {
+		return false
+	}
+
+	if !s.cursor.TryNext(ctx) {
+		return false
+	}
+
+	s.batchBytes += len(s.cursor.Current)
+	s.batchDocuments++
+
+	return true
 }
 
 // next returns the next record.
-func (s *snapshot) next(_ context.Context) (opencdc.Record, error) {
-	var element map[string]any
-	if err := s.cursor.Decode(&element); err != nil {
-		return opencdc.Record{}, fmt.Errorf("decode element: %w", err)
+func (s *snapshot) next(ctx context.Context) (opencdc.Record, error) {
+	if s.collectionMetadataPending {
+		return s.nextCollectionMetadata(ctx)
+	}
+
+	if s.idSet.hasDeleted() {
+		return s.nextDeleted()
+	}
+
+	element, elementID, err := s.currentElement()
+	if err != nil {
+		return opencdc.Record{}, err
 	}
 
 	// if the snapshot is polling new items,
@@ -137,46 +375,131 @@ func (s *snapshot) next(_ context.Context) (opencdc.Record, error) {
 		mode = modeCDC
 	}
 
+	progress := s.progress
+	if !s.polling {
+		progress.emitted++
+	}
+
 	// try to create and marshal the record position
 	position := &position{
-		Mode:        mode,
-		Element:     element[s.orderingField],
-		MaxElement:  s.orderingFieldMaxValue,
-		ResumeToken: s.resumeToken,
+		Mode:           mode,
+		Element:        element,
+		ElementID:      elementID,
+		MaxElement:     s.orderingFieldMaxValue,
+		ResumeToken:    s.resumeToken,
+		OplogTimestamp: s.oplogTimestamp,
+		TailID:         s.tailID,
+		SnapshotTime:   s.snapshotTime,
+		OperationTime:  s.cdcStartTime(),
+		Emitted:        progress.emitted,
+		SortStrategy:   s.sortStrategy,
+	}
+
+	if err := s.setUpdatedAt(position); err != nil {
+		return opencdc.Record{}, err
 	}
 
-	sdkPosition, err := position.marshalSDKPosition()
+	// the position of the document is kept even if it isn't checkpointed, as the next batch starts after it
+	sdkPosition, err := s.checkpoints.next(position, s.buffers)
 	if err != nil {
 		return opencdc.Record{}, fmt.Errorf("marshal sdk position: %w", err)
 	}
 
 	s.position = position
+	s.progress = progress
+
+	if s.idSet != nil {
+		s.idSet.add(s.cursor.Current)
+	}
+
+	return s.currentRecord(ctx, sdkPosition)
+}
+
+// currentRecord creates a record of the current document with the provided position.
+func (s *snapshot) currentRecord(ctx context.Context, sdkPosition opencdc.Position) (opencdc.Record, error) {
+	document, err := s.converter.ConvertRaw(s.cursor.Current)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document: %w", err)
+	}
 
 	// set the record metadata
 	metadata := make(opencdc.Metadata)
 	metadata[metadataFieldCollection] = s.collection.Name()
 	metadata.SetCreatedAt(time.Now())
 
-	elementBytes, err := json.Marshal(element)
-	if err != nil {
-		return opencdc.Record{}, fmt.Errorf("failed marshalling record into JSON: %w", err)
+	if !s.polling {
+		s.progress.setMetadata(metadata)
 	}
 
-	if s.polling {
-		return sdk.Util.Source.NewRecordCreate(
-			sdkPosition,
-			metadata,
-			opencdc.StructuredData{idFieldName: element[idFieldName]},
-			opencdc.RawData(elementBytes),
-		), nil
-	}
+	s.setSortStrategy(metadata)
 
-	return sdk.Util.Source.NewRecordSnapshot(
+	record := sdk.Util.Source.NewRecordSnapshot(
 		sdkPosition,
 		metadata,
-		opencdc.StructuredData{idFieldName: element[idFieldName]},
-		opencdc.RawData(elementBytes),
-	), nil
+		documentKey(document, s.idLess, s.orderingFields),
+		opencdc.StructuredData(document),
+	)
+	if s.polling {
+		record = s.newPolledRecord(sdkPosition, metadata, document)
+	}
+
+	// soft deleted documents are reported without payloads
+	rawDocument := s.cursor.Current
+	if record.Operation == opencdc.OperationDelete {
+		rawDocument = nil
+	}
+
+	db := s.collection.Database().Name()
+
+	record, err = formatRecord(record, s.payloadFormat, db, rawDocument, s.converter, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record payload: %w", err)
+	}
+
+	if s.payloadSchema != nil && rawDocument != nil {
+		record = s.payloadSchema.attach(record, document)
+	}
+
+	if rawDocument != nil {
+		record = s.schemaDrift.check(ctx, record, rawDocument)
+	}
+
+	record, err = formatKey(record, s.keyFormat, s.converter.IDType, s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record key: %w", err)
+	}
+
+	return record, nil
+}
+
+// nextCollectionMetadata returns the record describing the collection structure.
+// Its position has no element, so the snapshot starts from the first document,
+// but it's not nil, so the record isn't returned again after a restart.
+func (s *snapshot) nextCollectionMetadata(ctx context.Context) (opencdc.Record, error) {
+	position := &position{
+		Mode:           modeSnapshot,
+		MaxElement:     s.orderingFieldMaxValue,
+		ResumeToken:    s.resumeToken,
+		OplogTimestamp: s.oplogTimestamp,
+		TailID:         s.tailID,
+		SnapshotTime:   s.snapshotTime,
+		OperationTime:  s.cdcStartTime(),
+	}
+
+	sdkPosition, err := position.marshalSDKPosition(s.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal sdk position: %w", err)
+	}
+
+	record, err := collectionMetadataRecord(ctx, s.collection, sdkPosition)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("create collection metadata record: %w", err)
+	}
+
+	s.position = position
+	s.collectionMetadataPending = false
+
+	return record, nil
 }
 
 // stop stops the iterator.
@@ -187,41 +510,183 @@ func (s *snapshot) stop(ctx context.Context) error {
 		}
 	}
 
+	if s.session != nil {
+		s.session.EndSession(ctx)
+		s.session = nil
+	}
+
 	return nil
 }
 
+// readContext returns the context the snapshot queries are run with, which is bound to the snapshot session
+// if documents are read at the snapshot time, or to the causally consistent session if it's set.
+func (s *snapshot) readContext(ctx context.Context) context.Context {
+	switch {
+	case s.session != nil:
+		return mongo.NewSessionContext(ctx, s.session)
+
+	case s.causalSession != nil:
+		return mongo.NewSessionContext(ctx, s.causalSession)
+
+	default:
+		return ctx
+	}
+}
+
+// cdcStartTime returns the cluster time the Change Stream starts at after the snapshot,
+// or nil if the snapshot isn't read at the snapshot time.
+func (s *snapshot) cdcStartTime() *primitive.Timestamp {
+	if s.snapshotTime == nil {
+		return nil
+	}
+
+	return nextTimestamp(s.snapshotTime)
+}
+
 // loadBatch finds a batch of documents in a MongoDB collection, based on the snapshot's
-// collection, orderingField, batchSize, and the current position.
+// collection, orderingFields, batchSize, and the current position.
 func (s *snapshot) loadBatch(ctx context.Context) error {
-	opts := options.Find().
-		SetSort(bson.M{s.orderingField: 1}).
-		SetLimit(int64(s.batchSize))
+	// the previous batch may be cut by the max batch bytes, so its cursor is still open
+	if s.cursor != nil {
+		if err := s.cursor.Close(ctx); err != nil {
+			return fmt.Errorf("close cursor: %w", err)
+		}
+	}
+
+	// the batch waits for the documents of the previous one, so the first batch is never delayed
+	if s.throttle != nil && s.batchDocuments > 0 {
+		if err := s.throttle.WaitN(ctx, s.batchDocuments); err != nil {
+			return fmt.Errorf("wait for snapshot throttle: %w", err)
+		}
+	}
+
+	cursor, err := s.find(s.readContext(ctx))
+	if err != nil {
+		return fmt.Errorf("execute find: %w", err)
+	}
+
+	s.cursor = cursor
+	s.batchBytes = 0
+	s.batchDocuments = 0
+	s.resumeInclusive = false
+	s.pollingUpdates = false
+
+	if !s.polling {
+		s.progress.log(ctx)
+	}
+
+	return nil
+}
+
+// checkPositionOrdering checks whether elements of the position match the ordering fields.
+func checkPositionOrdering(pos *position, fields []string) error {
+	if pos == nil {
+		return nil
+	}
+
+	for _, element := range []any{pos.Element, pos.MaxElement} {
+		if _, ok := orderingTuple(fields, element); element != nil && !ok {
+			return errPositionOrderingMismatch
+		}
+	}
+
+	return nil
+}
+
+// batchFilter returns a filter of the next batch of documents.
+func (s *snapshot) batchFilter() bson.M {
+	var conditions []bson.M
 
-	orderingFieldFilter := bson.M{}
 	// if the snapshot ordering field max value is not nil,
 	// we'll ask for documents that are less or equal to that value
-	if s.orderingFieldMaxValue != nil {
-		orderingFieldFilter["$lte"] = s.orderingFieldMaxValue
+	if maxValues, ok := orderingTuple(s.orderingFields, s.orderingFieldMaxValue); ok {
+		conditions = append(conditions, tupleFilter(s.orderingFields, maxValues, "$lt", "$lte"))
 	}
+
 	// if the snapshot position is not nil and its element is not empty,
 	// we'll do cursor-based pagination and ask for documents that are greater
-	// than the element
-	if s.position != nil && s.position.Element != nil {
-		orderingFieldFilter["$gt"] = s.position.Element
+	// than the element, or equal to it and have a greater _id if the position has it,
+	// the first batch after a restart includes the element itself if the resume boundary is inclusive
+	if s.position != nil {
+		if values, ok := orderingTuple(s.orderingFields, s.position.Element); ok {
+			fields := s.orderingFields
+			if s.position.ElementID != nil {
+				fields = append(slices.Clone(fields), idFieldName)
+				values = append(slices.Clone(values), s.position.ElementID)
+			}
+
+			lastOp := "$gt"
+			if s.resumeInclusive {
+				lastOp = "$gte"
+			}
+
+			conditions = append(conditions, tupleFilter(fields, values, "$gt", lastOp))
+		}
+	}
+
+	switch len(conditions) {
+	case 0:
+		return bson.M{}
+
+	case 1:
+		return conditions[0]
+
+	default:
+		return bson.M{"$and": conditions}
 	}
+}
 
-	cursor, err := s.collection.Find(ctx, bson.M{s.orderingField: orderingFieldFilter}, opts)
+// currentElement returns the position element and its _id tie-breaker of the current document.
+// Documents polled as updated don't move the element, so the previous one is returned for them.
+func (s *snapshot) currentElement() (any, any, error) {
+	if s.pollingUpdates {
+		return s.position.Element, s.position.ElementID, nil
+	}
+
+	// only the ordering fields are decoded, as the position element
+	// must keep the original types to be used in queries
+	values := make([]any, len(s.orderingFields))
+	for i, field := range s.orderingFields {
+		value, err := s.currentFieldValue(field)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get ordering field value: %w", err)
+		}
+
+		values[i] = value
+	}
+
+	// the _id tie-breaks documents with equal values of non-unique ordering fields
+	var elementID any
+	if !s.idLess && tieBreaksOnID(s.orderingFields) {
+		var err error
+		elementID, err = s.currentFieldValue(idFieldName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("get _id value: %w", err)
+		}
+	}
+
+	return orderingElement(values), elementID, nil
+}
+
+// currentFieldValue returns a value of the top-level field of the current document,
+// or nil if the document doesn't have the field.
+func (s *snapshot) currentFieldValue(field string) (any, error) {
+	rawValue, err := s.cursor.Current.LookupErr(field)
 	if err != nil {
-		return fmt.Errorf("execute find: %w", err)
+		return nil, nil //nolint:nilnil // a missing field is represented as nil
 	}
 
-	s.cursor = cursor
+	var value any
+	if err := rawValue.Unmarshal(&value); err != nil {
+		return nil, fmt.Errorf("unmarshal %q field: %w", field, err)
+	}
 
-	return nil
+	return value, nil
 }
 
-// getMaxFieldValue returns the maximum field value that can be found in a MongoDB collection.
-func getMaxFieldValue(ctx context.Context, collection *mongo.Collection, fieldName string) (any, error) {
+// getMaxFieldValue returns the maximum value of the ordering fields that can be found in a MongoDB collection.
+// If there are multiple ordering fields, the value is a tuple of their values.
+func getMaxFieldValue(ctx context.Context, collection *mongo.Collection, fields []string) (any, error) {
 	documentCount, err := collection.CountDocuments(ctx, bson.M{})
 	if err != nil {
 		return nil, fmt.Errorf("count collection documents: %w", err)
@@ -233,8 +698,13 @@ func getMaxFieldValue(ctx context.Context, collection *mongo.Collection, fieldNa
 		return nil, errNoDocuments
 	}
 
-	// this is the way we can get the maximum value of a specific field
-	opts := options.Find().SetSort(bson.M{fieldName: -1}).SetLimit(1)
+	// this is the way we can get the maximum value of specific fields
+	sort := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		sort = append(sort, bson.E{Key: field, Value: -1})
+	}
+
+	opts := options.Find().SetSort(sort).SetLimit(1)
 
 	cursor, err := collection.Find(ctx, bson.M{}, opts)
 	if err != nil {
@@ -254,5 +724,10 @@ func getMaxFieldValue(ctx context.Context, collection *mongo.Collection, fieldNa
 		return nil, fmt.Errorf("decode cursor element: %w", err)
 	}
 
-	return element[fieldName], nil
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		values[i] = element[field]
+	}
+
+	return orderingElement(values), nil
 }
@@ -0,0 +1,162 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSnapshot_batchFilter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		snapshot *snapshot
+		want     bson.M
+	}{
+		{
+			name:     "no_position_no_max",
+			snapshot: &snapshot{orderingFields: []string{"createdAt"}},
+			want:     bson.M{},
+		},
+		{
+			name:     "no_position",
+			snapshot: &snapshot{orderingFields: []string{"createdAt"}, orderingFieldMaxValue: int32(20)},
+			want:     bson.M{"createdAt": bson.M{"$lte": int32(20)}},
+		},
+		{
+			name: "unique_ordering_field",
+			snapshot: &snapshot{
+				orderingFields:        []string{idFieldName},
+				orderingFieldMaxValue: int32(20),
+				position:              &position{Element: int32(10)},
+			},
+			want: bson.M{"$and": []bson.M{
+				{idFieldName: bson.M{"$lte": int32(20)}},
+				{idFieldName: bson.M{"$gt": int32(10)}},
+			}},
+		},
+		{
+			name: "non_unique_ordering_field",
+			snapshot: &snapshot{
+				orderingFields:        []string{"createdAt"},
+				orderingFieldMaxValue: int32(20),
+				position:              &position{Element: int32(10), ElementID: "63bd5ee3ad5b1d4c6ad2b7e0"},
+			},
+			want: bson.M{"$and": []bson.M{
+				{"createdAt": bson.M{"$lte": int32(20)}},
+				{"$or": bson.A{
+					bson.M{"createdAt": bson.M{"$gt": int32(10)}},
+					bson.M{"createdAt": int32(10), idFieldName: bson.M{"$gt": "63bd5ee3ad5b1d4c6ad2b7e0"}},
+				}},
+			}},
+		},
+		{
+			name: "inclusive_resume",
+			snapshot: &snapshot{
+				orderingFields:        []string{"createdAt"},
+				orderingFieldMaxValue: int32(20),
+				position:              &position{Element: int32(10), ElementID: "63bd5ee3ad5b1d4c6ad2b7e0"},
+				resumeInclusive:       true,
+			},
+			want: bson.M{"$and": []bson.M{
+				{"createdAt": bson.M{"$lte": int32(20)}},
+				{"$or": bson.A{
+					bson.M{"createdAt": bson.M{"$gt": int32(10)}},
+					bson.M{"createdAt": int32(10), idFieldName: bson.M{"$gte": "63bd5ee3ad5b1d4c6ad2b7e0"}},
+				}},
+			}},
+		},
+		{
+			name: "composite_ordering_fields",
+			snapshot: &snapshot{
+				orderingFields:        []string{"tenant", "seq"},
+				orderingFieldMaxValue: []any{"b", int32(5)},
+				position:              &position{Element: []any{"a", int32(3)}},
+			},
+			want: bson.M{"$and": []bson.M{
+				{"$or": bson.A{
+					bson.M{"tenant": bson.M{"$lt": "b"}},
+					bson.M{"tenant": "b", "seq": bson.M{"$lte": int32(5)}},
+				}},
+				{"$or": bson.A{
+					bson.M{"tenant": bson.M{"$gt": "a"}},
+					bson.M{"tenant": "a", "seq": bson.M{"$gt": int32(3)}},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(tt.snapshot.batchFilter(), tt.want)
+		})
+	}
+}
+
+func TestCheckPositionOrdering(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		position *position
+		fields   []string
+		wantErr  bool
+	}{
+		{
+			name:   "no_position",
+			fields: []string{"tenant", "seq"},
+		},
+		{
+			name:     "single_field",
+			position: &position{Element: int32(1), MaxElement: int32(2)},
+			fields:   []string{"seq"},
+		},
+		{
+			name:     "composite_fields",
+			position: &position{Element: []any{"a", int32(1)}, MaxElement: []any{"b", int32(2)}},
+			fields:   []string{"tenant", "seq"},
+		},
+		{
+			name:     "composite_fields_single_element",
+			position: &position{Element: int32(1)},
+			fields:   []string{"tenant", "seq"},
+			wantErr:  true,
+		},
+		{
+			name:     "composite_fields_tuple_length_mismatch",
+			position: &position{MaxElement: []any{"b"}},
+			fields:   []string{"tenant", "seq"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+
+			err := checkPositionOrdering(tt.position, tt.fields)
+			is.Equal(err != nil, tt.wantErr)
+		})
+	}
+}
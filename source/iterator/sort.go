@@ -0,0 +1,162 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// metadataFieldSortStrategy is a metadata field that holds the sort strategy
+// the snapshot fell back to after its sort exceeded the memory limit.
+const metadataFieldSortStrategy = "mongo.snapshot.sortStrategy"
+
+// sortStrategy defines how the snapshot sorts documents. The zero value means
+// documents are sorted by the ordering fields in memory.
+type sortStrategy string
+
+// The list of fallback sort strategies is listed below.
+const (
+	// sortStrategyDisk makes the snapshot let the server write temporary files to disk to sort documents.
+	sortStrategyDisk sortStrategy = "disk"
+	// sortStrategyID makes the snapshot paginate documents by their _id instead of the ordering fields,
+	// as the _id index never requires an in-memory sort.
+	sortStrategyID sortStrategy = "id"
+)
+
+// The MongoDB server error codes returned when a sort exceeds the memory limit are listed below.
+const (
+	// queryExceededMemoryLimitErrCode is returned by MongoDB 4.4 and newer.
+	queryExceededMemoryLimitErrCode = 292
+	// sortExceededMemoryLimitErrCode is returned by MongoDB versions older than 4.4.
+	sortExceededMemoryLimitErrCode = 16819
+	// externalSortFailedErrCode is returned by MongoDB versions older than 4.4 if the disk sort fails too.
+	externalSortFailedErrCode = 16820
+)
+
+// find executes the query of the next batch. The blocking sort is executed before
+// the first batch is returned, so its errors are returned by the query itself, not by the cursor.
+// If the sort exceeds the memory limit, the query is retried with a fallback sort strategy.
+func (s *snapshot) find(ctx context.Context) (*mongo.Cursor, error) {
+	for {
+		cursor, err := s.collection.Find(ctx, s.batchFilter(), s.findOptions())
+		if err == nil {
+			return cursor, nil
+		}
+
+		if !isSortMemoryErr(err) {
+			return nil, err //nolint:wrapcheck // the error is wrapped by the caller
+		}
+
+		ok, fallbackErr := s.fallbackSort(ctx)
+		if fallbackErr != nil {
+			return nil, errors.Join(err, fallbackErr)
+		}
+
+		if !ok {
+			return nil, err //nolint:wrapcheck // the error is wrapped by the caller
+		}
+
+		sdk.Logger(ctx).Warn().Err(err).Str("sortStrategy", string(s.sortStrategy)).
+			Msg("the snapshot sort exceeded the memory limit, retrying with a fallback sort strategy")
+	}
+}
+
+// findOptions returns the options of the query of the next batch.
+func (s *snapshot) findOptions() *options.FindOptions {
+	// documents are sorted by the ordering fields and their _id,
+	// so documents with equal values of non-unique ordering fields keep a stable order
+	sort := make(bson.D, 0, len(s.orderingFields)+1)
+	for _, field := range s.orderingFields {
+		sort = append(sort, bson.E{Key: field, Value: 1})
+	}
+
+	if !s.idLess && tieBreaksOnID(s.orderingFields) {
+		sort = append(sort, bson.E{Key: idFieldName, Value: 1})
+	}
+
+	opts := options.Find().
+		SetSort(sort).
+		SetLimit(int64(s.batchSize))
+
+	if s.sortStrategy == sortStrategyDisk {
+		opts = opts.SetAllowDiskUse(true)
+	}
+
+	return opts
+}
+
+// fallbackSort switches the snapshot to the next fallback sort strategy. It returns false
+// if there's no strategy left. Falling back to the _id pagination restarts the snapshot,
+// as the documents returned so far are ordered by other fields, so they may be returned again.
+// Polling snapshots never fall back to the _id pagination, as they'd miss documents inserted meanwhile,
+// and neither do snapshots of _id-less collections.
+func (s *snapshot) fallbackSort(ctx context.Context) (bool, error) {
+	if s.sortStrategy == "" && s.allowDiskUse {
+		s.sortStrategy = sortStrategyDisk
+
+		return true, nil
+	}
+
+	if s.sortStrategy == sortStrategyID || s.polling || s.idLess ||
+		slices.Equal(s.orderingFields, []string{idFieldName}) {
+		return false, nil
+	}
+
+	maxID, err := getMaxFieldValue(ctx, s.collection, []string{idFieldName})
+	if err != nil && !errors.Is(err, errNoDocuments) {
+		return false, fmt.Errorf("get _id max value: %w", err)
+	}
+
+	s.sortStrategy = sortStrategyID
+	s.orderingFields = []string{idFieldName}
+	s.orderingFieldMaxValue = maxID
+	s.progress.emitted = 0
+
+	if s.position != nil {
+		s.position.Element = nil
+		s.position.ElementID = nil
+	}
+
+	return true, nil
+}
+
+// setSortStrategy reports the fallback sort strategy in the record metadata, if the snapshot fell back to one.
+func (s *snapshot) setSortStrategy(metadata opencdc.Metadata) {
+	if s.sortStrategy != "" {
+		metadata[metadataFieldSortStrategy] = string(s.sortStrategy)
+	}
+}
+
+// isSortMemoryErr checks whether the provided error is returned by MongoDB
+// because a sort exceeded the memory limit.
+func isSortMemoryErr(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+
+	return serverErr.HasErrorCode(queryExceededMemoryLimitErrCode) ||
+		serverErr.HasErrorCode(sortExceededMemoryLimitErrCode) ||
+		serverErr.HasErrorCode(externalSortFailedErrCode)
+}
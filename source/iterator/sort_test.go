@@ -0,0 +1,82 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/matryer/is"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestIsSortMemoryErr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "query_exceeded_memory_limit", err: mongo.CommandError{Code: queryExceededMemoryLimitErrCode}, want: true},
+		{name: "sort_exceeded_memory_limit", err: mongo.CommandError{Code: sortExceededMemoryLimitErrCode}, want: true},
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("execute find: %w", mongo.CommandError{Code: externalSortFailedErrCode}),
+			want: true,
+		},
+		{name: "other_server_error", err: mongo.CommandError{Code: 11000}, want: false},
+		{name: "not_server_error", err: errors.New("some error"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			is := is.New(t)
+			is.Equal(isSortMemoryErr(tt.err), tt.want)
+		})
+	}
+}
+
+func TestSnapshot_fallbackSort(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	s := &snapshot{orderingFields: []string{idFieldName}, allowDiskUse: true}
+	is.True(s.findOptions().AllowDiskUse == nil)
+
+	// the disk sort is tried first
+	ok, err := s.fallbackSort(context.Background())
+	is.NoErr(err)
+	is.True(ok)
+	is.Equal(s.sortStrategy, sortStrategyDisk)
+	is.Equal(*s.findOptions().AllowDiskUse, true)
+
+	// snapshots ordered by the _id have no strategy left
+	ok, err = s.fallbackSort(context.Background())
+	is.NoErr(err)
+	is.True(!ok)
+
+	// as well as polling snapshots
+	s = &snapshot{orderingFields: []string{"createdAt"}, polling: true}
+	ok, err = s.fallbackSort(context.Background())
+	is.NoErr(err)
+	is.True(!ok)
+	is.Equal(s.sortStrategy, sortStrategy(""))
+}
@@ -0,0 +1,86 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"crypto/sha256"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// The names of Change Stream event fields used to suppress unchanged updates are listed below.
+const (
+	operationTypeFieldName = "operationType"
+	fullDocumentFieldName  = "fullDocument"
+)
+
+// changeSuppressor suppresses update events whose full document is byte-identical
+// to the previously emitted version of the document, e.g. caused by application-level touch writes.
+// It keeps hashes of the latest emitted documents by their keys. Once the max number of keys is reached,
+// the hashes are cleared, so it never grows unbounded.
+type changeSuppressor struct {
+	size   int
+	hashes map[string][sha256.Size]byte
+}
+
+// newChangeSuppressor creates a new instance of the [changeSuppressor] that remembers up to size documents.
+// It returns nil if the size is zero, which means suppression is disabled.
+func newChangeSuppressor(size int) *changeSuppressor {
+	if size <= 0 {
+		return nil
+	}
+
+	return &changeSuppressor{
+		size:   size,
+		hashes: make(map[string][sha256.Size]byte),
+	}
+}
+
+// unchanged checks whether the raw Change Stream event is an update that doesn't change the document,
+// remembering the hash of its document otherwise. A nil suppressor never suppresses events.
+func (s *changeSuppressor) unchanged(event bson.Raw) bool {
+	if s == nil {
+		return false
+	}
+
+	documentKey, ok := event.Lookup(documentKeyFieldName).DocumentOK()
+	if !ok {
+		return false
+	}
+
+	key := string(documentKey)
+	operationType, _ := event.Lookup(operationTypeFieldName).StringValueOK()
+
+	fullDocument, ok := event.Lookup(fullDocumentFieldName).DocumentOK()
+	if operationType == operationTypeDelete || !ok {
+		// the next version of the document doesn't have anything to compare with
+		delete(s.hashes, key)
+
+		return false
+	}
+
+	hash := sha256.Sum256(fullDocument)
+	if previous, ok := s.hashes[key]; ok && previous == hash && operationType == operationTypeUpdate {
+		return true
+	}
+
+	if _, ok := s.hashes[key]; !ok && len(s.hashes) >= s.size {
+		clear(s.hashes)
+	}
+
+	s.hashes[key] = hash
+
+	return false
+}
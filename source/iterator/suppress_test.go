@@ -0,0 +1,75 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestChangeSuppressor_unchanged(t *testing.T) {
+	t.Parallel()
+
+	event := func(operationType string, id int, document bson.D) bson.Raw {
+		fields := bson.D{
+			{Key: operationTypeFieldName, Value: operationType},
+			{Key: documentKeyFieldName, Value: bson.D{{Key: idFieldName, Value: id}}},
+		}
+		if document != nil {
+			fields = append(fields, bson.E{Key: fullDocumentFieldName, Value: document})
+		}
+
+		raw, err := bson.Marshal(fields)
+		if err != nil {
+			t.Fatalf("bson.Marshal() error = %v", err)
+		}
+
+		return raw
+	}
+
+	touched := bson.D{{Key: "name", Value: "alice"}}
+	changed := bson.D{{Key: "name", Value: "bob"}}
+
+	suppressor := newChangeSuppressor(2)
+
+	steps := []struct {
+		name  string
+		event bson.Raw
+		want  bool
+	}{
+		{name: "insert", event: event(operationTypeInsert, 1, touched), want: false},
+		{name: "touch_update", event: event(operationTypeUpdate, 1, touched), want: true},
+		{name: "changing_update", event: event(operationTypeUpdate, 1, changed), want: false},
+		{name: "touch_update_after_change", event: event(operationTypeUpdate, 1, changed), want: true},
+		{name: "delete", event: event(operationTypeDelete, 1, nil), want: false},
+		{name: "update_after_delete", event: event(operationTypeUpdate, 1, changed), want: false},
+		{name: "other_key", event: event(operationTypeUpdate, 2, changed), want: false},
+		// the cache is full, so it's cleared and the next update of the first key isn't suppressed
+		{name: "overflow", event: event(operationTypeUpdate, 3, changed), want: false},
+		{name: "update_after_overflow", event: event(operationTypeUpdate, 1, changed), want: false},
+	}
+
+	// the steps depend on each other, so they aren't run in parallel
+	for _, step := range steps {
+		if got := suppressor.unchanged(step.event); got != step.want {
+			t.Errorf("%s: changeSuppressor.unchanged() = %v, want %v", step.name, got, step.want)
+		}
+	}
+
+	if newChangeSuppressor(0).unchanged(event(operationTypeUpdate, 1, touched)) {
+		t.Errorf("a nil suppressor suppresses events")
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iterator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+	sdk "github.com/conduitio/conduit-connector-sdk"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// tailable implements a Change Data Capture iterator that tails a capped collection with a tailable cursor.
+// Capped collections keep documents in their insertion order and don't allow deletes,
+// so only inserts are captured. The position is the _id of the last read document,
+// which requires _ids to increase in the insertion order, e.g. ObjectIDs generated by the server.
+type tailable struct {
+	collection    *mongo.Collection
+	cursor        *mongo.Cursor
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+	// lastID is the _id of the last read document. If it's nil, the collection is tailed from its beginning.
+	lastID any
+}
+
+// tailableParams is an incoming params for the [newTailable] function.
+type tailableParams struct {
+	collection    *mongo.Collection
+	position      *position
+	payloadFormat PayloadFormat
+	keyFormat     KeyFormat
+	converter     codec.Converter
+	buffers       *codec.BufferPool
+	payloadSchema *payloadSchema
+	schemaDrift   *schemaDrift
+}
+
+// newTailable creates a new instance of the [tailable] iterator. It starts after the _id of the position,
+// or after the latest document if there's no position or it's a position of another CDC mode.
+// A snapshot position without the _id is taken while the collection was empty, so it's tailed from its beginning.
+func newTailable(ctx context.Context, params tailableParams) (*tailable, error) {
+	capped, err := isCapped(ctx, params.collection)
+	if err != nil {
+		return nil, fmt.Errorf("check collection is capped: %w", err)
+	}
+
+	if !capped {
+		return nil, errNotCappedCollection
+	}
+
+	tailer := &tailable{
+		collection:    params.collection,
+		payloadFormat: params.payloadFormat,
+		keyFormat:     params.keyFormat,
+		converter:     params.converter,
+		buffers:       params.buffers,
+		payloadSchema: params.payloadSchema,
+		schemaDrift:   params.schemaDrift,
+	}
+
+	switch pos := params.position; {
+	case pos != nil && (pos.TailID != nil || pos.Mode == modeSnapshot):
+		tailer.lastID = pos.TailID
+
+	default:
+		tailer.lastID, err = latestID(ctx, params.collection)
+		if err != nil {
+			return nil, fmt.Errorf("get latest document id: %w", err)
+		}
+	}
+
+	if err := tailer.tail(ctx); err != nil {
+		return nil, fmt.Errorf("tail collection: %w", err)
+	}
+
+	return tailer, nil
+}
+
+// tail opens a tailable cursor returning documents inserted after the last read one.
+func (t *tailable) tail(ctx context.Context) error {
+	filter := bson.M{}
+	if t.lastID != nil {
+		filter[idFieldName] = bson.M{"$gt": t.lastID}
+	}
+
+	opts := options.Find().SetCursorType(options.TailableAwait)
+
+	cursor, err := t.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return fmt.Errorf("execute find: %w", err)
+	}
+
+	t.cursor = cursor
+
+	return nil
+}
+
+// hasNext checks whether the [tailable] iterator has records to return or not.
+// If the tailable cursor is closed by the server, e.g. because the collection was empty,
+// it's reopened from the last read document.
+func (t *tailable) hasNext(ctx context.Context) (bool, error) {
+	if t.cursor.TryNext(ctx) {
+		return true, nil
+	}
+
+	if err := t.cursor.Err(); err != nil {
+		return false, fmt.Errorf("tailable cursor: %w", err)
+	}
+
+	if t.cursor.ID() == 0 {
+		// the cursor is already dead, so an error of closing it doesn't matter
+		_ = t.cursor.Close(ctx)
+
+		if err := t.tail(ctx); err != nil {
+			return false, fmt.Errorf("reopen tailable cursor: %w", err)
+		}
+	}
+
+	return false, nil
+}
+
+// next returns the next record.
+func (t *tailable) next(ctx context.Context) (opencdc.Record, error) {
+	document := t.cursor.Current
+
+	id, err := document.LookupErr(idFieldName)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("lookup document id: %w", err)
+	}
+
+	record, err := t.toRecord(document, id)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document to opencdc.Record: %w", err)
+	}
+
+	t.lastID = id
+
+	// keep the converted document for the payload schema, as formatting may replace the payload
+	converted, _ := record.Payload.After.(opencdc.StructuredData)
+
+	record, err = formatRecord(record, t.payloadFormat, t.collection.Database().Name(), document, t.converter, t.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record payload: %w", err)
+	}
+
+	if t.payloadSchema != nil {
+		record = t.payloadSchema.attach(record, converted)
+	}
+
+	record = t.schemaDrift.check(ctx, record, document)
+
+	record, err = formatKey(record, t.keyFormat, t.converter.IDType, t.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("format record key: %w", err)
+	}
+
+	return record, nil
+}
+
+// toRecord converts the inserted document with the _id to an [opencdc.Record].
+func (t *tailable) toRecord(document bson.Raw, id bson.RawValue) (opencdc.Record, error) {
+	position := &position{
+		Mode:   modeCDC,
+		TailID: id,
+	}
+
+	sdkPosition, err := position.marshalSDKPosition(t.buffers)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("marshal position into opencdc.Position: %w", err)
+	}
+
+	metadata := make(opencdc.Metadata)
+	metadata[metadataFieldCollection] = t.collection.Name()
+	metadata.SetCreatedAt(time.Now())
+
+	key, err := t.converter.ConvertRaw(oplogDocumentKey(document))
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document key: %w", err)
+	}
+
+	converted, err := t.converter.ConvertRaw(document)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("convert document: %w", err)
+	}
+
+	return sdk.Util.Source.NewRecordCreate(
+		sdkPosition, metadata, opencdc.StructuredData(key), opencdc.StructuredData(converted),
+	), nil
+}
+
+// stop stops the iterator.
+func (t *tailable) stop(ctx context.Context) error {
+	if t.cursor != nil {
+		if err := t.cursor.Close(ctx); err != nil {
+			return fmt.Errorf("close tailable cursor: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// currentID returns the _id of the last read document, or nil if none has been read yet.
+func (t *tailable) currentID() any {
+	return t.lastID
+}
+
+// isCapped checks whether the collection is capped.
+func isCapped(ctx context.Context, collection *mongo.Collection) (bool, error) {
+	specifications, err := collection.Database().ListCollectionSpecifications(ctx, bson.M{"name": collection.Name()})
+	if err != nil {
+		return false, fmt.Errorf("list collection specifications: %w", err)
+	}
+
+	if len(specifications) == 0 || specifications[0].Options == nil {
+		return false, nil
+	}
+
+	capped, _ := specifications[0].Options.Lookup("capped").BooleanOK()
+
+	return capped, nil
+}
+
+// latestID returns the _id of the latest document in the insertion order, or nil if the collection is empty.
+func latestID(ctx context.Context, collection *mongo.Collection) (any, error) {
+	opts := options.FindOne().SetSort(bson.D{{Key: "$natural", Value: -1}}).SetProjection(bson.M{idFieldName: 1})
+
+	document, err := collection.FindOne(ctx, bson.M{}, opts).Raw()
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil //nolint:nilnil // an empty collection has no latest _id
+		}
+
+		return nil, fmt.Errorf("find latest document: %w", err)
+	}
+
+	return document.Lookup(idFieldName), nil
+}
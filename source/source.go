@@ -19,13 +19,16 @@ package source
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
 	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/common"
 	mconfig "github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/metrics"
 	"github.com/conduitio-labs/conduit-connector-mongo/source/iterator"
+	"github.com/conduitio-labs/conduit-connector-mongo/transform"
 	"github.com/conduitio/conduit-commons/config"
 	"github.com/conduitio/conduit-commons/lang"
 	"github.com/conduitio/conduit-commons/opencdc"
@@ -35,6 +38,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// metadataFieldConnectorVersion is a name of a record metadata field that stores the version of the connector.
+const metadataFieldConnectorVersion = "mongo.connector.version"
+
+// metricsNameSuffix is appended to the connector ID to get the name the source's metrics are published under.
+const metricsNameSuffix = ".source"
+
 // Iterator defines an Iterator interface needed for the [Source].
 type Iterator interface {
 	HasNext(context.Context) (bool, error)
@@ -49,14 +58,35 @@ type Source struct {
 	config   Config
 	client   *mongo.Client
 	iterator Iterator
+	// snapshotClient is the client snapshots read documents through. If it's nil, they use the client.
+	snapshotClient *mongo.Client
+	// transform computes derived fields and filters records. If it's nil, records are not transformed.
+	transform *transform.Transform
+	// dbRefs normalizes or resolves DBRefs of documents. If it's nil, DBRefs are kept as they are.
+	dbRefs *iterator.DBRefResolver
+	// partitionKeyer attaches partition keys to records. If it's nil, no partition keys are attached.
+	partitionKeyer *iterator.PartitionKeyer
+	// buffers are used to serialize records, a nil pool disables pooling.
+	buffers *codec.BufferPool
+	// version is the version of the connector stamped on records, nothing is stamped if it's empty.
+	version string
+	// metrics are the metrics of the records the source reads.
+	metrics *metrics.Source
 }
 
 // NewSource creates a new instance of the [Source].
 func NewSource() sdk.Source {
+	return NewSourceWithVersion("")
+}
+
+// NewSourceWithVersion creates a new instance of the [Source] that stamps records
+// with the provided connector version.
+func NewSourceWithVersion(version string) sdk.Source {
 	return sdk.SourceWithMiddleware(
-		&Source{},
+		&Source{version: version, metrics: &metrics.Source{}},
 		sdk.DefaultSourceMiddleware(
 			// disable schema extraction by default, because the source produces raw data
+			// unless it attaches a payload schema generated from the collection itself
 			sdk.SourceWithSchemaExtractionConfig{
 				PayloadEnabled: lang.Ptr(false),
 				KeyEnabled:     lang.Ptr(false),
@@ -65,6 +95,17 @@ func NewSource() sdk.Source {
 	)
 }
 
+// NewSourceWithConfig creates a new instance of the [Source] that's already configured with the parsed config.
+// Unlike [NewSource], it returns the source without the SDK middleware, so it can be embedded into Go programs.
+func NewSourceWithConfig(cfg Config) *Source {
+	return &Source{config: cfg, metrics: &metrics.Source{}}
+}
+
+// Metrics returns the metrics of the records the source reads.
+func (s *Source) Metrics() *metrics.Source {
+	return s.metrics
+}
+
 // Parameters is a map of named Parameters that describe how to configure the [Source].
 //
 //nolint:funlen,nolintlint // yeah, this function can become long at some point.
@@ -115,6 +156,71 @@ func (s *Source) Parameters() config.Parameters {
 			Default:     "",
 			Description: "The path to the client certificate file or the client private key file.",
 		},
+		mconfig.KeyAuthTLSInsecureSkipVerify: {
+			Default: "false",
+			Description: "The field determines whether or not the connector skips the verification " +
+				"of the server's certificate chain and host name. It should only be used for development.",
+		},
+		mconfig.KeyAuthTLSServerName: {
+			Default:     "",
+			Description: "The host name used to verify the server's certificate instead of the one from the URI.",
+		},
+		mconfig.KeyAuthTLSMinVersion: {
+			Default:     "",
+			Description: "The minimum TLS version. The available values are 1.0, 1.1, 1.2 and 1.3.",
+		},
+		mconfig.KeyAtlasServerless: {
+			Default: "auto",
+			Description: "The Atlas Serverless compatibility mode. " +
+				"The available values are auto, enabled and disabled.",
+		},
+		mconfig.KeyBufferPoolEnabled: {
+			Default: "true",
+			Description: "The field determines whether or not records are serialized into pooled buffers, " +
+				"which reduces GC pressure under sustained load. Disabling it may be useful for debugging.",
+		},
+		mconfig.KeyTelemetryEnabled: {
+			Default: "true",
+			Description: "The field determines whether or not the client logs and monitors its operations, " +
+				"e.g. driver logs enabled via the MONGODB_LOG_* environment variables and collection statistics.",
+		},
+		mconfig.KeyCommandMonitorEnabled: {
+			Default: "false",
+			Description: "The field determines whether or not the client logs failed commands and the ones " +
+				"taking at least commandMonitor.slowThreshold, along with their durations. It requires telemetry.enabled.",
+		},
+		mconfig.KeyCommandMonitorSlowThreshold: {
+			Default:     "100ms",
+			Description: "The min duration of commands the command monitor logs as slow ones.",
+		},
+		mconfig.KeyObjectIDCodecEnabled: {
+			Default: "true",
+			Description: "The field determines whether or not string values that are valid hex representations " +
+				"of ObjectIDs are encoded into query filters as ObjectIDs. Disabling it keeps such strings as plain strings.",
+		},
+		mconfig.KeyIDType: {
+			Default: "auto",
+			Description: "The type of _id values, applied to record keys and document filters. " +
+				"The available values are auto, objectid, uuid, int and string.",
+		},
+		mconfig.KeyOpenMaxRetries: {
+			Default: "3",
+			Description: "The max number of times the connector checks again whether its database and collection " +
+				"exist on open, if they don't. Zero means the connector fails right away.",
+		},
+		mconfig.KeyOpenRetryBackoff: {
+			Default:     "1s",
+			Description: "The delay before the first open retry, every next retry waits twice as long.",
+		},
+		mconfig.KeySRVMaxHosts: {
+			Default: "0",
+			Description: "The max number of hosts randomly selected from the DNS seedlist of a mongodb+srv URI. " +
+				"Zero means no limit.",
+		},
+		mconfig.KeySRVServiceName: {
+			Default:     "",
+			Description: "The service name of the SRV records of a mongodb+srv URI. If it's empty, \"mongodb\" is used.",
+		},
 		ConfigKeyBatchSize: {
 			Default:     "1000",
 			Description: "The size of a document batch.",
@@ -127,7 +233,322 @@ func (s *Source) Parameters() config.Parameters {
 		ConfigKeyOrderingField: {
 			Default: "_id",
 			Description: "The name of a field that is used for ordering " +
-				"collection documents when capturing a snapshot.",
+				"collection documents when capturing a snapshot. " +
+				"It may be a comma-separated list of fields forming a compound sort key.",
+		},
+		ConfigKeySnapshotOnStaleToken: {
+			Default: "fail",
+			Description: "The field determines what the connector does when a stored resume token " +
+				"is no longer present in the oplog. " +
+				"If set to \"resnapshot\" the connector takes a fresh snapshot and starts CDC from the current time.",
+		},
+		ConfigKeySnapshotMode: {
+			Default: "blocking",
+			Description: "The way the connector captures a snapshot. " +
+				"If set to \"incremental\" the connector captures the snapshot in chunks " +
+				"interleaved with Change Stream events, so CDC is never paused.",
+		},
+		ConfigKeySnapshotTrigger: {
+			Default: "",
+			Description: "An arbitrary identifier of an incremental snapshot. " +
+				"Changing it makes the connector capture a new incremental snapshot without pausing CDC.",
+		},
+		ConfigKeySnapshotMaxBatchBytes: {
+			Default: "0",
+			Description: "The max total size of documents in a snapshot batch in bytes. " +
+				"Once it's exceeded, the rest of the batch is loaded by a new query. Zero means no limit.",
+		},
+		ConfigKeySnapshotCheckpointRecords: {
+			Default: "0",
+			Description: "The number of blocking snapshot records the position is checkpointed after. " +
+				"The records in between repeat the latest checkpoint, so a resumed snapshot emits them once again. " +
+				"Zero means it's not limited by records.",
+		},
+		ConfigKeySnapshotCheckpointInterval: {
+			Default: "0s",
+			Description: "The time the blocking snapshot position is checkpointed after. " +
+				"Zero means it's not limited by time. If both limits are zero, every record gets its own position.",
+		},
+		ConfigKeySnapshotMaxDocsPerSecond: {
+			Default: "0",
+			Description: "The max number of documents per second snapshots read, " +
+				"so initial loads don't starve application traffic. Zero means no limit.",
+		},
+		ConfigKeySnapshotAllowDiskUse: {
+			Default: "true",
+			Description: "The field determines whether or not the connector retries a snapshot query " +
+				"that exceeded the memory limit of sorts with sorting on disk allowed, " +
+				"before falling back to paginating documents by their _id.",
+		},
+		ConfigKeySnapshotResumeBoundary: {
+			Default: "exclusive",
+			Description: "The way a resumed snapshot treats the last document emitted before the restart. " +
+				"If set to \"inclusive\" the document is emitted once again, " +
+				"if set to \"exclusive\" the snapshot starts right after it.",
+		},
+		ConfigKeySnapshotSharded: {
+			Default: "false",
+			Description: "The field determines whether or not the blocking snapshot of a sharded collection " +
+				"reads its chunks in parallel, each from the shard owning it, " +
+				"instead of sorting all documents by the ordering field.",
+		},
+		ConfigKeySnapshotConsistent: {
+			Default: "false",
+			Description: "The field determines whether or not the blocking snapshot reads all documents " +
+				"at the cluster time it starts at, and the Change Stream starts right after that time, " +
+				"so writes made during the snapshot are neither missed nor captured twice. " +
+				"It requires MongoDB 5.0 or later.",
+		},
+		ConfigKeySnapshotParallelism: {
+			Default:     "4",
+			Description: "The max number of chunks the sharded snapshot reads at the same time.",
+		},
+		ConfigKeySnapshotURI: {
+			Default: "",
+			Description: "The connection string snapshots read documents through, " +
+				"e.g. a federated connection string of an Atlas Online Archive, " +
+				"while CDC runs against the uri. If it's empty, the uri is used for both.",
+		},
+		ConfigKeyReadConcernLevel: {
+			Default: "",
+			Description: "The read concern level of snapshot queries and the Change Stream. " +
+				"The available values are local, majority and snapshot. " +
+				"If it's empty, the read concern of the connection string or the server default is used.",
+		},
+		ConfigKeyReadPreferenceMode: {
+			Default: "",
+			Description: "The read preference mode snapshots and the Change Stream read with, so they can target " +
+				"secondary or analytics nodes. The available values are primary, primaryPreferred, secondary, " +
+				"secondaryPreferred and nearest. If it's empty, the read preference of the connection string is used.",
+		},
+		ConfigKeyReadPreferenceTags: {
+			Default: "",
+			Description: "The semicolon-separated list of tag sets of the read preference, each one being " +
+				"a comma-separated list of name:value tags, e.g. \"nodeType:ANALYTICS,dc:east;nodeType:ANALYTICS\". " +
+				"It requires readPreference.mode to be set to a mode other than primary.",
+		},
+		ConfigKeySignalCollection: {
+			Default: "",
+			Description: "The name of a collection in the same database the connector reads control documents from, " +
+				"allowing operators to trigger incremental snapshots and pause or resume the connector at runtime.",
+		},
+		ConfigKeyRateLimit: {
+			Default: "0",
+			Description: "The max number of records per second the connector reads, both during a snapshot and CDC, " +
+				"so a backfill doesn't saturate the database. Zero means no limit.",
+		},
+		ConfigKeyPayloadFormat: {
+			Default: "json",
+			Description: "The format of records' payloads. " +
+				"If set to \"extjson\" the connector puts documents into payloads as canonical Extended JSON. " +
+				"If set to \"debezium\" the connector wraps documents into Debezium-compatible envelopes.",
+		},
+		ConfigKeyKeyFormat: {
+			Default: "structured",
+			Description: "The format of records' keys. " +
+				"If set to \"json\" the connector puts keys into records as canonical raw JSON. " +
+				"If set to \"string\" the connector puts the _id value into records' keys as a raw string.",
+		},
+		ConfigKeyCDCStartAtOperationTime: {
+			Default: "",
+			Description: "The cluster time the Change Stream starts from if there's no resume token to resume from. " +
+				"The value is either an RFC 3339 date and time or a timestamp in the \"<seconds>[.<increment>]\" form.",
+		},
+		ConfigKeyCDCStopAtOperationTime: {
+			Default: "",
+			Description: "The cluster time the connector stops capturing changes at, so changes after it " +
+				"are not read. The value is either an RFC 3339 date and time " +
+				"or a timestamp in the \"<seconds>[.<increment>]\" form.",
+		},
+		ConfigKeyCDCResumeToken: {
+			Default: "",
+			Description: "The Change Stream resume token the connector resumes after, skipping the snapshot, " +
+				"unless the stored position is already past it. The value is either the hex-encoded _data field " +
+				"of the token or the base64-encoded BSON document of the whole token.",
+		},
+		ConfigKeySnapshotCollectionMetadata: {
+			Default: "false",
+			Description: "The field determines whether or not the connector emits a record describing " +
+				"the collection options, validator and indexes at the start of a snapshot.",
+		},
+		ConfigKeySchemaMode: {
+			Default: "none",
+			Description: "The way the connector generates a payload schema of the collection. " +
+				"If set to \"sample\" the schema is generated by sampling documents, " +
+				"if set to \"validator\" it's generated from the collection's $jsonSchema validator.",
+		},
+		ConfigKeySchemaSampleSize: {
+			Default:     "100",
+			Description: "The number of documents sampled to generate a payload schema.",
+		},
+		ConfigKeySchemaDrift: {
+			Default: "none",
+			Description: "The way the connector reports documents whose field set or field types differ " +
+				"from the first-seen document. The available values are none, log and metadata.",
+		},
+		ConfigKeyCDCVerifyResume: {
+			Default: "false",
+			Description: "The field determines whether or not the connector verifies that the Change Stream " +
+				"can be resumed by reopening it with its initial resume token when the connector starts.",
+		},
+		ConfigKeyCDCCollectionEvents: {
+			Default: "false",
+			Description: "The field determines whether or not the connector emits records describing drops " +
+				"and renames of the collection and drops of its database, marked with the collectionEvent record type.",
+		},
+		ConfigKeyCDCMaxRetries: {
+			Default: "0",
+			Description: "The max number of times the connector recreates a failed Change Stream " +
+				"from its latest resume token before returning the error. Zero means no retries.",
+		},
+		ConfigKeyCDCHeartbeatInterval: {
+			Default: "0",
+			Description: "How long the Change Stream has to stay quiet before the connector emits " +
+				"a heartbeat record carrying its latest resume token, so the position doesn't go stale " +
+				"on idle collections. Zero means no heartbeats.",
+		},
+		ConfigKeyCDCMaxAwaitTime: {
+			Default: "0",
+			Description: "How long the server waits for new events before responding to a request of " +
+				"the next batch of the Change Stream, e.g. 5s. Longer waits mean fewer empty reads " +
+				"of quiet collections, while shorter ones make the connector react to pauses sooner. " +
+				"Zero means the server default of 1s is used.",
+		},
+		ConfigKeyCDCPrefetchSize: {
+			Default: "0",
+			Description: "The number of Change Stream records the connector reads and decodes ahead " +
+				"in the background, so reads overlap with the processing of previous records. " +
+				"Zero means records are read when they're requested.",
+		},
+		ConfigKeyCDCMode: {
+			Default: "auto",
+			Description: "The way the connector captures changes. " +
+				"The available values are auto, changestream, oplog and tailable. " +
+				"If set to \"auto\" the connector uses a Change Stream and falls back to tailing the oplog " +
+				"if the deployment doesn't support Change Streams, " +
+				"if set to \"tailable\" the connector tails the capped collection with a tailable cursor.",
+		},
+		ConfigKeyCDCSuppressUnchanged: {
+			Default: "false",
+			Description: "The field determines whether or not the connector skips update events whose full document " +
+				"is byte-identical to the previously emitted version of the document, e.g. caused by touch writes.",
+		},
+		ConfigKeyCDCSuppressCacheSize: {
+			Default:     "10000",
+			Description: "The max number of documents whose hashes the connector keeps to suppress unchanged updates.",
+		},
+		ConfigKeyCDCCoalesceWindow: {
+			Default: "0",
+			Description: "How long the connector buffers Change Stream records to coalesce records " +
+				"of the same documents into their latest states. Zero means records are not coalesced.",
+		},
+		ConfigKeyCDCCoalesceMaxSize: {
+			Default:     "1000",
+			Description: "The max number of distinct documents the connector buffers within the coalesce window.",
+		},
+		ConfigKeyPollingUpdatedAtField: {
+			Default: "",
+			Description: "The name of a field containing the time of a document's last update. " +
+				"If it's set, the connector also polls for updated documents when CDC is not possible.",
+		},
+		ConfigKeyPollingDeleteStrategy: {
+			Default: "none",
+			Description: "The way the connector detects deleted documents when CDC is not possible. " +
+				"If set to \"idset\" the connector periodically compares the _ids of documents with the known ones. " +
+				"If set to \"softdelete\" the connector reports documents having the soft delete field set as deleted.",
+		},
+		ConfigKeyPollingSoftDeleteField: {
+			Default:     "",
+			Description: "The name of a field marking a document as deleted, used by the softdelete delete strategy.",
+		},
+		ConfigKeyPollingDeleteCheckInterval: {
+			Default: "1m",
+			Description: "How often the connector compares the _ids of documents with the known ones, " +
+				"used by the idset delete strategy.",
+		},
+		ConfigKeyCompatibility: {
+			Default: "none",
+			Description: "The MongoDB-compatible database the connector adapts the Change Stream to. " +
+				"If set to \"cosmosdb\" the connector uses the Change Stream pipeline shape " +
+				"Azure CosmosDB for MongoDB requires instead of falling back to insert-only polling. " +
+				"If set to \"ferretdb\" the connector polls for new documents. FerretDB is detected automatically.",
+		},
+		ConfigKeyCSFLEKeyVaultNamespace: {
+			Default: "",
+			Description: "The namespace of the key vault collection data encryption keys of " +
+				"client-side field level encryption are stored in, in the <db>.<collection> format.",
+		},
+		ConfigKeyCSFLEKMSProviders: {
+			Default: "",
+			Description: "An Extended JSON document with credentials of the KMS providers " +
+				"data encryption keys are encrypted with. If it's set, the connector decrypts encrypted fields.",
+		},
+		ConfigKeyCSFLESchemaMap: {
+			Default: "",
+			Description: "An Extended JSON document mapping namespaces to JSON schemas of their encrypted fields. " +
+				"If it's set, the connector also encrypts commands, which requires mongocryptd or the shared library.",
+		},
+		ConfigKeyTransformFilter: {
+			Default: "",
+			Description: "An expression records are filtered by, e.g. status == \"active\" && total > 100. " +
+				"Records of documents it's false for are skipped.",
+		},
+		ConfigKeyTransformFields: {
+			Default: "",
+			Description: "A JSON object mapping names of derived fields to expressions they're computed by, " +
+				"e.g. {\"fullName\": \"first + ' ' + last\"}.",
+		},
+		ConfigKeyPartitionKey: {
+			Default: "none",
+			Description: "The partition key the connector puts into the mongo.partitionKey metadata field. " +
+				"If set to \"hash\" it's a hash of the record key, if set to \"shard\" the values of " +
+				"the shard key fields of the document.",
+		},
+		ConfigKeyDBRefMode: {
+			Default: "none",
+			Description: "The field determines how the connector treats DBRefs. If set to \"normalize\" " +
+				"it replaces them with documents with the collection, id and db fields, " +
+				"if set to \"resolve\" with the documents they refer to. It requires the json payload format.",
+		},
+		ConfigKeyDBRefMaxDepth: {
+			Default: "1",
+			Description: "The max depth of DBRefs the connector resolves, references of resolved documents " +
+				"deeper than it are normalized.",
+		},
+		ConfigKeyConvertDateTime: {
+			Default: "rfc3339",
+			Description: "The representation BSON dates are converted to. " +
+				"If set to \"millis\" the connector converts dates to milliseconds since the Unix epoch, " +
+				"if set to \"native\" it keeps them as native time values in structured payloads, " +
+				"which requires \"schema.mode\" to be set.",
+		},
+		ConfigKeyConvertTimestamp: {
+			Default: "timestamp",
+			Description: "The representation BSON timestamps are converted to. " +
+				"If set to \"date\" the connector converts timestamps the same way as dates, " +
+				"losing their increments.",
+		},
+		ConfigKeyConvertDecimal: {
+			Default: "string",
+			Description: "The representation BSON decimals are converted to. " +
+				"If set to \"float\" the connector converts decimals to floating-point numbers, which may lose precision.",
+		},
+		ConfigKeyConvertUUID: {
+			Default: "bytes",
+			Description: "The representation BSON binary UUIDs are converted to. " +
+				"If set to \"string\" the connector converts UUIDs to canonical UUID strings.",
+		},
+		ConfigKeyConvertBinary: {
+			Default: "bytes",
+			Description: "The representation BSON binaries are converted to. " +
+				"If set to \"base64\" the connector converts binaries to base64-encoded strings " +
+				"and records their subtypes in the mongo.binarySubtypes metadata field.",
+		},
+		ConfigKeyStrictTypes: {
+			Default: "false",
+			Description: "The field determines whether or not the connector fails with the field path and type " +
+				"on BSON values that can't be represented faithfully in the json payload format, " +
+				"instead of converting them.",
 		},
 	}
 }
@@ -145,45 +566,288 @@ func (s *Source) Configure(_ context.Context, raw config.Config) error {
 	return nil
 }
 
+// LifecycleOnCreated checks that the configured collection exists, can be read and its changes can be captured
+// in the configured CDC mode, so missing permissions or an unsupported deployment fail the pipeline's creation
+// instead of its first run. If a signal collection is configured, it checks that it exists too.
+func (s *Source) LifecycleOnCreated(ctx context.Context, raw config.Config) error {
+	sourceConfig, err := ParseConfig(raw)
+	if err != nil {
+		return fmt.Errorf("parse source config: %w", err)
+	}
+
+	// the capabilities are checked on the members the connector reads from
+	sourceConfig.Config.ReadPreference, err = sourceConfig.ReadPreference()
+	if err != nil {
+		return fmt.Errorf("get read preference: %w", err)
+	}
+
+	client, err := common.Connect(ctx, sourceConfig.Config, newBSONCodecRegistry(sourceConfig.ObjectIDCodec()))
+	if err != nil {
+		return fmt.Errorf("connect to mongo: %w", err)
+	}
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			sdk.Logger(ctx).Warn().Err(err).Msg("client disconnect")
+		}
+	}()
+
+	namespaces := common.NewNamespaces(client)
+
+	collection, err := namespaces.Collection(ctx, sourceConfig.DB, sourceConfig.Collection)
+	if err != nil {
+		return fmt.Errorf("get mongo collection: %w", err)
+	}
+
+	compatibility, err := detectCompatibility(ctx, client, sourceConfig.Compatibility)
+	if err != nil {
+		return err
+	}
+
+	err = iterator.CheckCapabilities(ctx, collection, sourceConfig.CDCMode, compatibility)
+	if err != nil {
+		return fmt.Errorf("check capabilities: %w", err)
+	}
+
+	if sourceConfig.SignalCollection != "" {
+		if _, err := namespaces.Collection(ctx, sourceConfig.DB, sourceConfig.SignalCollection); err != nil {
+			return fmt.Errorf("get mongo signal collection: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// LifecycleOnDeleted removes the signals addressed to the connector that are left unprocessed
+// in the signal collection, if it's configured, so they are not picked up by another connector
+// reusing the connector ID. Signals addressed to all connectors are kept, as the collection is owned by the operators.
+// The source keeps no other state, as its position is stored by Conduit.
+func (s *Source) LifecycleOnDeleted(ctx context.Context, raw config.Config) error {
+	sourceConfig, err := ParseConfig(raw)
+	if err != nil {
+		return fmt.Errorf("parse source config: %w", err)
+	}
+
+	if sourceConfig.SignalCollection == "" {
+		return nil
+	}
+
+	client, err := common.Connect(ctx, sourceConfig.Config, newBSONCodecRegistry(sourceConfig.ObjectIDCodec()))
+	if err != nil {
+		return fmt.Errorf("connect to mongo: %w", err)
+	}
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			sdk.Logger(ctx).Warn().Err(err).Msg("client disconnect")
+		}
+	}()
+
+	signalCollection := client.Database(sourceConfig.DB).Collection(sourceConfig.SignalCollection)
+
+	// signals addressed to all connectors are left to the other connectors reading the collection
+	deleted, err := iterator.DeleteConnectorSignals(ctx, signalCollection, sdk.ConnectorIDFromContext(ctx))
+	if err != nil {
+		return fmt.Errorf("delete connector signals: %w", err)
+	}
+
+	sdk.Logger(ctx).Info().Int64("count", deleted).Msg("deleted unprocessed signals addressed to the connector")
+
+	return nil
+}
+
 // Open opens needed connections and prepares to start producing records.
 func (s *Source) Open(ctx context.Context, sdkPosition opencdc.Position) error {
-	opts := s.config.GetClientOptions().SetRegistry(newBSONCodecRegistry())
+	clientConfig := s.config.Config
 
 	var err error
-	s.client, err = mongo.Connect(ctx, opts)
+	clientConfig.AutoEncryption, err = s.config.AutoEncryptionOptions()
 	if err != nil {
-		return fmt.Errorf("connect to mongo: %w", err)
+		return fmt.Errorf("get auto encryption options: %w", err)
 	}
 
-	if err = s.client.Ping(ctx, nil); err != nil {
-		return fmt.Errorf("ping mongo server: %w", err)
+	clientConfig.ReadPreference, err = s.config.ReadPreference()
+	if err != nil {
+		return fmt.Errorf("get read preference: %w", err)
 	}
 
-	collection, err := common.GetMongoCollection(ctx, s.client, s.config.DB, s.config.Collection)
+	s.transform, err = s.config.Transform()
+	if err != nil {
+		return fmt.Errorf("get transform: %w", err)
+	}
+
+	s.client, err = common.Connect(ctx, clientConfig, newBSONCodecRegistry(clientConfig.ObjectIDCodec()))
+	if err != nil {
+		return fmt.Errorf("connect to mongo: %w", err)
+	}
+
+	// the collection and the signal collection share the registry,
+	// so the database's collections are listed only once
+	namespaces := common.NewNamespaces(s.client)
+
+	collection, err := namespaces.CollectionWithRetry(ctx, s.config.DB, s.config.Collection,
+		s.config.OpenMaxRetries, s.config.OpenRetryBackoff)
 	if err != nil {
 		return fmt.Errorf("get mongo collection: %w", err)
 	}
 
+	if s.config.TelemetryEnabled {
+		common.LogCollectionStats(ctx, collection)
+	}
+
+	compatibility, err := detectCompatibility(ctx, s.client, s.config.Compatibility)
+	if err != nil {
+		return err
+	}
+
+	var snapshotCollection *mongo.Collection
+	if s.config.SnapshotURI != nil {
+		snapshotCollection, err = s.connectSnapshot(ctx, clientConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	var signalCollection *mongo.Collection
+	if s.config.SignalCollection != "" {
+		signalCollection, err = namespaces.CollectionWithRetry(ctx, s.config.DB, s.config.SignalCollection,
+			s.config.OpenMaxRetries, s.config.OpenRetryBackoff)
+		if err != nil {
+			return fmt.Errorf("get mongo signal collection: %w", err)
+		}
+	}
+
+	converter := codec.Converter{
+		DateTimeFormat:  s.config.ConvertDateTime,
+		TimestampFormat: s.config.ConvertTimestamp,
+		DecimalFormat:   s.config.ConvertDecimal,
+		UUIDFormat:      s.config.ConvertUUID,
+		BinaryFormat:    s.config.ConvertBinary,
+		Strict:          s.config.StrictTypes && s.config.PayloadFormat == iterator.PayloadFormatJSON,
+		IDType:          s.config.IDType,
+	}
+
+	s.partitionKeyer, err = iterator.NewPartitionKeyer(ctx, collection, s.config.PartitionKey)
+	if err != nil {
+		return fmt.Errorf("create partition keyer: %w", err)
+	}
+
+	s.dbRefs = iterator.NewDBRefResolver(collection.Database(), converter, s.config.DBRefMode, s.config.DBRefMaxDepth)
+
+	s.buffers = s.config.GetBufferPool()
+
 	s.iterator, err = iterator.NewCombined(ctx, iterator.CombinedParams{
-		Collection:    collection,
-		BatchSize:     s.config.BatchSize,
-		Snapshot:      s.config.Snapshot,
-		OrderingField: s.config.OrderingField,
-		SDKPosition:   sdkPosition,
+		Collection:                 collection,
+		BatchSize:                  s.config.BatchSize,
+		Snapshot:                   s.config.Snapshot,
+		OrderingFields:             s.config.OrderingFields(),
+		SDKPosition:                sdkPosition,
+		PayloadFormat:              s.config.PayloadFormat,
+		KeyFormat:                  s.config.KeyFormat,
+		StartAtOperationTime:       s.config.CDCStartAtOperationTime,
+		ResumeToken:                s.config.CDCResumeToken,
+		StopAtOperationTime:        s.config.CDCStopAtOperationTime,
+		ResnapshotOnStaleToken:     s.config.SnapshotOnStaleToken == StaleTokenResnapshot,
+		CollectionMetadata:         s.config.SnapshotCollectionMetadata,
+		SchemaMode:                 s.config.SchemaMode,
+		SchemaSampleSize:           s.config.SchemaSampleSize,
+		SchemaDriftMode:            s.config.SchemaDrift,
+		VerifyResume:               s.config.CDCVerifyResume,
+		CollectionEvents:           s.config.CDCCollectionEvents,
+		SnapshotMode:               s.config.SnapshotMode,
+		SnapshotTrigger:            s.config.SnapshotTrigger,
+		ReadConcernLevel:           s.config.ReadConcernLevel,
+		MaxBatchBytes:              s.config.SnapshotMaxBatchBytes,
+		CheckpointRecords:          s.config.SnapshotCheckpointRecords,
+		CheckpointInterval:         s.config.SnapshotCheckpointInterval,
+		SnapshotMaxDocsPerSecond:   s.config.SnapshotMaxDocsPerSecond,
+		AllowDiskUse:               s.config.SnapshotAllowDiskUse,
+		SnapshotSharded:            s.config.SnapshotSharded,
+		SnapshotParallelism:        s.config.SnapshotParallelism,
+		SnapshotConsistent:         s.config.SnapshotConsistent,
+		ResumeBoundary:             s.config.SnapshotResumeBoundary,
+		SignalCollection:           signalCollection,
+		ConnectorID:                sdk.ConnectorIDFromContext(ctx),
+		SnapshotCollection:         snapshotCollection,
+		Buffers:                    s.buffers,
+		RateLimit:                  s.config.RateLimit,
+		MaxRetries:                 s.config.CDCMaxRetries,
+		HeartbeatInterval:          s.config.CDCHeartbeatInterval,
+		MaxAwaitTime:               s.config.CDCMaxAwaitTime,
+		PrefetchSize:               s.config.CDCPrefetchSize,
+		SuppressUnchanged:          s.config.CDCSuppressUnchanged,
+		SuppressCacheSize:          s.config.CDCSuppressCacheSize,
+		CDCMode:                    s.config.CDCMode,
+		CoalesceWindow:             s.config.CDCCoalesceWindow,
+		CoalesceMaxSize:            s.config.CDCCoalesceMaxSize,
+		PollingUpdatedAtField:      s.config.PollingUpdatedAtField,
+		PollingDeleteStrategy:      s.config.PollingDeleteStrategy,
+		PollingSoftDeleteField:     s.config.PollingSoftDeleteField,
+		PollingDeleteCheckInterval: s.config.PollingDeleteCheckInterval,
+		Compatibility:              compatibility,
+		Converter:                  converter,
 	})
 	if err != nil {
 		return fmt.Errorf("create combined iterator: %w", err)
 	}
 
+	if id := sdk.ConnectorIDFromContext(ctx); id != "" {
+		metrics.Publish(id+metricsNameSuffix, s.metrics)
+	}
+
 	return nil
 }
 
-func newBSONCodecRegistry() *bsoncodec.Registry {
+// detectCompatibility returns the configured compatibility, or detects FerretDB if none is configured.
+func detectCompatibility(
+	ctx context.Context, client *mongo.Client, compatibility iterator.Compatibility,
+) (iterator.Compatibility, error) {
+	if compatibility != iterator.CompatibilityNone {
+		return compatibility, nil
+	}
+
+	ferretDB, err := common.IsFerretDB(ctx, client)
+	if err != nil {
+		return "", fmt.Errorf("detect ferretdb: %w", err)
+	}
+
+	if ferretDB {
+		sdk.Logger(ctx).Info().Msg("ferretdb detected, polling for new documents instead of using change streams")
+
+		return iterator.CompatibilityFerretDB, nil
+	}
+
+	return compatibility, nil
+}
+
+// connectSnapshot connects to the snapshot connection string, e.g. a federated one of an Atlas Online Archive,
+// and returns the collection snapshots read documents from. The connection uses the rest of the client config.
+func (s *Source) connectSnapshot(ctx context.Context, clientConfig mconfig.Config) (*mongo.Collection, error) {
+	clientConfig.URI = s.config.SnapshotURI
+
+	var err error
+	s.snapshotClient, err = common.Connect(ctx, clientConfig, newBSONCodecRegistry(clientConfig.ObjectIDCodec()))
+	if err != nil {
+		return nil, fmt.Errorf("connect to snapshot mongo: %w", err)
+	}
+
+	collection, err := common.NewNamespaces(s.snapshotClient).CollectionWithRetry(ctx, s.config.DB,
+		s.config.Collection, s.config.OpenMaxRetries, s.config.OpenRetryBackoff)
+	if err != nil {
+		return nil, fmt.Errorf("get snapshot mongo collection: %w", err)
+	}
+
+	return collection, nil
+}
+
+// newBSONCodecRegistry returns a registry decoding ObjectIDs into strings.
+// If objectIDCodec is true, strings that are valid hex representations of ObjectIDs are encoded into ObjectIDs.
+func newBSONCodecRegistry(objectIDCodec bool) *bsoncodec.Registry {
 	registry := bson.NewRegistry()
 
 	registry.RegisterTypeMapEntry(bson.TypeObjectID, reflect.TypeOf(""))
 	registry.RegisterTypeMapEntry(bson.TypeArray, reflect.TypeOf([]any{}))
-	registry.RegisterKindEncoder(reflect.String, codec.StringObjectIDCodec{})
+	if objectIDCodec {
+		registry.RegisterKindEncoder(reflect.String, codec.StringObjectIDCodec{})
+	}
 
 	return registry
 }
@@ -192,23 +856,71 @@ func newBSONCodecRegistry() *bsoncodec.Registry {
 // It can return the error [sdk.ErrBackoffRetry] to signal to the SDK
 // it should call Read again with a backoff retry.
 func (s *Source) Read(ctx context.Context) (opencdc.Record, error) {
-	hasNext, err := s.iterator.HasNext(ctx)
+	record, err := s.next(ctx)
 	if err != nil {
-		return opencdc.Record{}, fmt.Errorf("has next: %w", err)
+		if !errors.Is(err, sdk.ErrBackoffRetry) {
+			s.metrics.ObserveError()
+		}
+
+		return opencdc.Record{}, err
 	}
 
-	if !hasNext {
-		return opencdc.Record{}, sdk.ErrBackoffRetry
+	s.metrics.ObserveRecord(record)
+
+	record, err = s.partitionKeyer.Apply(record)
+	if err != nil {
+		return opencdc.Record{}, fmt.Errorf("attach partition key: %w", err)
 	}
 
-	record, err := s.iterator.Next(ctx)
+	record, err = iterator.EncodePayload(record, s.config.PayloadFormat, s.buffers)
 	if err != nil {
-		return opencdc.Record{}, fmt.Errorf("get next record: %w", err)
+		return opencdc.Record{}, fmt.Errorf("encode record payload: %w", err)
+	}
+
+	if s.version != "" {
+		if record.Metadata == nil {
+			record.Metadata = make(opencdc.Metadata)
+		}
+
+		record.Metadata[metadataFieldConnectorVersion] = s.version
 	}
 
 	return record, nil
 }
 
+// next returns the next record of the iterator, skipping records filtered out by the transformation.
+func (s *Source) next(ctx context.Context) (opencdc.Record, error) {
+	for {
+		hasNext, err := s.iterator.HasNext(ctx)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("has next: %w", err)
+		}
+
+		if !hasNext {
+			return opencdc.Record{}, sdk.ErrBackoffRetry
+		}
+
+		record, err := s.iterator.Next(ctx)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("get next record: %w", err)
+		}
+
+		record, err = s.dbRefs.Apply(ctx, record)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("resolve dbrefs: %w", err)
+		}
+
+		record, keep, err := s.transform.Apply(record)
+		if err != nil {
+			return opencdc.Record{}, fmt.Errorf("transform record: %w", err)
+		}
+
+		if keep {
+			return record, nil
+		}
+	}
+}
+
 // Ack just logs a provided position.
 func (s *Source) Ack(ctx context.Context, position opencdc.Position) error {
 	sdk.Logger(ctx).Debug().Str("position", string(position)).Msg("got ack")
@@ -218,6 +930,10 @@ func (s *Source) Ack(ctx context.Context, position opencdc.Position) error {
 
 // Teardown closes connections, stops iterators and prepares for a graceful shutdown.
 func (s *Source) Teardown(ctx context.Context) error {
+	if id := sdk.ConnectorIDFromContext(ctx); id != "" {
+		metrics.Unpublish(id + metricsNameSuffix)
+	}
+
 	if s.iterator != nil {
 		if err := s.iterator.Stop(ctx); err != nil {
 			return fmt.Errorf("stop iterator: %w", err)
@@ -230,5 +946,11 @@ func (s *Source) Teardown(ctx context.Context) error {
 		}
 	}
 
+	if s.snapshotClient != nil {
+		if err := s.snapshotClient.Disconnect(ctx); err != nil {
+			return fmt.Errorf("snapshot client disconnect: %w", err)
+		}
+	}
+
 	return nil
 }
@@ -16,6 +16,7 @@ package source
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -142,6 +143,212 @@ func TestSource_Read_successSnapshot(t *testing.T) {
 	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
 }
 
+func TestSource_Read_successSnapshotView(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection with a view of it under the configured name
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	baseName := sourceConfig[config.KeyCollection] + "_base"
+	is.NoErr(testDatabase.CreateCollection(ctx, baseName))
+	is.NoErr(testDatabase.CreateView(ctx, sourceConfig[config.KeyCollection], baseName, mongo.Pipeline{}))
+	testCollection := testDatabase.Collection(baseName)
+	testView := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test view and collection after the test
+	t.Cleanup(func() {
+		is.NoErr(testView.Drop(context.Background()))
+		is.NoErr(testCollection.Drop(context.Background()))
+	})
+
+	// insert a test item to the test collection
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationSnapshot)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+
+	// views don't support change streams, so new items are polled
+	testItem, err = createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	for range 10 {
+		record, err = source.Read(ctx)
+		if !errors.Is(err, sdk.ErrBackoffRetry) {
+			break
+		}
+	}
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+
+	err = source.Teardown(ctx)
+	is.NoErr(err)
+}
+
+func TestSource_Read_successSnapshotSharded(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeySnapshotSharded] = "true"
+	sourceConfig[ConfigKeySnapshotParallelism] = "2"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	// the collection is sharded only if the tests run against a sharded cluster,
+	// otherwise the connector falls back to the snapshot ordered by the ordering field
+	_ = mongoClient.Database("admin").RunCommand(ctx, bson.D{
+		{Key: "shardCollection", Value: testDatabase.Name() + "." + testCollection.Name()},
+		{Key: "key", Value: bson.D{{Key: "_id", Value: "hashed"}}},
+	}).Err()
+
+	// insert test items to the test collection
+	wantItems := make(map[string]opencdc.StructuredData)
+	for range 5 {
+		testItem, err := createTestItem(ctx, testCollection)
+		is.NoErr(err)
+
+		wantItems[testItem["_id"].(string)] = testItem //nolint:forcetypeassert // the _id is set as a string
+	}
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// chunks are read in parallel, so the documents aren't ordered
+	gotItems := make(map[string]opencdc.StructuredData)
+	for range wantItems {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, opencdc.OperationSnapshot)
+
+		var payload opencdc.StructuredData
+		is.NoErr(json.Unmarshal(record.Payload.After.Bytes(), &payload))
+
+		gotItems[payload["_id"].(string)] = payload //nolint:forcetypeassert // the _id is converted to a string
+	}
+
+	is.Equal(gotItems, wantItems)
+
+	err = source.Teardown(ctx)
+	is.NoErr(err)
+}
+
+func TestSource_Read_successSnapshotCollectionMetadata(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeySnapshotCollectionMetadata] = "true"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection with an index
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	_, err = testCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName("email_1").SetUnique(true),
+	})
+	is.NoErr(err)
+
+	// insert a test item to the test collection
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// check that the first record describes the collection
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationSnapshot)
+	is.Equal(record.Metadata["mongo.recordType"], "collectionMetadata")
+
+	var collectionMetadata struct {
+		Collection string `bson:"collection"`
+		Indexes    []struct {
+			Name   string `bson:"name"`
+			Unique bool   `bson:"unique"`
+		} `bson:"indexes"`
+	}
+	is.NoErr(bson.UnmarshalExtJSON(record.Payload.After.Bytes(), false, &collectionMetadata))
+	is.Equal(collectionMetadata.Collection, sourceConfig[config.KeyCollection])
+	is.Equal(len(collectionMetadata.Indexes), 2)
+	is.Equal(collectionMetadata.Indexes[1].Name, "email_1")
+	is.True(collectionMetadata.Indexes[1].Unique)
+
+	// check that the document follows the collection metadata
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationSnapshot)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+}
+
 func TestSource_Read_continueSnapshot(t *testing.T) {
 	is := is.New(t)
 
@@ -209,6 +416,68 @@ func TestSource_Read_continueSnapshot(t *testing.T) {
 	is.Equal(record.Payload.After, opencdc.RawData(secondTestItem.Bytes()))
 }
 
+func TestSource_Read_successSnapshotNonUniqueOrderingField(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config with a non-unique ordering field and one document per batch
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeyOrderingField] = "group"
+	sourceConfig[ConfigKeyBatchSize] = "1"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	// insert documents sharing the same value of the ordering field
+	const documentsCount = 3
+	for range documentsCount {
+		_, err = testCollection.InsertOne(ctx, bson.M{"group": "same"})
+		is.NoErr(err)
+	}
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(context.Background()))
+	})
+
+	// every document is returned exactly once, despite the equal values of the ordering field
+	ids := make(map[any]struct{})
+	for range documentsCount {
+		record, err := source.Read(ctx)
+		is.NoErr(err)
+		is.Equal(record.Operation, opencdc.OperationSnapshot)
+
+		key, ok := record.Key.(opencdc.StructuredData)
+		is.True(ok)
+
+		ids[key["_id"]] = struct{}{}
+	}
+
+	is.Equal(len(ids), documentsCount)
+}
+
 func TestSource_Read_successCDC(t *testing.T) {
 	is := is.New(t)
 
@@ -277,6 +546,458 @@ func TestSource_Read_successCDC(t *testing.T) {
 	is.Equal(record.Operation, opencdc.OperationDelete)
 }
 
+func TestSource_Read_successCDCOplog(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeyCDCMode] = "oplog"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// we expect backoff retry and switch to tailing the oplog here
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	// insert a test item to the test collection
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	// compare the record operation and its payload
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+
+	// update the test item
+	updatedTestItem, err := updateTestItem(ctx, testCollection, testItem)
+	is.NoErr(err)
+
+	// compare the record operation and its payload
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationUpdate)
+	is.Equal(record.Payload.After, opencdc.RawData(updatedTestItem.Bytes()))
+
+	// delete the test item
+	err = deleteTestItem(ctx, testCollection, updatedTestItem)
+	is.NoErr(err)
+
+	// compare the record operation, we expect it to be delete
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationDelete)
+	is.Equal(record.Key, opencdc.StructuredData{"_id": updatedTestItem["_id"]})
+}
+
+func TestSource_Read_successCDCTailable(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeyCDCMode] = "tailable"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the capped test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection],
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(1<<20)))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// insert a test item to the test collection
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	// the tailable cursor awaits new documents, so the item may take a few reads to show up
+	var record opencdc.Record
+	for range 10 {
+		record, err = source.Read(ctx)
+		if !errors.Is(err, sdk.ErrBackoffRetry) {
+			break
+		}
+	}
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+
+	err = source.Teardown(ctx)
+	is.NoErr(err)
+}
+
+func TestSource_Read_successCDCVerifyResume(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeyCDCVerifyResume] = "true"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	// the Change Stream is reopened from its initial resume token here
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// we expect backoff retry and switch to CDC mode here
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	// insert a test item to the test collection
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	// check that the reopened Change Stream captures the changes
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+}
+
+func TestSource_Read_successCDCHeartbeat(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeyCDCHeartbeatInterval] = "100ms"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// the collection stays quiet, so a heartbeat record is returned once the interval passes
+	var record opencdc.Record
+	for i := 0; i < 50; i++ {
+		record, err = source.Read(ctx)
+		if !errors.Is(err, sdk.ErrBackoffRetry) {
+			break
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+	is.NoErr(err)
+	is.Equal(record.Metadata["mongo.recordType"], "heartbeat")
+	is.Equal(record.Payload.After, nil)
+
+	// the heartbeat position resumes the Change Stream
+	is.NoErr(source.Teardown(ctx))
+
+	source = NewSource()
+	is.NoErr(source.Configure(ctx, sourceConfig))
+	is.NoErr(source.Open(ctx, record.Position))
+	t.Cleanup(func() {
+		is.NoErr(source.Teardown(context.Background()))
+	})
+
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	for i := 0; i < 50; i++ {
+		record, err = source.Read(ctx)
+		if !errors.Is(err, sdk.ErrBackoffRetry) {
+			break
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+}
+
+func TestSource_Read_successIncrementalSnapshot(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeySnapshotMode] = "incremental"
+	sourceConfig[ConfigKeySnapshotTrigger] = "backfill"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test collection
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	// drop the created test collection after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	// insert a test item to the test collection before the snapshot is captured
+	snapshotItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationSnapshot)
+	is.Equal(record.Payload.After, opencdc.RawData(snapshotItem.Bytes()))
+
+	// insert a test item that must be captured by CDC once the snapshot is completed
+	cdcItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	record, err = source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(cdcItem.Bytes()))
+
+	err = source.Teardown(ctx)
+	is.NoErr(err)
+
+	// reopen the source with the same trigger, the snapshot must not be captured again
+	source = NewSource()
+
+	err = source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	err = source.Open(ctx, record.Position)
+	is.NoErr(err)
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	err = source.Teardown(ctx)
+	is.NoErr(err)
+}
+
+func TestSource_LifecycleOnCreated_failCollectionNotExist(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := NewSource().LifecycleOnCreated(ctx, sourceConfig)
+	is.True(err != nil)
+	is.Equal(err.Error(), fmt.Sprintf(
+		`get mongo collection: collection "%s" doesn't exist`, sourceConfig[config.KeyCollection]),
+	)
+}
+
+func TestSource_LifecycleOnDeleted_successSignals(t *testing.T) {
+	is := is.New(t)
+
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeySignalCollection] = sourceConfig[config.KeyCollection] + "_signals"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	signalCollection := mongoClient.Database(sourceConfig[config.KeyDB]).
+		Collection(sourceConfig[ConfigKeySignalCollection])
+	t.Cleanup(func() {
+		err = signalCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	_, err = signalCollection.InsertOne(ctx, bson.M{"type": "pause"})
+	is.NoErr(err)
+
+	err = NewSource().LifecycleOnDeleted(ctx, sourceConfig)
+	is.NoErr(err)
+
+	// signals addressed to all connectors are kept for other connectors
+	count, err := signalCollection.CountDocuments(ctx, bson.M{})
+	is.NoErr(err)
+	is.Equal(count, int64(1))
+}
+
+func TestSource_Read_successSignalPauseResume(t *testing.T) {
+	is := is.New(t)
+
+	// prepare a config, configure and open a new source
+	sourceConfig := prepareConfig(t)
+	sourceConfig[ConfigKeySignalCollection] = sourceConfig[config.KeyCollection] + "_signals"
+
+	source := NewSource()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := source.Configure(ctx, sourceConfig)
+	is.NoErr(err)
+
+	mongoClient, err := createTestMongoClient(ctx, sourceConfig[config.KeyURI])
+	is.NoErr(err)
+	t.Cleanup(func() {
+		err = mongoClient.Disconnect(context.Background())
+		is.NoErr(err)
+	})
+
+	// connect to the test database and create the test and signal collections
+	testDatabase := mongoClient.Database(sourceConfig[config.KeyDB])
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[config.KeyCollection]))
+	is.NoErr(testDatabase.CreateCollection(ctx, sourceConfig[ConfigKeySignalCollection]))
+	testCollection := testDatabase.Collection(sourceConfig[config.KeyCollection])
+	signalCollection := testDatabase.Collection(sourceConfig[ConfigKeySignalCollection])
+	// drop the created collections after the test
+	t.Cleanup(func() {
+		err = testCollection.Drop(context.Background())
+		is.NoErr(err)
+
+		err = signalCollection.Drop(context.Background())
+		is.NoErr(err)
+	})
+
+	err = source.Open(ctx, nil)
+	is.NoErr(err)
+
+	// we expect backoff retry and switch to CDC mode here
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	// pause the source and insert a test item
+	_, err = signalCollection.InsertOne(ctx, bson.M{"type": "pause"})
+	is.NoErr(err)
+
+	testItem, err := createTestItem(ctx, testCollection)
+	is.NoErr(err)
+
+	// wait for the signal collection to be polled again
+	time.Sleep(time.Second)
+
+	_, err = source.Read(ctx)
+	is.Equal(err, sdk.ErrBackoffRetry)
+
+	// resume the source, the item must be returned
+	_, err = signalCollection.InsertOne(ctx, bson.M{"type": "resume"})
+	is.NoErr(err)
+
+	time.Sleep(time.Second)
+
+	record, err := source.Read(ctx)
+	is.NoErr(err)
+	is.Equal(record.Operation, opencdc.OperationCreate)
+	is.Equal(record.Payload.After, opencdc.RawData(testItem.Bytes()))
+
+	// processed signals are deleted
+	signalsCount, err := signalCollection.CountDocuments(ctx, bson.M{})
+	is.NoErr(err)
+	is.Equal(signalsCount, int64(0))
+
+	err = source.Teardown(ctx)
+	is.NoErr(err)
+}
+
 func TestSource_Read_successCDCAfterSnapshotPause(t *testing.T) {
 	is := is.New(t)
 
@@ -485,7 +1206,7 @@ func prepareConfig(t *testing.T) map[string]string {
 
 // createTestMongoClient connects to a MongoDB by a provided URI.
 func createTestMongoClient(ctx context.Context, uri string) (*mongo.Client, error) {
-	opts := options.Client().ApplyURI(uri).SetRegistry(newBSONCodecRegistry())
+	opts := options.Client().ApplyURI(uri).SetRegistry(newBSONCodecRegistry(true))
 
 	mongoClient, err := mongo.Connect(ctx, opts)
 	if err != nil {
@@ -21,9 +21,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
 	"github.com/conduitio-labs/conduit-connector-mongo/config"
+	"github.com/conduitio-labs/conduit-connector-mongo/source/iterator"
 	"github.com/conduitio-labs/conduit-connector-mongo/source/mock"
+	"github.com/conduitio-labs/conduit-connector-mongo/transform"
 	"github.com/conduitio/conduit-commons/opencdc"
+	opencdcv1 "github.com/conduitio/conduit-commons/proto/opencdc/v1"
 	"github.com/matryer/is"
 	"go.uber.org/mock/gomock"
 )
@@ -48,12 +52,45 @@ func TestSource_Configure_success(t *testing.T) {
 				Scheme: "mongodb",
 				Host:   "localhost:27017",
 			},
-			DB:         "test",
-			Collection: "users",
+			DB:                          "test",
+			Collection:                  "users",
+			Serverless:                  config.ServerlessAuto,
+			BufferPoolEnabled:           true,
+			TelemetryEnabled:            true,
+			ObjectIDCodecEnabled:        true,
+			IDType:                      codec.IDTypeAuto,
+			OpenMaxRetries:              3,
+			OpenRetryBackoff:            time.Second,
+			CommandMonitorSlowThreshold: 100 * time.Millisecond,
 		},
-		BatchSize:     defaultBatchSize,
-		Snapshot:      defaultSnapshot,
-		OrderingField: defaultOrderingField,
+		BatchSize:                  defaultBatchSize,
+		Snapshot:                   defaultSnapshot,
+		OrderingField:              defaultOrderingField,
+		SnapshotOnStaleToken:       defaultSnapshotOnStaleToken,
+		PayloadFormat:              defaultPayloadFormat,
+		KeyFormat:                  defaultKeyFormat,
+		ConvertDateTime:            defaultConvertDateTime,
+		ConvertTimestamp:           defaultConvertTimestamp,
+		ConvertDecimal:             defaultConvertDecimal,
+		ConvertUUID:                defaultConvertUUID,
+		ConvertBinary:              defaultConvertBinary,
+		SchemaMode:                 defaultSchemaMode,
+		SchemaDrift:                defaultSchemaDrift,
+		CDCSuppressCacheSize:       defaultCDCSuppressCacheSize,
+		CDCMode:                    defaultCDCMode,
+		CDCCoalesceMaxSize:         defaultCDCCoalesceMaxSize,
+		PollingDeleteStrategy:      defaultPollingDeleteStrategy,
+		PollingDeleteCheckInterval: defaultPollingDeleteCheckInterval,
+		Compatibility:              defaultCompatibility,
+		DBRefMode:                  defaultDBRefMode,
+		DBRefMaxDepth:              defaultDBRefMaxDepth,
+		PartitionKey:               defaultPartitionKey,
+		SnapshotAllowDiskUse:       defaultSnapshotAllowDiskUse,
+		SnapshotParallelism:        defaultSnapshotParallelism,
+		StrictTypes:                defaultStrictTypes,
+		SnapshotResumeBoundary:     defaultSnapshotResumeBoundary,
+		SchemaSampleSize:           defaultSchemaSampleSize,
+		SnapshotMode:               defaultSnapshotMode,
 	}
 	is.Equal(s.config, want)
 }
@@ -110,6 +147,112 @@ func TestSource_Read_success(t *testing.T) {
 	is.Equal(r, record)
 }
 
+func TestSource_Read_proto(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	// the value is above 2^53, so it's rounded if it's serialized as a double
+	it := mock.NewMockIterator(ctrl)
+	it.EXPECT().HasNext(ctx).Return(true, nil)
+	it.EXPECT().Next(ctx).Return(opencdc.Record{
+		Position:  opencdc.Position(`{"lastId": 1}`),
+		Operation: opencdc.OperationCreate,
+		Metadata:  opencdc.Metadata{"mongo.captureMode": "cdc"},
+		Key:       opencdc.RawData("1"),
+		Payload: opencdc.Change{After: opencdc.StructuredData{
+			"_id":       "1",
+			"total":     int64(9007199254740993),
+			"createdAt": time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+	}, nil)
+
+	s := Source{
+		iterator: it,
+		config:   Config{PayloadFormat: iterator.PayloadFormatJSON},
+	}
+
+	r, err := s.Read(ctx)
+	is.NoErr(err)
+
+	var proto opencdcv1.Record
+	is.NoErr(r.ToProto(&proto))
+
+	var got opencdc.Record
+	is.NoErr(got.FromProto(&proto))
+	is.Equal(got.Payload.After, opencdc.RawData(`{"_id":"1","createdAt":"2026-01-02T03:04:05Z","total":9007199254740993}`))
+
+	// the destination decodes the integer back without rounding it
+	var payload map[string]any
+	is.NoErr(codec.UnmarshalJSON(got.Payload.After.Bytes(), &payload))
+	is.Equal(codec.IntegerDecodingInt64.Decode(payload["total"]), int64(9007199254740993))
+}
+
+func TestSource_Read_version(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	it := mock.NewMockIterator(ctrl)
+	it.EXPECT().HasNext(ctx).Return(true, nil)
+	it.EXPECT().Next(ctx).Return(opencdc.Record{
+		Position: opencdc.Position(`{"lastId": 1}`),
+		Metadata: opencdc.Metadata{"mongo.captureMode": "snapshot"},
+	}, nil)
+
+	s := Source{
+		iterator: it,
+		version:  "v1.2.3",
+	}
+
+	r, err := s.Read(ctx)
+	is.NoErr(err)
+
+	is.Equal(r.Metadata, opencdc.Metadata{
+		"mongo.captureMode":       "snapshot",
+		"mongo.connector.version": "v1.2.3",
+	})
+}
+
+func TestSource_Read_transform(t *testing.T) {
+	t.Parallel()
+
+	is := is.New(t)
+
+	ctrl := gomock.NewController(t)
+	ctx := context.Background()
+
+	transformation, err := transform.New("total > 100", map[string]string{"double": "total * 2"})
+	is.NoErr(err)
+
+	it := mock.NewMockIterator(ctrl)
+	it.EXPECT().HasNext(ctx).Return(true, nil).Times(2)
+	gomock.InOrder(
+		it.EXPECT().Next(ctx).Return(opencdc.Record{
+			Payload: opencdc.Change{After: opencdc.StructuredData{"total": int32(10)}},
+		}, nil),
+		it.EXPECT().Next(ctx).Return(opencdc.Record{
+			Payload: opencdc.Change{After: opencdc.StructuredData{"total": int32(200)}},
+		}, nil),
+	)
+
+	s := Source{
+		iterator:  it,
+		transform: transformation,
+	}
+
+	// the first record is filtered out
+	r, err := s.Read(ctx)
+	is.NoErr(err)
+	is.Equal(r.Payload.After, opencdc.StructuredData{"total": int32(200), "double": int64(400)})
+}
+
 func TestSource_Read_failHasNext(t *testing.T) {
 	t.Parallel()
 
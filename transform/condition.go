@@ -0,0 +1,86 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// Condition is a predicate evaluated against whole records.
+//
+// The expression refers to the record's fields as operation, key, metadata, payload.before and payload.after,
+// e.g. operation != "delete" && payload.after.status == "active". Metadata keys contain dots,
+// so they're referred to as a whole, e.g. metadata.opencdc.collection. Raw data is a string.
+type Condition struct {
+	expression node
+}
+
+// NewCondition compiles the expression into a new [Condition].
+func NewCondition(expression string) (*Condition, error) {
+	compiled, err := parse(expression)
+	if err != nil {
+		return nil, fmt.Errorf("parse condition: %w", err)
+	}
+
+	return &Condition{expression: compiled}, nil
+}
+
+// Match evaluates the condition against the record and reports whether the record satisfies it.
+// A nil condition matches any record.
+func (c *Condition) Match(record opencdc.Record) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+
+	metadata := make(map[string]any, len(record.Metadata))
+	for key, value := range record.Metadata {
+		metadata[key] = value
+	}
+
+	result, err := c.expression.eval(map[string]any{
+		"operation": record.Operation.String(),
+		"key":       dataValue(record.Key),
+		"metadata":  metadata,
+		"payload": map[string]any{
+			"before": dataValue(record.Payload.Before),
+			"after":  dataValue(record.Payload.After),
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("evaluate condition: %w", err)
+	}
+
+	match, ok := result.(bool)
+	if !ok {
+		return false, errConditionNotBool
+	}
+
+	return match, nil
+}
+
+// dataValue returns the value expressions see for the data,
+// which is a document for structured data, a string for raw data and null if there's no data.
+func dataValue(data opencdc.Data) any {
+	switch typed := data.(type) {
+	case opencdc.StructuredData:
+		return map[string]any(typed)
+	case opencdc.RawData:
+		return string(typed)
+	default:
+		return nil
+	}
+}
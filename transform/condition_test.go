@@ -0,0 +1,102 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+func TestCondition_Match(t *testing.T) {
+	t.Parallel()
+
+	record := opencdc.Record{
+		Operation: opencdc.OperationUpdate,
+		Metadata:  opencdc.Metadata{"opencdc.collection": "users"},
+		Key:       opencdc.RawData("63bd5ee3ad5b1d4c6ad2b7e0"),
+		Payload: opencdc.Change{
+			Before: opencdc.StructuredData{"status": "pending"},
+			After:  testDocument(),
+		},
+	}
+
+	tests := []struct {
+		name       string
+		expression string
+		want       bool
+	}{
+		{name: "operation", expression: `operation == "update"`, want: true},
+		{name: "raw_key", expression: `key == "63bd5ee3ad5b1d4c6ad2b7e0"`, want: true},
+		{name: "metadata_key_with_dots", expression: `metadata.opencdc.collection == "users"`, want: true},
+		{name: "missing_metadata", expression: "has(metadata.opencdc.createdAt)", want: false},
+		{
+			name:       "payload",
+			expression: `payload.before.status != payload.after.status && payload.after.total > 100`,
+			want:       true,
+		},
+		{name: "nested_payload_field", expression: `payload.after.address.city == "Paris"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			condition, err := NewCondition(tt.expression)
+			if err != nil {
+				t.Fatalf("NewCondition() error = %v", err)
+			}
+
+			got, err := condition.Match(record)
+			if err != nil {
+				t.Fatalf("Match() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCondition_Match_nil(t *testing.T) {
+	t.Parallel()
+
+	var condition *Condition
+
+	got, err := condition.Match(opencdc.Record{})
+	if err != nil {
+		t.Fatalf("Match() error = %v", err)
+	}
+
+	if !got {
+		t.Errorf("Match() = %v, want true", got)
+	}
+}
+
+func TestCondition_Match_notBool(t *testing.T) {
+	t.Parallel()
+
+	condition, err := NewCondition("operation")
+	if err != nil {
+		t.Fatalf("NewCondition() error = %v", err)
+	}
+
+	_, err = condition.Match(opencdc.Record{Operation: opencdc.OperationCreate})
+	if !errors.Is(err, errConditionNotBool) {
+		t.Errorf("Match() error = %v, want %v", err, errConditionNotBool)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import "fmt"
+
+// SyntaxError occurs when an expression can't be parsed.
+type SyntaxError struct {
+	// Pos is the offset in the expression the error occurred at.
+	Pos    int
+	Reason string
+}
+
+// Error returns a formatted error message for the [SyntaxError].
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Pos, e.Reason)
+}
+
+// EvalError occurs when an expression can't be evaluated against a document,
+// e.g. if an operator is applied to values of unsupported types.
+type EvalError struct {
+	Reason string
+}
+
+// Error returns a formatted error message for the [EvalError].
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("evaluate expression: %s", e.Reason)
+}
@@ -0,0 +1,412 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+// node is a node of an expression tree evaluated against a document.
+type node interface {
+	eval(document map[string]any) (any, error)
+}
+
+// literalNode is a literal value.
+type literalNode struct {
+	value any
+}
+
+func (n *literalNode) eval(map[string]any) (any, error) {
+	return n.value, nil
+}
+
+// fieldNode is a reference to a document field, nested fields are separated by dots.
+type fieldNode struct {
+	path []string
+}
+
+func (n *fieldNode) eval(document map[string]any) (any, error) {
+	value, _ := lookup(document, n.path)
+
+	return value, nil
+}
+
+// unaryNode is a negation.
+type unaryNode struct {
+	op      string
+	operand node
+}
+
+func (n *unaryNode) eval(document map[string]any) (any, error) {
+	value, err := n.operand.eval(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "!" {
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, &EvalError{Reason: fmt.Sprintf("operator ! on %s", typeName(value))}
+		}
+
+		return !boolean, nil
+	}
+
+	switch number := normalizeNumber(value).(type) {
+	case int64:
+		return -number, nil
+	case float64:
+		return -number, nil
+	default:
+		return nil, &EvalError{Reason: fmt.Sprintf("operator - on %s", typeName(value))}
+	}
+}
+
+// binaryNode is an operation on two operands.
+type binaryNode struct {
+	op          string
+	left, right node
+}
+
+func (n *binaryNode) eval(document map[string]any) (any, error) {
+	left, err := n.left.eval(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// the logical operators short-circuit
+	if n.op == "&&" || n.op == "||" {
+		return n.evalLogical(document, left)
+	}
+
+	right, err := n.right.eval(document)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return equal(left, right), nil
+	case "!=":
+		return !equal(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	default:
+		return arithmetic(n.op, left, right)
+	}
+}
+
+// evalLogical evaluates the right operand of a logical operator only if the left one doesn't decide the result.
+func (n *binaryNode) evalLogical(document map[string]any, left any) (any, error) {
+	leftBool, ok := left.(bool)
+	if !ok {
+		return nil, &EvalError{Reason: fmt.Sprintf("operator %s on %s", n.op, typeName(left))}
+	}
+
+	if (n.op == "&&" && !leftBool) || (n.op == "||" && leftBool) {
+		return leftBool, nil
+	}
+
+	right, err := n.right.eval(document)
+	if err != nil {
+		return nil, err
+	}
+
+	rightBool, ok := right.(bool)
+	if !ok {
+		return nil, &EvalError{Reason: fmt.Sprintf("operator %s on %s", n.op, typeName(right))}
+	}
+
+	return rightBool, nil
+}
+
+// callNode is a function call.
+type callNode struct {
+	name     string
+	function function
+	args     []node
+}
+
+func (n *callNode) eval(document map[string]any) (any, error) {
+	// functions taking a field get the field node itself, as they inspect the field rather than its value
+	if n.function.fieldArg {
+		field, _ := n.args[0].(*fieldNode)
+		_, found := lookup(document, field.path)
+
+		return found, nil
+	}
+
+	value, err := n.args[0].eval(document)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := n.function.call(value)
+	if err != nil {
+		return nil, &EvalError{Reason: fmt.Sprintf("function %s: %s", n.name, err)}
+	}
+
+	return result, nil
+}
+
+// function is a function callable from expressions.
+type function struct {
+	arity int
+	// fieldArg defines if the function takes a field reference instead of a value.
+	fieldArg bool
+	call     func(value any) (any, error)
+}
+
+// functions is the list of functions callable from expressions.
+var functions = map[string]function{
+	// has checks whether the document has the field, even if its value is null
+	"has": {arity: 1, fieldArg: true},
+	"size": {arity: 1, call: func(value any) (any, error) {
+		switch typed := value.(type) {
+		case string:
+			return int64(utf8.RuneCountInString(typed)), nil
+		case []any:
+			return int64(len(typed)), nil
+		case map[string]any:
+			return int64(len(typed)), nil
+		case opencdc.StructuredData:
+			return int64(len(typed)), nil
+		default:
+			return nil, fmt.Errorf("unsupported argument of type %s", typeName(value))
+		}
+	}},
+	"lower": {arity: 1, call: stringFunction(strings.ToLower)},
+	"upper": {arity: 1, call: stringFunction(strings.ToUpper)},
+}
+
+// stringFunction wraps a function of strings into a function of values.
+func stringFunction(fn func(string) string) func(any) (any, error) {
+	return func(value any) (any, error) {
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported argument of type %s", typeName(value))
+		}
+
+		return fn(str), nil
+	}
+}
+
+// lookup returns the value of the field at the path, and whether the field exists.
+// Names containing dots, e.g. the metadata keys, take precedence over nested fields with the same path.
+func lookup(document map[string]any, path []string) (any, bool) {
+	var current any = document
+	for len(path) > 0 {
+		var fields map[string]any
+		switch typed := current.(type) {
+		case map[string]any:
+			fields = typed
+		case opencdc.StructuredData:
+			fields = typed
+		default:
+			return nil, false
+		}
+
+		found := false
+		for end := len(path); end > 0 && !found; end-- {
+			var value any
+			if value, found = fields[strings.Join(path[:end], ".")]; found {
+				current, path = value, path[end:]
+			}
+		}
+
+		if !found {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// normalizeNumber converts numbers of any type into either int64 or float64.
+// Values other than numbers are returned as they are.
+func normalizeNumber(value any) any {
+	switch number := value.(type) {
+	case int:
+		return int64(number)
+	case int32:
+		return int64(number)
+	case int64:
+		return number
+	case float32:
+		return float64(number)
+	case float64:
+		return number
+	default:
+		return value
+	}
+}
+
+// toFloat converts a normalized number into float64.
+func toFloat(value any) (float64, bool) {
+	switch number := value.(type) {
+	case int64:
+		return float64(number), true
+	case float64:
+		return number, true
+	default:
+		return 0, false
+	}
+}
+
+// equal checks whether the values are equal, numbers of different types are compared by their values.
+func equal(left, right any) bool {
+	left, right = normalizeNumber(left), normalizeNumber(right)
+
+	leftFloat, leftNumber := toFloat(left)
+	rightFloat, rightNumber := toFloat(right)
+	if leftNumber && rightNumber {
+		return leftFloat == rightFloat
+	}
+
+	return reflect.DeepEqual(left, right)
+}
+
+// compare compares two numbers or two strings. Comparisons with null are always false.
+func compare(op string, left, right any) (any, error) {
+	if left == nil || right == nil {
+		return false, nil
+	}
+
+	left, right = normalizeNumber(left), normalizeNumber(right)
+
+	var result int
+
+	leftFloat, leftNumber := toFloat(left)
+	rightFloat, rightNumber := toFloat(right)
+	leftString, leftIsString := left.(string)
+	rightString, rightIsString := right.(string)
+
+	switch {
+	case leftNumber && rightNumber:
+		result = compareOrdered(leftFloat, rightFloat)
+	case leftIsString && rightIsString:
+		result = strings.Compare(leftString, rightString)
+	default:
+		return nil, &EvalError{Reason: fmt.Sprintf("operator %s on %s and %s", op, typeName(left), typeName(right))}
+	}
+
+	switch op {
+	case "<":
+		return result < 0, nil
+	case "<=":
+		return result <= 0, nil
+	case ">":
+		return result > 0, nil
+	default:
+		return result >= 0, nil
+	}
+}
+
+// compareOrdered returns -1, 0 or 1 if the left value is less than, equal to or greater than the right one.
+func compareOrdered(left, right float64) int {
+	switch {
+	case left < right:
+		return -1
+	case left > right:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// arithmetic applies arithmetic operators to numbers, and + to strings as well.
+// Operations on integers result in integers, unless one of the operands is a float.
+func arithmetic(op string, left, right any) (any, error) {
+	left, right = normalizeNumber(left), normalizeNumber(right)
+
+	if leftString, ok := left.(string); ok && op == "+" {
+		if rightString, ok := right.(string); ok {
+			return leftString + rightString, nil
+		}
+	}
+
+	leftInt, leftIsInt := left.(int64)
+	rightInt, rightIsInt := right.(int64)
+	if leftIsInt && rightIsInt {
+		return intArithmetic(op, leftInt, rightInt)
+	}
+
+	leftFloat, leftNumber := toFloat(left)
+	rightFloat, rightNumber := toFloat(right)
+	if !leftNumber || !rightNumber {
+		return nil, &EvalError{Reason: fmt.Sprintf("operator %s on %s and %s", op, typeName(left), typeName(right))}
+	}
+
+	switch op {
+	case "+":
+		return leftFloat + rightFloat, nil
+	case "-":
+		return leftFloat - rightFloat, nil
+	case "*":
+		return leftFloat * rightFloat, nil
+	case "/":
+		return leftFloat / rightFloat, nil
+	default:
+		return math.Mod(leftFloat, rightFloat), nil
+	}
+}
+
+// intArithmetic applies arithmetic operators to integers.
+func intArithmetic(op string, left, right int64) (any, error) {
+	if (op == "/" || op == "%") && right == 0 {
+		return nil, &EvalError{Reason: "division by zero"}
+	}
+
+	switch op {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		return left / right, nil
+	default:
+		return left % right, nil
+	}
+}
+
+// typeName returns the name of the value's type used in error messages.
+func typeName(value any) string {
+	switch normalizeNumber(value).(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	case float64:
+		return "float"
+	case string:
+		return "string"
+	case []any:
+		return "list"
+	case map[string]any, opencdc.StructuredData:
+		return "map"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
@@ -0,0 +1,351 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind defines the kind of a [token].
+type tokenKind int
+
+// The available token kinds are listed below.
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+// token is a lexical token of an expression.
+type token struct {
+	kind  tokenKind
+	value string
+	// pos is the offset of the token in the expression, used in error messages.
+	pos int
+}
+
+// operators is the list of operators and punctuation, longer ones first so they're matched greedily.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ","}
+
+// binaryPrecedence maps binary operators to their precedence, higher binds tighter.
+var binaryPrecedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+// tokenize splits the expression into tokens.
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(expression); {
+		char := rune(expression[pos])
+
+		switch {
+		case unicode.IsSpace(char):
+			pos++
+
+		case char == '"' || char == '\'':
+			end := pos + 1
+			for end < len(expression) && expression[end] != expression[pos] {
+				if expression[end] == '\\' {
+					end++
+				}
+
+				end++
+			}
+
+			if end >= len(expression) {
+				return nil, &SyntaxError{Pos: pos, Reason: "unterminated string"}
+			}
+
+			value, err := unquote(expression[pos : end+1])
+			if err != nil {
+				return nil, &SyntaxError{Pos: pos, Reason: err.Error()}
+			}
+
+			tokens = append(tokens, token{kind: tokenString, value: value, pos: pos})
+			pos = end + 1
+
+		case unicode.IsDigit(char):
+			end := pos
+			for end < len(expression) && (unicode.IsDigit(rune(expression[end])) || expression[end] == '.') {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenNumber, value: expression[pos:end], pos: pos})
+			pos = end
+
+		case isIdentChar(char):
+			end := pos
+			for end < len(expression) && (isIdentChar(rune(expression[end])) || expression[end] == '.') {
+				end++
+			}
+
+			tokens = append(tokens, token{kind: tokenIdent, value: expression[pos:end], pos: pos})
+			pos = end
+
+		default:
+			operator := matchOperator(expression[pos:])
+			if operator == "" {
+				return nil, &SyntaxError{Pos: pos, Reason: fmt.Sprintf("unexpected character %q", char)}
+			}
+
+			tokens = append(tokens, token{kind: tokenOperator, value: operator, pos: pos})
+			pos += len(operator)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(expression)}), nil
+}
+
+// isIdentChar checks whether the character may be a part of an identifier.
+func isIdentChar(char rune) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || unicode.IsDigit(char)
+}
+
+// matchOperator returns the operator the input starts with, or an empty string if there's none.
+func matchOperator(input string) string {
+	for _, operator := range operators {
+		if strings.HasPrefix(input, operator) {
+			return operator
+		}
+	}
+
+	return ""
+}
+
+// unquote unquotes a string literal enclosed in either double or single quotes.
+func unquote(literal string) (string, error) {
+	if literal[0] == '\'' {
+		// single-quoted strings are converted to double-quoted ones, so they're unquoted the same way
+		literal = `"` + strings.ReplaceAll(strings.ReplaceAll(literal[1:len(literal)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+
+	value, err := strconv.Unquote(literal)
+	if err != nil {
+		return "", fmt.Errorf("invalid string literal: %w", err)
+	}
+
+	return value, nil
+}
+
+// parser builds an expression tree from tokens using precedence climbing.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// parse parses the expression into a tree of nodes.
+func parse(expression string) (node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	root, err := p.parseBinary(1)
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: next.pos, Reason: fmt.Sprintf("unexpected %q", next.value)}
+	}
+
+	return root, nil
+}
+
+// peek returns the current token without consuming it.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// advance consumes the current token and returns it.
+func (p *parser) advance() token {
+	current := p.tokens[p.pos]
+	if current.kind != tokenEOF {
+		p.pos++
+	}
+
+	return current
+}
+
+// expect consumes the current token if it's the provided operator, and fails otherwise.
+func (p *parser) expect(operator string) error {
+	if current := p.advance(); current.kind != tokenOperator || current.value != operator {
+		return &SyntaxError{Pos: current.pos, Reason: fmt.Sprintf("expected %q", operator)}
+	}
+
+	return nil
+}
+
+// parseBinary parses binary operations of the provided precedence or higher.
+func (p *parser) parseBinary(minPrecedence int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		current := p.peek()
+		precedence, ok := binaryPrecedence[current.value]
+		if current.kind != tokenOperator || !ok || precedence < minPrecedence {
+			return left, nil
+		}
+
+		p.advance()
+
+		right, err := p.parseBinary(precedence + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = &binaryNode{op: current.value, left: left, right: right}
+	}
+}
+
+// parseUnary parses negations and operands.
+func (p *parser) parseUnary() (node, error) {
+	if current := p.peek(); current.kind == tokenOperator && (current.value == "!" || current.value == "-") {
+		p.advance()
+
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return &unaryNode{op: current.value, operand: operand}, nil
+	}
+
+	return p.parseOperand()
+}
+
+// parseOperand parses literals, field references, function calls and parenthesized expressions.
+func (p *parser) parseOperand() (node, error) {
+	current := p.advance()
+
+	switch current.kind {
+	case tokenNumber:
+		return parseNumber(current)
+
+	case tokenString:
+		return &literalNode{value: current.value}, nil
+
+	case tokenIdent:
+		return p.parseIdent(current)
+
+	case tokenOperator:
+		if current.value != "(" {
+			break
+		}
+
+		inner, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+
+		return inner, nil
+
+	case tokenEOF:
+		return nil, &SyntaxError{Pos: current.pos, Reason: "unexpected end of expression"}
+	}
+
+	return nil, &SyntaxError{Pos: current.pos, Reason: fmt.Sprintf("unexpected %q", current.value)}
+}
+
+// parseIdent parses keywords, function calls and field references.
+func (p *parser) parseIdent(current token) (node, error) {
+	switch current.value {
+	case "true":
+		return &literalNode{value: true}, nil
+	case "false":
+		return &literalNode{value: false}, nil
+	case "null":
+		return &literalNode{value: nil}, nil
+	}
+
+	if next := p.peek(); next.kind != tokenOperator || next.value != "(" {
+		return &fieldNode{path: strings.Split(current.value, ".")}, nil
+	}
+
+	function, ok := functions[current.value]
+	if !ok {
+		return nil, &SyntaxError{Pos: current.pos, Reason: fmt.Sprintf("unknown function %q", current.value)}
+	}
+
+	p.advance()
+
+	var args []node
+	for next := p.peek(); next.kind != tokenOperator || next.value != ")"; next = p.peek() {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+
+		arg, err := p.parseBinary(1)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	p.advance()
+
+	if len(args) != function.arity {
+		return nil, &SyntaxError{
+			Pos:    current.pos,
+			Reason: fmt.Sprintf("function %q takes %d argument(s), got %d", current.value, function.arity, len(args)),
+		}
+	}
+
+	if _, isField := args[0].(*fieldNode); function.fieldArg && !isField {
+		return nil, &SyntaxError{Pos: current.pos, Reason: fmt.Sprintf("function %q takes a field", current.value)}
+	}
+
+	return &callNode{name: current.value, function: function, args: args}, nil
+}
+
+// parseNumber parses a number literal, which is an integer unless it has a fractional part.
+func parseNumber(current token) (node, error) {
+	if !strings.Contains(current.value, ".") {
+		value, err := strconv.ParseInt(current.value, 10, 64)
+		if err == nil {
+			return &literalNode{value: value}, nil
+		}
+	}
+
+	value, err := strconv.ParseFloat(current.value, 64)
+	if err != nil {
+		return nil, &SyntaxError{Pos: current.pos, Reason: fmt.Sprintf("invalid number %q", current.value)}
+	}
+
+	return &literalNode{value: value}, nil
+}
@@ -0,0 +1,130 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package transform implements a small CEL-like expression language the connectors evaluate connector-side:
+// the source against documents to compute derived fields and filter records,
+// and the destination against records to skip writing them.
+//
+// Expressions consist of field references, with nested fields separated by dots, e.g. address.city,
+// literals, i.e. numbers, 'single' or "double" quoted strings, true, false and null,
+// the operators ! - * / % + < <= > >= == != && || and parentheses,
+// and the functions has(field), size(value), lower(string) and upper(string).
+package transform
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+var (
+	// errFilterNotBool occurs when a filter expression evaluates to a value other than a bool.
+	errFilterNotBool = errors.New("filter must evaluate to a bool")
+	// errConditionNotBool occurs when a condition evaluates to a value other than a bool.
+	errConditionNotBool = errors.New("condition must evaluate to a bool")
+)
+
+// Transform computes derived fields of documents and filters records by their documents.
+type Transform struct {
+	filter node
+	fields []derivedField
+}
+
+// derivedField is a field computed by an expression.
+type derivedField struct {
+	name       string
+	expression node
+}
+
+// New compiles the filter and the expressions of the derived fields into a new [Transform].
+// The filter may be empty, so records are never filtered out.
+func New(filter string, fields map[string]string) (*Transform, error) {
+	transform := &Transform{}
+
+	if filter != "" {
+		var err error
+		transform.filter, err = parse(filter)
+		if err != nil {
+			return nil, fmt.Errorf("parse filter: %w", err)
+		}
+	}
+
+	// the fields are ordered by their names, so they're computed in a stable order
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		expression, err := parse(fields[name])
+		if err != nil {
+			return nil, fmt.Errorf("parse field %q: %w", name, err)
+		}
+
+		transform.fields = append(transform.fields, derivedField{name: name, expression: expression})
+	}
+
+	return transform, nil
+}
+
+// Apply computes the derived fields of the record's document and evaluates the filter against it,
+// including the derived fields. It returns false if the record must be skipped.
+// Derived fields are computed from the original document, so they can't refer to each other.
+// Records without structured documents, e.g. deletes, and control records, e.g. heartbeats
+// or collection events, are returned as they are.
+func (t *Transform) Apply(record opencdc.Record) (opencdc.Record, bool, error) {
+	if t == nil || record.Metadata[codec.MetadataFieldRecordType] != "" {
+		return record, true, nil
+	}
+
+	document, ok := record.Payload.After.(opencdc.StructuredData)
+	if !ok {
+		return record, true, nil
+	}
+
+	derived := make(map[string]any, len(t.fields))
+	for _, field := range t.fields {
+		value, err := field.expression.eval(document)
+		if err != nil {
+			return opencdc.Record{}, false, fmt.Errorf("compute field %q: %w", field.name, err)
+		}
+
+		derived[field.name] = value
+	}
+
+	for name, value := range derived {
+		document[name] = value
+	}
+
+	if t.filter == nil {
+		return record, true, nil
+	}
+
+	result, err := t.filter.eval(document)
+	if err != nil {
+		return opencdc.Record{}, false, fmt.Errorf("evaluate filter: %w", err)
+	}
+
+	keep, ok := result.(bool)
+	if !ok {
+		return opencdc.Record{}, false, errFilterNotBool
+	}
+
+	return record, keep, nil
+}
@@ -0,0 +1,260 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transform
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/conduitio-labs/conduit-connector-mongo/codec"
+	"github.com/conduitio/conduit-commons/opencdc"
+)
+
+func testDocument() opencdc.StructuredData {
+	return opencdc.StructuredData{
+		"_id":    "63bd5ee3ad5b1d4c6ad2b7e0",
+		"first":  "Ada",
+		"last":   "Lovelace",
+		"status": "active",
+		"total":  int32(150),
+		"price":  2.5,
+		"tags":   []any{"a", "b"},
+		"address": map[string]any{
+			"city": "London",
+			"zip":  nil,
+		},
+	}
+}
+
+func TestExpression_eval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expression string
+		want       any
+	}{
+		{name: "field", expression: "first", want: "Ada"},
+		{name: "nested_field", expression: "address.city", want: "London"},
+		{name: "missing_field", expression: "address.country", want: nil},
+		{name: "concat", expression: `first + ' ' + last`, want: "Ada Lovelace"},
+		{name: "int_arithmetic", expression: "total * 2 - 10 / 3 % 2", want: int64(299)},
+		{name: "float_arithmetic", expression: "price * total", want: 375.0},
+		{name: "precedence", expression: "(1 + 2) * 3", want: int64(9)},
+		{name: "negation", expression: "-total", want: int64(-150)},
+		{name: "equal_numbers_of_different_types", expression: "total == 150.0", want: true},
+		{name: "not_equal", expression: `status != "active"`, want: false},
+		{name: "compare_strings", expression: `last > "A"`, want: true},
+		{name: "compare_null", expression: "address.country > 1", want: false},
+		{name: "logical", expression: `status == "active" && (total > 100 || !has(price))`, want: true},
+		{name: "short_circuit", expression: `false && first > 1`, want: false},
+		{name: "has_null_field", expression: "has(address.zip)", want: true},
+		{name: "has_missing_field", expression: "has(address.country)", want: false},
+		{name: "size", expression: "size(tags) + size(first) + size(address)", want: int64(7)},
+		{name: "lower_upper", expression: "lower(first) + upper(last)", want: "adaLOVELACE"},
+		{name: "null_literal", expression: "address.zip == null", want: true},
+		{name: "escaped_string", expression: `"say \"hi\"" + 'it\'s'`, want: `say "hi"it's`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := parse(tt.expression)
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+
+			got, err := expression.eval(testDocument())
+			if err != nil {
+				t.Fatalf("eval() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("eval() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpression_evalError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{name: "add_string_and_number", expression: "first + 1"},
+		{name: "compare_string_and_number", expression: "first < 1"},
+		{name: "logical_on_string", expression: "first && true"},
+		{name: "not_on_number", expression: "!total"},
+		{name: "division_by_zero", expression: "total / 0"},
+		{name: "size_of_number", expression: "size(total)"},
+		{name: "lower_of_number", expression: "lower(total)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			expression, err := parse(tt.expression)
+			if err != nil {
+				t.Fatalf("parse() error = %v", err)
+			}
+
+			var evalErr *EvalError
+			if _, err := expression.eval(testDocument()); !errors.As(err, &evalErr) {
+				t.Errorf("eval() error = %v, want an EvalError", err)
+			}
+		})
+	}
+}
+
+func TestParse_syntaxError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expression string
+	}{
+		{name: "empty", expression: ""},
+		{name: "unterminated_string", expression: `first == "Ada`},
+		{name: "unexpected_character", expression: "first # last"},
+		{name: "missing_operand", expression: "total >"},
+		{name: "unbalanced_parentheses", expression: "(total > 1"},
+		{name: "trailing_token", expression: "total 1"},
+		{name: "unknown_function", expression: "trim(first)"},
+		{name: "wrong_arity", expression: "size(first, last)"},
+		{name: "has_of_value", expression: "has(1)"},
+		{name: "invalid_number", expression: "1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var syntaxErr *SyntaxError
+			if _, err := parse(tt.expression); !errors.As(err, &syntaxErr) {
+				t.Errorf("parse() error = %v, want a SyntaxError", err)
+			}
+		})
+	}
+}
+
+func TestTransform_Apply(t *testing.T) {
+	t.Parallel()
+
+	transform, err := New("total > threshold", map[string]string{
+		"fullName":  "first + ' ' + last",
+		"threshold": "100",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	record := opencdc.Record{Payload: opencdc.Change{After: testDocument()}}
+
+	got, keep, err := transform.Apply(record)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if !keep {
+		t.Errorf("Apply() keep = false, want true")
+	}
+
+	document, _ := got.Payload.After.(opencdc.StructuredData)
+	if document["fullName"] != "Ada Lovelace" || document["threshold"] != int64(100) {
+		t.Errorf("Apply() document = %v, want derived fields", document)
+	}
+
+	document = testDocument()
+	document["total"] = 50
+
+	record = opencdc.Record{Payload: opencdc.Change{After: document}}
+	if _, keep, err = transform.Apply(record); err != nil || keep {
+		t.Errorf("Apply() keep = %v, error = %v, want the record filtered out", keep, err)
+	}
+}
+
+func TestTransform_Apply_passThrough(t *testing.T) {
+	t.Parallel()
+
+	transform, err := New("total > 100", nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// deletes don't carry documents
+	record := opencdc.Record{Operation: opencdc.OperationDelete, Key: opencdc.StructuredData{"_id": "1"}}
+
+	got, keep, err := transform.Apply(record)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	if !keep || !reflect.DeepEqual(got, record) {
+		t.Errorf("Apply() = %v, %v, want the record as it is", got, keep)
+	}
+
+	var nilTransform *Transform
+	if _, keep, err := nilTransform.Apply(record); err != nil || !keep {
+		t.Errorf("Apply() of nil transform keep = %v, error = %v, want true", keep, err)
+	}
+}
+
+func TestTransform_Apply_controlRecord(t *testing.T) {
+	t.Parallel()
+
+	transform, err := New("total > 100", map[string]string{"fullName": "first + ' ' + last"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// the collection event carries a structured payload the filter would otherwise drop
+	record := opencdc.Record{
+		Operation: opencdc.OperationUpdate,
+		Metadata:  opencdc.Metadata{codec.MetadataFieldRecordType: codec.RecordTypeCollectionEvent},
+		Key:       opencdc.StructuredData{"collection": "users"},
+		Payload: opencdc.Change{
+			After: opencdc.StructuredData{"operationType": "drop", "db": "test", "collection": "users"},
+		},
+	}
+
+	got, keep, err := transform.Apply(record)
+	if err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	want := opencdc.StructuredData{"operationType": "drop", "db": "test", "collection": "users"}
+	if !keep || !reflect.DeepEqual(got.Payload.After, want) {
+		t.Errorf("Apply() = %v, %v, want the record as it is", got, keep)
+	}
+}
+
+func TestTransform_Apply_filterNotBool(t *testing.T) {
+	t.Parallel()
+
+	transform, err := New("total", nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	record := opencdc.Record{Payload: opencdc.Change{After: testDocument()}}
+	if _, _, err := transform.Apply(record); !errors.Is(err, errFilterNotBool) {
+		t.Errorf("Apply() error = %v, want %v", err, errFilterNotBool)
+	}
+}
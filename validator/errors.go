@@ -0,0 +1,118 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import "fmt"
+
+// The list of constraints a [FieldError] can report is listed below.
+const (
+	// ConstraintRequired means the parameter must be set.
+	ConstraintRequired = "required"
+	// ConstraintURI means the parameter must be a valid URI.
+	ConstraintURI = "uri"
+	// ConstraintMax means the parameter must not be longer or greater than the param.
+	ConstraintMax = "max"
+	// ConstraintFile means the parameter must be a path of an existing file.
+	ConstraintFile = "file"
+	// ConstraintGTE means the parameter must be greater than or equal to the param.
+	ConstraintGTE = "gte"
+	// ConstraintLTE means the parameter must be less than or equal to the param.
+	ConstraintLTE = "lte"
+	// ConstraintOneOf means the parameter must be one of the space-separated values of the param.
+	ConstraintOneOf = "oneof"
+	// ConstraintFormat means the parameter's value can't be parsed.
+	ConstraintFormat = "format"
+	// ConstraintCompatible means the parameter's value conflicts with the values of other parameters.
+	ConstraintCompatible = "compatible"
+)
+
+// FieldError is an error of a single config parameter. It carries the name of the parameter
+// and the violated constraint, so clients can point at the offending field.
+type FieldError struct {
+	// Key is the name of the parameter, e.g. "batchSize".
+	Key string
+	// Constraint is the violated constraint, e.g. [ConstraintRequired].
+	Constraint string
+	// Param is the parameter of the constraint, e.g. the limit of [ConstraintGTE], if it has any.
+	Param string
+	// Err is the cause of the error, e.g. the error of parsing the value, if there's any.
+	Err error
+}
+
+// NewFormatError returns a [FieldError] of the parameter whose value can't be parsed because of the err.
+func NewFormatError(key string, err error) error {
+	return &FieldError{Key: key, Constraint: ConstraintFormat, Err: err}
+}
+
+// NewFieldError returns a [FieldError] of the parameter that violates the constraint, described by the err.
+func NewFieldError(key, constraint string, err error) error {
+	return &FieldError{Key: key, Constraint: constraint, Err: err}
+}
+
+// Error returns a formatted error message for the [FieldError].
+func (e *FieldError) Error() string {
+	if e.Constraint == ConstraintFormat {
+		return fmt.Sprintf("parse %q: %s", e.Key, e.Err)
+	}
+
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+
+	switch e.Constraint {
+	case ConstraintRequired:
+		return fmt.Sprintf("%q value must be set", e.Key)
+	case ConstraintURI:
+		return fmt.Sprintf("%q value must be a valid URI", e.Key)
+	case ConstraintMax, ConstraintLTE:
+		return fmt.Sprintf("%q value must be less than or equal to %s", e.Key, e.Param)
+	case ConstraintFile:
+		return fmt.Sprintf("%q value must be a valid file path and exist", e.Key)
+	case ConstraintGTE:
+		return fmt.Sprintf("%q value must be greater than or equal to %s", e.Key, e.Param)
+	case ConstraintOneOf:
+		return fmt.Sprintf("%q value must be one of [%s]", e.Key, e.Param)
+	default:
+		return fmt.Sprintf("%q value violates the %s constraint", e.Key, e.Constraint)
+	}
+}
+
+// Unwrap returns the cause of the [FieldError].
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors returns all the [FieldError] the err consists of, including the ones joined and wrapped into it.
+func FieldErrors(err error) []*FieldError {
+	// the field errors are checked first, as they wrap their causes too
+	switch unwrapped := err.(type) { //nolint:errorlint // the errors are unwrapped one by one
+	case *FieldError:
+		return []*FieldError{unwrapped}
+
+	case interface{ Unwrap() []error }:
+		var fieldErrs []*FieldError
+		for _, joined := range unwrapped.Unwrap() {
+			fieldErrs = append(fieldErrs, FieldErrors(joined)...)
+		}
+
+		return fieldErrs
+
+	case interface{ Unwrap() error }:
+		return FieldErrors(unwrapped.Unwrap())
+
+	default:
+		return nil
+	}
+}
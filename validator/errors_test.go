@@ -0,0 +1,112 @@
+// Copyright © 2026 Meroxa, Inc. & Yalantis
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestValidateStruct_fieldErrors(t *testing.T) {
+	t.Parallel()
+
+	data := &struct {
+		Name      string `key:"name" validate:"required"`
+		BatchSize int    `key:"batchSize" validate:"gte=1"`
+		Mode      string `key:"mode" validate:"oneof=a b"`
+	}{
+		BatchSize: 0,
+		Mode:      "c",
+	}
+
+	err := fmt.Errorf("validate config: %w", ValidateStruct(data))
+
+	got := FieldErrors(err)
+	want := []*FieldError{
+		{Key: "name", Constraint: ConstraintRequired},
+		{Key: "batchSize", Constraint: ConstraintGTE, Param: "1"},
+		{Key: "mode", Constraint: ConstraintOneOf, Param: "a b"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FieldErrors() = %v, want %v", got, want)
+	}
+
+	wantMessage := `validate config: "name" value must be set; ` +
+		`"batchSize" value must be greater than or equal to 1; "mode" value must be one of [a b]`
+	if err.Error() != wantMessage {
+		t.Errorf("Error() = %q, want %q", err.Error(), wantMessage)
+	}
+}
+
+func TestFieldErrors(t *testing.T) {
+	t.Parallel()
+
+	_, parseErr := strconv.Atoi("ten")
+	formatErr := NewFormatError("batchSize", parseErr)
+
+	tests := []struct {
+		name string
+		err  error
+		want []*FieldError
+	}{
+		{
+			name: "format_error",
+			err:  fmt.Errorf("parse config: %w", formatErr),
+			want: []*FieldError{{Key: "batchSize", Constraint: ConstraintFormat, Err: parseErr}},
+		},
+		{
+			name: "joined_errors",
+			err: errors.Join(
+				NewFieldError("mode", ConstraintCompatible, errors.New("conflict")),
+				errors.New("not a field error"),
+			),
+			want: []*FieldError{{Key: "mode", Constraint: ConstraintCompatible, Err: errors.New("conflict")}},
+		},
+		{
+			name: "other_error",
+			err:  errors.New("connect"),
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := FieldErrors(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldErrors() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldError_Error_format(t *testing.T) {
+	t.Parallel()
+
+	_, parseErr := strconv.ParseBool("maybe")
+
+	err := NewFormatError("snapshot", parseErr)
+	if want := `parse "snapshot": strconv.ParseBool: parsing "maybe": invalid syntax`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("errors.Is(%v, strconv.ErrSyntax) = false, want true", err)
+	}
+}
@@ -34,7 +34,7 @@ var (
 	once     sync.Once
 )
 
-// ValidateStruct validates a struct.
+// ValidateStruct validates a struct. The returned error joins a [FieldError] per violated constraint.
 func ValidateStruct(data any) error {
 	lazyInit()
 
@@ -49,20 +49,12 @@ func ValidateStruct(data any) error {
 			for _, fieldErr := range validationErrs {
 				fieldName := getFieldKey(data, fieldErr.StructField())
 
-				switch fieldErr.Tag() {
-				case "required":
-					err = multierr.Append(err, requiredErr(fieldName))
-				case "uri":
-					err = multierr.Append(err, uriErr(fieldName))
-				case "max":
-					err = multierr.Append(err, maxErr(fieldName, fieldErr.Param()))
-				case "file":
-					err = multierr.Append(err, fileErr(fieldName))
-				case "gte":
-					err = multierr.Append(err, gteErr(fieldName, fieldErr.Param()))
-				case "lte":
-					err = multierr.Append(err, lteErr(fieldName, fieldErr.Param()))
+				constraint := fieldErr.Tag()
+				if constraint == "required_if" {
+					constraint = ConstraintRequired
 				}
+
+				err = multierr.Append(err, &FieldError{Key: fieldName, Constraint: constraint, Param: fieldErr.Param()})
 			}
 		}
 	}
@@ -71,36 +63,6 @@ func ValidateStruct(data any) error {
 	return err
 }
 
-// requiredErr returns the formatted required error.
-func requiredErr(name string) error {
-	return fmt.Errorf("%q value must be set", name)
-}
-
-// uriErr returns the formatted uri error.
-func uriErr(name string) error {
-	return fmt.Errorf("%q value must be a valid URI", name)
-}
-
-// maxErr returns the formatted max error.
-func maxErr(name, limit string) error {
-	return fmt.Errorf("%q value must be less than or equal to %s", name, limit)
-}
-
-// fileErr returns the formatted file error.
-func fileErr(name string) error {
-	return fmt.Errorf("%q value must be a valid file path and exist", name)
-}
-
-// gteErr returns the formatted gte error.
-func gteErr(name, gte string) error {
-	return fmt.Errorf("%q value must be greater than or equal to %s", name, gte)
-}
-
-// lteErr returns the formatted lte error.
-func lteErr(name, lte string) error {
-	return fmt.Errorf("%q value must be less than or equal to %s", name, lte)
-}
-
 // getFieldKey returns a key ("key" tag) for the provided fieldName. If the "key" tag is not present,
 // the function will return a fieldName.
 func getFieldKey(data any, fieldName string) string {